@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 	"database/sql"
-	"log"
 	"net/http"
 	"os"
 	"os/signal"
@@ -13,10 +12,14 @@ import (
 	"github.com/booksage/booksage-api/internal/agent"
 	"github.com/booksage/booksage-api/internal/config"
 	"github.com/booksage/booksage-api/internal/database/bunstore"
+	"github.com/booksage/booksage-api/internal/database/factory"
 	"github.com/booksage/booksage-api/internal/embedding"
 	"github.com/booksage/booksage-api/internal/fusion"
+	"github.com/booksage/booksage-api/internal/health"
+	"github.com/booksage/booksage-api/internal/infrastructure/observability"
 	"github.com/booksage/booksage-api/internal/ingest"
 	"github.com/booksage/booksage-api/internal/llm"
+	"github.com/booksage/booksage-api/internal/logging"
 	neo4jpkg "github.com/booksage/booksage-api/internal/neo4j"
 	pb "github.com/booksage/booksage-api/internal/pb/booksage/v1"
 	qdrantpkg "github.com/booksage/booksage-api/internal/qdrant"
@@ -28,19 +31,42 @@ import (
 )
 
 func main() {
-	log.Println("Starting BookSage API Orchestrator...")
+	logger := logging.New()
+	logger.Info("starting booksage api orchestrator")
 
 	// Load Configuration
 	cfg := config.Load()
 
+	// Install the global TracerProvider/MeterProvider. With cfg.OTLPEndpoint
+	// unset, spans are still created but nothing exports them -- fine for
+	// local dev; point SAGE_OTEL_EXPORTER_OTLP_ENDPOINT at a collector to
+	// ship them to Jaeger/Tempo.
+	telemetry, err := observability.NewTelemetry(observability.Config{
+		ServiceName:  "booksage-api",
+		OTLPEndpoint: cfg.OTLPEndpoint,
+		OTLPInsecure: cfg.OTLPInsecure,
+	})
+	if err != nil {
+		logger.Error("failed to initialize telemetry", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := telemetry.Shutdown(shutdownCtx); err != nil {
+			logger.Error("telemetry shutdown error", "error", err)
+		}
+	}()
+
 	// Connect to the Python ML Worker
-	log.Printf("Connecting to ML Worker at %s...", cfg.WorkerAddr)
+	logger.Info("connecting to ml worker", "addr", cfg.WorkerAddr)
 	conn, err := grpc.NewClient(cfg.WorkerAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
-		log.Fatalf("Failed to connect to worker: %v", err)
+		logger.Error("failed to connect to worker", "error", err)
+		os.Exit(1)
 	}
 	defer func() { _ = conn.Close() }()
-	log.Printf("Connected successfully.")
+	logger.Info("connected to ml worker successfully")
 
 	// ==========================================
 	// Initialize Dependencies (Dependency Injection)
@@ -51,12 +77,14 @@ func main() {
 	var geminiClient llm.LLMClient
 	if !cfg.UseLocalOnlyLLM {
 		if cfg.GeminiAPIKey == "" {
-			log.Fatalf("[Error] BS_GEMINI_API_KEY is not set and BS_USE_LOCAL_ONLY_LLM is false. Cannot start Orchestrator.")
+			logger.Error("BS_GEMINI_API_KEY is not set and BS_USE_LOCAL_ONLY_LLM is false, cannot start orchestrator")
+			os.Exit(1)
 		}
 		var err error
 		geminiClient, err = llm.NewGeminiClient(ctx, cfg.GeminiAPIKey)
 		if err != nil {
-			log.Fatalf("[Error] Failed to initialize Gemini: %v", err)
+			logger.Error("failed to initialize gemini", "error", err)
+			os.Exit(1)
 		}
 	}
 
@@ -65,14 +93,13 @@ func main() {
 
 	// Override Gemini with Local Client if requested
 	if cfg.UseLocalOnlyLLM {
-		log.Println("[System] 🏠 BS_USE_LOCAL_ONLY_LLM is true. Overriding Gemini with Local Ollama.")
+		logger.Info("BS_USE_LOCAL_ONLY_LLM is true, overriding gemini with local ollama")
 		geminiClient = localClient
 	}
 
 	// Initialize the LLM Router
-	llmRouter := llm.NewRouter(localClient, geminiClient)
-	log.Printf("[System] 🛤️  LLM Router initialized (Cloud: %s | Local: %s)",
-		geminiClient.Name(), localClient.Name())
+	llmRouter := llm.NewRouter(localClient, geminiClient, logger)
+	logger.Info("llm router initialized", "cloud", geminiClient.Name(), "local", localClient.Name())
 
 	// NOTE: Generator will be initialized after DB clients so we can inject the retriever.
 	// See below after Qdrant/Neo4j initialization.
@@ -81,47 +108,130 @@ func main() {
 	parserClient := pb.NewDocumentParserServiceClient(conn)
 	embedClient := pb.NewEmbeddingServiceClient(conn)
 
-	// Wrap embedClient in a Batcher (max 100 texts per gRPC batch)
-	embedBatcher := embedding.NewBatcher(embedClient, 100)
+	// embedBatcher runs the ML worker through a content-addressed cache
+	// (embedding.CachingEmbeddingClient), so re-embedding text RAPTOR has
+	// already summarized, or a document re-processed after a failed
+	// ingest, never makes a second call to the ML worker, then packs the
+	// result into a Batcher by token budget rather than a fixed text count
+	// (embedding.Batcher). See embedding.NewCachedBatcher.
+	embedBatcher := embedding.NewCachedBatcher(embedClient, nil, cfg.EmbedMaxTokensPerBatch, cfg.EmbedMaxConcurrentBatches, logger)
 
-	// Initialize Database Clients and Saga Orchestrator
-	sqldb, err := sql.Open(sqliteshim.ShimName, "booksage.db")
+	// Initialize Database Clients and Saga Orchestrator. The saga/document
+	// backend is selected by cfg.SagaStoreDriver (sqlite/postgres/mongo/
+	// redis); see internal/database/factory. UploadRepository and
+	// CritiqueRepository aren't part of that abstraction yet, so they
+	// still go through a dedicated bun/SQLite store regardless of driver.
+	docRepo, sagaRepo, err := factory.New(ctx, factory.Config{
+		Driver:        cfg.SagaStoreDriver,
+		SQLiteDSN:     cfg.SQLiteDSN,
+		PostgresDSN:   cfg.PostgresDSN,
+		MongoURI:      cfg.MongoURI,
+		MongoDatabase: cfg.MongoDatabase,
+		RedisAddr:     cfg.RedisAddr,
+		RedisPassword: cfg.RedisPassword,
+		RedisDB:       cfg.RedisDB,
+	})
 	if err != nil {
-		log.Fatalf("[Error] Failed to open sqlite: %v", err)
+		logger.Error("failed to initialize database", "error", err)
+		os.Exit(1)
 	}
+	logger.Info("saga store driver selected", "driver", cfg.SagaStoreDriver)
 
+	sqldb, err := sql.Open(sqliteshim.ShimName, cfg.SQLiteDSN)
+	if err != nil {
+		logger.Error("failed to open sqlite", "error", err)
+		os.Exit(1)
+	}
 	bunStore, err := bunstore.NewBunStore(sqldb, sqlitedialect.New())
 	if err != nil {
-		log.Fatalf("[Error] Failed to initialize Database: %v", err)
+		logger.Error("failed to initialize upload/critique store", "error", err)
+		os.Exit(1)
 	}
 
 	qdrantClient, err := qdrantpkg.NewClient(cfg.QdrantHost, cfg.QdrantPort, cfg.QdrantCollection)
 	if err != nil {
-		log.Fatalf("[Error] Failed to connect to Qdrant: %v", err)
+		logger.Error("failed to connect to qdrant", "error", err)
+		os.Exit(1)
 	}
 	defer func() { _ = qdrantClient.Close() }()
 
 	neo4jClient, err := neo4jpkg.NewClient(ctx, cfg.Neo4jURI, cfg.Neo4jUser, cfg.Neo4jPassword)
 	if err != nil {
-		log.Fatalf("[Error] Failed to connect to Neo4j: %v", err)
+		logger.Error("failed to connect to neo4j", "error", err)
+		os.Exit(1)
 	}
 	defer func() { _ = neo4jClient.Close(ctx) }()
 
-	sagaOrchestrator := ingest.NewOrchestrator(qdrantClient, neo4jClient, bunStore, bunStore)
+	// Build the health registry and one circuit breaker per dependency
+	// call site, before anything that needs to consult either. qdrant,
+	// neo4j, and bunStore always implement health.Prober; geminiClient is
+	// only registered separately from localClient when it isn't just
+	// localClient again under cfg.UseLocalOnlyLLM, so a local-only
+	// deployment doesn't ping the same Ollama server twice under one name.
+	healthRegistry := health.NewRegistry()
+	healthRegistry.Register(qdrantClient)
+	healthRegistry.Register(neo4jClient)
+	healthRegistry.Register(localClient)
+	healthRegistry.Register(bunStore)
+	if !cfg.UseLocalOnlyLLM {
+		if prober, ok := geminiClient.(health.Prober); ok {
+			healthRegistry.Register(prober)
+		}
+	}
+
+	qdrantBreaker := health.NewCircuitBreaker("qdrant", health.DefaultFailureThreshold, health.DefaultCooldown)
+	neo4jBreaker := health.NewCircuitBreaker("neo4j", health.DefaultFailureThreshold, health.DefaultCooldown)
+	treeBreaker := health.NewCircuitBreaker("tree", health.DefaultFailureThreshold, health.DefaultCooldown)
+	healthRegistry.RegisterBreaker("qdrant", qdrantBreaker)
+	healthRegistry.RegisterBreaker("neo4j", neo4jBreaker)
+	healthRegistry.RegisterBreaker("tree", treeBreaker)
+
+	sagaOrchestrator := ingest.NewOrchestrator(qdrantClient, neo4jClient, docRepo, sagaRepo, logger).
+		WithRaptorBuilder(ingest.NewRaptorBuilder(llmRouter, embedBatcher)).
+		WithCircuitBreakers(qdrantBreaker, neo4jBreaker)
 
-	// Initialize the Fusion Retriever (Qdrant + Neo4j + Embedding)
-	fusionRetriever := fusion.NewFusionRetriever(qdrantClient, neo4jClient, embedBatcher)
+	// Redrive any saga a prior process crashed mid-ingestion on, before
+	// serving new requests -- otherwise a document interrupted between its
+	// embedding and indexing steps would sit stuck until something else
+	// happened to retry it.
+	if err := sagaOrchestrator.Recover(context.Background()); err != nil {
+		logger.Error("saga recovery failed", "error", err)
+	}
+
+	// Initialize the Fusion Retriever (Qdrant + Neo4j + Embedding). The
+	// graph engine shares neo4jBreaker with the saga's indexing step since
+	// both guard the same Neo4j dependency; the tree engine gets its own,
+	// since a RAPTOR tree can be absent or slow to query independently of
+	// Neo4j's base graph health.
+	fusionRetriever := fusion.NewFusionRetriever(qdrantClient, neo4jClient, embedBatcher, llmRouter, logger).
+		WithIntentFeedbackRepository(bunStore).
+		WithCircuitBreakers(qdrantBreaker, neo4jBreaker, treeBreaker)
+	if cfg.IntentClassifier == "llm" {
+		fusionRetriever.WithClassifier(fusion.NewLLMClassifier(llmRouter))
+	}
+	fusionRetriever.SetUseLearnedWeights(cfg.UseLearnedIntentWeights)
 
 	// Inject the Router and Retriever into the Agentic Generator
-	generator := agent.NewGenerator(llmRouter, fusionRetriever)
+	generator := agent.NewGenerator(llmRouter, fusionRetriever, logger).
+		WithMaxReflectionIterations(cfg.SelfRAGMaxIterations)
 
 	// ==========================================
 	// Initialize and Start HTTP Server
 	// ==========================================
 
-	apiServer := server.NewServer(generator, embedBatcher, parserClient, sagaOrchestrator)
+	apiServer := server.NewServer(generator, embedBatcher, parserClient, sagaOrchestrator).
+		WithUploadRepository(bunStore, cfg.UploadDir).
+		WithTelemetry(telemetry).
+		WithCritiqueRepository(bunStore).
+		WithHealthRegistry(healthRegistry)
 	handler := apiServer.RegisterRoutes()
 
+	// Reclaim temp files left behind by uploads nobody ever resumed or committed.
+	janitorCtx, stopJanitor := context.WithCancel(context.Background())
+	defer stopJanitor()
+	apiServer.StartUploadJanitor(janitorCtx, cfg.UploadJanitorInterval, cfg.UploadMaxAge)
+	fusionRetriever.StartIntentFeedbackJob(janitorCtx, cfg.IntentFeedbackJobInterval)
+
 	// ==========================================
 	// Graceful Shutdown
 	// ==========================================
@@ -136,21 +246,22 @@ func main() {
 	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
 
 	go func() {
-		log.Println("[System] 🌐 Starting REST API Server on :8080")
+		logger.Info("starting rest api server", "addr", ":8080")
 		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("[Error] HTTP server failed: %v", err)
+			logger.Error("http server failed", "error", err)
+			os.Exit(1)
 		}
 	}()
 
 	<-stop
-	log.Println("[System] 🛑 Shutdown signal received. Draining connections...")
+	logger.Info("shutdown signal received, draining connections")
 
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	if err := httpServer.Shutdown(shutdownCtx); err != nil {
-		log.Printf("[Error] HTTP shutdown error: %v", err)
+		logger.Error("http shutdown error", "error", err)
 	}
 
-	log.Println("[System] ✅ Server stopped gracefully.")
+	logger.Info("server stopped gracefully")
 }
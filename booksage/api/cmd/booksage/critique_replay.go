@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/booksage/booksage-api/internal/config"
+	"github.com/booksage/booksage-api/internal/domain/repository"
+	"github.com/booksage/booksage-api/internal/llm"
+	"github.com/booksage/booksage-api/internal/logging"
+	"github.com/booksage/booksage-api/internal/usecase/query"
+)
+
+// llmRouterAdapter lets the live *llm.Router stand in for the
+// repository.LLMRouter that query.SelfRAGCritique was built against.
+// The two diverge only in that llm.Router.RouteLLMTask takes a context --
+// plumbing one through from Critique/Reflect* callers would mean forking
+// repository.LLMRouter's signature for a single offline CLI, so this
+// backfills context.Background() instead.
+type llmRouterAdapter struct {
+	router *llm.Router
+}
+
+func (a llmRouterAdapter) RouteLLMTask(task repository.TaskType) repository.LLMClient {
+	return llmClientAdapter{client: a.router.RouteLLMTask(context.Background(), llm.TaskType(task))}
+}
+
+// llmClientAdapter lets an llm.LLMClient stand in for a repository.LLMClient.
+// llm.LLMClient has no structured-output capability, so
+// SupportsStructuredOutput always reports false and callers fall back to
+// prose-parsed verdicts -- the same outcome critique-replay has always had.
+type llmClientAdapter struct {
+	client llm.LLMClient
+}
+
+func (a llmClientAdapter) Generate(ctx context.Context, prompt string) (string, error) {
+	return a.client.Generate(ctx, prompt)
+}
+
+func (a llmClientAdapter) Name() string {
+	return a.client.Name()
+}
+
+func (a llmClientAdapter) SupportsStructuredOutput() bool {
+	return false
+}
+
+// replayItem is one line of the -input file: a (query, context) pair a
+// prior CritiqueEvent was judged against, recovered from wherever the
+// operator keeps retrieval logs. The audit log itself only stores a
+// ContextHash (see models.CritiqueEvent), not the context text, so it can't
+// drive a replay on its own -- this file is how the actual text gets back
+// in. PreviousVerdict is optional context for the diff printed per item;
+// leave it blank to just see what the current judge model says.
+type replayItem struct {
+	Kind            string `json:"kind"` // "retrieval" or "generation"
+	Query           string `json:"query"`
+	Context         string `json:"context"`
+	Answer          string `json:"answer,omitempty"` // required for Kind == "generation"
+	PreviousVerdict string `json:"previous_verdict,omitempty"`
+}
+
+// replayResult is one line of critique-replay's output: the new verdict for
+// a replayItem, and whether it agrees with the verdict the item was
+// recorded with.
+type replayResult struct {
+	Kind            string  `json:"kind"`
+	Query           string  `json:"query"`
+	PreviousVerdict string  `json:"previous_verdict,omitempty"`
+	NewVerdict      string  `json:"new_verdict"`
+	Confidence      float64 `json:"confidence"`
+	Changed         bool    `json:"changed,omitempty"`
+}
+
+// runCritiqueReplay re-judges every (query, context) pair in -input against
+// the LLM router's current judge model, so an operator can tell whether a
+// model swap would flip verdicts before rolling it out.
+func runCritiqueReplay(args []string) error {
+	fs := flag.NewFlagSet("critique-replay", flag.ExitOnError)
+	inputPath := fs.String("input", "", "path to a JSONL file of replay items (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *inputPath == "" {
+		return fmt.Errorf("-input is required")
+	}
+
+	f, err := os.Open(*inputPath)
+	if err != nil {
+		return fmt.Errorf("opening input: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	cfg := config.Load()
+	localClient := llm.NewLocalOllamaClient(cfg.OllamaHost, cfg.OllamaModel)
+	var geminiClient llm.LLMClient = localClient
+	if !cfg.UseLocalOnlyLLM {
+		if cfg.GeminiAPIKey == "" {
+			return fmt.Errorf("SAGE_GEMINI_API_KEY is not set and SAGE_USE_LOCAL_ONLY_LLM is false")
+		}
+		geminiClient, err = llm.NewGeminiClient(context.Background(), cfg.GeminiAPIKey)
+		if err != nil {
+			return fmt.Errorf("initializing Gemini: %w", err)
+		}
+	}
+	critique := query.NewSelfRAGCritique(llmRouterAdapter{router: llm.NewRouter(localClient, geminiClient, logging.New())})
+
+	ctx := context.Background()
+	scanner := bufio.NewScanner(f)
+	encoder := json.NewEncoder(os.Stdout)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var item replayItem
+		if err := json.Unmarshal(line, &item); err != nil {
+			log.Printf("[critique-replay] skipping line %d: %v", lineNo, err)
+			continue
+		}
+
+		var result replayResult
+		switch item.Kind {
+		case "retrieval":
+			reflection := critique.ReflectRetrieval(ctx, item.Query, item.Context)
+			verdict := "irrelevant"
+			if reflection.Relevant {
+				verdict = "relevant"
+			}
+			result = replayResult{Kind: item.Kind, Query: item.Query, NewVerdict: verdict, Confidence: reflection.Confidence}
+		case "generation":
+			reflection := critique.ReflectGeneration(ctx, item.Answer, item.Context)
+			result = replayResult{Kind: item.Kind, Query: item.Query, NewVerdict: string(reflection.Support), Confidence: reflection.Confidence}
+		default:
+			log.Printf("[critique-replay] skipping line %d: unknown kind %q", lineNo, item.Kind)
+			continue
+		}
+
+		result.PreviousVerdict = item.PreviousVerdict
+		result.Changed = item.PreviousVerdict != "" && item.PreviousVerdict != result.NewVerdict
+		if err := encoder.Encode(result); err != nil {
+			return fmt.Errorf("writing result: %w", err)
+		}
+	}
+	if err := scanner.Err(); err != nil && err != io.EOF {
+		return fmt.Errorf("reading input: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	switch os.Args[1] {
+	case "critique-replay":
+		if err := runCritiqueReplay(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "critique-replay: %v\n", err)
+			os.Exit(1)
+		}
+	case "saga-migrate":
+		if err := runSagaMigrate(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "saga-migrate: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		usage()
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: booksage <command> [flags]")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	fmt.Fprintln(os.Stderr, "  critique-replay   Re-run stored critique pairs against the current judge model")
+	fmt.Fprintln(os.Stderr, "  saga-migrate      Replay an existing saga store's history into a different backend")
+}
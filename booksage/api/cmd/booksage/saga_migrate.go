@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/booksage/booksage-api/internal/database/factory"
+)
+
+// storeFlags holds one side (-from-* or -to-*) of runSagaMigrate's flag set.
+type storeFlags struct {
+	driver        *string
+	sqliteDSN     *string
+	postgresDSN   *string
+	mongoURI      *string
+	mongoDatabase *string
+	redisAddr     *string
+	redisPassword *string
+	redisDB       *int
+}
+
+func registerStoreFlags(fs *flag.FlagSet, prefix string) *storeFlags {
+	return &storeFlags{
+		driver:        fs.String(prefix, "", fmt.Sprintf("source/destination backend for -%s (sqlite, postgres, mongo, or redis; required)", prefix)),
+		sqliteDSN:     fs.String(prefix+"-sqlite-dsn", "booksage.db", "sqlite DSN, if -"+prefix+"=sqlite"),
+		postgresDSN:   fs.String(prefix+"-postgres-dsn", "", "postgres DSN, if -"+prefix+"=postgres"),
+		mongoURI:      fs.String(prefix+"-mongo-uri", "mongodb://localhost:27017", "mongo URI, if -"+prefix+"=mongo"),
+		mongoDatabase: fs.String(prefix+"-mongo-db", "booksage", "mongo database name, if -"+prefix+"=mongo"),
+		redisAddr:     fs.String(prefix+"-redis-addr", "localhost:6379", "redis host:port, if -"+prefix+"=redis"),
+		redisPassword: fs.String(prefix+"-redis-password", "", "redis password, if -"+prefix+"=redis"),
+		redisDB:       fs.Int(prefix+"-redis-db", 0, "redis logical DB index, if -"+prefix+"=redis"),
+	}
+}
+
+func (f *storeFlags) config() factory.Config {
+	return factory.Config{
+		Driver:        factory.Driver(*f.driver),
+		SQLiteDSN:     *f.sqliteDSN,
+		PostgresDSN:   *f.postgresDSN,
+		MongoURI:      *f.mongoURI,
+		MongoDatabase: *f.mongoDatabase,
+		RedisAddr:     *f.redisAddr,
+		RedisPassword: *f.redisPassword,
+		RedisDB:       *f.redisDB,
+	}
+}
+
+// runSagaMigrate replays every document, saga, and saga step from one
+// database.DocumentRepository/SagaRepository backend into another, so an
+// operator can cut a running deployment over to a different
+// internal/database/factory driver (e.g. sqlite -> mongo) without losing
+// ingest history.
+//
+// IDs are not preserved: each destination record is created through the
+// destination backend's own ID allocator, and old-ID -> new-ID mappings are
+// used to rewrite the DocumentID/SagaID references that follow. Anything
+// outside this store that was keyed by the original document ID -- Qdrant
+// vector IDs, Neo4j graph nodes -- is not touched by this tool and must be
+// re-keyed separately.
+func runSagaMigrate(args []string) error {
+	fs := flag.NewFlagSet("saga-migrate", flag.ExitOnError)
+	from := registerStoreFlags(fs, "from")
+	to := registerStoreFlags(fs, "to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *from.driver == "" || *to.driver == "" {
+		return fmt.Errorf("-from and -to are both required")
+	}
+	fromCfg, toCfg := from.config(), to.config()
+	if fromCfg == toCfg {
+		return fmt.Errorf("-from and -to resolve to the same store; refusing to migrate a store into itself")
+	}
+
+	ctx := context.Background()
+	fromDocs, fromSagas, err := factory.New(ctx, fromCfg)
+	if err != nil {
+		return fmt.Errorf("opening -from store: %w", err)
+	}
+	toDocs, toSagas, err := factory.New(ctx, toCfg)
+	if err != nil {
+		return fmt.Errorf("opening -to store: %w", err)
+	}
+
+	docs, err := fromDocs.ListAllDocuments(ctx)
+	if err != nil {
+		return fmt.Errorf("listing documents: %w", err)
+	}
+	docIDMap := make(map[int64]int64, len(docs))
+	for _, doc := range docs {
+		oldID := doc.ID
+		doc.ID = 0
+		newID, err := toDocs.CreateDocument(ctx, doc)
+		if err != nil {
+			return fmt.Errorf("migrating document %d: %w", oldID, err)
+		}
+		docIDMap[oldID] = newID
+	}
+	log.Printf("[saga-migrate] migrated %d documents", len(docs))
+
+	sagas, err := fromSagas.ListAllSagas(ctx)
+	if err != nil {
+		return fmt.Errorf("listing sagas: %w", err)
+	}
+	var sagaCount, stepCount int
+	for _, saga := range sagas {
+		oldSagaID := saga.ID
+		newDocID, ok := docIDMap[saga.DocumentID]
+		if !ok {
+			log.Printf("[saga-migrate] skipping saga %d: its document %d was not migrated", oldSagaID, saga.DocumentID)
+			continue
+		}
+
+		steps, err := fromSagas.GetSagaSteps(ctx, oldSagaID)
+		if err != nil {
+			return fmt.Errorf("listing steps for saga %d: %w", oldSagaID, err)
+		}
+
+		saga.ID = 0
+		saga.DocumentID = newDocID
+		newSagaID, err := toSagas.CreateSaga(ctx, saga)
+		if err != nil {
+			return fmt.Errorf("migrating saga %d: %w", oldSagaID, err)
+		}
+		sagaCount++
+
+		for _, step := range steps {
+			step.ID = 0
+			step.SagaID = newSagaID
+			if _, err := toSagas.UpsertSagaStep(ctx, step); err != nil {
+				return fmt.Errorf("migrating step for saga %d: %w", oldSagaID, err)
+			}
+			stepCount++
+		}
+	}
+	log.Printf("[saga-migrate] migrated %d sagas (%d steps)", sagaCount, stepCount)
+	return nil
+}
@@ -0,0 +1,300 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/booksage/booksage-api/internal/llm"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// tracer is package-scoped so every SelfRAGCritique shares one tracer,
+// matching ingest.SagaOrchestrator's convention.
+var tracer = otel.Tracer("booksage-api/agent")
+
+// SupportLevel is the verdict EvaluateGeneration settles on for how well an
+// answer is grounded in its context, mirroring usecase/query.SupportLevel
+// for this package's plain-prompt critique.
+type SupportLevel string
+
+const (
+	FullySupported SupportLevel = "fully_supported"
+	Partially      SupportLevel = "partially_supported"
+	NoSupport      SupportLevel = "no_support"
+)
+
+// Reflection is a critique verdict with a confidence score attached, so a
+// caller can weight a passage or answer instead of taking a hard boolean --
+// mirroring usecase/query.Reflection for this package's SSE-streaming
+// critique. Relevant and Support only ever mean something in the pass they
+// came from: ReflectRetrieval populates Relevant, ReflectGeneration
+// populates Support, and the other stays zero-valued. Confidence is a flat
+// 0.5 on the plain-word fallback path, where nothing better is available.
+type Reflection struct {
+	Relevant   bool
+	Support    SupportLevel
+	Confidence float64
+}
+
+// StructuredLLMClient is implemented by llm.LLMClient backends that can
+// constrain their output to a JSON schema instead of free text.
+// ReflectRetrieval/ReflectGeneration type-assert for it on whatever client
+// the Router routes to, falling back to a plain-word prompt when a backend
+// doesn't support it -- mirroring fusion.LLMClassifier's capability
+// type-assertion for the same interface shape.
+type StructuredLLMClient interface {
+	GenerateStructured(ctx context.Context, prompt string, schema json.RawMessage) (string, error)
+}
+
+// retrievalReflectionSchema is the JSON Schema for a structured
+// ReflectRetrieval verdict.
+var retrievalReflectionSchema = json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"relevant": {"type": "boolean"},
+		"confidence": {"type": "number"}
+	},
+	"required": ["relevant", "confidence"]
+}`)
+
+// generationReflectionSchema is the JSON Schema for a structured
+// ReflectGeneration verdict.
+var generationReflectionSchema = json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"support": {"type": "string", "enum": ["fully_supported", "partially_supported", "no_support"]},
+		"confidence": {"type": "number"}
+	},
+	"required": ["support", "confidence"]
+}`)
+
+// structuredClientFor unwraps client to the StructuredLLMClient it can
+// issue a schema-constrained call against, if any. RouteLLMTask returns an
+// *llm.LLMClientChain rather than a bare LLMClient, so the chain's primary
+// candidate -- the only one a structured call can target -- is checked
+// instead of the chain itself, mirroring fusion.LLMClassifier.generate's
+// identical chain-unwrap.
+func structuredClientFor(client llm.LLMClient) (StructuredLLMClient, bool) {
+	target := client
+	if chain, ok := client.(*llm.LLMClientChain); ok {
+		target = chain.Primary()
+	}
+	structured, ok := target.(StructuredLLMClient)
+	return structured, ok
+}
+
+// parseRetrievalReflection unmarshals and validates a structured
+// ReflectRetrieval verdict, rejecting a confidence outside [0, 1].
+func parseRetrievalReflection(raw []byte) (*Reflection, error) {
+	var payload struct {
+		Relevant   bool    `json:"relevant"`
+		Confidence float64 `json:"confidence"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("invalid retrieval reflection JSON: %w", err)
+	}
+	if payload.Confidence < 0 || payload.Confidence > 1 {
+		return nil, fmt.Errorf("confidence %v out of range [0, 1]", payload.Confidence)
+	}
+	return &Reflection{Relevant: payload.Relevant, Confidence: payload.Confidence}, nil
+}
+
+// parseGenerationReflection unmarshals and validates a structured
+// ReflectGeneration verdict, rejecting a confidence outside [0, 1] or an
+// unrecognized support level.
+func parseGenerationReflection(raw []byte) (*Reflection, error) {
+	var payload struct {
+		Support    SupportLevel `json:"support"`
+		Confidence float64      `json:"confidence"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("invalid generation reflection JSON: %w", err)
+	}
+	switch payload.Support {
+	case FullySupported, Partially, NoSupport:
+	default:
+		return nil, fmt.Errorf("unrecognized support level %q", payload.Support)
+	}
+	if payload.Confidence < 0 || payload.Confidence > 1 {
+		return nil, fmt.Errorf("confidence %v out of range [0, 1]", payload.Confidence)
+	}
+	return &Reflection{Support: payload.Support, Confidence: payload.Confidence}, nil
+}
+
+// SelfRAGCritique evaluates retrieval relevance and generation grounding
+// with an LLM prompt, streaming its verdicts straight into the SSE event
+// channel rather than persisting them for offline replay. It's this
+// package's lighter-weight sibling to usecase/query.SelfRAGCritique.
+type SelfRAGCritique struct {
+	router *llm.Router
+}
+
+// NewSelfRAGCritique creates a new critique component backed by router's
+// cheapest task-appropriate candidate for each verdict.
+func NewSelfRAGCritique(router *llm.Router) *SelfRAGCritique {
+	return &SelfRAGCritique{router: router}
+}
+
+// EvaluateRetrieval checks if a retrieved chunk is relevant to the query.
+// It's a thin wrapper over ReflectRetrieval for callers that only need the
+// boolean verdict; use ReflectRetrieval directly to see the confidence
+// behind it.
+func (c *SelfRAGCritique) EvaluateRetrieval(ctx context.Context, query, contextText string) bool {
+	return c.ReflectRetrieval(ctx, query, contextText).Relevant
+}
+
+// ReflectRetrieval asks the LLM whether contextText is relevant to query and
+// returns the full Reflection: relevance plus a confidence score. It
+// prefers a structured, schema-constrained verdict via StructuredLLMClient
+// and falls back to parsing a plain "relevant"/"irrelevant" word for
+// clients that don't support JSON mode, in which case Confidence is a flat
+// 0.5. A failed or unreachable critique model fails open (Relevant: true)
+// rather than dropping content that might still be useful.
+func (c *SelfRAGCritique) ReflectRetrieval(ctx context.Context, query, contextText string) (reflection *Reflection) {
+	ctx, span := tracer.Start(ctx, "critique.reflect_retrieval")
+	start := time.Now()
+	reflection = &Reflection{Relevant: true, Confidence: 0.5}
+	defer func() {
+		span.SetAttributes(
+			attribute.Float64("critique.latency_ms", float64(time.Since(start).Microseconds())/1000),
+			attribute.Bool("critique.relevant", reflection.Relevant),
+			attribute.Float64("critique.confidence", reflection.Confidence),
+		)
+		span.End()
+	}()
+
+	if c == nil || c.router == nil {
+		return reflection
+	}
+	client := c.router.RouteLLMTask(ctx, llm.TaskSimpleKeywordExtraction)
+	if client == nil {
+		return reflection
+	}
+	span.SetAttributes(attribute.String("critique.model", client.Name()))
+
+	if structured, ok := structuredClientFor(client); ok {
+		prompt := fmt.Sprintf(`Evaluate if the following context is relevant to the user query.
+
+Query: %s
+Context: %s`, query, contextText)
+
+		raw, err := structured.GenerateStructured(ctx, prompt, retrievalReflectionSchema)
+		if err == nil {
+			span.SetAttributes(attribute.Int("critique.response_tokens", len(strings.Fields(raw))))
+			if parsed, verr := parseRetrievalReflection([]byte(raw)); verr == nil {
+				reflection = parsed
+				return reflection
+			} else {
+				log.Printf("[Self-RAG] Retrieval reflection schema validation failed: %v", verr)
+			}
+		} else {
+			log.Printf("[Self-RAG] Structured retrieval critique failed, falling back to plain-word: %v", err)
+		}
+	}
+
+	prompt := fmt.Sprintf(`Evaluate if the following context is relevant to the user query.
+Respond ONLY with "relevant" or "irrelevant".
+
+Query: %s
+Context: %s`, query, contextText)
+
+	resp, err := client.Generate(ctx, prompt)
+	if err != nil {
+		return reflection
+	}
+	span.SetAttributes(attribute.Int("critique.response_tokens", len(strings.Fields(resp))))
+
+	lower := strings.ToLower(resp)
+	reflection = &Reflection{
+		Relevant:   strings.Contains(lower, "relevant") && !strings.Contains(lower, "irrelevant"),
+		Confidence: 0.5,
+	}
+	return reflection
+}
+
+// EvaluateGeneration checks if an answer is supported by the context. It's
+// a thin wrapper over ReflectGeneration for callers that only need the
+// SupportLevel; use ReflectGeneration directly to see the confidence
+// behind it.
+func (c *SelfRAGCritique) EvaluateGeneration(ctx context.Context, answer, contextText string) SupportLevel {
+	return c.ReflectGeneration(ctx, answer, contextText).Support
+}
+
+// ReflectGeneration asks the LLM how well answer is grounded in contextText
+// and returns the full Reflection: the SupportLevel plus a confidence
+// score. It prefers a structured, schema-constrained verdict via
+// StructuredLLMClient and falls back to parsing a plain support-level word
+// for clients that don't support JSON mode, in which case Confidence is a
+// flat 0.5. A failed or unreachable critique model fails open
+// (FullySupported) rather than forcing an endless regeneration loop on a
+// critique outage.
+func (c *SelfRAGCritique) ReflectGeneration(ctx context.Context, answer, contextText string) (reflection *Reflection) {
+	ctx, span := tracer.Start(ctx, "critique.reflect_generation")
+	start := time.Now()
+	reflection = &Reflection{Support: FullySupported, Confidence: 0.5}
+	defer func() {
+		span.SetAttributes(
+			attribute.Float64("critique.latency_ms", float64(time.Since(start).Microseconds())/1000),
+			attribute.String("critique.verdict", string(reflection.Support)),
+			attribute.Float64("critique.confidence", reflection.Confidence),
+		)
+		span.End()
+	}()
+
+	if c == nil || c.router == nil {
+		return reflection
+	}
+	client := c.router.RouteLLMTask(ctx, llm.TaskSimpleKeywordExtraction)
+	if client == nil {
+		return reflection
+	}
+	span.SetAttributes(attribute.String("critique.model", client.Name()))
+
+	if structured, ok := structuredClientFor(client); ok {
+		prompt := fmt.Sprintf(`Evaluate how well the following answer is supported by the given context.
+
+Context: %s
+Answer: %s`, contextText, answer)
+
+		raw, err := structured.GenerateStructured(ctx, prompt, generationReflectionSchema)
+		if err == nil {
+			span.SetAttributes(attribute.Int("critique.response_tokens", len(strings.Fields(raw))))
+			if parsed, verr := parseGenerationReflection([]byte(raw)); verr == nil {
+				reflection = parsed
+				return reflection
+			} else {
+				log.Printf("[Self-RAG] Generation reflection schema validation failed: %v", verr)
+			}
+		} else {
+			log.Printf("[Self-RAG] Structured generation critique failed, falling back to plain-word: %v", err)
+		}
+	}
+
+	prompt := fmt.Sprintf(`Evaluate how well the following answer is supported by the given context.
+Respond ONLY with one of: "fully_supported", "partially_supported", "no_support".
+
+Context: %s
+Answer: %s`, contextText, answer)
+
+	resp, err := client.Generate(ctx, prompt)
+	if err != nil {
+		return reflection
+	}
+	span.SetAttributes(attribute.Int("critique.response_tokens", len(strings.Fields(resp))))
+
+	lower := strings.ToLower(resp)
+	switch {
+	case strings.Contains(lower, "no_support"):
+		reflection = &Reflection{Support: NoSupport, Confidence: 0.5}
+	case strings.Contains(lower, "partially_supported"):
+		reflection = &Reflection{Support: Partially, Confidence: 0.5}
+	default:
+		reflection = &Reflection{Support: FullySupported, Confidence: 0.5}
+	}
+	return reflection
+}
@@ -0,0 +1,86 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/booksage/booksage-api/internal/llm"
+	"github.com/hashicorp/go-hclog"
+)
+
+// structuredMockClient is a mockClient that also implements
+// StructuredLLMClient, returning a canned JSON payload so tests can
+// exercise the schema-constrained path in ReflectRetrieval/ReflectGeneration.
+type structuredMockClient struct {
+	mockClient
+	structuredResp string
+	structuredErr  error
+}
+
+func (s *structuredMockClient) GenerateStructured(ctx context.Context, prompt string, schema json.RawMessage) (string, error) {
+	return s.structuredResp, s.structuredErr
+}
+
+// newTestRouter builds an *llm.Router routing every task to client, so
+// tests don't need a distinct local/Gemini pair to exercise critique's
+// TaskSimpleKeywordExtraction routing.
+func newTestRouter(client *structuredMockClient) *llm.Router {
+	return llm.NewRouter(client, client, hclog.NewNullLogger())
+}
+
+func TestReflectRetrieval_StructuredPath(t *testing.T) {
+	client := &structuredMockClient{
+		mockClient:     mockClient{name: "structured-stub"},
+		structuredResp: `{"relevant": true, "confidence": 0.82}`,
+	}
+	critique := NewSelfRAGCritique(newTestRouter(client))
+
+	reflection := critique.ReflectRetrieval(context.Background(), "query", "context")
+
+	if !reflection.Relevant || reflection.Confidence != 0.82 {
+		t.Errorf("expected a structured reflection, got %+v", reflection)
+	}
+}
+
+func TestReflectRetrieval_InvalidConfidenceFallsBackToPlainWord(t *testing.T) {
+	client := &structuredMockClient{
+		mockClient:     mockClient{name: "structured-stub", resp: "relevant"},
+		structuredResp: `{"relevant": true, "confidence": 4.2}`,
+	}
+	critique := NewSelfRAGCritique(newTestRouter(client))
+
+	reflection := critique.ReflectRetrieval(context.Background(), "query", "context")
+
+	if !reflection.Relevant || reflection.Confidence != 0.5 {
+		t.Errorf("expected the plain-word fallback after schema validation failure, got %+v", reflection)
+	}
+}
+
+func TestReflectGeneration_StructuredPath(t *testing.T) {
+	client := &structuredMockClient{
+		mockClient:     mockClient{name: "structured-stub"},
+		structuredResp: `{"support": "partially_supported", "confidence": 0.55}`,
+	}
+	critique := NewSelfRAGCritique(newTestRouter(client))
+
+	reflection := critique.ReflectGeneration(context.Background(), "answer", "context")
+
+	if reflection.Support != Partially || reflection.Confidence != 0.55 {
+		t.Errorf("expected a structured partially-supported reflection, got %+v", reflection)
+	}
+}
+
+func TestReflectGeneration_UnrecognizedSupportFallsBackToPlainWord(t *testing.T) {
+	client := &structuredMockClient{
+		mockClient:     mockClient{name: "structured-stub", resp: "no_support"},
+		structuredResp: `{"support": "mostly_fine", "confidence": 0.9}`,
+	}
+	critique := NewSelfRAGCritique(newTestRouter(client))
+
+	reflection := critique.ReflectGeneration(context.Background(), "answer", "context")
+
+	if reflection.Support != NoSupport || reflection.Confidence != 0.5 {
+		t.Errorf("expected the plain-word fallback after an unrecognized support level, got %+v", reflection)
+	}
+}
@@ -2,151 +2,701 @@ package agent
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"log"
 	"strings"
 
 	"github.com/booksage/booksage-api/internal/fusion"
 	"github.com/booksage/booksage-api/internal/llm"
+	"github.com/hashicorp/go-hclog"
 )
 
-// Generator is responsible for the Agentic RAG Generation loop (CoR, Self-RAG).
+// WebSearchClient performs a live web search. It's the Corrective RAG (CRAG)
+// fallback for when a sub-query's internal retrieval comes back empty or
+// entirely filtered out by Self-RAG, mirroring how FusionRetriever already
+// ensembles across vector/graph engines but reaching outside the library.
+type WebSearchClient interface {
+	Search(ctx context.Context, query string) ([]fusion.SearchResult, error)
+}
+
+// Token is one incremental chunk of generated text emitted by a
+// StreamingLLMClient. It's a straight alias for llm.Token rather than its
+// own struct so that any llm.LLMClient backend's GenerateStream method
+// satisfies StreamingLLMClient without llm needing to import agent.
+type Token = llm.Token
+
+// StreamingLLMClient is implemented by llm.LLMClient backends that can
+// emit tokens incrementally instead of returning the full response in one
+// shot. It's a straight alias for llm.StreamingLLMClient, which streamGenerate
+// type-asserts for directly when client isn't a chain; when it is a chain,
+// llm.LLMClientChain.GenerateStream already does this same check (and the
+// primary-then-rest fallback) itself.
+type StreamingLLMClient = llm.StreamingLLMClient
+
+// Tool is an action the ReAct loop can invoke mid-generation to fetch
+// information the retrieved context doesn't cover, e.g. a calculator or a
+// live lookup.
+type Tool interface {
+	Name() string
+	Description() string
+	Execute(ctx context.Context, input string) (string, error)
+}
+
+// Retriever is implemented by *fusion.FusionRetriever. Generator depends on
+// this interface rather than the concrete type so tests can substitute a
+// scripted retriever without standing up qdrant/neo4j.
+type Retriever interface {
+	Retrieve(ctx context.Context, query string) ([]fusion.SearchResult, error)
+}
+
+// StreamingRetriever is implemented by retrievers (currently
+// *fusion.FusionRetriever) that can report per-engine results as they
+// arrive instead of only once the whole fusion round finishes. GenerateAnswer
+// uses it when available so the SSE stream reflects vector/graph/tree hits
+// as each engine completes rather than waiting on the slowest one; a
+// retriever that only implements Retriever works exactly as before.
+type StreamingRetriever interface {
+	Retriever
+	RetrieveStream(ctx context.Context, query string) (<-chan fusion.FusionEvent, error)
+}
+
+// maxReActIterations bounds how many Thought/Action/Observation rounds the
+// ReAct loop runs before giving up on a Final Answer and generating a
+// plain answer from whatever it has learned.
+const maxReActIterations = 5
+
+// Generator is responsible for the Agentic RAG Generation loop (CoR, Self-RAG, CRAG).
 type Generator struct {
 	router    *llm.Router
-	retriever *fusion.FusionRetriever
+	retriever Retriever
 	critique  *SelfRAGCritique
+	webSearch WebSearchClient
+	tools     []Tool
+	logger    hclog.Logger
+
+	// maxReflectionIterations bounds GenerateWithReflection's critique loop.
+	// Zero (the default) means defaultMaxReflectionIterations.
+	maxReflectionIterations int
 }
 
-// NewGenerator initializes the Agentic Generator with the necessary routing logic.
-func NewGenerator(router *llm.Router, retriever *fusion.FusionRetriever) *Generator {
+// NewGenerator initializes the Agentic Generator with the necessary routing
+// logic. logger is named "generator" and derived from whatever logger the
+// caller injected, rather than reaching for hclog.Default().
+func NewGenerator(router *llm.Router, retriever Retriever, logger hclog.Logger) *Generator {
 	return &Generator{
 		router:    router,
 		retriever: retriever,
 		critique:  NewSelfRAGCritique(router),
+		logger:    logger.Named("generator"),
 	}
 }
 
-// GeneratorEvent represents an event in the generation stream
+// WithWebSearch attaches the CRAG web-search fallback. A Generator built
+// without one still runs CoR/Self-RAG normally, it just has no recourse when
+// a sub-query's retrieval comes back empty.
+func (g *Generator) WithWebSearch(client WebSearchClient) *Generator {
+	g.webSearch = client
+	return g
+}
+
+// WithTools attaches tools the ReAct loop may invoke during answer
+// generation. A Generator built without any still runs the plain
+// CoR/Self-RAG/CRAG pipeline; it just never enters the ReAct loop.
+func (g *Generator) WithTools(tools ...Tool) *Generator {
+	g.tools = append(g.tools, tools...)
+	return g
+}
+
+// WithMaxReflectionIterations overrides GenerateWithReflection's critique
+// loop bound. n <= 0 restores the default.
+func (g *Generator) WithMaxReflectionIterations(n int) *Generator {
+	g.maxReflectionIterations = n
+	return g
+}
+
+// GeneratorEvent represents an event in the generation stream. Seq is a
+// monotonic, per-stream sequence number; the HTTP layer uses it as the SSE
+// event ID so a reconnecting client can say "replay everything after N" via
+// Last-Event-ID.
 type GeneratorEvent struct {
-	Type    string `json:"type"` // "reasoning", "source", "answer", "error"
+	Seq     int64  `json:"seq"`
+	Type    string `json:"type"` // "reasoning", "partial_results", "source", "action", "observation", "answer_token", "answer", "error"
 	Content string `json:"content"`
+
+	// SupportLevel is set on the terminal "answer" event to the Self-RAG
+	// grounding verdict the answer drew, so a caller can surface it
+	// alongside the answer (e.g. a "may be incomplete" badge) without
+	// re-parsing the "reasoning" events' free text. It's the zero value on
+	// every other event type, and on "answer" itself when no critique ran
+	// (no retriever configured, or nothing was retrieved to critique
+	// against).
+	SupportLevel SupportLevel `json:"support_level,omitempty"`
 }
 
 // GenerateAnswer orchestrates the full RAG pipeline:
 // 1. Chain-of-Retrieval (CoR): decompose complex queries into sub-queries
 // 2. Fusion retrieval with intent-driven weights
 // 3. Self-RAG: critique retrieval relevance
+// 3b. Corrective RAG (CRAG): if a sub-query comes back with nothing
+//
+//	relevant, rewrite it and retry, then fall back to web search
+//
 // 4. Context-aware answer generation
 // 5. Self-RAG: critique generation grounding
 // Results are streamed via SSE events through the provided channel.
 func (g *Generator) GenerateAnswer(ctx context.Context, query string, stream chan<- GeneratorEvent) {
 	defer close(stream)
-	log.Printf("[Agent] Starting generation for query: %s", query)
+	g.logger.Info("starting generation", "query", query)
 
-	// Step 1: CoR — Sub-query decomposition
-	stream <- GeneratorEvent{Type: "reasoning", Content: "[CoR] Analyzing query complexity..."}
-	subQueries := g.decomposeQuery(ctx, query)
+	var seq int64
+	emit := func(eventType, content string) {
+		seq++
+		stream <- GeneratorEvent{Seq: seq, Type: eventType, Content: content}
+	}
 
+	// Step 1: CoR — Strategy classification and sub-query decomposition
+	emit("reasoning", "[CoR] Analyzing query complexity...")
+	decision := g.decomposeQuery(ctx, query)
+	subQueries := decision.SubQueries
+
+	if decision.Strategy != StrategyFactual {
+		emit("reasoning", fmt.Sprintf("[CoR] Detected %s query (confidence %.2f)", decision.Strategy, decision.Confidence))
+	}
 	if len(subQueries) > 1 {
-		stream <- GeneratorEvent{Type: "reasoning", Content: fmt.Sprintf("[CoR] Decomposed into %d sub-queries", len(subQueries))}
+		emit("reasoning", fmt.Sprintf("[CoR] Decomposed into %d sub-queries", len(subQueries)))
 	}
 
 	// Step 2: Fusion Retrieval for each sub-query
 	var allContextChunks []string
+	var allResults []fusion.SearchResult
 
 	if g.retriever != nil {
 		for i, sq := range subQueries {
-			stream <- GeneratorEvent{Type: "reasoning", Content: fmt.Sprintf("[Fusion] Searching for sub-query %d/%d: %s", i+1, len(subQueries), truncate(sq, 80))}
+			if ctx.Err() != nil {
+				return
+			}
+
+			emit("reasoning", fmt.Sprintf("[Fusion] Searching for sub-query %d/%d: %s", i+1, len(subQueries), truncate(sq, 80)))
 
-			results, err := g.retriever.Retrieve(ctx, sq)
+			results, err := g.retrieve(ctx, sq, emit)
 			if err != nil {
-				stream <- GeneratorEvent{Type: "reasoning", Content: fmt.Sprintf("[Fusion] Search warning: %v", err)}
-				continue
+				emit("reasoning", fmt.Sprintf("[Fusion] Search warning: %v", err))
+				results = nil
 			}
 
 			// Step 3: Self-RAG — Retrieval Critique
-			for _, r := range results {
-				if g.critique != nil {
-					if !g.critique.EvaluateRetrieval(ctx, sq, r.Content) {
-						stream <- GeneratorEvent{Type: "reasoning", Content: fmt.Sprintf("[Self-RAG] Filtered irrelevant result from %s", r.Source)}
-						continue
-					}
-				}
+			relevant := g.filterRelevant(ctx, sq, results, emit)
 
+			// Step 3b: CRAG — nothing survived critique, so the internal
+			// stores don't have what this sub-query needs. Rewrite it and
+			// retry once before giving up on the stores entirely.
+			if len(relevant) == 0 {
+				relevant = g.correctRetrieval(ctx, sq, emit)
+			}
+
+			for _, r := range relevant {
 				allContextChunks = append(allContextChunks, r.Content)
-				stream <- GeneratorEvent{
-					Type:    "source",
-					Content: fmt.Sprintf("[%s] (score: %.2f) %s", r.Source, r.Score, truncate(r.Content, 200)),
-				}
+				allResults = append(allResults, r)
+				emit("source", fmt.Sprintf("[%s] (score: %.2f) %s", r.Source, r.Score, truncate(r.Content, 200)))
 			}
 		}
 
-		stream <- GeneratorEvent{Type: "reasoning", Content: fmt.Sprintf("[Agent] %d relevant context chunks after Self-RAG filtering.", len(allContextChunks))}
+		emit("reasoning", fmt.Sprintf("[Agent] %d relevant context chunks after Self-RAG filtering.", len(allContextChunks)))
 	} else {
-		stream <- GeneratorEvent{Type: "reasoning", Content: "[Agent] No retriever configured. Generating without context."}
+		emit("reasoning", "[Agent] No retriever configured. Generating without context.")
 	}
 
-	// Step 4: Context-aware Generation
-	stream <- GeneratorEvent{Type: "reasoning", Content: "[Agent] Generating answer..."}
-	geminiClient := g.router.RouteLLMTask(llm.TaskAgenticReasoning)
+	if ctx.Err() != nil {
+		return
+	}
+
+	// Step 4: Context-aware Generation, optionally via the ReAct loop when
+	// tools are configured.
+	emit("reasoning", "[Agent] Generating answer...")
+	prompt := buildCitedRAGPrompt(query, allResults)
+	geminiClient := g.router.RouteLLMTaskWithContext(ctx, llm.TaskAgenticReasoning, llm.RoutingHint{
+		PromptTokens: estimateTokens(prompt),
+	})
 
-	prompt := buildRAGPrompt(query, allContextChunks)
-	answer, err := geminiClient.Generate(ctx, prompt)
+	var answer string
+	var err error
+	if len(g.tools) > 0 {
+		answer, err = g.runReAct(ctx, geminiClient, query, allContextChunks, emit)
+	} else {
+		answer, err = g.streamGenerate(ctx, geminiClient, prompt, emit)
+	}
 	if err != nil {
-		stream <- GeneratorEvent{Type: "error", Content: fmt.Sprintf("generation failed: %v", err)}
+		emit("error", fmt.Sprintf("generation failed: %v", err))
 		return
 	}
 
 	// Step 5: Self-RAG — Generation Critique
+	var support SupportLevel
 	if g.critique != nil && len(allContextChunks) > 0 {
 		contextJoined := strings.Join(allContextChunks, "\n\n")
-		support := g.critique.EvaluateGeneration(ctx, answer, contextJoined)
-		stream <- GeneratorEvent{Type: "reasoning", Content: fmt.Sprintf("[Self-RAG] Support level: %s", support)}
+		support = g.critique.EvaluateGeneration(ctx, answer, contextJoined)
+		emit("reasoning", fmt.Sprintf("[Self-RAG] Support level: %s", support))
 
 		if support == NoSupport {
-			stream <- GeneratorEvent{Type: "reasoning", Content: "[Self-RAG] Answer not supported by context. Regenerating..."}
+			emit("reasoning", "[Self-RAG] Answer not supported by context. Regenerating...")
 
-			answer, err = geminiClient.Generate(ctx, prompt+"\n\nIMPORTANT: Base your answer STRICTLY on the provided context.")
+			answer, err = g.streamGenerate(ctx, geminiClient, prompt+"\n\nIMPORTANT: Base your answer STRICTLY on the provided context.", emit)
 			if err != nil {
-				stream <- GeneratorEvent{Type: "error", Content: fmt.Sprintf("regeneration failed: %v", err)}
+				emit("error", fmt.Sprintf("regeneration failed: %v", err))
 				return
 			}
+			support = g.critique.EvaluateGeneration(ctx, answer, contextJoined)
 		}
 	}
 
-	stream <- GeneratorEvent{Type: "answer", Content: answer}
-	log.Printf("[Agent] Generation complete.")
+	seq++
+	stream <- GeneratorEvent{Seq: seq, Type: "answer", Content: answer, SupportLevel: support}
+	g.logger.Info("generation complete")
 }
 
-// decomposeQuery uses an LLM to break complex queries into sub-queries (CoR).
-// Falls back to the original query if decomposition fails or isn't needed.
-func (g *Generator) decomposeQuery(ctx context.Context, query string) []string {
-	client := g.router.RouteLLMTask(llm.TaskSimpleKeywordExtraction)
+// retrieve runs one fusion retrieval for query, emitting a "reasoning"
+// event per engine's partial results as they arrive when g.retriever
+// implements StreamingRetriever, then returns the same final ranked
+// results a plain Retrieve call would. Against a retriever that doesn't
+// support streaming, it's exactly g.retriever.Retrieve.
+func (g *Generator) retrieve(ctx context.Context, query string, emit func(string, string)) ([]fusion.SearchResult, error) {
+	streaming, ok := g.retriever.(StreamingRetriever)
+	if !ok {
+		return g.retriever.Retrieve(ctx, query)
+	}
 
-	prompt := fmt.Sprintf(`Analyze this question. If it contains multiple distinct information needs, decompose it into 2-3 simpler sub-questions. If it's already simple, return it as-is.
+	events, err := streaming.RetrieveStream(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	var ranked []fusion.SearchResult
+	for event := range events {
+		switch event.Type {
+		case "partial_results":
+			if len(event.Results) > 0 {
+				emit("partial_results", partialResultsJSON(event.Source, event.Results))
+			}
+		case "ranked":
+			ranked = event.Results
+		}
+	}
+	return ranked, nil
+}
 
-Return ONLY the questions, one per line. No numbering, no explanations.
+// partialResultsJSON encodes one engine's just-arrived hits as the SSE
+// "partial_results" event's content: enough for a client to render the
+// engine's results immediately, without waiting on Self-RAG filtering or
+// final ranking.
+func partialResultsJSON(source string, results []fusion.SearchResult) string {
+	type hit struct {
+		Content string  `json:"content"`
+		Score   float32 `json:"score"`
+	}
+	payload := struct {
+		Source string `json:"source"`
+		Hits   []hit  `json:"hits"`
+	}{Source: source}
+	for _, r := range results {
+		payload.Hits = append(payload.Hits, hit{Content: truncate(r.Content, 200), Score: r.Score})
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Sprintf(`{"source":%q,"hits":[]}`, source)
+	}
+	return string(data)
+}
+
+// filterRelevant applies Self-RAG retrieval critique to results, emitting a
+// reasoning event for each one it drops so the stream shows why.
+func (g *Generator) filterRelevant(ctx context.Context, query string, results []fusion.SearchResult, emit func(string, string)) []fusion.SearchResult {
+	if g.critique == nil {
+		return results
+	}
+
+	var relevant []fusion.SearchResult
+	for _, r := range results {
+		if !g.critique.EvaluateRetrieval(ctx, query, r.Content) {
+			emit("reasoning", fmt.Sprintf("[Self-RAG] Filtered irrelevant result from %s", r.Source))
+			continue
+		}
+		relevant = append(relevant, r)
+	}
+	return relevant
+}
+
+// correctRetrieval is the CRAG fallback for a sub-query whose retrieval came
+// back empty: it rewrites the query with an LLM and retries the fusion
+// retriever once, then falls back to a live web search if that still turns
+// up nothing and a WebSearchClient is configured.
+func (g *Generator) correctRetrieval(ctx context.Context, query string, emit func(string, string)) []fusion.SearchResult {
+	emit("reasoning", "[CRAG] No relevant results from internal stores. Rewriting query...")
+
+	rewritten := g.rewriteQuery(ctx, query)
+	if rewritten != "" && rewritten != query {
+		emit("reasoning", fmt.Sprintf("[CRAG] Retrying with rewritten query: %s", truncate(rewritten, 80)))
+
+		results, err := g.retrieve(ctx, rewritten, emit)
+		if err != nil {
+			emit("reasoning", fmt.Sprintf("[CRAG] Rewritten search warning: %v", err))
+		} else if relevant := g.filterRelevant(ctx, rewritten, results, emit); len(relevant) > 0 {
+			return relevant
+		}
+	}
+
+	if g.webSearch == nil {
+		emit("reasoning", "[CRAG] No web search configured; continuing without this sub-query's context.")
+		return nil
+	}
+
+	emit("reasoning", "[CRAG] Falling back to web search...")
+	webResults, err := g.webSearch.Search(ctx, query)
+	if err != nil {
+		emit("reasoning", fmt.Sprintf("[CRAG] Web search failed: %v", err))
+		return nil
+	}
+	return webResults
+}
+
+// rewriteQuery asks an LLM to restate query so it's more likely to match
+// content in the corpus, e.g. expanding jargon or dropping conversational
+// filler. Falls back to the original query if rewriting fails.
+func (g *Generator) rewriteQuery(ctx context.Context, query string) string {
+	client := g.router.RouteLLMTask(ctx, llm.TaskSimpleKeywordExtraction)
+
+	prompt := fmt.Sprintf(`The following search query returned no relevant results from a book library's search index. Rewrite it to be more likely to match indexed content: expand abbreviations, remove conversational phrasing, and prefer concrete terms.
+
+Return ONLY the rewritten query, nothing else.
+
+Query: %s`, query)
+
+	resp, err := client.Generate(ctx, prompt)
+	if err != nil {
+		g.logger.Warn("query rewrite failed, keeping original query", "error", err)
+		return query
+	}
+
+	rewritten := strings.TrimSpace(resp)
+	if rewritten == "" {
+		return query
+	}
+	return rewritten
+}
+
+// QueryStrategy classifies what kind of retrieval a query calls for, so
+// GenerateAnswer can dispatch on more than a flat list of sub-queries.
+type QueryStrategy string
+
+const (
+	// StrategyFactual is answerable from a single passage; decomposition
+	// is unnecessary and SubQueries is just the original query.
+	StrategyFactual QueryStrategy = "factual"
+	// StrategyMultiHop needs chaining facts across more than one passage
+	// (e.g. "what did the character introduced in chapter 2 do at the end?").
+	StrategyMultiHop QueryStrategy = "multi_hop"
+	// StrategyComparative compares two or more things side by side
+	// (e.g. "compare chapter 3 and chapter 7", "X vs Y").
+	StrategyComparative QueryStrategy = "comparative"
+	// StrategyTemporal depends on ordering or timing (e.g. "what happens
+	// after X", "before the war").
+	StrategyTemporal QueryStrategy = "temporal"
+)
+
+// StrategyDecision is decomposeQuery's structured result: which strategy the
+// query calls for, how confident the classifier was, the sub-queries to
+// retrieve separately (every strategy but StrategyFactual typically wants
+// more than one), and the named entities the query is about.
+type StrategyDecision struct {
+	Strategy   QueryStrategy
+	Confidence float64
+	SubQueries []string
+	Entities   []string
+}
+
+// rawStrategyDecision is the JSON shape decomposeQuery's prompt asks the LLM
+// to fill in -- a plain struct so encoding/json can decode straight into it,
+// kept separate from StrategyDecision so a malformed "strategy" string
+// doesn't silently become a valid QueryStrategy value.
+type rawStrategyDecision struct {
+	Strategy   string   `json:"strategy"`
+	Confidence float64  `json:"confidence"`
+	SubQueries []string `json:"sub_queries"`
+	Entities   []string `json:"entities"`
+}
+
+// decomposeQuery classifies query's retrieval strategy and, for anything
+// beyond StrategyFactual, breaks it into sub-queries to retrieve separately
+// (CoR). It asks the LLM for a JSON object and parses it with encoding/json;
+// if the call fails or the response doesn't parse as JSON, it falls back to
+// classifyQueryHeuristically rather than defaulting to StrategyFactual on
+// every garbled response the way a bare free-text parse would.
+func (g *Generator) decomposeQuery(ctx context.Context, query string) StrategyDecision {
+	client := g.router.RouteLLMTask(ctx, llm.TaskSimpleKeywordExtraction)
+
+	prompt := fmt.Sprintf(`Classify this question's retrieval strategy and, if it needs more than one piece of information, break it into simpler sub-questions.
+
+Respond with ONLY a JSON object of this exact shape, no other text:
+{"strategy": "factual|multi_hop|comparative|temporal", "confidence": 0.0-1.0, "sub_queries": ["..."], "entities": ["..."]}
+
+- factual: answerable from a single passage.
+- multi_hop: needs chaining facts found across more than one passage.
+- comparative: compares two or more things against each other (e.g. "X vs Y").
+- temporal: depends on the ordering or timing of events (e.g. "what happens after X").
+
+sub_queries should list the simpler sub-questions to retrieve separately, or just the question itself if it doesn't need decomposing. entities should list the named people, places, or things the question is about.
 
 Question: %s`, query)
 
 	resp, err := client.Generate(ctx, prompt)
 	if err != nil {
-		log.Printf("[CoR] Decomposition failed: %v (using original query)", err)
-		return []string{query}
+		g.logger.Warn("strategy classification failed, falling back to heuristics", "error", err)
+		return classifyQueryHeuristically(query)
+	}
+
+	decision, ok := parseStrategyDecision(resp)
+	if !ok {
+		g.logger.Warn("strategy classification returned unparseable response, falling back to heuristics", "response", truncate(resp, 200))
+		return classifyQueryHeuristically(query)
+	}
+	if len(decision.SubQueries) == 0 {
+		decision.SubQueries = []string{query}
+	}
+	return decision
+}
+
+// parseStrategyDecision decodes resp as a rawStrategyDecision JSON object,
+// tolerating surrounding prose or a code fence by extracting the outermost
+// {...} span first (mirrors fusion.parseLLMIntentScores). An unrecognized
+// "strategy" value, not just a JSON syntax error, also counts as failure --
+// decomposeQuery's caller should fall back to heuristics rather than build a
+// StrategyDecision around a strategy classifyQueryHeuristically doesn't know.
+func parseStrategyDecision(resp string) (StrategyDecision, bool) {
+	start := strings.Index(resp, "{")
+	end := strings.LastIndex(resp, "}")
+	if start == -1 || end == -1 || end < start {
+		return StrategyDecision{}, false
+	}
+
+	var raw rawStrategyDecision
+	if err := json.Unmarshal([]byte(resp[start:end+1]), &raw); err != nil {
+		return StrategyDecision{}, false
+	}
+
+	strategy := QueryStrategy(raw.Strategy)
+	switch strategy {
+	case StrategyFactual, StrategyMultiHop, StrategyComparative, StrategyTemporal:
+	default:
+		return StrategyDecision{}, false
+	}
+
+	return StrategyDecision{
+		Strategy:   strategy,
+		Confidence: raw.Confidence,
+		SubQueries: raw.SubQueries,
+		Entities:   raw.Entities,
+	}, true
+}
+
+// comparativeKeywords and temporalKeywords are the cues
+// classifyQueryHeuristically looks for, checked in this order since a
+// comparison can itself be phrased temporally ("before" is also a
+// comparative cue in some questions) and comparative is the more specific
+// match of the two.
+var (
+	comparativeKeywords = []string{"compare", "comparison", " vs ", " vs. ", " versus ", "difference between"}
+	temporalKeywords    = []string{"after", "before", "during", "when did", "timeline", "sequence of events"}
+	multiHopKeywords    = []string{"why", "how did", "what led", "relationship between"}
+)
+
+// classifyQueryHeuristically is decomposeQuery's fallback when the LLM call
+// fails or its response doesn't parse: a plain keyword scan rather than
+// another model call, so a classification is always available even when
+// the LLM backend itself is the thing that's down. It returns low
+// confidence since a keyword match is a much weaker signal than the LLM's
+// own judgment, and never decomposes the query (SubQueries is just the
+// query itself) since the heuristic has no way to generate sub-questions.
+func classifyQueryHeuristically(query string) StrategyDecision {
+	lower := strings.ToLower(query)
+
+	strategy := StrategyFactual
+	switch {
+	case containsAny(lower, comparativeKeywords):
+		strategy = StrategyComparative
+	case containsAny(lower, temporalKeywords):
+		strategy = StrategyTemporal
+	case containsAny(lower, multiHopKeywords):
+		strategy = StrategyMultiHop
 	}
 
-	var subQueries []string
-	for _, line := range strings.Split(resp, "\n") {
-		trimmed := strings.TrimSpace(line)
-		if trimmed != "" && len(trimmed) > 5 {
-			subQueries = append(subQueries, trimmed)
+	return StrategyDecision{
+		Strategy:   strategy,
+		Confidence: 0.3,
+		SubQueries: []string{query},
+	}
+}
+
+func containsAny(s string, substrings []string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
 		}
 	}
+	return false
+}
 
-	if len(subQueries) == 0 {
-		return []string{query}
+// streamGenerate runs prompt through client, streaming "answer_token"
+// events as text arrives when client supports it, and falling back to a
+// single blocking Generate call otherwise. Either way it returns the fully
+// assembled response text. When client is an *llm.LLMClientChain, the
+// primary-candidate-then-rest-of-chain fallback is delegated straight to
+// LLMClientChain.GenerateStream rather than duplicated here.
+func (g *Generator) streamGenerate(ctx context.Context, client llm.LLMClient, prompt string, emit func(string, string)) (string, error) {
+	var tokens <-chan Token
+	var errs <-chan error
+
+	if chain, ok := client.(*llm.LLMClientChain); ok {
+		tokens, errs = chain.GenerateStream(ctx, prompt)
+	} else if streaming, ok := client.(StreamingLLMClient); ok {
+		tokens, errs = streaming.GenerateStream(ctx, prompt)
+	} else {
+		return client.Generate(ctx, prompt)
 	}
-	return subQueries
+
+	var sb strings.Builder
+	for tok := range tokens {
+		sb.WriteString(tok.Text)
+		emit("answer_token", tok.Text)
+	}
+	if err := <-errs; err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// runReAct drives a ReAct (Reason+Act) loop: the model alternates between
+// narrating a Thought, naming a Tool to invoke with an Action Input, and
+// reading back the Observation, until it emits a Final Answer or
+// maxReActIterations is exhausted. Each turn runs through streamGenerate
+// so the raw reasoning is visible live as it's produced; the accumulated
+// text is then parsed for the Action or Final Answer that drives the next
+// step.
+func (g *Generator) runReAct(ctx context.Context, client llm.LLMClient, query string, contextChunks []string, emit func(string, string)) (string, error) {
+	var scratchpad strings.Builder
+
+	for i := 0; i < maxReActIterations; i++ {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+
+		prompt := buildReActPrompt(query, contextChunks, g.tools, scratchpad.String())
+		resp, err := g.streamGenerate(ctx, client, prompt, emit)
+		if err != nil {
+			return "", err
+		}
+
+		if final, ok := extractFinalAnswer(resp); ok {
+			return final, nil
+		}
+
+		name, input, ok := extractAction(resp)
+		if !ok {
+			// The model didn't follow the ReAct format; treat whatever it
+			// said as the answer rather than looping on a malformed turn.
+			return strings.TrimSpace(resp), nil
+		}
+
+		emit("action", fmt.Sprintf("%s[%s]", name, input))
+		observation := g.invokeTool(ctx, name, input)
+		emit("observation", observation)
+
+		fmt.Fprintf(&scratchpad, "\nThought: %s\nAction: %s\nAction Input: %s\nObservation: %s\n", firstLine(resp), name, input, observation)
+	}
+
+	emit("reasoning", fmt.Sprintf("[ReAct] Exhausted %d iterations without a Final Answer; answering with what's known so far.", maxReActIterations))
+	return g.streamGenerate(ctx, client, buildRAGPrompt(query, contextChunks), emit)
+}
+
+// invokeTool runs the named tool, returning its result as the Observation
+// text, or a descriptive "error: ..." string if the tool is unknown or
+// fails — the ReAct loop treats either the same way, feeding it back to
+// the model as the next Observation rather than aborting the answer.
+func (g *Generator) invokeTool(ctx context.Context, name, input string) string {
+	for _, t := range g.tools {
+		if t.Name() == name {
+			result, err := t.Execute(ctx, input)
+			if err != nil {
+				return fmt.Sprintf("error: %v", err)
+			}
+			return result
+		}
+	}
+	return fmt.Sprintf("error: unknown tool %q", name)
+}
+
+// buildReActPrompt constructs the ReAct-format prompt for one loop turn:
+// the tool catalog, the retrieved context (if any), the question, and the
+// scratchpad of prior Thought/Action/Observation rounds.
+func buildReActPrompt(query string, contextChunks []string, tools []Tool, scratchpad string) string {
+	var sb strings.Builder
+	sb.WriteString("Answer the question using the ReAct format: alternate between a Thought, an Action naming one of the tools below with its Action Input, and an Observation, until you have enough information, then respond with a Final Answer.\n\n")
+	sb.WriteString("Tools:\n")
+	for _, t := range tools {
+		sb.WriteString(fmt.Sprintf("- %s: %s\n", t.Name(), t.Description()))
+	}
+	sb.WriteString("\nUse this exact format:\nThought: <your reasoning>\nAction: <tool name>\nAction Input: <input to the tool>\n...(the Observation will be provided for you)...\nThought: <your reasoning>\nFinal Answer: <the answer to the question>\n\n")
+
+	if len(contextChunks) > 0 {
+		sb.WriteString("=== CONTEXT ===\n")
+		for i, chunk := range contextChunks {
+			sb.WriteString(fmt.Sprintf("[Source %d]\n%s\n\n", i+1, chunk))
+		}
+	}
+
+	sb.WriteString("=== QUESTION ===\n")
+	sb.WriteString(query)
+	sb.WriteString("\n")
+	sb.WriteString(scratchpad)
+	return sb.String()
+}
+
+// extractFinalAnswer reports whether resp contains a "Final Answer:"
+// marker and, if so, the text following it.
+func extractFinalAnswer(resp string) (string, bool) {
+	idx := strings.Index(resp, "Final Answer:")
+	if idx == -1 {
+		return "", false
+	}
+	return strings.TrimSpace(resp[idx+len("Final Answer:"):]), true
+}
+
+// extractAction pulls the tool name and input out of a ReAct turn's
+// "Action: ...\nAction Input: ..." lines, reporting ok=false if either
+// marker is missing or out of order.
+func extractAction(resp string) (name, input string, ok bool) {
+	nameIdx := strings.Index(resp, "Action:")
+	inputIdx := strings.Index(resp, "Action Input:")
+	if nameIdx == -1 || inputIdx == -1 || inputIdx < nameIdx {
+		return "", "", false
+	}
+
+	name = strings.TrimSpace(resp[nameIdx+len("Action:") : inputIdx])
+
+	rest := resp[inputIdx+len("Action Input:"):]
+	if nl := strings.IndexByte(rest, '\n'); nl != -1 {
+		rest = rest[:nl]
+	}
+	input = strings.TrimSpace(rest)
+
+	return name, input, name != ""
+}
+
+// firstLine returns s up to its first newline, trimmed, for recording a
+// turn's Thought in the scratchpad without the Action/Observation lines
+// that follow it.
+func firstLine(s string) string {
+	if idx := strings.IndexByte(s, '\n'); idx != -1 {
+		return strings.TrimSpace(s[:idx])
+	}
+	return strings.TrimSpace(s)
 }
 
 // buildRAGPrompt constructs a prompt with retrieved context for the LLM.
@@ -168,6 +718,31 @@ func buildRAGPrompt(query string, contextChunks []string) string {
 	return sb.String()
 }
 
+// buildCitedRAGPrompt is buildRAGPrompt's counterpart for callers that have
+// the retrieved fusion.SearchResults on hand rather than bare strings: each
+// context passage is labeled with its source engine and chunk ID alongside
+// its Source number, and the model is explicitly instructed to cite the
+// Source numbers it draws on, so an answer's claims can be traced back to
+// the chunk that supports them.
+func buildCitedRAGPrompt(query string, results []fusion.SearchResult) string {
+	if len(results) == 0 {
+		return "Answer the following question to the best of your ability:\n\n" + query
+	}
+
+	var sb strings.Builder
+	sb.WriteString("You are a helpful assistant that answers questions based on the provided context.\n")
+	sb.WriteString("Use ONLY the information in the context to answer. If the context doesn't contain the answer, say so.\n")
+	sb.WriteString("Cite the Source number(s) your answer draws on, e.g. \"(Source 2)\".\n\n")
+	sb.WriteString("=== CONTEXT ===\n")
+	for i, r := range results {
+		sb.WriteString(fmt.Sprintf("[Source %d | %s:%s]\n%s\n\n", i+1, r.Source, r.ID, r.Content))
+	}
+	sb.WriteString("=== QUESTION ===\n")
+	sb.WriteString(query)
+	sb.WriteString("\n\n=== ANSWER ===\n")
+	return sb.String()
+}
+
 // truncate shortens a string to maxLen characters.
 func truncate(s string, maxLen int) string {
 	if len(s) <= maxLen {
@@ -175,3 +750,11 @@ func truncate(s string, maxLen int) string {
 	}
 	return s[:maxLen] + "..."
 }
+
+// estimateTokens approximates a prompt's token count as one token per ~4
+// characters (a common rule of thumb for English text), good enough for
+// RouteLLMTaskWithContext's candidate-fit check without pulling in a real
+// tokenizer.
+func estimateTokens(prompt string) int {
+	return len(prompt) / 4
+}
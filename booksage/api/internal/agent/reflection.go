@@ -0,0 +1,241 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/booksage/booksage-api/internal/fusion"
+	"github.com/booksage/booksage-api/internal/llm"
+)
+
+// defaultMaxReflectionIterations bounds GenerateWithReflection's critique
+// loop when the Generator wasn't built with WithMaxReflectionIterations.
+const defaultMaxReflectionIterations = 3
+
+// maxRetrievalRewrites bounds how many times GenerateWithReflection rewrites
+// a query to chase down relevant context before giving up and generating
+// from whatever it has (possibly nothing).
+const maxRetrievalRewrites = 2
+
+// ReflectionStep records one iteration of GenerateWithReflection's critique
+// loop: the query in effect that round, the grounding verdict the answer
+// drew, and the action taken in response.
+type ReflectionStep struct {
+	Iteration    int          `json:"iteration"`
+	Query        string       `json:"query"`
+	SupportLevel SupportLevel `json:"support_level"`
+	Action       string       `json:"action"` // "accepted", "revised_answer", "widened_retrieval"
+}
+
+// ReflectionTrace is GenerateWithReflection's return value: the final
+// answer plus the critique verdict that produced it at every iteration, so
+// a caller (or a test) can inspect why the loop stopped where it did.
+type ReflectionTrace struct {
+	Steps  []ReflectionStep `json:"steps"`
+	Answer string           `json:"answer"`
+}
+
+// GenerateWithReflection runs an iterative Self-RAG loop, in contrast to
+// GenerateAnswer's single critique-and-regenerate-once pass: retrieve,
+// critique the retrieval's relevance (rewriting the query and retrying up
+// to maxRetrievalRewrites times if nothing relevant comes back), generate
+// an answer, then critique its grounding. A NoSupport verdict widens
+// retrieval with a diversified query and regenerates; a Partially verdict
+// asks the LLM to revise the answer down to only what the context
+// supports; a FullySupported verdict returns immediately. The loop runs at
+// most g.maxReflectionIterations rounds (default defaultMaxReflectionIterations),
+// after which it returns the last answer produced regardless of verdict --
+// a caller that needs a hard-correct guarantee should inspect the returned
+// trace's final SupportLevel rather than assume one.
+func (g *Generator) GenerateWithReflection(ctx context.Context, query string) (*ReflectionTrace, error) {
+	maxIters := g.maxReflectionIterations
+	if maxIters <= 0 {
+		maxIters = defaultMaxReflectionIterations
+	}
+
+	trace := &ReflectionTrace{}
+
+	currentQuery := query
+	var contextChunks []string
+	if g.retriever != nil {
+		contextChunks, currentQuery = g.retrieveWithRewrite(ctx, query)
+	}
+
+	var answer string
+	for iter := 1; iter <= maxIters; iter++ {
+		if err := ctx.Err(); err != nil {
+			return trace, err
+		}
+
+		prompt := buildRAGPrompt(query, contextChunks)
+		client := g.router.RouteLLMTaskWithContext(ctx, llm.TaskAgenticReasoning, llm.RoutingHint{
+			PromptTokens: estimateTokens(prompt),
+		})
+
+		var err error
+		answer, err = client.Generate(ctx, prompt)
+		if err != nil {
+			return trace, fmt.Errorf("generation failed: %w", err)
+		}
+
+		support := FullySupported
+		if g.critique != nil && len(contextChunks) > 0 {
+			support = g.critique.EvaluateGeneration(ctx, answer, strings.Join(contextChunks, "\n\n"))
+		}
+
+		step := ReflectionStep{Iteration: iter, Query: currentQuery, SupportLevel: support}
+
+		switch support {
+		case NoSupport:
+			step.Action = "widened_retrieval"
+			trace.Steps = append(trace.Steps, step)
+
+			if g.retriever == nil || iter == maxIters {
+				break
+			}
+			currentQuery = g.diversifyQuery(ctx, query)
+			additional := g.retrieveRelevant(ctx, currentQuery)
+			for _, r := range additional {
+				contextChunks = append(contextChunks, r.Content)
+			}
+
+		case Partially:
+			step.Action = "revised_answer"
+			trace.Steps = append(trace.Steps, step)
+
+			revised, err := g.reviseAnswer(ctx, answer, contextChunks)
+			if err == nil {
+				answer = revised
+			} else {
+				g.logger.Warn("answer revision failed, keeping unrevised answer", "error", err)
+			}
+
+		default: // FullySupported
+			step.Action = "accepted"
+			trace.Steps = append(trace.Steps, step)
+			trace.Answer = answer
+			return trace, nil
+		}
+	}
+
+	trace.Answer = answer
+	return trace, nil
+}
+
+// retrieveWithRewrite retrieves and Self-RAG-filters results for query,
+// rewriting the query and retrying up to maxRetrievalRewrites times if
+// nothing survives the relevance critique. It returns the relevant chunks
+// found (possibly none) and the query that produced them.
+func (g *Generator) retrieveWithRewrite(ctx context.Context, query string) ([]string, string) {
+	currentQuery := query
+	relevant := g.retrieveRelevant(ctx, currentQuery)
+
+	for attempt := 0; len(relevant) == 0 && attempt < maxRetrievalRewrites; attempt++ {
+		rewritten := g.rewriteQuery(ctx, currentQuery)
+		if rewritten == currentQuery {
+			break
+		}
+		currentQuery = rewritten
+		relevant = g.retrieveRelevant(ctx, currentQuery)
+	}
+
+	chunks := make([]string, 0, len(relevant))
+	for _, r := range relevant {
+		chunks = append(chunks, r.Content)
+	}
+	return chunks, currentQuery
+}
+
+// retrieveRelevant retrieves query and drops anything that fails the
+// Self-RAG retrieval critique, discarding the retriever's own error (the
+// caller treats "nothing came back" and "retrieval failed" the same way: a
+// rewrite or a diversified retry).
+func (g *Generator) retrieveRelevant(ctx context.Context, query string) []fusion.SearchResult {
+	results, err := g.retriever.Retrieve(ctx, query)
+	if err != nil {
+		return nil
+	}
+	return g.filterRelevant(ctx, query, results, func(string, string) {})
+}
+
+// diversifyQuery asks an LLM to approach query from a different angle than
+// a plain rewrite would, for widening retrieval after a NoSupport verdict
+// rather than re-running the same search that already fed the ungrounded
+// answer.
+func (g *Generator) diversifyQuery(ctx context.Context, query string) string {
+	client := g.router.RouteLLMTask(ctx, llm.TaskSimpleKeywordExtraction)
+
+	prompt := fmt.Sprintf(`The retrieved context was not enough to fully support an answer to this question. Propose an alternative phrasing that approaches it from a different angle or emphasizes different keywords, to surface passages a plain rewrite would miss.
+
+Return ONLY the alternative question, nothing else.
+
+Question: %s`, query)
+
+	resp, err := client.Generate(ctx, prompt)
+	if err != nil {
+		g.logger.Warn("query diversification failed, keeping original query", "error", err)
+		return query
+	}
+
+	diversified := strings.TrimSpace(resp)
+	if diversified == "" {
+		return query
+	}
+	return diversified
+}
+
+// reviseAnswer asks an LLM to cut answer down to only what contextChunks
+// supports, for a Partially verdict where outright regeneration would
+// throw away the parts that were already grounded.
+func (g *Generator) reviseAnswer(ctx context.Context, answer string, contextChunks []string) (string, error) {
+	client := g.router.RouteLLMTask(ctx, llm.TaskAgenticReasoning)
+
+	prompt := fmt.Sprintf(`The following answer is only partially supported by the given context. Revise it to keep only the claims the context backs up, citing the supporting source inline, and remove or soften anything the context doesn't back up.
+
+=== CONTEXT ===
+%s
+
+=== ORIGINAL ANSWER ===
+%s
+
+Return ONLY the revised answer.`, strings.Join(contextChunks, "\n\n"), answer)
+
+	return client.Generate(ctx, prompt)
+}
+
+// GenerateAnswerWithReflection runs GenerateWithReflection's iterative
+// Self-RAG loop and reports it over stream in GenerateAnswer's SSE event
+// shape, so the query path can offer the deeper iterative loop as an
+// alternative to GenerateAnswer's single critique-and-regenerate-once pass
+// without the HTTP layer needing to know the two loops return differently.
+// Each completed iteration is emitted as a "reasoning" event before the
+// final "answer" event, rather than streaming answer tokens incrementally,
+// since the reflection loop only has a finished answer to show at the end
+// of each iteration.
+func (g *Generator) GenerateAnswerWithReflection(ctx context.Context, query string, stream chan<- GeneratorEvent) {
+	defer close(stream)
+	g.logger.Info("starting generation with reflection", "query", query)
+
+	var seq int64
+	emit := func(eventType, content string) {
+		seq++
+		stream <- GeneratorEvent{Seq: seq, Type: eventType, Content: content}
+	}
+
+	trace, err := g.GenerateWithReflection(ctx, query)
+	if err != nil {
+		emit("error", fmt.Sprintf("generation failed: %v", err))
+		return
+	}
+
+	var finalSupport SupportLevel
+	for _, step := range trace.Steps {
+		emit("reasoning", fmt.Sprintf("[Self-RAG] iteration %d: support=%s action=%s", step.Iteration, step.SupportLevel, step.Action))
+		finalSupport = step.SupportLevel
+	}
+
+	seq++
+	stream <- GeneratorEvent{Seq: seq, Type: "answer", Content: trace.Answer, SupportLevel: finalSupport}
+	g.logger.Info("generation with reflection complete")
+}
@@ -0,0 +1,179 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/booksage/booksage-api/internal/fusion"
+	"github.com/booksage/booksage-api/internal/llm"
+	"github.com/hashicorp/go-hclog"
+)
+
+// mockRetriever always returns the same canned results regardless of
+// query, standing in for FusionRetriever so tests don't need real
+// qdrant/neo4j backends to get non-empty context into the reflection loop.
+type mockRetriever struct {
+	results []fusion.SearchResult
+}
+
+func (m *mockRetriever) Retrieve(ctx context.Context, query string) ([]fusion.SearchResult, error) {
+	return m.results, nil
+}
+
+// scriptedLocalClient answers every TaskSimpleKeywordExtraction prompt
+// GenerateWithReflection issues (retrieval critique, query rewrite/diversify,
+// generation critique) by sniffing which one it is from the prompt's fixed
+// wording: the generation-critique prompt is the only one that mentions
+// "fully_supported", so that's the one whose answer is scripted per test;
+// everything else gets "relevant" so retrieval critique and any query
+// rewrite pass through untouched.
+type scriptedLocalClient struct {
+	generationVerdict string
+}
+
+func (m *scriptedLocalClient) Generate(ctx context.Context, prompt string) (string, error) {
+	if strings.Contains(prompt, "fully_supported") {
+		return m.generationVerdict, nil
+	}
+	return "relevant", nil
+}
+
+func (m *scriptedLocalClient) Name() string { return "local" }
+
+func newReflectionGenerator(answer, generationVerdict string) *Generator {
+	local := &scriptedLocalClient{generationVerdict: generationVerdict}
+	gemini := &mockClient{name: "gemini", resp: answer}
+	router := llm.NewRouter(local, gemini, hclog.NewNullLogger())
+
+	retriever := &mockRetriever{results: []fusion.SearchResult{
+		{ID: "1", Content: "supporting passage", Score: 0.9, Source: "vector"},
+	}}
+
+	return NewGenerator(router, retriever, hclog.NewNullLogger())
+}
+
+func TestGenerateWithReflection_FullySupportedStopsImmediately(t *testing.T) {
+	gen := newReflectionGenerator("a grounded answer", "fully_supported")
+
+	trace, err := gen.GenerateWithReflection(context.Background(), "test query")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(trace.Steps) != 1 {
+		t.Fatalf("expected 1 step, got %d: %+v", len(trace.Steps), trace.Steps)
+	}
+	if trace.Steps[0].SupportLevel != FullySupported {
+		t.Errorf("expected FullySupported, got %s", trace.Steps[0].SupportLevel)
+	}
+	if trace.Steps[0].Action != "accepted" {
+		t.Errorf("expected accepted action, got %s", trace.Steps[0].Action)
+	}
+	if trace.Answer != "a grounded answer" {
+		t.Errorf("expected trace answer to be the generated answer, got %q", trace.Answer)
+	}
+}
+
+func TestGenerateWithReflection_NoSupportWidensUntilCap(t *testing.T) {
+	gen := newReflectionGenerator("an ungrounded answer", "no_support")
+
+	trace, err := gen.GenerateWithReflection(context.Background(), "test query")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(trace.Steps) != defaultMaxReflectionIterations {
+		t.Fatalf("expected %d steps, got %d: %+v", defaultMaxReflectionIterations, len(trace.Steps), trace.Steps)
+	}
+	for _, step := range trace.Steps {
+		if step.SupportLevel != NoSupport {
+			t.Errorf("expected every step to be NoSupport, got %s", step.SupportLevel)
+		}
+		if step.Action != "widened_retrieval" {
+			t.Errorf("expected widened_retrieval action, got %s", step.Action)
+		}
+	}
+}
+
+func TestGenerateWithReflection_PartiallyRevisesThenAccepts(t *testing.T) {
+	local := &scriptedLocalClient{generationVerdict: "partially_supported"}
+	gemini := &mockClient{name: "gemini", resp: "a partly grounded answer"}
+	router := llm.NewRouter(local, gemini, hclog.NewNullLogger())
+	retriever := &mockRetriever{results: []fusion.SearchResult{
+		{ID: "1", Content: "supporting passage", Score: 0.9, Source: "vector"},
+	}}
+
+	gen := NewGenerator(router, retriever, hclog.NewNullLogger())
+
+	trace, err := gen.GenerateWithReflection(context.Background(), "test query")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(trace.Steps) != defaultMaxReflectionIterations {
+		t.Fatalf("expected the loop to run out the cap since every verdict stays Partially, got %d steps: %+v", len(trace.Steps), trace.Steps)
+	}
+	for _, step := range trace.Steps {
+		if step.SupportLevel != Partially || step.Action != "revised_answer" {
+			t.Errorf("expected every step to be a Partially revision, got %+v", step)
+		}
+	}
+}
+
+func TestGenerateWithReflection_RespectsMaxIterationsOverride(t *testing.T) {
+	gen := newReflectionGenerator("an ungrounded answer", "no_support")
+	gen.WithMaxReflectionIterations(1)
+
+	trace, err := gen.GenerateWithReflection(context.Background(), "test query")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(trace.Steps) != 1 {
+		t.Fatalf("expected 1 step with a 1-iteration cap, got %d", len(trace.Steps))
+	}
+}
+
+func TestGenerateWithReflection_NoRetrieverSkipsCritique(t *testing.T) {
+	local := &scriptedLocalClient{generationVerdict: "no_support"}
+	gemini := &mockClient{name: "gemini", resp: "an answer with no context"}
+	router := llm.NewRouter(local, gemini, hclog.NewNullLogger())
+
+	gen := NewGenerator(router, nil, hclog.NewNullLogger())
+
+	trace, err := gen.GenerateWithReflection(context.Background(), "test query")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(trace.Steps) != 1 {
+		t.Fatalf("expected 1 step since there's no context to critique, got %d: %+v", len(trace.Steps), trace.Steps)
+	}
+	if trace.Steps[0].SupportLevel != FullySupported {
+		t.Errorf("expected FullySupported when there's no context to critique, got %s", trace.Steps[0].SupportLevel)
+	}
+}
+
+func TestGenerateAnswerWithReflection_StreamsStepsThenAnswer(t *testing.T) {
+	gen := newReflectionGenerator("a grounded answer", "fully_supported")
+
+	stream := make(chan GeneratorEvent)
+	go gen.GenerateAnswerWithReflection(context.Background(), "test query", stream)
+
+	var events []GeneratorEvent
+	for ev := range stream {
+		events = append(events, ev)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("expected one reasoning event and one answer event, got %d: %+v", len(events), events)
+	}
+	if events[0].Type != "reasoning" {
+		t.Errorf("expected first event to be reasoning, got %s", events[0].Type)
+	}
+	last := events[len(events)-1]
+	if last.Type != "answer" || last.Content != "a grounded answer" {
+		t.Errorf("expected final answer event with the generated answer, got %+v", last)
+	}
+}
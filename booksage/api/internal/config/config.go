@@ -7,6 +7,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/booksage/booksage-api/internal/database/factory"
 )
 
 // Config holds all environmentally dependent settings for the BookSage API.
@@ -15,6 +17,7 @@ type Config struct {
 	GeminiAPIKey     string
 	OllamaHost       string
 	OllamaModel      string
+	OllamaKeepAlive  time.Duration
 	UseLocalOnlyLLM  bool
 	DefaultTimeout   time.Duration
 	EmbeddingTimeout time.Duration
@@ -29,6 +32,48 @@ type Config struct {
 	Neo4jURI      string
 	Neo4jUser     string
 	Neo4jPassword string
+
+	// Resumable ingest uploads
+	UploadDir             string
+	UploadJanitorInterval time.Duration
+	UploadMaxAge          time.Duration
+
+	// Saga/document persistence backend. SagaStoreDriver selects one of
+	// "sqlite" (default), "postgres", "mongo", or "redis"; only the DSNs
+	// relevant to the selected driver are read.
+	SagaStoreDriver factory.Driver
+	SQLiteDSN       string
+	PostgresDSN     string
+	MongoURI        string
+	MongoDatabase   string
+	RedisAddr       string
+	RedisPassword   string
+	RedisDB         int
+
+	// Learned intent classification (internal/fusion.LearnedIntentClassifier
+	// and RouteOperator)
+	UseLearnedIntentWeights   bool
+	IntentFeedbackJobInterval time.Duration
+
+	// IntentClassifier selects which fusion.Classifier FusionRetriever uses:
+	// "learned" (default, fusion.LearnedIntentClassifier's embedding-centroid
+	// tier with an LLM fallback) or "llm" (fusion.LLMClassifier, every query
+	// routed straight to the LLM).
+	IntentClassifier string
+
+	// OpenTelemetry tracing
+	OTLPEndpoint string
+	OTLPInsecure bool
+
+	// SelfRAGMaxIterations bounds agent.Generator.GenerateWithReflection's
+	// critique loop. 0 leaves the agent package's own default in effect.
+	SelfRAGMaxIterations int
+
+	// embedding.Batcher's token-budget scheduler: EmbedMaxTokensPerBatch caps
+	// a single Embed call's estimated token count, EmbedMaxConcurrentBatches
+	// caps how many such calls run at once.
+	EmbedMaxTokensPerBatch    int
+	EmbedMaxConcurrentBatches int
 }
 
 // Validate ensures that all required configuration is present and valid.
@@ -49,6 +94,7 @@ func Load() *Config {
 		GeminiAPIKey:     getEnv("SAGE_GEMINI_API_KEY", ""),
 		OllamaHost:       getEnv("SAGE_OLLAMA_HOST", "http://localhost:11434"),
 		OllamaModel:      getEnv("SAGE_OLLAMA_MODEL", "llama3"),
+		OllamaKeepAlive:  getEnvDuration("SAGE_OLLAMA_KEEP_ALIVE_SEC", 1800) * time.Second,
 		UseLocalOnlyLLM:  getEnvBool("SAGE_USE_LOCAL_ONLY_LLM", false),
 		DefaultTimeout:   getEnvDuration("SAGE_DEFAULT_TIMEOUT_SEC", 30) * time.Second,
 		EmbeddingTimeout: getEnvDuration("SAGE_EMBEDDING_TIMEOUT_SEC", 5) * time.Second,
@@ -61,6 +107,31 @@ func Load() *Config {
 		Neo4jURI:      getEnv("SAGE_NEO4J_URI", "neo4j://localhost:7687"),
 		Neo4jUser:     getEnv("SAGE_NEO4J_USER", "neo4j"),
 		Neo4jPassword: getEnv("SAGE_NEO4J_PASSWORD", "booksage_dev"),
+
+		UploadDir:             getEnv("SAGE_UPLOAD_DIR", os.TempDir()),
+		UploadJanitorInterval: getEnvDuration("SAGE_UPLOAD_JANITOR_INTERVAL_SEC", 600) * time.Second,
+		UploadMaxAge:          getEnvDuration("SAGE_UPLOAD_MAX_AGE_SEC", 86400) * time.Second,
+
+		SagaStoreDriver: factory.Driver(getEnv("SAGE_SAGA_STORE_DRIVER", string(factory.DriverSQLite))),
+		SQLiteDSN:       getEnv("SAGE_SQLITE_DSN", "booksage.db"),
+		PostgresDSN:     getEnv("SAGE_POSTGRES_DSN", ""),
+		MongoURI:        getEnv("SAGE_MONGO_URI", "mongodb://localhost:27017"),
+		MongoDatabase:   getEnv("SAGE_MONGO_DATABASE", "booksage"),
+		RedisAddr:       getEnv("SAGE_REDIS_ADDR", "localhost:6379"),
+		RedisPassword:   getEnv("SAGE_REDIS_PASSWORD", ""),
+		RedisDB:         getEnvInt("SAGE_REDIS_DB", 0),
+
+		UseLearnedIntentWeights:   getEnvBool("SAGE_USE_LEARNED_INTENT_WEIGHTS", false),
+		IntentFeedbackJobInterval: getEnvDuration("SAGE_INTENT_FEEDBACK_JOB_INTERVAL_SEC", 1800) * time.Second,
+		IntentClassifier:          getEnv("SAGE_INTENT_CLASSIFIER", "learned"),
+
+		OTLPEndpoint: getEnv("SAGE_OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+		OTLPInsecure: getEnvBool("SAGE_OTEL_EXPORTER_OTLP_INSECURE", true),
+
+		SelfRAGMaxIterations: getEnvInt("SAGE_SELF_RAG_MAX_ITERS", 3),
+
+		EmbedMaxTokensPerBatch:    getEnvInt("SAGE_EMBED_MAX_TOKENS_PER_BATCH", 8000),
+		EmbedMaxConcurrentBatches: getEnvInt("SAGE_EMBED_MAX_CONCURRENT_BATCHES", 4),
 	}
 
 	if err := cfg.Validate(); err != nil {
@@ -22,11 +22,8 @@ func TestLoadDefaults(t *testing.T) {
 	if cfg.OllamaHost != "http://localhost:11434" {
 		t.Errorf("expected OllamaHost to be http://localhost:11434, got %v", cfg.OllamaHost)
 	}
-	if cfg.OllamaLLMModel != "llama3" {
-		t.Errorf("expected OllamaLLMModel to be llama3, got %v", cfg.OllamaLLMModel)
-	}
-	if cfg.OllamaEmbedModel != "nomic-embed-text" {
-		t.Errorf("expected OllamaEmbedModel to be nomic-embed-text, got %v", cfg.OllamaEmbedModel)
+	if cfg.OllamaModel != "llama3" {
+		t.Errorf("expected OllamaModel to be llama3, got %v", cfg.OllamaModel)
 	}
 	if cfg.UseLocalOnlyLLM != false {
 		t.Errorf("expected UseLocalOnlyLLM to be false, got %v", cfg.UseLocalOnlyLLM)
@@ -47,8 +44,7 @@ func TestLoadWithEnvironmentVariables(t *testing.T) {
 	_ = os.Setenv("SAGE_WORKER_ADDR", "worker:50051")
 	_ = os.Setenv("SAGE_GEMINI_API_KEY", "test-key")
 	_ = os.Setenv("SAGE_OLLAMA_HOST", "http://ollama:11434")
-	_ = os.Setenv("SAGE_OLLAMA_LLM_MODEL", "llama2")
-	_ = os.Setenv("SAGE_OLLAMA_EMBED_MODEL", "all-minilm")
+	_ = os.Setenv("SAGE_OLLAMA_MODEL", "llama2")
 	_ = os.Setenv("SAGE_USE_LOCAL_ONLY_LLM", "true")
 	_ = os.Setenv("SAGE_DEFAULT_TIMEOUT_SEC", "45")
 	_ = os.Setenv("SAGE_EMBEDDING_TIMEOUT_SEC", "10")
@@ -66,11 +62,8 @@ func TestLoadWithEnvironmentVariables(t *testing.T) {
 	if cfg.OllamaHost != "http://ollama:11434" {
 		t.Errorf("expected OllamaHost to be http://ollama:11434, got %v", cfg.OllamaHost)
 	}
-	if cfg.OllamaLLMModel != "llama2" {
-		t.Errorf("expected OllamaLLMModel to be llama2, got %v", cfg.OllamaLLMModel)
-	}
-	if cfg.OllamaEmbedModel != "all-minilm" {
-		t.Errorf("expected OllamaEmbedModel to be all-minilm, got %v", cfg.OllamaEmbedModel)
+	if cfg.OllamaModel != "llama2" {
+		t.Errorf("expected OllamaModel to be llama2, got %v", cfg.OllamaModel)
 	}
 	if cfg.UseLocalOnlyLLM != true {
 		t.Errorf("expected UseLocalOnlyLLM to be true, got %v", cfg.UseLocalOnlyLLM)
@@ -199,6 +192,63 @@ func TestGetEnvIntInvalid(t *testing.T) {
 	}
 }
 
+func TestLoadSelfRAGMaxIterationsDefault(t *testing.T) {
+	os.Clearenv()
+	_ = os.Setenv("SAGE_GEMINI_API_KEY", "dummy")
+	defer os.Clearenv()
+
+	cfg := Load()
+
+	if cfg.SelfRAGMaxIterations != 3 {
+		t.Errorf("expected SelfRAGMaxIterations to default to 3, got %v", cfg.SelfRAGMaxIterations)
+	}
+}
+
+func TestLoadSelfRAGMaxIterationsOverride(t *testing.T) {
+	os.Clearenv()
+	_ = os.Setenv("SAGE_GEMINI_API_KEY", "dummy")
+	_ = os.Setenv("SAGE_SELF_RAG_MAX_ITERS", "5")
+	defer os.Clearenv()
+
+	cfg := Load()
+
+	if cfg.SelfRAGMaxIterations != 5 {
+		t.Errorf("expected SelfRAGMaxIterations to be 5, got %v", cfg.SelfRAGMaxIterations)
+	}
+}
+
+func TestLoadEmbedBatcherDefaults(t *testing.T) {
+	os.Clearenv()
+	_ = os.Setenv("SAGE_GEMINI_API_KEY", "dummy")
+	defer os.Clearenv()
+
+	cfg := Load()
+
+	if cfg.EmbedMaxTokensPerBatch != 8000 {
+		t.Errorf("expected EmbedMaxTokensPerBatch to default to 8000, got %v", cfg.EmbedMaxTokensPerBatch)
+	}
+	if cfg.EmbedMaxConcurrentBatches != 4 {
+		t.Errorf("expected EmbedMaxConcurrentBatches to default to 4, got %v", cfg.EmbedMaxConcurrentBatches)
+	}
+}
+
+func TestLoadEmbedBatcherOverrides(t *testing.T) {
+	os.Clearenv()
+	_ = os.Setenv("SAGE_GEMINI_API_KEY", "dummy")
+	_ = os.Setenv("SAGE_EMBED_MAX_TOKENS_PER_BATCH", "2000")
+	_ = os.Setenv("SAGE_EMBED_MAX_CONCURRENT_BATCHES", "8")
+	defer os.Clearenv()
+
+	cfg := Load()
+
+	if cfg.EmbedMaxTokensPerBatch != 2000 {
+		t.Errorf("expected EmbedMaxTokensPerBatch to be 2000, got %v", cfg.EmbedMaxTokensPerBatch)
+	}
+	if cfg.EmbedMaxConcurrentBatches != 8 {
+		t.Errorf("expected EmbedMaxConcurrentBatches to be 8, got %v", cfg.EmbedMaxConcurrentBatches)
+	}
+}
+
 func TestValidate_MissingWorkerAddr(t *testing.T) {
 	cfg := &Config{
 		WorkerAddr:   "",
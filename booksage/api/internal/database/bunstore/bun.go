@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/booksage/booksage-api/internal/database"
 	"github.com/booksage/booksage-api/internal/database/models"
@@ -31,6 +32,32 @@ func NewBunStore(db *sql.DB, dialect schema.Dialect) (*BunStore, error) {
 	if _, err := bunDB.NewCreateTable().Model((*models.SagaStep)(nil)).IfNotExists().Exec(ctx); err != nil {
 		return nil, fmt.Errorf("failed to create saga_steps table: %w", err)
 	}
+	if _, err := bunDB.NewCreateTable().Model((*models.UploadSession)(nil)).IfNotExists().Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create upload_sessions table: %w", err)
+	}
+	// upload_sessions predates the completing column; add it for deployments
+	// where CreateTable above was a no-op against an already-existing table.
+	if _, err := bunDB.NewAddColumn().Model((*models.UploadSession)(nil)).
+		ColumnExpr("completing BOOLEAN NOT NULL DEFAULT FALSE").IfNotExists().Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to add upload_sessions.completing column: %w", err)
+	}
+	// saga_steps predates the compensation-tracking columns; add them for
+	// deployments where CreateTable above was a no-op against an
+	// already-existing table.
+	if _, err := bunDB.NewAddColumn().Model((*models.SagaStep)(nil)).
+		ColumnExpr("compensation_status BIGINT").IfNotExists().Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to add saga_steps.compensation_status column: %w", err)
+	}
+	if _, err := bunDB.NewAddColumn().Model((*models.SagaStep)(nil)).
+		ColumnExpr("compensation_error VARCHAR").IfNotExists().Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to add saga_steps.compensation_error column: %w", err)
+	}
+	if _, err := bunDB.NewCreateTable().Model((*models.CritiqueEvent)(nil)).IfNotExists().Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create critique_events table: %w", err)
+	}
+	if _, err := bunDB.NewCreateTable().Model((*models.IntentFeedback)(nil)).IfNotExists().Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create intent_feedback table: %w", err)
+	}
 
 	return store, nil
 }
@@ -72,6 +99,33 @@ func (s *BunStore) DeleteDocument(ctx context.Context, id int64) error {
 	return nil
 }
 
+func (s *BunStore) UpdateDocumentHash(ctx context.Context, id int64, hash []byte) error {
+	res, err := s.db.NewUpdate().Model((*models.Document)(nil)).
+		Set("file_hash = ?", hash).
+		Set("updated_at = current_timestamp").
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return database.ErrNotFound
+	}
+	return nil
+}
+
+func (s *BunStore) ListAllDocuments(ctx context.Context) ([]*models.Document, error) {
+	var docs []*models.Document
+	if err := s.db.NewSelect().Model(&docs).Order("created_at ASC").Scan(ctx); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
 // SagaRepository Implementation
 func (s *BunStore) CreateSaga(ctx context.Context, saga *models.IngestSaga) (int64, error) {
 	if _, err := s.db.NewInsert().Model(saga).Exec(ctx); err != nil {
@@ -145,3 +199,125 @@ func (s *BunStore) GetSagaSteps(ctx context.Context, sagaID int64) ([]*models.Sa
 	}
 	return steps, nil
 }
+
+func (s *BunStore) ListAllSagas(ctx context.Context) ([]*models.IngestSaga, error) {
+	var sagas []*models.IngestSaga
+	if err := s.db.NewSelect().Model(&sagas).Order("created_at ASC").Scan(ctx); err != nil {
+		return nil, err
+	}
+	return sagas, nil
+}
+
+// UploadRepository Implementation
+func (s *BunStore) CreateUpload(ctx context.Context, upload *models.UploadSession) error {
+	_, err := s.db.NewInsert().Model(upload).Exec(ctx)
+	return err
+}
+
+func (s *BunStore) GetUpload(ctx context.Context, id string) (*models.UploadSession, error) {
+	upload := new(models.UploadSession)
+	if err := s.db.NewSelect().Model(upload).Where("id = ?", id).Scan(ctx); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, database.ErrNotFound
+		}
+		return nil, err
+	}
+	return upload, nil
+}
+
+func (s *BunStore) UpdateUploadOffset(ctx context.Context, id string, offset int64) error {
+	res, err := s.db.NewUpdate().Model((*models.UploadSession)(nil)).
+		Set("offset = ?", offset).
+		Set("updated_at = current_timestamp").
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return database.ErrNotFound
+	}
+	return nil
+}
+
+func (s *BunStore) ClaimUpload(ctx context.Context, id string) (bool, error) {
+	res, err := s.db.NewUpdate().Model((*models.UploadSession)(nil)).
+		Set("completing = ?", true).
+		Set("updated_at = current_timestamp").
+		Where("id = ? AND completing = ?", id, false).
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (s *BunStore) DeleteUpload(ctx context.Context, id string) error {
+	_, err := s.db.NewDelete().Model((*models.UploadSession)(nil)).Where("id = ?", id).Exec(ctx)
+	return err
+}
+
+func (s *BunStore) ListStaleUploads(ctx context.Context, cutoff time.Time) ([]*models.UploadSession, error) {
+	var uploads []*models.UploadSession
+	if err := s.db.NewSelect().Model(&uploads).Where("updated_at < ?", cutoff).Scan(ctx); err != nil {
+		return nil, err
+	}
+	return uploads, nil
+}
+
+// CritiqueRepository Implementation
+func (s *BunStore) CreateCritiqueEvent(ctx context.Context, event *models.CritiqueEvent) (int64, error) {
+	if _, err := s.db.NewInsert().Model(event).Exec(ctx); err != nil {
+		return 0, err
+	}
+	return event.ID, nil
+}
+
+func (s *BunStore) ListCritiqueEventsSince(ctx context.Context, since time.Time) ([]*models.CritiqueEvent, error) {
+	var events []*models.CritiqueEvent
+	if err := s.db.NewSelect().Model(&events).Where("created_at >= ?", since).Order("created_at ASC").Scan(ctx); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// IntentFeedbackRepository Implementation
+func (s *BunStore) CreateIntentFeedback(ctx context.Context, event *models.IntentFeedback) (int64, error) {
+	if _, err := s.db.NewInsert().Model(event).Exec(ctx); err != nil {
+		return 0, err
+	}
+	return event.ID, nil
+}
+
+func (s *BunStore) RecordIntentSignal(ctx context.Context, id int64, signal string) error {
+	_, err := s.db.NewUpdate().Model((*models.IntentFeedback)(nil)).
+		Set("signal = ?", signal).Where("id = ?", id).Exec(ctx)
+	return err
+}
+
+func (s *BunStore) ListIntentFeedbackSince(ctx context.Context, since time.Time) ([]*models.IntentFeedback, error) {
+	var events []*models.IntentFeedback
+	if err := s.db.NewSelect().Model(&events).Where("created_at >= ?", since).Order("created_at ASC").Scan(ctx); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Ping reports whether the underlying database connection is alive,
+// satisfying health.Prober.
+func (s *BunStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// Name identifies this store in a health.Registry.
+func (s *BunStore) Name() string {
+	return "database"
+}
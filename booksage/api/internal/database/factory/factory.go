@@ -0,0 +1,94 @@
+// Package factory selects and opens the saga/document persistence backend
+// an operator names in config, so ingest.NewOrchestrator (and the
+// saga-migrate tool) can compose with whichever store is configured
+// without main.go or the migration tool each hand-rolling a type switch.
+package factory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/booksage/booksage-api/internal/database"
+	"github.com/booksage/booksage-api/internal/database/bunstore"
+	"github.com/booksage/booksage-api/internal/database/mongostore"
+	"github.com/booksage/booksage-api/internal/database/postgres"
+	"github.com/booksage/booksage-api/internal/database/redisstore"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/sqliteshim"
+)
+
+// Driver names a saga/document persistence backend New knows how to open.
+type Driver string
+
+const (
+	// DriverSQLite is the original bun-over-SQLite backend -- the default
+	// when Driver is left empty, so existing deployments don't need a new
+	// env var just to keep working.
+	DriverSQLite   Driver = "sqlite"
+	DriverPostgres Driver = "postgres"
+	DriverMongo    Driver = "mongo"
+	DriverRedis    Driver = "redis"
+)
+
+// Config collects the connection settings every backend's constructor
+// needs; New only reads the fields relevant to Driver.
+type Config struct {
+	Driver Driver
+
+	// DriverSQLite
+	SQLiteDSN string
+
+	// DriverPostgres
+	PostgresDSN string
+
+	// DriverMongo
+	MongoURI      string
+	MongoDatabase string
+
+	// DriverRedis
+	RedisAddr     string
+	RedisPassword string
+	RedisDB       int
+}
+
+// New opens the backend cfg.Driver names and returns it as both a
+// database.DocumentRepository and a database.SagaRepository.
+func New(ctx context.Context, cfg Config) (database.DocumentRepository, database.SagaRepository, error) {
+	switch cfg.Driver {
+	case "", DriverSQLite:
+		sqldb, err := sql.Open(sqliteshim.ShimName, cfg.SQLiteDSN)
+		if err != nil {
+			return nil, nil, fmt.Errorf("factory: failed to open sqlite %s: %w", cfg.SQLiteDSN, err)
+		}
+		store, err := bunstore.NewBunStore(sqldb, sqlitedialect.New())
+		if err != nil {
+			return nil, nil, err
+		}
+		return store, store, nil
+
+	case DriverPostgres:
+		store, err := postgres.NewPostgresStore(cfg.PostgresDSN)
+		if err != nil {
+			return nil, nil, err
+		}
+		return store, store, nil
+
+	case DriverMongo:
+		store, err := mongostore.NewMongoStore(ctx, cfg.MongoURI, cfg.MongoDatabase)
+		if err != nil {
+			return nil, nil, err
+		}
+		return store, store, nil
+
+	case DriverRedis:
+		store, err := redisstore.NewRedisStore(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB)
+		if err != nil {
+			return nil, nil, err
+		}
+		return store, store, nil
+
+	default:
+		return nil, nil, fmt.Errorf("factory: unknown saga store driver %q", cfg.Driver)
+	}
+}
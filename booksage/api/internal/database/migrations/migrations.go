@@ -0,0 +1,9 @@
+// Package migrations embeds the versioned schema files SQLiteStore and
+// PostgresStore apply via sqlbuilder.Migrator, one subdirectory per
+// dialect.
+package migrations
+
+import "embed"
+
+//go:embed sqlite postgres
+var FS embed.FS
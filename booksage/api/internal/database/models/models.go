@@ -30,8 +30,12 @@ const (
 type Document struct {
 	bun.BaseModel `bun:"table:documents,alias:d"`
 
-	ID        int64     `bun:",pk,autoincrement"`
-	FileHash  []byte    `bun:",unique,notnull"`
+	ID int64 `bun:",pk,autoincrement"`
+	// FileHash is unique but not NOT NULL: a single-pass upload (see
+	// ingestFile) creates the document row before it has streamed enough
+	// of the file to know its digest, and attaches it after the fact via
+	// Orchestrator.AttachHash.
+	FileHash  []byte    `bun:",unique"`
 	Title     string    `bun:",notnull"`
 	Author    string    `bun:",nullzero"`
 	FilePath  string    `bun:",notnull"`
@@ -56,17 +60,91 @@ type IngestSaga struct {
 	UpdatedAt    time.Time  `bun:",nullzero,notnull,default:current_timestamp"`
 }
 
+// UploadSession tracks a resumable, chunked ingest upload between the
+// POST that creates it and the PUT that commits it: TempPath is where
+// PATCHed bytes are appended, and Offset mirrors the temp file's size so a
+// client can resume after a dropped connection with a HEAD request.
+type UploadSession struct {
+	bun.BaseModel `bun:"table:upload_sessions,alias:us"`
+
+	ID       string `bun:",pk"`
+	TempPath string `bun:",notnull"`
+	Offset   int64  `bun:",notnull,default:0"`
+	// Completing is set by ClaimUpload once a PUT has started finalizing
+	// this upload ID, so a concurrent or retried PUT for the same ID is
+	// rejected instead of racing the first one through StartOrResumeIngestion.
+	Completing bool      `bun:",notnull,default:false"`
+	CreatedAt  time.Time `bun:",nullzero,notnull,default:current_timestamp"`
+	UpdatedAt  time.Time `bun:",nullzero,notnull,default:current_timestamp"`
+}
+
+// CritiqueEvent is one persisted SelfRAGCritique verdict -- a retrieval
+// relevance check or a generation grounding check -- so an operator can
+// build offline evaluation dashboards, diff verdicts across model
+// versions, and replay stored (query, context) pairs against a new judge
+// model. Context is stored hashed rather than verbatim since a chunk's
+// full text isn't needed to diff verdicts, only to tell two events apart.
+type CritiqueEvent struct {
+	bun.BaseModel `bun:"table:critique_events,alias:ce"`
+
+	ID          int64   `bun:",pk,autoincrement"`
+	Kind        string  `bun:",notnull"` // "retrieval" or "generation"
+	Query       string  `bun:",notnull"`
+	ContextHash []byte  `bun:",notnull"`
+	Verdict     string  `bun:",notnull"`
+	Model       string  `bun:",notnull"`
+	LatencyMS   float64 `bun:",notnull"`
+	// Answer is only populated for Kind == "generation"; a retrieval
+	// verdict has no produced answer yet.
+	Answer    string    `bun:",nullzero"`
+	CreatedAt time.Time `bun:",nullzero,notnull,default:current_timestamp"`
+}
+
+// IntentFeedback is one persisted fusion.LearnedIntentClassifier decision --
+// the query, the intent it was classified as, and the RouteOperator engine
+// weights that decision selected -- so a later outcome signal (a click, a
+// rejection) can be attached and used to nudge RouteOperator's learned
+// weights toward whatever engine mix actually helped. EngineWeights is
+// stored JSON-encoded since its keys are dynamic engine source names, not a
+// fixed column set.
+type IntentFeedback struct {
+	bun.BaseModel `bun:"table:intent_feedback,alias:ifb"`
+
+	ID            int64  `bun:",pk,autoincrement"`
+	Query         string `bun:",notnull"`
+	Intent        string `bun:",notnull"`
+	EngineWeights string `bun:",notnull"`
+	// Signal is empty until a later outcome (e.g. "clicked", "rejected")
+	// is recorded against this row.
+	Signal    string    `bun:",nullzero"`
+	CreatedAt time.Time `bun:",nullzero,notnull,default:current_timestamp"`
+}
+
 // SagaStep represents a detailed log of a single step
 type SagaStep struct {
 	bun.BaseModel `bun:"table:saga_steps,alias:ss"`
 
-	ID        int64       `bun:",pk,autoincrement"`
-	SagaID    int64       `bun:",notnull"`
-	Saga      *IngestSaga `bun:"rel:belongs-to,join:saga_id=id"`
-	Name      IngestStep  `bun:",notnull"`
-	Status    SagaStatus  `bun:",notnull"`
-	Metadata  string      `bun:",nullzero"` // JSON blob
-	ErrorLog  string      `bun:",nullzero"`
-	CreatedAt time.Time   `bun:",nullzero,notnull,default:current_timestamp"`
-	UpdatedAt time.Time   `bun:",nullzero,notnull,default:current_timestamp"`
+	ID     int64       `bun:",pk,autoincrement"`
+	SagaID int64       `bun:",notnull"`
+	Saga   *IngestSaga `bun:"rel:belongs-to,join:saga_id=id"`
+	Name   IngestStep  `bun:",notnull"`
+	Status SagaStatus  `bun:",notnull"`
+	// AttemptID is a fresh random token written before each attempt at this
+	// step mutates the DB (see Orchestrator.runSagaStep). It doesn't
+	// identify anything by itself; its presence and the step's Status
+	// together let a resumed saga tell an in-flight attempt that crashed
+	// before updating the row apart from one that finished and was
+	// correctly marked Completed or Failed.
+	AttemptID string `bun:",nullzero"`
+	Metadata  string `bun:",nullzero"` // JSON blob
+	ErrorLog  string `bun:",nullzero"`
+	// CompensationStatus tracks whether Orchestrator.CompensateSaga has
+	// undone this step's write, separately from Status (which records
+	// whether the original write succeeded). It stays SagaStatusPending
+	// until compensation is attempted, so a step that was never completed
+	// in the first place is correctly skipped rather than "compensated".
+	CompensationStatus SagaStatus `bun:",nullzero"`
+	CompensationError  string     `bun:",nullzero"`
+	CreatedAt          time.Time  `bun:",nullzero,notnull,default:current_timestamp"`
+	UpdatedAt          time.Time  `bun:",nullzero,notnull,default:current_timestamp"`
 }
@@ -0,0 +1,434 @@
+// Package mongostore implements database.DocumentRepository and
+// database.SagaRepository on top of MongoDB, storing Document, IngestSaga,
+// and SagaStep as native documents in their own collections ("documents",
+// "ingest_sagas", "saga_steps") rather than bun/SQL rows. It exists for
+// deployments where SagaStep.Metadata JSON blobs grow past what a
+// row-oriented database handles cleanly.
+package mongostore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/booksage/booksage-api/internal/database"
+	"github.com/booksage/booksage-api/internal/database/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoStore is a database.DocumentRepository and database.SagaRepository
+// backed by a single MongoDB database.
+type MongoStore struct {
+	db *mongo.Database
+}
+
+// NewMongoStore connects to uri and returns a store scoped to database
+// dbName, creating the indexes GetDocumentByHash and
+// GetLatestSagaByDocumentID rely on.
+func NewMongoStore(ctx context.Context, uri, dbName string) (*MongoStore, error) {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, fmt.Errorf("mongostore: failed to connect to %s: %w", uri, err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		return nil, fmt.Errorf("mongostore: failed to ping mongo: %w", err)
+	}
+
+	store := &MongoStore{db: client.Database(dbName)}
+	if err := store.ensureIndexes(ctx); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *MongoStore) ensureIndexes(ctx context.Context) error {
+	if _, err := s.documents().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "file_hash", Value: 1}},
+		Options: options.Index().SetUnique(true).SetSparse(true),
+	}); err != nil {
+		return fmt.Errorf("mongostore: failed to index documents.file_hash: %w", err)
+	}
+	if _, err := s.sagas().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "document_id", Value: 1}, {Key: "created_at", Value: -1}},
+	}); err != nil {
+		return fmt.Errorf("mongostore: failed to index ingest_sagas.document_id: %w", err)
+	}
+	if _, err := s.steps().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "saga_id", Value: 1}},
+	}); err != nil {
+		return fmt.Errorf("mongostore: failed to index saga_steps.saga_id: %w", err)
+	}
+	return nil
+}
+
+func (s *MongoStore) documents() *mongo.Collection { return s.db.Collection("documents") }
+func (s *MongoStore) sagas() *mongo.Collection     { return s.db.Collection("ingest_sagas") }
+func (s *MongoStore) steps() *mongo.Collection     { return s.db.Collection("saga_steps") }
+func (s *MongoStore) counters() *mongo.Collection  { return s.db.Collection("counters") }
+
+// nextSequence atomically allocates the next int64 ID for name, so
+// document/saga/step IDs stay the int64s database.DocumentRepository and
+// database.SagaRepository expect instead of Mongo's native ObjectID.
+func (s *MongoStore) nextSequence(ctx context.Context, name string) (int64, error) {
+	var result struct {
+		Seq int64 `bson:"seq"`
+	}
+	err := s.counters().FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": name},
+		bson.M{"$inc": bson.M{"seq": int64(1)}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&result)
+	if err != nil {
+		return 0, fmt.Errorf("mongostore: failed to allocate %s id: %w", name, err)
+	}
+	return result.Seq, nil
+}
+
+type mongoDocument struct {
+	ID        int64     `bson:"_id"`
+	FileHash  []byte    `bson:"file_hash,omitempty"`
+	Title     string    `bson:"title"`
+	Author    string    `bson:"author,omitempty"`
+	FilePath  string    `bson:"file_path"`
+	FileSize  int64     `bson:"file_size"`
+	MimeType  string    `bson:"mime_type"`
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+func (d *mongoDocument) toModel() *models.Document {
+	return &models.Document{
+		ID:        d.ID,
+		FileHash:  d.FileHash,
+		Title:     d.Title,
+		Author:    d.Author,
+		FilePath:  d.FilePath,
+		FileSize:  d.FileSize,
+		MimeType:  d.MimeType,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
+}
+
+func documentFromModel(doc *models.Document) *mongoDocument {
+	return &mongoDocument{
+		ID:        doc.ID,
+		FileHash:  doc.FileHash,
+		Title:     doc.Title,
+		Author:    doc.Author,
+		FilePath:  doc.FilePath,
+		FileSize:  doc.FileSize,
+		MimeType:  doc.MimeType,
+		CreatedAt: doc.CreatedAt,
+		UpdatedAt: doc.UpdatedAt,
+	}
+}
+
+// DocumentRepository
+
+func (s *MongoStore) CreateDocument(ctx context.Context, doc *models.Document) (int64, error) {
+	id, err := s.nextSequence(ctx, "documents")
+	if err != nil {
+		return 0, err
+	}
+
+	rec := documentFromModel(doc)
+	rec.ID = id
+	now := time.Now()
+	if rec.CreatedAt.IsZero() {
+		rec.CreatedAt = now
+	}
+	rec.UpdatedAt = now
+
+	if _, err := s.documents().InsertOne(ctx, rec); err != nil {
+		return 0, fmt.Errorf("mongostore: failed to insert document: %w", err)
+	}
+	doc.ID = id
+	return id, nil
+}
+
+func (s *MongoStore) GetDocumentByID(ctx context.Context, id int64) (*models.Document, error) {
+	var rec mongoDocument
+	if err := s.documents().FindOne(ctx, bson.M{"_id": id}).Decode(&rec); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, database.ErrNotFound
+		}
+		return nil, fmt.Errorf("mongostore: failed to get document %d: %w", id, err)
+	}
+	return rec.toModel(), nil
+}
+
+func (s *MongoStore) GetDocumentByHash(ctx context.Context, hash []byte) (*models.Document, error) {
+	var rec mongoDocument
+	if err := s.documents().FindOne(ctx, bson.M{"file_hash": hash}).Decode(&rec); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, database.ErrNotFound
+		}
+		return nil, fmt.Errorf("mongostore: failed to get document by hash: %w", err)
+	}
+	return rec.toModel(), nil
+}
+
+func (s *MongoStore) DeleteDocument(ctx context.Context, id int64) error {
+	res, err := s.documents().DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("mongostore: failed to delete document %d: %w", id, err)
+	}
+	if res.DeletedCount == 0 {
+		return database.ErrNotFound
+	}
+	return nil
+}
+
+func (s *MongoStore) ListAllDocuments(ctx context.Context) ([]*models.Document, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := s.documents().Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("mongostore: failed to list documents: %w", err)
+	}
+	defer func() { _ = cursor.Close(ctx) }()
+
+	var docs []*models.Document
+	for cursor.Next(ctx) {
+		var rec mongoDocument
+		if err := cursor.Decode(&rec); err != nil {
+			return nil, fmt.Errorf("mongostore: failed to decode document: %w", err)
+		}
+		docs = append(docs, rec.toModel())
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("mongostore: failed to iterate documents: %w", err)
+	}
+	return docs, nil
+}
+
+func (s *MongoStore) UpdateDocumentHash(ctx context.Context, id int64, hash []byte) error {
+	res, err := s.documents().UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"file_hash": hash, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		return fmt.Errorf("mongostore: failed to update document %d hash: %w", id, err)
+	}
+	if res.MatchedCount == 0 {
+		return database.ErrNotFound
+	}
+	return nil
+}
+
+// SagaRepository
+
+type mongoSaga struct {
+	ID           int64     `bson:"_id"`
+	DocumentID   int64     `bson:"document_id"`
+	Status       int       `bson:"status"`
+	Version      int       `bson:"version"`
+	CurrentStep  int       `bson:"current_step"`
+	ErrorMessage string    `bson:"error_message,omitempty"`
+	CreatedAt    time.Time `bson:"created_at"`
+	UpdatedAt    time.Time `bson:"updated_at"`
+}
+
+func (r *mongoSaga) toModel() *models.IngestSaga {
+	return &models.IngestSaga{
+		ID:           r.ID,
+		DocumentID:   r.DocumentID,
+		Status:       models.SagaStatus(r.Status),
+		Version:      r.Version,
+		CurrentStep:  models.IngestStep(r.CurrentStep),
+		ErrorMessage: r.ErrorMessage,
+		CreatedAt:    r.CreatedAt,
+		UpdatedAt:    r.UpdatedAt,
+	}
+}
+
+func (s *MongoStore) CreateSaga(ctx context.Context, saga *models.IngestSaga) (int64, error) {
+	id, err := s.nextSequence(ctx, "ingest_sagas")
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	if saga.Version == 0 {
+		saga.Version = 1
+	}
+	rec := &mongoSaga{
+		ID:           id,
+		DocumentID:   saga.DocumentID,
+		Status:       int(saga.Status),
+		Version:      saga.Version,
+		CurrentStep:  int(saga.CurrentStep),
+		ErrorMessage: saga.ErrorMessage,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if _, err := s.sagas().InsertOne(ctx, rec); err != nil {
+		return 0, fmt.Errorf("mongostore: failed to insert saga: %w", err)
+	}
+	saga.ID = id
+	saga.CreatedAt, saga.UpdatedAt = now, now
+	return id, nil
+}
+
+func (s *MongoStore) GetSagaByID(ctx context.Context, id int64) (*models.IngestSaga, error) {
+	var rec mongoSaga
+	if err := s.sagas().FindOne(ctx, bson.M{"_id": id}).Decode(&rec); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, database.ErrNotFound
+		}
+		return nil, fmt.Errorf("mongostore: failed to get saga %d: %w", id, err)
+	}
+	return rec.toModel(), nil
+}
+
+func (s *MongoStore) GetLatestSagaByDocumentID(ctx context.Context, docID int64) (*models.IngestSaga, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	var rec mongoSaga
+	err := s.sagas().FindOne(ctx, bson.M{"document_id": docID}, opts).Decode(&rec)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, database.ErrNotFound
+		}
+		return nil, fmt.Errorf("mongostore: failed to get latest saga for document %d: %w", docID, err)
+	}
+	return rec.toModel(), nil
+}
+
+func (s *MongoStore) UpdateSagaStatus(ctx context.Context, sagaID int64, currentVersion int, status models.SagaStatus, currentStep models.IngestStep, errorMsg string) error {
+	res, err := s.sagas().UpdateOne(ctx,
+		bson.M{"_id": sagaID, "version": currentVersion},
+		bson.M{
+			"$set": bson.M{
+				"status":        int(status),
+				"current_step":  int(currentStep),
+				"error_message": errorMsg,
+				"updated_at":    time.Now(),
+			},
+			"$inc": bson.M{"version": 1},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("mongostore: failed to update saga %d: %w", sagaID, err)
+	}
+	if res.MatchedCount == 0 {
+		return database.ErrConcurrentUpdate
+	}
+	return nil
+}
+
+type mongoSagaStep struct {
+	ID        int64     `bson:"_id"`
+	SagaID    int64     `bson:"saga_id"`
+	Name      int       `bson:"name"`
+	Status    int       `bson:"status"`
+	AttemptID string    `bson:"attempt_id,omitempty"`
+	Metadata  string    `bson:"metadata,omitempty"`
+	ErrorLog  string    `bson:"error_log,omitempty"`
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+func (r *mongoSagaStep) toModel() *models.SagaStep {
+	return &models.SagaStep{
+		ID:        r.ID,
+		SagaID:    r.SagaID,
+		Name:      models.IngestStep(r.Name),
+		Status:    models.SagaStatus(r.Status),
+		AttemptID: r.AttemptID,
+		Metadata:  r.Metadata,
+		ErrorLog:  r.ErrorLog,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
+
+func (s *MongoStore) UpsertSagaStep(ctx context.Context, step *models.SagaStep) (int64, error) {
+	now := time.Now()
+	if step.ID == 0 {
+		id, err := s.nextSequence(ctx, "saga_steps")
+		if err != nil {
+			return 0, err
+		}
+		rec := &mongoSagaStep{
+			ID:        id,
+			SagaID:    step.SagaID,
+			Name:      int(step.Name),
+			Status:    int(step.Status),
+			AttemptID: step.AttemptID,
+			Metadata:  step.Metadata,
+			ErrorLog:  step.ErrorLog,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if _, err := s.steps().InsertOne(ctx, rec); err != nil {
+			return 0, fmt.Errorf("mongostore: failed to insert saga step: %w", err)
+		}
+		step.ID = id
+		return id, nil
+	}
+
+	_, err := s.steps().UpdateOne(ctx,
+		bson.M{"_id": step.ID},
+		bson.M{"$set": bson.M{
+			"saga_id":    step.SagaID,
+			"name":       int(step.Name),
+			"status":     int(step.Status),
+			"attempt_id": step.AttemptID,
+			"metadata":   step.Metadata,
+			"error_log":  step.ErrorLog,
+			"updated_at": now,
+		}},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("mongostore: failed to update saga step %d: %w", step.ID, err)
+	}
+	return step.ID, nil
+}
+
+func (s *MongoStore) GetSagaSteps(ctx context.Context, sagaID int64) ([]*models.SagaStep, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := s.steps().Find(ctx, bson.M{"saga_id": sagaID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("mongostore: failed to list saga steps for saga %d: %w", sagaID, err)
+	}
+	defer func() { _ = cursor.Close(ctx) }()
+
+	var steps []*models.SagaStep
+	for cursor.Next(ctx) {
+		var rec mongoSagaStep
+		if err := cursor.Decode(&rec); err != nil {
+			return nil, fmt.Errorf("mongostore: failed to decode saga step: %w", err)
+		}
+		steps = append(steps, rec.toModel())
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("mongostore: failed to iterate saga steps for saga %d: %w", sagaID, err)
+	}
+	return steps, nil
+}
+
+func (s *MongoStore) ListAllSagas(ctx context.Context) ([]*models.IngestSaga, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := s.sagas().Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("mongostore: failed to list sagas: %w", err)
+	}
+	defer func() { _ = cursor.Close(ctx) }()
+
+	var sagas []*models.IngestSaga
+	for cursor.Next(ctx) {
+		var rec mongoSaga
+		if err := cursor.Decode(&rec); err != nil {
+			return nil, fmt.Errorf("mongostore: failed to decode saga: %w", err)
+		}
+		sagas = append(sagas, rec.toModel())
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("mongostore: failed to iterate sagas: %w", err)
+	}
+	return sagas, nil
+}
@@ -0,0 +1,45 @@
+// Package postgres wires sqlbuilder.Store to the lib/pq driver: it opens
+// the connection, applies the embedded postgres migrations, and hands back
+// a Store ready to serve DocumentRepository and SagaRepository. It exists
+// so an operator can point BookSage at a shared Postgres instance for
+// multi-node ingest workers instead of a per-node SQLite file.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/booksage/booksage-api/internal/database/migrations"
+	"github.com/booksage/booksage-api/internal/database/sqlbuilder"
+	_ "github.com/lib/pq"
+)
+
+// PostgresStore is a sqlbuilder.Store bound to the Postgres dialect.
+type PostgresStore struct {
+	*sqlbuilder.Store
+}
+
+// NewPostgresStore opens dsn (a "postgres://..." connection string) and
+// applies any migrations under internal/database/migrations/postgres that
+// haven't run yet.
+func NewPostgresStore(dsn string) (*PostgresStore, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+
+	dialect := sqlbuilder.Postgres{}
+	migs, err := sqlbuilder.LoadMigrations(migrations.FS, "", dialect)
+	if err != nil {
+		return nil, err
+	}
+	if err := (sqlbuilder.Migrator{Dialect: dialect}).Apply(context.Background(), db, migs); err != nil {
+		return nil, fmt.Errorf("failed to migrate postgres schema: %w", err)
+	}
+
+	return &PostgresStore{Store: &sqlbuilder.Store{DB: db, Dialect: dialect}}, nil
+}
@@ -0,0 +1,446 @@
+// Package redisstore implements database.DocumentRepository and
+// database.SagaRepository on top of Redis. Saga state lives in plain hashes
+// for random access (GetSagaByID, GetSagaSteps), but every status
+// transition is also XADDed to a per-saga stream so downstream services
+// (a live ingest dashboard, say) can tail state changes via a consumer
+// group instead of polling.
+package redisstore
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/booksage/booksage-api/internal/database"
+	"github.com/booksage/booksage-api/internal/database/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore is a database.DocumentRepository and database.SagaRepository
+// backed by a single Redis instance (or cluster, via the same client).
+type RedisStore struct {
+	rdb *redis.Client
+}
+
+// NewRedisStore dials addr (host:port) and returns a store ready to serve
+// DocumentRepository and SagaRepository. db selects the Redis logical
+// database, matching redis.Options.DB.
+func NewRedisStore(addr, password string, db int) (*RedisStore, error) {
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("redisstore: failed to connect to %s: %w", addr, err)
+	}
+
+	return &RedisStore{rdb: rdb}, nil
+}
+
+func documentKey(id int64) string          { return fmt.Sprintf("doc:%d", id) }
+func documentByHashKey(hash string) string { return "doc:byhash:" + hash }
+func sagaKey(id int64) string              { return fmt.Sprintf("saga:%d", id) }
+func sagaByDocKey(docID int64) string      { return fmt.Sprintf("saga:bydoc:%d", docID) }
+func sagaStepKey(id int64) string          { return fmt.Sprintf("step:%d", id) }
+func sagaStepsKey(sagaID int64) string     { return fmt.Sprintf("saga:%d:steps", sagaID) }
+func sagaEventsStream(sagaID int64) string { return fmt.Sprintf("saga:%d:events", sagaID) }
+
+// allDocumentsKey and allSagasKey are sorted sets of every document/saga ID
+// ever created, scored by creation time. Plain Redis hashes have no way to
+// enumerate their own keys efficiently, so ListAllDocuments/ListAllSagas
+// (needed by tooling like saga-migrate) walk these instead.
+const (
+	allDocumentsKey = "doc:all"
+	allSagasKey     = "saga:all"
+)
+
+func parseInt64(s string) int64 {
+	v, _ := strconv.ParseInt(s, 10, 64)
+	return v
+}
+
+func parseTime(s string) time.Time {
+	nanos := parseInt64(s)
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// DocumentRepository
+
+func (s *RedisStore) CreateDocument(ctx context.Context, doc *models.Document) (int64, error) {
+	// Claim the file_hash index before allocating an ID, mirroring the
+	// unique constraint (bun) / unique sparse index (mongostore) the other
+	// backends enforce on Document.FileHash: a second concurrent insert of
+	// the same hash loses the SetNX race and fails instead of silently
+	// shadowing the first document.
+	if len(doc.FileHash) > 0 {
+		ok, err := s.rdb.SetNX(ctx, documentByHashKey(hex.EncodeToString(doc.FileHash)), "pending", 0).Result()
+		if err != nil {
+			return 0, fmt.Errorf("redisstore: failed to claim document hash: %w", err)
+		}
+		if !ok {
+			return 0, database.ErrConcurrentUpdate
+		}
+	}
+
+	id, err := s.rdb.Incr(ctx, "doc:seq").Result()
+	if err != nil {
+		return 0, fmt.Errorf("redisstore: failed to allocate document id: %w", err)
+	}
+
+	now := time.Now()
+	doc.ID = id
+	if doc.CreatedAt.IsZero() {
+		doc.CreatedAt = now
+	}
+	doc.UpdatedAt = now
+
+	fields := map[string]any{
+		"id":         doc.ID,
+		"file_hash":  hex.EncodeToString(doc.FileHash),
+		"title":      doc.Title,
+		"author":     doc.Author,
+		"file_path":  doc.FilePath,
+		"file_size":  doc.FileSize,
+		"mime_type":  doc.MimeType,
+		"created_at": doc.CreatedAt.UnixNano(),
+		"updated_at": doc.UpdatedAt.UnixNano(),
+	}
+	if err := s.rdb.HSet(ctx, documentKey(id), fields).Err(); err != nil {
+		return 0, fmt.Errorf("redisstore: failed to store document %d: %w", id, err)
+	}
+	if len(doc.FileHash) > 0 {
+		if err := s.rdb.Set(ctx, documentByHashKey(hex.EncodeToString(doc.FileHash)), id, 0).Err(); err != nil {
+			return 0, fmt.Errorf("redisstore: failed to index document %d by hash: %w", id, err)
+		}
+	}
+	if err := s.rdb.ZAdd(ctx, allDocumentsKey, redis.Z{Score: float64(doc.CreatedAt.UnixNano()), Member: id}).Err(); err != nil {
+		return 0, fmt.Errorf("redisstore: failed to index document %d: %w", id, err)
+	}
+	return id, nil
+}
+
+func (s *RedisStore) GetDocumentByID(ctx context.Context, id int64) (*models.Document, error) {
+	vals, err := s.rdb.HGetAll(ctx, documentKey(id)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redisstore: failed to get document %d: %w", id, err)
+	}
+	if len(vals) == 0 {
+		return nil, database.ErrNotFound
+	}
+	return documentFromHash(vals), nil
+}
+
+func (s *RedisStore) GetDocumentByHash(ctx context.Context, hash []byte) (*models.Document, error) {
+	idStr, err := s.rdb.Get(ctx, documentByHashKey(hex.EncodeToString(hash))).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, database.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redisstore: failed to look up document by hash: %w", err)
+	}
+	return s.GetDocumentByID(ctx, parseInt64(idStr))
+}
+
+func (s *RedisStore) DeleteDocument(ctx context.Context, id int64) error {
+	n, err := s.rdb.Del(ctx, documentKey(id)).Result()
+	if err != nil {
+		return fmt.Errorf("redisstore: failed to delete document %d: %w", id, err)
+	}
+	if n == 0 {
+		return database.ErrNotFound
+	}
+	return nil
+}
+
+func (s *RedisStore) UpdateDocumentHash(ctx context.Context, id int64, hash []byte) error {
+	exists, err := s.rdb.Exists(ctx, documentKey(id)).Result()
+	if err != nil {
+		return fmt.Errorf("redisstore: failed to check document %d: %w", id, err)
+	}
+	if exists == 0 {
+		return database.ErrNotFound
+	}
+
+	now := time.Now()
+	if err := s.rdb.HSet(ctx, documentKey(id), map[string]any{
+		"file_hash":  hex.EncodeToString(hash),
+		"updated_at": now.UnixNano(),
+	}).Err(); err != nil {
+		return fmt.Errorf("redisstore: failed to update document %d hash: %w", id, err)
+	}
+	return s.rdb.Set(ctx, documentByHashKey(hex.EncodeToString(hash)), id, 0).Err()
+}
+
+func (s *RedisStore) ListAllDocuments(ctx context.Context) ([]*models.Document, error) {
+	ids, err := s.rdb.ZRange(ctx, allDocumentsKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redisstore: failed to list documents: %w", err)
+	}
+
+	docs := make([]*models.Document, 0, len(ids))
+	for _, idStr := range ids {
+		vals, err := s.rdb.HGetAll(ctx, documentKey(parseInt64(idStr))).Result()
+		if err != nil {
+			return nil, fmt.Errorf("redisstore: failed to get document %s: %w", idStr, err)
+		}
+		if len(vals) == 0 {
+			continue
+		}
+		docs = append(docs, documentFromHash(vals))
+	}
+	return docs, nil
+}
+
+func documentFromHash(vals map[string]string) *models.Document {
+	hash, _ := hex.DecodeString(vals["file_hash"])
+	return &models.Document{
+		ID:        parseInt64(vals["id"]),
+		FileHash:  hash,
+		Title:     vals["title"],
+		Author:    vals["author"],
+		FilePath:  vals["file_path"],
+		FileSize:  parseInt64(vals["file_size"]),
+		MimeType:  vals["mime_type"],
+		CreatedAt: parseTime(vals["created_at"]),
+		UpdatedAt: parseTime(vals["updated_at"]),
+	}
+}
+
+// SagaRepository
+
+func (s *RedisStore) CreateSaga(ctx context.Context, saga *models.IngestSaga) (int64, error) {
+	id, err := s.rdb.Incr(ctx, "saga:seq").Result()
+	if err != nil {
+		return 0, fmt.Errorf("redisstore: failed to allocate saga id: %w", err)
+	}
+
+	now := time.Now()
+	saga.ID = id
+	if saga.Version == 0 {
+		saga.Version = 1
+	}
+	saga.CreatedAt, saga.UpdatedAt = now, now
+
+	if err := s.rdb.HSet(ctx, sagaKey(id), sagaHashFields(saga)).Err(); err != nil {
+		return 0, fmt.Errorf("redisstore: failed to store saga %d: %w", id, err)
+	}
+	if err := s.rdb.ZAdd(ctx, sagaByDocKey(saga.DocumentID), redis.Z{Score: float64(now.UnixNano()), Member: id}).Err(); err != nil {
+		return 0, fmt.Errorf("redisstore: failed to index saga %d by document: %w", id, err)
+	}
+	if err := s.appendSagaEvent(ctx, id, saga.Status, saga.CurrentStep); err != nil {
+		return 0, err
+	}
+	if err := s.rdb.ZAdd(ctx, allSagasKey, redis.Z{Score: float64(now.UnixNano()), Member: id}).Err(); err != nil {
+		return 0, fmt.Errorf("redisstore: failed to index saga %d: %w", id, err)
+	}
+	return id, nil
+}
+
+func (s *RedisStore) GetSagaByID(ctx context.Context, id int64) (*models.IngestSaga, error) {
+	vals, err := s.rdb.HGetAll(ctx, sagaKey(id)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redisstore: failed to get saga %d: %w", id, err)
+	}
+	if len(vals) == 0 {
+		return nil, database.ErrNotFound
+	}
+	return sagaFromHash(vals), nil
+}
+
+func (s *RedisStore) GetLatestSagaByDocumentID(ctx context.Context, docID int64) (*models.IngestSaga, error) {
+	ids, err := s.rdb.ZRevRange(ctx, sagaByDocKey(docID), 0, 0).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redisstore: failed to look up latest saga for document %d: %w", docID, err)
+	}
+	if len(ids) == 0 {
+		return nil, database.ErrNotFound
+	}
+	return s.GetSagaByID(ctx, parseInt64(ids[0]))
+}
+
+// UpdateSagaStatus uses WATCH/MULTI on the saga's hash key to emulate the
+// compare-and-swap UPDATE ... WHERE version = ? the SQL backends rely on:
+// if another writer touches the key between the WATCH and the EXEC, Redis
+// aborts the transaction and this returns database.ErrConcurrentUpdate,
+// exactly as a zero-rows-affected UPDATE would.
+func (s *RedisStore) UpdateSagaStatus(ctx context.Context, sagaID int64, currentVersion int, status models.SagaStatus, currentStep models.IngestStep, errorMsg string) error {
+	key := sagaKey(sagaID)
+	txf := func(tx *redis.Tx) error {
+		versionStr, err := tx.HGet(ctx, key, "version").Result()
+		if errors.Is(err, redis.Nil) {
+			return database.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if int(parseInt64(versionStr)) != currentVersion {
+			return database.ErrConcurrentUpdate
+		}
+
+		now := time.Now()
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, key, map[string]any{
+				"status":        int(status),
+				"current_step":  int(currentStep),
+				"error_message": errorMsg,
+				"version":       currentVersion + 1,
+				"updated_at":    now.UnixNano(),
+			})
+			return nil
+		})
+		return err
+	}
+
+	err := s.rdb.Watch(ctx, txf, key)
+	if errors.Is(err, redis.TxFailedErr) {
+		return database.ErrConcurrentUpdate
+	}
+	if err != nil {
+		return fmt.Errorf("redisstore: failed to update saga %d: %w", sagaID, err)
+	}
+	return s.appendSagaEvent(ctx, sagaID, status, currentStep)
+}
+
+// appendSagaEvent records a state transition on the saga's stream so a
+// consumer group can follow ingestion progress in real time without
+// polling GetSagaByID.
+func (s *RedisStore) appendSagaEvent(ctx context.Context, sagaID int64, status models.SagaStatus, step models.IngestStep) error {
+	err := s.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: sagaEventsStream(sagaID),
+		Values: map[string]any{
+			"status": int(status),
+			"step":   int(step),
+			"at":     time.Now().UnixNano(),
+		},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("redisstore: failed to append saga %d event: %w", sagaID, err)
+	}
+	return nil
+}
+
+func (s *RedisStore) ListAllSagas(ctx context.Context) ([]*models.IngestSaga, error) {
+	ids, err := s.rdb.ZRange(ctx, allSagasKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redisstore: failed to list sagas: %w", err)
+	}
+
+	sagas := make([]*models.IngestSaga, 0, len(ids))
+	for _, idStr := range ids {
+		vals, err := s.rdb.HGetAll(ctx, sagaKey(parseInt64(idStr))).Result()
+		if err != nil {
+			return nil, fmt.Errorf("redisstore: failed to get saga %s: %w", idStr, err)
+		}
+		if len(vals) == 0 {
+			continue
+		}
+		sagas = append(sagas, sagaFromHash(vals))
+	}
+	return sagas, nil
+}
+
+func sagaHashFields(saga *models.IngestSaga) map[string]any {
+	return map[string]any{
+		"id":            saga.ID,
+		"document_id":   saga.DocumentID,
+		"status":        int(saga.Status),
+		"version":       saga.Version,
+		"current_step":  int(saga.CurrentStep),
+		"error_message": saga.ErrorMessage,
+		"created_at":    saga.CreatedAt.UnixNano(),
+		"updated_at":    saga.UpdatedAt.UnixNano(),
+	}
+}
+
+func sagaFromHash(vals map[string]string) *models.IngestSaga {
+	return &models.IngestSaga{
+		ID:           parseInt64(vals["id"]),
+		DocumentID:   parseInt64(vals["document_id"]),
+		Status:       models.SagaStatus(parseInt64(vals["status"])),
+		Version:      int(parseInt64(vals["version"])),
+		CurrentStep:  models.IngestStep(parseInt64(vals["current_step"])),
+		ErrorMessage: vals["error_message"],
+		CreatedAt:    parseTime(vals["created_at"]),
+		UpdatedAt:    parseTime(vals["updated_at"]),
+	}
+}
+
+func (s *RedisStore) UpsertSagaStep(ctx context.Context, step *models.SagaStep) (int64, error) {
+	now := time.Now()
+	if step.ID == 0 {
+		id, err := s.rdb.Incr(ctx, "saga_step:seq").Result()
+		if err != nil {
+			return 0, fmt.Errorf("redisstore: failed to allocate saga step id: %w", err)
+		}
+		step.ID = id
+		step.CreatedAt, step.UpdatedAt = now, now
+	} else {
+		step.UpdatedAt = now
+	}
+
+	if err := s.rdb.HSet(ctx, sagaStepKey(step.ID), sagaStepHashFields(step)).Err(); err != nil {
+		return 0, fmt.Errorf("redisstore: failed to store saga step %d: %w", step.ID, err)
+	}
+	if err := s.rdb.ZAdd(ctx, sagaStepsKey(step.SagaID), redis.Z{Score: float64(step.CreatedAt.UnixNano()), Member: step.ID}).Err(); err != nil {
+		return 0, fmt.Errorf("redisstore: failed to index saga step %d: %w", step.ID, err)
+	}
+	return step.ID, nil
+}
+
+func (s *RedisStore) GetSagaSteps(ctx context.Context, sagaID int64) ([]*models.SagaStep, error) {
+	ids, err := s.rdb.ZRange(ctx, sagaStepsKey(sagaID), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redisstore: failed to list saga steps for saga %d: %w", sagaID, err)
+	}
+
+	steps := make([]*models.SagaStep, 0, len(ids))
+	for _, idStr := range ids {
+		vals, err := s.rdb.HGetAll(ctx, sagaStepKey(parseInt64(idStr))).Result()
+		if err != nil {
+			return nil, fmt.Errorf("redisstore: failed to get saga step %s: %w", idStr, err)
+		}
+		if len(vals) == 0 {
+			continue
+		}
+		steps = append(steps, sagaStepFromHash(vals))
+	}
+	return steps, nil
+}
+
+func sagaStepHashFields(step *models.SagaStep) map[string]any {
+	return map[string]any{
+		"id":         step.ID,
+		"saga_id":    step.SagaID,
+		"name":       int(step.Name),
+		"status":     int(step.Status),
+		"attempt_id": step.AttemptID,
+		"metadata":   step.Metadata,
+		"error_log":  step.ErrorLog,
+		"created_at": step.CreatedAt.UnixNano(),
+		"updated_at": step.UpdatedAt.UnixNano(),
+	}
+}
+
+func sagaStepFromHash(vals map[string]string) *models.SagaStep {
+	return &models.SagaStep{
+		ID:        parseInt64(vals["id"]),
+		SagaID:    parseInt64(vals["saga_id"]),
+		Name:      models.IngestStep(parseInt64(vals["name"])),
+		Status:    models.SagaStatus(parseInt64(vals["status"])),
+		AttemptID: vals["attempt_id"],
+		Metadata:  vals["metadata"],
+		ErrorLog:  vals["error_log"],
+		CreatedAt: parseTime(vals["created_at"]),
+		UpdatedAt: parseTime(vals["updated_at"]),
+	}
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/booksage/booksage-api/internal/database/models"
 )
@@ -18,6 +19,16 @@ type DocumentRepository interface {
 	GetDocumentByID(ctx context.Context, id int64) (*models.Document, error)
 	GetDocumentByHash(ctx context.Context, hash []byte) (*models.Document, error)
 	DeleteDocument(ctx context.Context, id int64) error
+
+	// UpdateDocumentHash attaches a content hash to a document created
+	// before its digest was known, e.g. a single-pass upload that only
+	// finishes hashing once the whole file has streamed past.
+	UpdateDocumentHash(ctx context.Context, id int64, hash []byte) error
+
+	// ListAllDocuments returns every document, oldest first. It exists for
+	// tooling that needs to walk the whole table rather than look up one
+	// record at a time, e.g. the saga-migrate backend cutover tool.
+	ListAllDocuments(ctx context.Context) ([]*models.Document, error)
 }
 
 // SagaRepository handles Ingest Saga state persistence
@@ -29,4 +40,61 @@ type SagaRepository interface {
 
 	UpsertSagaStep(ctx context.Context, step *models.SagaStep) (int64, error)
 	GetSagaSteps(ctx context.Context, sagaID int64) ([]*models.SagaStep, error)
+
+	// ListAllSagas returns every saga, oldest first. Like
+	// ListAllDocuments, it exists for tooling that walks the whole saga
+	// history rather than looking up one saga (or one document's latest
+	// saga) at a time, e.g. the saga-migrate backend cutover tool.
+	ListAllSagas(ctx context.Context) ([]*models.IngestSaga, error)
+}
+
+// CritiqueRepository persists SelfRAGCritique verdicts for offline
+// evaluation: diffing verdicts across model versions, building dashboards,
+// and replaying stored (query, context) pairs against a new judge model.
+type CritiqueRepository interface {
+	CreateCritiqueEvent(ctx context.Context, event *models.CritiqueEvent) (int64, error)
+
+	// ListCritiqueEventsSince returns every event recorded at or after
+	// since, oldest first.
+	ListCritiqueEventsSince(ctx context.Context, since time.Time) ([]*models.CritiqueEvent, error)
+}
+
+// IntentFeedbackRepository persists fusion.LearnedIntentClassifier decisions
+// and the outcome signals later attached to them, so a periodic job can
+// recompute RouteOperator's learned engine weights from real usage.
+type IntentFeedbackRepository interface {
+	CreateIntentFeedback(ctx context.Context, event *models.IntentFeedback) (int64, error)
+
+	// RecordIntentSignal attaches an outcome signal (e.g. "clicked",
+	// "rejected") to a previously created IntentFeedback row.
+	RecordIntentSignal(ctx context.Context, id int64, signal string) error
+
+	// ListIntentFeedbackSince returns every event recorded at or after
+	// since, oldest first.
+	ListIntentFeedbackSince(ctx context.Context, since time.Time) ([]*models.IntentFeedback, error)
+}
+
+// UploadRepository tracks in-progress resumable ingest uploads: the opaque
+// upload ID a client PATCHes chunks against, the temp file those chunks
+// land in, and how many bytes have been durably written so far. It exists
+// alongside SagaRepository rather than folded into it because an upload
+// session only becomes ingestion-relevant once it's committed; until then
+// it's pure transfer bookkeeping a janitor needs to sweep independently of
+// any saga.
+type UploadRepository interface {
+	CreateUpload(ctx context.Context, upload *models.UploadSession) error
+	GetUpload(ctx context.Context, id string) (*models.UploadSession, error)
+	UpdateUploadOffset(ctx context.Context, id string, offset int64) error
+	DeleteUpload(ctx context.Context, id string) error
+
+	// ClaimUpload atomically flips an upload session from not-completing to
+	// completing, so that a client's retried or duplicated completion
+	// request for the same upload ID can't race the first one through the
+	// ingest saga. It reports false (with no error) if the session was
+	// already claimed.
+	ClaimUpload(ctx context.Context, id string) (bool, error)
+
+	// ListStaleUploads returns upload sessions last touched before cutoff,
+	// so a janitor can reclaim their temp files and DB rows.
+	ListStaleUploads(ctx context.Context, cutoff time.Time) ([]*models.UploadSession, error)
 }
@@ -0,0 +1,62 @@
+// Package sqlbuilder holds the driver-agnostic pieces SQLiteStore and
+// PostgresStore share: a Dialect that adapts one canonical query written
+// with `?` placeholders to a specific driver's syntax, a Store that
+// implements DocumentRepository/SagaRepository against any database/sql
+// driver via that Dialect, and a Migrator that applies the dialect's own
+// versioned migration files.
+package sqlbuilder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect adapts the canonical, `?`-placeholder query text Store is
+// written against to a specific SQL driver.
+type Dialect interface {
+	// Name identifies the dialect's migrations subdirectory under
+	// internal/database/migrations (e.g. "sqlite", "postgres").
+	Name() string
+	// Rewrite rewrites a query's `?` placeholders, in argument order, into
+	// this dialect's native placeholder syntax. It assumes `?` never
+	// appears inside a string literal in Store's query text, which holds
+	// for every query Store issues.
+	Rewrite(query string) string
+	// ReturningClause is the SQL fragment an INSERT needs appended so
+	// Store.insert can recover the new row's ID uniformly: empty for
+	// dialects where the driver's Result.LastInsertId works (SQLite), or
+	// " RETURNING id" for dialects where it doesn't (Postgres).
+	ReturningClause() string
+}
+
+// SQLite leaves `?` placeholders untouched -- that's what database/sql's
+// sqlite3 driver expects natively -- and relies on Result.LastInsertId
+// after a plain INSERT.
+type SQLite struct{}
+
+func (SQLite) Name() string                { return "sqlite" }
+func (SQLite) Rewrite(query string) string { return query }
+func (SQLite) ReturningClause() string     { return "" }
+
+// Postgres rewrites each `?` into a sequential $N placeholder and appends
+// "RETURNING id" to INSERTs, since lib/pq and pgx both require numbered
+// parameters and neither populates Result.LastInsertId.
+type Postgres struct{}
+
+func (Postgres) Name() string { return "postgres" }
+
+func (Postgres) Rewrite(query string) string {
+	var sb strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			sb.WriteString(fmt.Sprintf("$%d", n))
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func (Postgres) ReturningClause() string { return " RETURNING id" }
@@ -0,0 +1,136 @@
+package sqlbuilder
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Migration is one versioned schema change, loaded from a dialect's
+// migrations subdirectory.
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// LoadMigrations reads every "NNNN_description.sql" file under
+// path.Join(root, dialect.Name()) in fsys, ordered by version. root is
+// typically "" so the migrations package's embed.FS (which embeds
+// "sqlite" and "postgres" at its own root) resolves directly.
+func LoadMigrations(fsys embed.FS, root string, dialect Dialect) ([]Migration, error) {
+	dir := path.Join(root, dialect.Name())
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("sqlbuilder: read migrations dir %s: %w", dir, err)
+	}
+
+	migrations := make([]Migration, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		version, name, err := parseMigrationFilename(entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("sqlbuilder: %w", err)
+		}
+		contents, err := fsys.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("sqlbuilder: read %s: %w", entry.Name(), err)
+		}
+		migrations = append(migrations, Migration{Version: version, Name: name, SQL: string(contents)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// parseMigrationFilename splits "0001_init.sql" into version 1 and name "init".
+func parseMigrationFilename(name string) (version int, label string, err error) {
+	base := strings.TrimSuffix(name, ".sql")
+	prefix, label, ok := strings.Cut(base, "_")
+	if !ok {
+		return 0, "", fmt.Errorf("migration file %q must be named NNNN_description.sql", name)
+	}
+	version, err = strconv.Atoi(prefix)
+	if err != nil {
+		return 0, "", fmt.Errorf("migration file %q has a non-numeric version: %w", name, err)
+	}
+	return version, label, nil
+}
+
+// Migrator applies a dialect's migrations in version order, recording each
+// applied version in a schema_migrations table so re-running Apply against
+// an already-migrated database is a no-op.
+type Migrator struct {
+	Dialect Dialect
+}
+
+// Apply runs every migration whose version isn't yet recorded in
+// schema_migrations, each inside its own transaction so a failure partway
+// through one migration's SQL can't leave it half-applied.
+func (m Migrator) Apply(ctx context.Context, db *sql.DB, migrations []Migration) error {
+	createTable := m.Dialect.Rewrite(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at INTEGER NOT NULL
+	)`)
+	if _, err := db.ExecContext(ctx, createTable); err != nil {
+		return fmt.Errorf("sqlbuilder: create schema_migrations: %w", err)
+	}
+
+	applied, err := m.appliedVersions(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	recordMigration := m.Dialect.Rewrite(`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`)
+
+	for _, mig := range migrations {
+		if applied[mig.Version] {
+			continue
+		}
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("sqlbuilder: begin migration %d_%s: %w", mig.Version, mig.Name, err)
+		}
+		if _, err := tx.ExecContext(ctx, mig.SQL); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("sqlbuilder: apply migration %d_%s: %w", mig.Version, mig.Name, err)
+		}
+		if _, err := tx.ExecContext(ctx, recordMigration, mig.Version, mig.Name, time.Now().Unix()); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("sqlbuilder: record migration %d_%s: %w", mig.Version, mig.Name, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("sqlbuilder: commit migration %d_%s: %w", mig.Version, mig.Name, err)
+		}
+	}
+	return nil
+}
+
+func (m Migrator) appliedVersions(ctx context.Context, db *sql.DB) (map[int]bool, error) {
+	rows, err := db.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("sqlbuilder: read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			return nil, fmt.Errorf("sqlbuilder: scan schema_migrations: %w", err)
+		}
+		applied[v] = true
+	}
+	return applied, rows.Err()
+}
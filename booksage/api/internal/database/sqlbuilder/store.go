@@ -0,0 +1,433 @@
+package sqlbuilder
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/booksage/booksage-api/internal/database"
+	"github.com/booksage/booksage-api/internal/database/models"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer is package-scoped so every Store shares one tracer; ctx already
+// carries whatever span the caller started (SagaOrchestrator.runStep, an
+// HTTP handler's request span, ...), so these DB-layer spans nest under it
+// rather than needing their own propagation.
+var tracer = otel.Tracer("booksage-api/database/sqlbuilder")
+
+const (
+	// DefaultSagaTxMaxRetries bounds how many times WithSagaTx retries a
+	// versioned saga update after losing a concurrent-write race before
+	// giving up and returning database.ErrConcurrentUpdate.
+	DefaultSagaTxMaxRetries = 5
+
+	sagaTxBaseDelay = 10 * time.Millisecond
+	sagaTxMaxDelay  = 500 * time.Millisecond
+)
+
+// Store implements DocumentRepository and SagaRepository against any
+// database/sql driver, rewriting every query's canonical `?` placeholders
+// through Dialect before it reaches the driver. SQLiteStore and
+// PostgresStore are thin wrappers around a Store: they differ only in how
+// they open the *sql.DB and which migrations they apply on construction.
+type Store struct {
+	DB      *sql.DB
+	Dialect Dialect
+}
+
+func (s *Store) Close() error { return s.DB.Close() }
+
+// withSpan runs fn inside a child span named "db."+name carrying sagaID,
+// recording any returned error on the span before returning it unchanged.
+func withSpan(ctx context.Context, name string, sagaID int64, fn func(context.Context) error) error {
+	ctx, span := tracer.Start(ctx, "db."+name, trace.WithAttributes(attribute.Int64("saga.id", sagaID)))
+	defer span.End()
+
+	err := fn(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+// insert runs an INSERT written with a trailing `?`-style VALUES clause and
+// returns the new row's ID, using the Dialect's ReturningClause where
+// Result.LastInsertId isn't available (Postgres) and LastInsertId where it
+// is (SQLite).
+func (s *Store) insert(ctx context.Context, query string, args ...any) (int64, error) {
+	query = s.Dialect.Rewrite(query) + s.Dialect.ReturningClause()
+	if s.Dialect.ReturningClause() != "" {
+		var id int64
+		err := s.DB.QueryRowContext(ctx, query, args...).Scan(&id)
+		return id, err
+	}
+	res, err := s.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// Implement DocumentRepository
+
+func (s *Store) CreateDocument(ctx context.Context, doc *models.Document) (int64, error) {
+	now := time.Now().Unix()
+	query := `INSERT INTO documents (file_hash, title, author, file_path, file_size, mime_type, created_at, updated_at)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	return s.insert(ctx, query, doc.FileHash, doc.Title, doc.Author, doc.FilePath, doc.FileSize, doc.MimeType, now, now)
+}
+
+func (s *Store) GetDocumentByID(ctx context.Context, id int64) (*models.Document, error) {
+	query := s.Dialect.Rewrite(`SELECT id, file_hash, title, author, file_path, file_size, mime_type, created_at, updated_at FROM documents WHERE id = ?`)
+	doc := &models.Document{}
+	var createdAt, updatedAt int64
+	err := s.DB.QueryRowContext(ctx, query, id).Scan(&doc.ID, &doc.FileHash, &doc.Title, &doc.Author, &doc.FilePath, &doc.FileSize, &doc.MimeType, &createdAt, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, database.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	doc.CreatedAt = time.Unix(createdAt, 0)
+	doc.UpdatedAt = time.Unix(updatedAt, 0)
+	return doc, nil
+}
+
+func (s *Store) GetDocumentByHash(ctx context.Context, hash []byte) (*models.Document, error) {
+	query := s.Dialect.Rewrite(`SELECT id, file_hash, title, author, file_path, file_size, mime_type, created_at, updated_at FROM documents WHERE file_hash = ?`)
+	doc := &models.Document{}
+	var createdAt, updatedAt int64
+	err := s.DB.QueryRowContext(ctx, query, hash).Scan(&doc.ID, &doc.FileHash, &doc.Title, &doc.Author, &doc.FilePath, &doc.FileSize, &doc.MimeType, &createdAt, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, database.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	doc.CreatedAt = time.Unix(createdAt, 0)
+	doc.UpdatedAt = time.Unix(updatedAt, 0)
+	return doc, nil
+}
+
+func (s *Store) DeleteDocument(ctx context.Context, id int64) error {
+	_, err := s.DB.ExecContext(ctx, s.Dialect.Rewrite(`DELETE FROM documents WHERE id = ?`), id)
+	return err
+}
+
+func (s *Store) ListAllDocuments(ctx context.Context) ([]*models.Document, error) {
+	query := s.Dialect.Rewrite(`SELECT id, file_hash, title, author, file_path, file_size, mime_type, created_at, updated_at FROM documents ORDER BY created_at ASC`)
+	rows, err := s.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var docs []*models.Document
+	for rows.Next() {
+		doc := &models.Document{}
+		var createdAt, updatedAt int64
+		if err := rows.Scan(&doc.ID, &doc.FileHash, &doc.Title, &doc.Author, &doc.FilePath, &doc.FileSize, &doc.MimeType, &createdAt, &updatedAt); err != nil {
+			return nil, err
+		}
+		doc.CreatedAt = time.Unix(createdAt, 0)
+		doc.UpdatedAt = time.Unix(updatedAt, 0)
+		docs = append(docs, doc)
+	}
+	return docs, rows.Err()
+}
+
+func (s *Store) UpdateDocumentHash(ctx context.Context, id int64, hash []byte) error {
+	now := time.Now().Unix()
+	query := s.Dialect.Rewrite(`UPDATE documents SET file_hash = ?, updated_at = ? WHERE id = ?`)
+	res, err := s.DB.ExecContext(ctx, query, hash, now, id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return database.ErrNotFound
+	}
+	return nil
+}
+
+// Implement SagaRepository
+
+func (s *Store) CreateSaga(ctx context.Context, saga *models.IngestSaga) (int64, error) {
+	ctx, span := tracer.Start(ctx, "db.CreateSaga", trace.WithAttributes(attribute.Int64("document.id", saga.DocumentID)))
+	defer span.End()
+
+	now := time.Now().Unix()
+	query := `INSERT INTO ingest_sagas (document_id, status, version, current_step, error_message, created_at, updated_at)
+	          VALUES (?, ?, ?, ?, ?, ?, ?)`
+	id, err := s.insert(ctx, query, saga.DocumentID, saga.Status, 1, saga.CurrentStep, saga.ErrorMessage, now, now)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return id, err
+}
+
+func (s *Store) GetSagaByID(ctx context.Context, id int64) (*models.IngestSaga, error) {
+	query := s.Dialect.Rewrite(`SELECT id, document_id, status, version, current_step, error_message, created_at, updated_at FROM ingest_sagas WHERE id = ?`)
+	saga := &models.IngestSaga{}
+	var createdAt, updatedAt int64
+	err := s.DB.QueryRowContext(ctx, query, id).Scan(&saga.ID, &saga.DocumentID, &saga.Status, &saga.Version, &saga.CurrentStep, &saga.ErrorMessage, &createdAt, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, database.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	saga.CreatedAt = time.Unix(createdAt, 0)
+	saga.UpdatedAt = time.Unix(updatedAt, 0)
+	return saga, nil
+}
+
+func (s *Store) GetLatestSagaByDocumentID(ctx context.Context, docID int64) (*models.IngestSaga, error) {
+	query := s.Dialect.Rewrite(`SELECT id, document_id, status, version, current_step, error_message, created_at, updated_at FROM ingest_sagas WHERE document_id = ? ORDER BY created_at DESC LIMIT 1`)
+	saga := &models.IngestSaga{}
+	var createdAt, updatedAt int64
+	err := s.DB.QueryRowContext(ctx, query, docID).Scan(&saga.ID, &saga.DocumentID, &saga.Status, &saga.Version, &saga.CurrentStep, &saga.ErrorMessage, &createdAt, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, database.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	saga.CreatedAt = time.Unix(createdAt, 0)
+	saga.UpdatedAt = time.Unix(updatedAt, 0)
+	return saga, nil
+}
+
+func (s *Store) ListAllSagas(ctx context.Context) ([]*models.IngestSaga, error) {
+	query := s.Dialect.Rewrite(`SELECT id, document_id, status, version, current_step, error_message, created_at, updated_at FROM ingest_sagas ORDER BY created_at ASC`)
+	rows, err := s.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sagas []*models.IngestSaga
+	for rows.Next() {
+		saga := &models.IngestSaga{}
+		var createdAt, updatedAt int64
+		if err := rows.Scan(&saga.ID, &saga.DocumentID, &saga.Status, &saga.Version, &saga.CurrentStep, &saga.ErrorMessage, &createdAt, &updatedAt); err != nil {
+			return nil, err
+		}
+		saga.CreatedAt = time.Unix(createdAt, 0)
+		saga.UpdatedAt = time.Unix(updatedAt, 0)
+		sagas = append(sagas, saga)
+	}
+	return sagas, rows.Err()
+}
+
+func (s *Store) UpdateSagaStatus(ctx context.Context, sagaID int64, currentVersion int, status models.SagaStatus, currentStep models.IngestStep, errorMsg string) error {
+	return withSpan(ctx, "UpdateSagaStatus", sagaID, func(ctx context.Context) error {
+		return s.updateSagaVersion(ctx, s.DB, sagaID, currentVersion, status, currentStep, errorMsg)
+	})
+}
+
+// execer is the subset of *sql.DB and *sql.Tx that updateSagaVersion needs,
+// so the same versioned UPDATE can run standalone (UpdateSagaStatus,
+// WithSagaTx) or inside a caller-managed transaction (SagaStepTx).
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// updateSagaVersion runs the optimistic-concurrency UPDATE shared by
+// UpdateSagaStatus, WithSagaTx, and SagaStepTx, returning
+// database.ErrConcurrentUpdate if currentVersion no longer matches the
+// stored row.
+func (s *Store) updateSagaVersion(ctx context.Context, exec execer, sagaID int64, currentVersion int, status models.SagaStatus, currentStep models.IngestStep, errorMsg string) error {
+	now := time.Now().Unix()
+	query := s.Dialect.Rewrite(`UPDATE ingest_sagas SET status = ?, version = version + 1, current_step = ?, error_message = ?, updated_at = ? WHERE id = ? AND version = ?`)
+	res, err := exec.ExecContext(ctx, query, status, currentStep, errorMsg, now, sagaID, currentVersion)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return database.ErrConcurrentUpdate
+	}
+	return nil
+}
+
+// WithSagaTx loads the saga identified by sagaID, lets fn mutate its
+// Status/CurrentStep/ErrorMessage in place, and attempts the versioned
+// UpdateSagaStatus write -- retrying with exponential backoff and full
+// jitter up to DefaultSagaTxMaxRetries times whenever another writer wins
+// the race and the update comes back database.ErrConcurrentUpdate. Callers
+// no longer need to re-implement the load-mutate-retry dance themselves.
+func (s *Store) WithSagaTx(ctx context.Context, sagaID int64, fn func(*models.IngestSaga) error) error {
+	ctx, span := tracer.Start(ctx, "db.WithSagaTx", trace.WithAttributes(attribute.Int64("saga.id", sagaID)))
+	defer span.End()
+
+	err := s.withSagaTx(ctx, sagaID, fn)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+// withSagaTx is WithSagaTx's body, split out so the exported method can wrap
+// it in a single span covering every retry instead of one span per attempt.
+func (s *Store) withSagaTx(ctx context.Context, sagaID int64, fn func(*models.IngestSaga) error) error {
+	var lastErr error
+	for attempt := 0; attempt <= DefaultSagaTxMaxRetries; attempt++ {
+		saga, err := s.GetSagaByID(ctx, sagaID)
+		if err != nil {
+			return err
+		}
+		if err := fn(saga); err != nil {
+			return err
+		}
+
+		err = s.updateSagaVersion(ctx, s.DB, saga.ID, saga.Version, saga.Status, saga.CurrentStep, saga.ErrorMessage)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, database.ErrConcurrentUpdate) {
+			return err
+		}
+		lastErr = err
+
+		if attempt == DefaultSagaTxMaxRetries {
+			break
+		}
+		if err := sleepSagaBackoff(ctx, attempt); err != nil {
+			return err
+		}
+	}
+	return lastErr
+}
+
+// sleepSagaBackoff waits rand(0, min(sagaTxMaxDelay, sagaTxBaseDelay*2^attempt))
+// before a WithSagaTx retry, returning early with ctx.Err() if ctx is
+// canceled first.
+func sleepSagaBackoff(ctx context.Context, attempt int) error {
+	maxDelay := sagaTxMaxDelay
+	if scaled := sagaTxBaseDelay << uint(attempt); scaled > 0 && scaled < maxDelay {
+		maxDelay = scaled
+	}
+	timer := time.NewTimer(time.Duration(rand.Int63n(int64(maxDelay))))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// SagaStepTx upserts step and bumps the parent saga identified by sagaID to
+// status/currentStep/errorMsg in a single SQL transaction, guarded by the
+// same version check as UpdateSagaStatus. This keeps a step row and its
+// saga's version in lockstep: a crash or error between the two writes rolls
+// both back instead of leaving a step committed against a saga that never
+// advanced.
+func (s *Store) SagaStepTx(ctx context.Context, step *models.SagaStep, sagaID int64, currentVersion int, status models.SagaStatus, currentStep models.IngestStep, errorMsg string) (int64, error) {
+	ctx, span := tracer.Start(ctx, "db.SagaStepTx", trace.WithAttributes(attribute.Int64("saga.id", sagaID)))
+	defer span.End()
+
+	stepID, err := s.sagaStepTx(ctx, step, sagaID, currentVersion, status, currentStep, errorMsg)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return stepID, err
+}
+
+// sagaStepTx is SagaStepTx's body, split out so the exported method can wrap
+// the whole transaction -- insert/update plus version bump -- in one span.
+func (s *Store) sagaStepTx(ctx context.Context, step *models.SagaStep, sagaID int64, currentVersion int, status models.SagaStatus, currentStep models.IngestStep, errorMsg string) (int64, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().Unix()
+	var stepID int64
+	if step.ID == 0 {
+		query := s.Dialect.Rewrite(`INSERT INTO saga_steps (saga_id, name, status, attempt_id, metadata, error_log, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`) + s.Dialect.ReturningClause()
+		if s.Dialect.ReturningClause() != "" {
+			if err := tx.QueryRowContext(ctx, query, step.SagaID, step.Name, step.Status, step.AttemptID, step.Metadata, step.ErrorLog, now, now).Scan(&stepID); err != nil {
+				return 0, err
+			}
+		} else {
+			res, err := tx.ExecContext(ctx, query, step.SagaID, step.Name, step.Status, step.AttemptID, step.Metadata, step.ErrorLog, now, now)
+			if err != nil {
+				return 0, err
+			}
+			if stepID, err = res.LastInsertId(); err != nil {
+				return 0, err
+			}
+		}
+	} else {
+		query := s.Dialect.Rewrite(`UPDATE saga_steps SET status = ?, attempt_id = ?, metadata = ?, error_log = ?, updated_at = ? WHERE id = ?`)
+		if _, err := tx.ExecContext(ctx, query, step.Status, step.AttemptID, step.Metadata, step.ErrorLog, now, step.ID); err != nil {
+			return 0, err
+		}
+		stepID = step.ID
+	}
+
+	if err := s.updateSagaVersion(ctx, tx, sagaID, currentVersion, status, currentStep, errorMsg); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return stepID, nil
+}
+
+func (s *Store) UpsertSagaStep(ctx context.Context, step *models.SagaStep) (int64, error) {
+	now := time.Now().Unix()
+	if step.ID == 0 {
+		query := `INSERT INTO saga_steps (saga_id, name, status, attempt_id, metadata, error_log, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+		return s.insert(ctx, query, step.SagaID, step.Name, step.Status, step.AttemptID, step.Metadata, step.ErrorLog, now, now)
+	}
+	query := s.Dialect.Rewrite(`UPDATE saga_steps SET status = ?, attempt_id = ?, metadata = ?, error_log = ?, updated_at = ? WHERE id = ?`)
+	_, err := s.DB.ExecContext(ctx, query, step.Status, step.AttemptID, step.Metadata, step.ErrorLog, now, step.ID)
+	if err != nil {
+		return 0, err
+	}
+	return step.ID, nil
+}
+
+func (s *Store) GetSagaSteps(ctx context.Context, sagaID int64) ([]*models.SagaStep, error) {
+	query := s.Dialect.Rewrite(`SELECT id, saga_id, name, status, attempt_id, metadata, error_log, created_at, updated_at FROM saga_steps WHERE saga_id = ? ORDER BY created_at ASC`)
+	rows, err := s.DB.QueryContext(ctx, query, sagaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var steps []*models.SagaStep
+	for rows.Next() {
+		step := &models.SagaStep{}
+		var createdAt, updatedAt int64
+		if err := rows.Scan(&step.ID, &step.SagaID, &step.Name, &step.Status, &step.AttemptID, &step.Metadata, &step.ErrorLog, &createdAt, &updatedAt); err != nil {
+			return nil, err
+		}
+		step.CreatedAt = time.Unix(createdAt, 0)
+		step.UpdatedAt = time.Unix(updatedAt, 0)
+		steps = append(steps, step)
+	}
+	return steps, rows.Err()
+}
@@ -10,6 +10,36 @@ type SearchResult struct {
 	Content string
 	Score   float32
 	Source  string // "vector", "graph", "tree", etc.
+
+	// Graph carries graph-derived relevance signals for this result's node.
+	// SkylineRanker uses it as its second Pareto axis in place of a
+	// Source=="graph" fudge factor. Populated directly for results that
+	// came from GraphRepository.SearchChunks; imputed via a GraphSignals
+	// batch lookup for results that came from elsewhere (e.g. vector
+	// search), so cross-source comparison is meaningful.
+	Graph GraphSignals
+
+	// Embedding is the result's own dense vector, when the engine that
+	// produced it has one handy (vector search always does; graph search
+	// usually doesn't). It's optional -- nil disables anything that needs
+	// it, such as RRFRanker's near-duplicate merge and MMR diversification
+	// -- rather than required, so implementations that can't cheaply
+	// produce it don't have to.
+	Embedding []float32
+}
+
+// GraphSignals holds graph-derived relevance signals for one chunk node,
+// computed relative to a set of seed entities (DualKeyExtractor.Entities).
+// PageRank is a personalized PageRank over the node's graph, restarting at
+// the nodes matching seedEntities; TraversalDistance is the fewest hops
+// from any seed node to this one (-1 if unreachable or there were no
+// seeds); DegreeCentrality and BetweennessCentrality are computed over the
+// whole graph the node belongs to.
+type GraphSignals struct {
+	PageRank              float32
+	TraversalDistance     int
+	DegreeCentrality      float32
+	BetweennessCentrality float32
 }
 
 // VectorRepository defines the interface for vector database operations.
@@ -17,6 +47,10 @@ type VectorRepository interface {
 	Search(ctx context.Context, vector []float32, limit int) ([]SearchResult, error)
 	InsertChunks(ctx context.Context, docID string, chunks []map[string]any) error
 	DeleteDocument(ctx context.Context, docID string) error
+	// DocumentExists reports whether docID already has vectors stored, so a
+	// caller retrying a possibly-crashed write can tell an attempt that
+	// already landed apart from one that never did.
+	DocumentExists(ctx context.Context, docID string) (bool, error)
 	Close() error
 }
 
@@ -25,5 +59,14 @@ type GraphRepository interface {
 	SearchChunks(ctx context.Context, query string, limit int) ([]SearchResult, error)
 	InsertNodesAndEdges(ctx context.Context, docID string, nodes []map[string]any, edges []map[string]any) error
 	DeleteDocument(ctx context.Context, docID string) error
+	// DocumentExists is VectorRepository.DocumentExists's counterpart for
+	// the graph store.
+	DocumentExists(ctx context.Context, docID string) (bool, error)
 	Close(ctx context.Context) error
+
+	// GraphSignals computes GraphSignals for each of nodeIDs, personalized
+	// around whichever nodes best match seedEntities. Implementations that
+	// can't resolve a node ID to a graph node simply omit it from the
+	// result rather than erroring.
+	GraphSignals(ctx context.Context, seedEntities []string, nodeIDs []string) (map[string]GraphSignals, error)
 }
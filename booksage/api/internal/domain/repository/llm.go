@@ -2,12 +2,42 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 )
 
 // LLMClient defines the interface for generating text from a prompt.
 type LLMClient interface {
 	Generate(ctx context.Context, prompt string) (string, error)
 	Name() string
+	// SupportsStructuredOutput reports whether this client can be safely
+	// asserted to StructuredLLMClient for schema-constrained decoding.
+	SupportsStructuredOutput() bool
+}
+
+// StructuredLLMClient is implemented by LLMClients that can constrain their
+// decoding to a JSON Schema instead of relying on prompt-and-parse.
+// Callers should only assert to this interface when SupportsStructuredOutput
+// returns true.
+type StructuredLLMClient interface {
+	LLMClient
+	GenerateStructured(ctx context.Context, prompt string, schema json.RawMessage) ([]byte, error)
+}
+
+// Token is one incremental chunk of generated text emitted by a
+// StreamingLLMClient.
+type Token struct {
+	Text string
+}
+
+// StreamingLLMClient is implemented by LLMClients that can emit generated
+// text incrementally instead of returning the full response in one shot.
+// Callers type-assert for it on whatever client LLMRouter.RouteLLMTask
+// returns, falling back to a single blocking Generate call when the
+// selected backend doesn't support it -- mirroring how StructuredLLMClient
+// is only asserted to when SupportsStructuredOutput says it's safe.
+type StreamingLLMClient interface {
+	LLMClient
+	GenerateStream(ctx context.Context, prompt string) (<-chan Token, <-chan error)
 }
 
 // TaskType defines the type of LLM task.
@@ -3,96 +3,342 @@ package embedding
 import (
 	"context"
 	"fmt"
-	"log"
+	"math"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/booksage/booksage-api/internal/domain/repository"
 	pb "github.com/booksage/booksage-api/internal/pb/booksage/v1"
+	"github.com/booksage/booksage-api/internal/resilience"
+	"github.com/hashicorp/go-hclog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
-// Batcher handles safely chunking embedding requests to respect memory limits.
+// tracer is package-scoped so every Batcher shares one tracer, matching
+// ingest.SagaOrchestrator's convention.
+var tracer = otel.Tracer("booksage-api/embedding")
+
+// batchFailureThreshold and batchOpenDuration tune how quickly a
+// persistently failing embedding client trips the breaker and how long it
+// stays tripped before a probe is let through. batchRetry* tune the backoff
+// a single batch gets before that failure counts against the threshold,
+// mirroring llm.Router's per-candidate resilience.
+const (
+	batchFailureThreshold = 3
+	batchOpenDuration     = 30 * time.Second
+
+	batchRetryMaxRetries = 2
+	batchRetryBaseDelay  = 50 * time.Millisecond
+	batchRetryCapDelay   = 2 * time.Second
+)
+
+// minTokensPerBatch is the floor budgetBackoff halves down to: a batch of
+// one oversized text still has to go out as its own batch (see packByBudget),
+// so a budget that keeps halving toward zero wouldn't shrink anything
+// further, just spin.
+const minTokensPerBatch = 256
+
+// budgetRestoreSuccesses is how many consecutive clean batches it takes to
+// grow the effective budget back up by budgetRestoreFactor. Restoring
+// immediately after one success would flap the budget right back into the
+// same rate limit or OOM that triggered the last backoff.
+const (
+	budgetRestoreSuccesses = 5
+	budgetRestoreFactor    = 1.5
+)
+
+// avgTokensPerWord approximates the subword-to-word expansion ratio of a
+// real BPE tokenizer (tiktoken and friends typically land around 1.3 tokens
+// per English word), standing in until this package takes on a real
+// tokenizer dependency.
+const avgTokensPerWord = 1.3
+
+// Batcher handles safely chunking embedding requests to respect memory and
+// rate limits. Instead of splitting texts into fixed-size count batches, it
+// packs them greedily against a token budget so small texts share a batch
+// and a handful of very large ones don't.
 type Batcher struct {
-	client    repository.EmbeddingClient
-	batchSize int
+	client               repository.EmbeddingClient
+	maxTokensPerBatch    int
+	maxConcurrentBatches int
+	logger               hclog.Logger
+
+	// breaker and retrier guard every batch's client.Embed call the same
+	// way llm.Router guards a candidate's Generate call: retrier absorbs an
+	// isolated transient error before it's recorded as one success/failure
+	// against breaker, so a sustained embedding-client outage trips the
+	// breaker and fails the rest of the batches fast instead of retrying
+	// each one in turn.
+	breaker *resilience.CircuitBreaker
+	retrier *resilience.Retrier
+
+	// budgetMu guards effectiveBudget and consecutiveSuccesses, the
+	// adaptive-backoff state shared across concurrently running batches: a
+	// rate-limit/OOM-style error from any one of them halves the budget for
+	// every batch dispatched after it, and a run of clean batches grows it
+	// back.
+	budgetMu             sync.Mutex
+	effectiveBudget      int
+	consecutiveSuccesses int
 }
 
-// NewBatcher creates a new embedding batcher.
-func NewBatcher(client repository.EmbeddingClient, batchSize int) *Batcher {
+// NewBatcher creates a new embedding batcher. maxTokensPerBatch bounds how
+// many tokens (by the package's own whitespace-based estimate, see
+// countTokens) a single Embed call is allowed to carry; maxConcurrentBatches
+// bounds how many such calls run at once. logger is named "embed-batcher" so
+// every line it emits is attributable back to this subsystem.
+func NewBatcher(client repository.EmbeddingClient, maxTokensPerBatch, maxConcurrentBatches int, logger hclog.Logger) *Batcher {
 	return &Batcher{
-		client:    client,
-		batchSize: batchSize,
+		client:               client,
+		maxTokensPerBatch:    maxTokensPerBatch,
+		maxConcurrentBatches: maxConcurrentBatches,
+		logger:               logger.Named("embed-batcher"),
+		breaker:              resilience.NewCircuitBreaker(batchFailureThreshold, batchOpenDuration),
+		retrier:              resilience.NewRetrier(batchRetryMaxRetries, batchRetryBaseDelay, batchRetryCapDelay, nil),
+		effectiveBudget:      maxTokensPerBatch,
 	}
 }
 
-// GenerateEmbeddingsBatched splits large text arrays into smaller batches and executes them.
-// Provides concurrent execution while bounding memory limits.
+// tokenBatch is one greedily-packed group of texts, carrying the original
+// indices so results can be reassembled in input order once every batch's
+// goroutine has finished.
+type tokenBatch struct {
+	indices []int
+	texts   []string
+	tokens  int
+}
+
+// GenerateEmbeddingsBatched splits texts into token-budgeted batches and
+// executes them concurrently, bounded by maxConcurrentBatches.
 func (b *Batcher) GenerateEmbeddingsBatched(ctx context.Context, texts []string, embType, taskType string) ([]*pb.EmbeddingResult, int32, error) {
 	if len(texts) == 0 {
 		return nil, 0, nil
 	}
 
-	totalItems := len(texts)
-	numBatches := (totalItems + b.batchSize - 1) / b.batchSize
+	ctx, span := tracer.Start(ctx, "embedding.generate_embeddings_batched")
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int("embedding.texts", len(texts)),
+		attribute.String("embedding.type", embType),
+		attribute.String("embedding.task_type", taskType),
+	)
 
-	log.Printf("[Embedding Batcher] Splitting %d texts into %d batches (max %d/batch)", totalItems, numBatches, b.batchSize)
+	budget := b.currentBudget()
+	batches := packByBudget(texts, budget)
+	span.SetAttributes(
+		attribute.Int("embedding.batches", len(batches)),
+		attribute.Int("embedding.token_budget", budget),
+	)
 
-	results := make([]*pb.EmbeddingResult, totalItems)
+	b.logger.Info("packed texts into token-budgeted batches",
+		"total_items", len(texts), "num_batches", len(batches), "token_budget", budget)
+
+	results := make([]*pb.EmbeddingResult, len(texts))
 	var totalTokens int32
 	var mu sync.Mutex
 
-	// We use an errgroup or WaitGroup to dispatch the batches concurrently
 	var wg sync.WaitGroup
-	errCh := make(chan error, numBatches)
-
-	for i := 0; i < numBatches; i++ {
-		start := i * b.batchSize
-		end := start + b.batchSize
-		if end > totalItems {
-			end = totalItems
-		}
-
-		batchTexts := texts[start:end]
-		batchIndex := i // captured for goroutine
+	sem := make(chan struct{}, b.maxConcurrentBatches)
+	errCh := make(chan error, len(batches))
 
+	for i, batch := range batches {
 		wg.Add(1)
-		go func(pts []string, startIdx int, bIdx int) {
+		sem <- struct{}{} // Acquire semaphore
+		go func(batch tokenBatch, bIdx int) {
 			defer wg.Done()
+			defer func() { <-sem }() // Release semaphore
 
-			// Call local/cloud endpoint directly instead of gRPC
-			embeddings, err := b.client.Embed(ctx, pts)
+			embeddings, err := b.embedWithAdaptiveBackoff(ctx, batch)
 			if err != nil {
-				log.Printf("[Embedding Batcher] Batch %d failed: %v", bIdx, err)
+				b.logger.Error("batch failed", "batch_index", bIdx, "error", err)
 				errCh <- fmt.Errorf("batch %d failed: %w", bIdx, err)
 				return
 			}
 
 			mu.Lock()
-			// Reassemble results based on original indexing
 			for j, vec := range embeddings {
-				results[startIdx+j] = &pb.EmbeddingResult{
-					Text: pts[j],
+				results[batch.indices[j]] = &pb.EmbeddingResult{
+					Text: batch.texts[j],
 					Vector: &pb.EmbeddingResult_Dense{
 						Dense: &pb.DenseVector{Values: vec},
 					},
 				}
 			}
-			// Approximate token count (simplified)
-			totalTokens += int32(len(pts) * 10) // Mock token count
+			totalTokens += int32(batch.tokens)
 			mu.Unlock()
 
-			log.Printf("[Embedding Batcher] Batch %d completed successfully.", bIdx)
-		}(batchTexts, start, batchIndex)
+			b.logger.Debug("batch completed", "batch_index", bIdx, "items", len(batch.texts), "tokens", batch.tokens)
+		}(batch, i)
 	}
 
 	wg.Wait()
 	close(errCh)
 
-	// Determine if any errors occurred during processing
 	for err := range errCh {
 		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
 			return nil, 0, err
 		}
 	}
 
+	span.SetAttributes(attribute.Int("embedding.total_tokens", int(totalTokens)))
 	return results, totalTokens, nil
 }
+
+// embedWithAdaptiveBackoff runs batch through the breaker+retrier pair like
+// a fixed-size batch always did, but additionally watches for a rate-limit
+// or OOM-style error: on one, it halves the shared budget for every batch
+// dispatched after this point and splits batch itself in half, retrying
+// each half independently (recursively, in case a half is still too large).
+// A clean run records a success toward restoring the budget.
+func (b *Batcher) embedWithAdaptiveBackoff(ctx context.Context, batch tokenBatch) ([][]float32, error) {
+	var embeddings [][]float32
+	err := b.breaker.Execute(func() error {
+		return b.retrier.Execute(ctx, func() error {
+			var embedErr error
+			embeddings, embedErr = b.client.Embed(ctx, batch.texts)
+			return embedErr
+		})
+	})
+
+	if err == nil {
+		b.recordSuccess()
+		return embeddings, nil
+	}
+
+	if !isBackoffTriggering(err) || len(batch.texts) <= 1 {
+		return nil, err
+	}
+
+	b.halveBudget()
+	b.logger.Warn("batch hit a rate-limit/OOM-style error, splitting and retrying",
+		"items", len(batch.texts), "error", err)
+
+	mid := len(batch.texts) / 2
+	first, err := b.embedWithAdaptiveBackoff(ctx, subBatch(batch, 0, mid))
+	if err != nil {
+		return nil, err
+	}
+	second, err := b.embedWithAdaptiveBackoff(ctx, subBatch(batch, mid, len(batch.texts)))
+	if err != nil {
+		return nil, err
+	}
+	return append(first, second...), nil
+}
+
+// subBatch returns the [start:end) slice of batch's texts/indices as its
+// own tokenBatch, recomputing its token count since it no longer matches
+// the parent.
+func subBatch(batch tokenBatch, start, end int) tokenBatch {
+	texts := batch.texts[start:end]
+	tokens := 0
+	for _, t := range texts {
+		tokens += countTokens(t)
+	}
+	return tokenBatch{
+		indices: batch.indices[start:end],
+		texts:   texts,
+		tokens:  tokens,
+	}
+}
+
+// currentBudget returns the token budget new batches should be packed
+// against right now.
+func (b *Batcher) currentBudget() int {
+	b.budgetMu.Lock()
+	defer b.budgetMu.Unlock()
+	return b.effectiveBudget
+}
+
+// halveBudget drops the effective budget by half (floored at
+// minTokensPerBatch) and resets the success streak, so a restore doesn't
+// kick in immediately after the backoff that just happened.
+func (b *Batcher) halveBudget() {
+	b.budgetMu.Lock()
+	defer b.budgetMu.Unlock()
+	b.effectiveBudget = max(b.effectiveBudget/2, minTokensPerBatch)
+	b.consecutiveSuccesses = 0
+}
+
+// recordSuccess counts a clean batch toward restoring the budget,
+// multiplying it by budgetRestoreFactor (capped at maxTokensPerBatch) every
+// budgetRestoreSuccesses in a row.
+func (b *Batcher) recordSuccess() {
+	b.budgetMu.Lock()
+	defer b.budgetMu.Unlock()
+
+	if b.effectiveBudget >= b.maxTokensPerBatch {
+		return
+	}
+
+	b.consecutiveSuccesses++
+	if b.consecutiveSuccesses < budgetRestoreSuccesses {
+		return
+	}
+	b.consecutiveSuccesses = 0
+	b.effectiveBudget = min(int(float64(b.effectiveBudget)*budgetRestoreFactor), b.maxTokensPerBatch)
+	b.logger.Info("restoring embedding batch token budget after a run of clean batches", "budget", b.effectiveBudget)
+}
+
+// isBackoffTriggering reports whether err looks like a rate-limit or
+// OOM-style rejection from the embedding backend, as opposed to an
+// ordinary transient failure the retrier already absorbed. The embedding
+// backend doesn't give callers a structured error type to key off of (see
+// resilience.DefaultIsRetryable's doc comment for the same caveat), so this
+// is a best-effort substring match over the error chain's text.
+func isBackoffTriggering(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, marker := range []string{"rate limit", "too many requests", "resource exhausted", "429", "out of memory", "oom"} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// countTokens estimates text's token count as its whitespace-separated
+// word count scaled by avgTokensPerWord, standing in for a real BPE
+// tokenizer (e.g. tiktoken) until this package takes a dependency on one.
+func countTokens(text string) int {
+	words := len(strings.Fields(text))
+	if words == 0 {
+		return 0
+	}
+	return int(math.Ceil(float64(words) * avgTokensPerWord))
+}
+
+// packByBudget greedily packs texts into batches so each batch's total
+// estimated token count stays within budget. A single text that exceeds
+// budget on its own still goes out alone rather than being dropped or
+// truncated.
+func packByBudget(texts []string, budget int) []tokenBatch {
+	var batches []tokenBatch
+	var current tokenBatch
+
+	flush := func() {
+		if len(current.texts) > 0 {
+			batches = append(batches, current)
+			current = tokenBatch{}
+		}
+	}
+
+	for i, text := range texts {
+		tokens := countTokens(text)
+		if len(current.texts) > 0 && current.tokens+tokens > budget {
+			flush()
+		}
+		current.indices = append(current.indices, i)
+		current.texts = append(current.texts, text)
+		current.tokens += tokens
+	}
+	flush()
+
+	return batches
+}
@@ -5,39 +5,52 @@ import (
 	"errors"
 	"testing"
 
-	pb "github.com/booksage/booksage-api/internal/pb/booksage/v1"
-	"google.golang.org/grpc"
+	"github.com/hashicorp/go-hclog"
 )
 
+// mockEmbeddingClient implements repository.EmbeddingClient directly (no
+// gRPC plumbing) so tests can exercise Batcher's packing and adaptive
+// backoff without a live worker connection.
 type mockEmbeddingClient struct {
 	err error
 }
 
-func (m *mockEmbeddingClient) GenerateEmbeddings(ctx context.Context, in *pb.EmbeddingRequest, opts ...grpc.CallOption) (*pb.EmbeddingResponse, error) {
+func (m *mockEmbeddingClient) Embed(ctx context.Context, texts []string) ([][]float32, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
-
-	results := make([]*pb.EmbeddingResult, len(in.Texts))
-	for i, text := range in.Texts {
-		results[i] = &pb.EmbeddingResult{
-			Vector: &pb.EmbeddingResult_Dense{
-				Dense: &pb.DenseVector{
-					Values: []float32{1.0, 2.0, float32(len(text))},
-				},
-			},
-		}
+	vecs := make([][]float32, len(texts))
+	for i, t := range texts {
+		vecs[i] = []float32{1.0, 2.0, float32(len(t))}
 	}
+	return vecs, nil
+}
+
+func (m *mockEmbeddingClient) Name() string { return "mock" }
+
+// rateLimitedEmbeddingClient fails with a rate-limit-flavored error whenever
+// a single Embed call carries more than maxItems texts, so tests can drive
+// embedWithAdaptiveBackoff's split-and-retry path deterministically.
+type rateLimitedEmbeddingClient struct {
+	maxItems int
+}
 
-	return &pb.EmbeddingResponse{
-		Results:     results,
-		TotalTokens: int32(len(in.Texts) * 2), // Mock token count
-	}, nil
+func (m *rateLimitedEmbeddingClient) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) > m.maxItems {
+		return nil, errors.New("429 too many requests")
+	}
+	vecs := make([][]float32, len(texts))
+	for i, t := range texts {
+		vecs[i] = []float32{float32(len(t))}
+	}
+	return vecs, nil
 }
 
+func (m *rateLimitedEmbeddingClient) Name() string { return "rate-limited" }
+
 func TestBatcher_Empty(t *testing.T) {
 	client := &mockEmbeddingClient{}
-	batcher := NewBatcher(client, 2)
+	batcher := NewBatcher(client, 100, 2, hclog.NewNullLogger())
 
 	res, tokens, err := batcher.GenerateEmbeddingsBatched(context.Background(), []string{}, "text", "search")
 	if err != nil {
@@ -53,7 +66,7 @@ func TestBatcher_Empty(t *testing.T) {
 
 func TestBatcher_GenerateEmbeddingsBatched(t *testing.T) {
 	client := &mockEmbeddingClient{}
-	batcher := NewBatcher(client, 2)
+	batcher := NewBatcher(client, 100, 2, hclog.NewNullLogger())
 
 	texts := []string{"one", "two", "three", "four", "five"}
 	res, tokens, err := batcher.GenerateEmbeddingsBatched(context.Background(), texts, "text", "search")
@@ -64,8 +77,13 @@ func TestBatcher_GenerateEmbeddingsBatched(t *testing.T) {
 	if len(res) != 5 {
 		t.Fatalf("Expected 5 results, got %d", len(res))
 	}
-	if tokens != 10 { // 5 texts * 2 tokens/text
-		t.Errorf("Expected 10 total tokens, got %d", tokens)
+
+	var wantTokens int32
+	for _, text := range texts {
+		wantTokens += int32(countTokens(text))
+	}
+	if tokens != wantTokens {
+		t.Errorf("Expected %d true total tokens, got %d", wantTokens, tokens)
 	}
 
 	// Verify order is preserved
@@ -79,7 +97,7 @@ func TestBatcher_GenerateEmbeddingsBatched(t *testing.T) {
 
 func TestBatcher_Error(t *testing.T) {
 	client := &mockEmbeddingClient{err: errors.New("mock error")}
-	batcher := NewBatcher(client, 2)
+	batcher := NewBatcher(client, 100, 2, hclog.NewNullLogger())
 
 	texts := []string{"one", "two"}
 	_, _, err := batcher.GenerateEmbeddingsBatched(context.Background(), texts, "text", "search")
@@ -90,3 +108,98 @@ func TestBatcher_Error(t *testing.T) {
 		t.Errorf("Unexpected error message: %v", err)
 	}
 }
+
+func TestCountTokens(t *testing.T) {
+	cases := map[string]int{
+		"":            0,
+		"one":         2, // ceil(1 * 1.3)
+		"one two":     3, // ceil(2 * 1.3)
+		"a b c d e f": 8, // ceil(6 * 1.3)
+	}
+	for text, want := range cases {
+		if got := countTokens(text); got != want {
+			t.Errorf("countTokens(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
+
+func TestPackByBudget_GreedilyFillsBatches(t *testing.T) {
+	texts := []string{"a", "b", "c", "d"} // 2 tokens each
+	batches := packByBudget(texts, 5)
+
+	if len(batches) != 2 {
+		t.Fatalf("expected 2 batches, got %d: %+v", len(batches), batches)
+	}
+	if len(batches[0].texts) != 2 || batches[0].tokens != 4 {
+		t.Errorf("expected first batch to hold 2 items totalling 4 tokens, got %+v", batches[0])
+	}
+	if len(batches[1].texts) != 2 || batches[1].tokens != 4 {
+		t.Errorf("expected second batch to hold 2 items totalling 4 tokens, got %+v", batches[1])
+	}
+}
+
+func TestPackByBudget_OversizedTextGoesOutAlone(t *testing.T) {
+	big := "a b c d e f g h i j" // 10 words, 13 tokens -- over any small budget
+	texts := []string{big, "x"}
+
+	batches := packByBudget(texts, 5)
+
+	if len(batches) != 2 {
+		t.Fatalf("expected the oversized text to be split into its own batch, got %d batches: %+v", len(batches), batches)
+	}
+	if len(batches[0].texts) != 1 || batches[0].texts[0] != big {
+		t.Errorf("expected the oversized text alone in the first batch, got %+v", batches[0])
+	}
+	if len(batches[1].texts) != 1 || batches[1].texts[0] != "x" {
+		t.Errorf("expected the small text alone in the second batch, got %+v", batches[1])
+	}
+}
+
+func TestBatcher_AdaptiveBackoffSplitsOnRateLimitError(t *testing.T) {
+	client := &rateLimitedEmbeddingClient{maxItems: 1}
+	// A generous token budget packs all 4 texts into a single batch; the
+	// client then rejects anything over 1 item, forcing embedWithAdaptiveBackoff
+	// to halve the budget and recursively split until each sub-batch fits.
+	batcher := NewBatcher(client, 1000, 4, hclog.NewNullLogger())
+
+	texts := []string{"a", "b", "c", "d"}
+	res, _, err := batcher.GenerateEmbeddingsBatched(context.Background(), texts, "text", "search")
+	if err != nil {
+		t.Fatalf("expected the split-and-retry path to recover, got %v", err)
+	}
+	if len(res) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(res))
+	}
+	for i, r := range res {
+		if r == nil {
+			t.Errorf("expected a result at index %d, got nil", i)
+		}
+	}
+
+	if got := batcher.currentBudget(); got >= 1000 {
+		t.Errorf("expected the rate-limit error to have halved the budget below 1000, got %d", got)
+	}
+}
+
+func TestBatcher_AdaptiveBackoffRestoresBudgetAfterCleanRuns(t *testing.T) {
+	client := &rateLimitedEmbeddingClient{maxItems: 1}
+	batcher := NewBatcher(client, 1000, 4, hclog.NewNullLogger())
+
+	// Trip the backoff once.
+	if _, _, err := batcher.GenerateEmbeddingsBatched(context.Background(), []string{"a", "b"}, "text", "search"); err != nil {
+		t.Fatalf("expected recovery via split-and-retry, got %v", err)
+	}
+	halved := batcher.currentBudget()
+
+	// Run enough single-item batches (which the client always accepts) to
+	// cross budgetRestoreSuccesses.
+	for i := 0; i < budgetRestoreSuccesses; i++ {
+		if _, _, err := batcher.GenerateEmbeddingsBatched(context.Background(), []string{"solo"}, "text", "search"); err != nil {
+			t.Fatalf("unexpected error on clean batch %d: %v", i, err)
+		}
+	}
+
+	if got := batcher.currentBudget(); got <= halved {
+		t.Errorf("expected the budget to grow back above %d after %d clean batches, got %d", halved, budgetRestoreSuccesses, got)
+	}
+}
@@ -0,0 +1,248 @@
+package embedding
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/booksage/booksage-api/internal/domain/repository"
+	"golang.org/x/sync/singleflight"
+)
+
+// defaultCacheCapacity bounds NewCachingEmbeddingClient's default LRUCache,
+// so a long-running process that keeps seeing new text doesn't grow its
+// cache without bound. Callers embedding more distinct text than this per
+// process lifetime should pass an explicit, larger (or persistent) Cache.
+const defaultCacheCapacity = 10000
+
+// Cache is CachingEmbeddingClient's pluggable storage backend. Get reports
+// whether a vector was previously stored for key; Set stores one.
+// NewLRUCache is the default in-memory backend; production deployments can
+// swap in Postgres/BadgerDB/Redis by implementing this interface instead.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]float32, bool, error)
+	Set(ctx context.Context, key string, vector []float32) error
+}
+
+// CacheStats are CachingEmbeddingClient's hit/miss counters, snapshotted by
+// Stats for metrics/health reporting.
+type CacheStats struct {
+	Hits   int64
+	Misses int64
+	// MissBytes is the total length, in bytes, of text sent to the
+	// wrapped client on cache misses -- a rough proxy for upstream
+	// embedding cost saved by the cache.
+	MissBytes int64
+}
+
+// CachingEmbeddingClient decorates a repository.EmbeddingClient with a
+// content-addressed cache keyed by sha256(model name, text), so re-embedding
+// the same chunk text -- a document re-processed, or RAPTOR summarizing
+// already-seen text at a higher tree level -- never makes a second upstream
+// call. Concurrent Embed calls that miss on the same text are collapsed into
+// one upstream call via singleflight, so a stampede of identical requests
+// (several ingestion workers racing on the same document) only pays for the
+// miss once.
+type CachingEmbeddingClient struct {
+	client repository.EmbeddingClient
+	cache  Cache
+	group  singleflight.Group
+
+	hits      int64
+	misses    int64
+	missBytes int64
+}
+
+// NewCachingEmbeddingClient wraps client with cache. A nil cache defaults to
+// an in-memory LRU capped at defaultCacheCapacity entries (see NewLRUCache).
+func NewCachingEmbeddingClient(client repository.EmbeddingClient, cache Cache) *CachingEmbeddingClient {
+	if cache == nil {
+		cache = NewLRUCache(defaultCacheCapacity)
+	}
+	return &CachingEmbeddingClient{client: client, cache: cache}
+}
+
+// Name delegates to the wrapped client, so callers can't tell caching is in
+// front of it.
+func (c *CachingEmbeddingClient) Name() string {
+	return c.client.Name()
+}
+
+// Embed looks up each text in the cache, forwards only the misses to the
+// wrapped client as a single batched call -- preserving whatever batching
+// the caller (typically a Batcher) already did, rather than fanning each
+// miss out as its own request -- then reassembles the result in the
+// caller's original order. A miss that fails to embed fails the whole call,
+// matching the wrapped client's own all-or-nothing batch semantics.
+func (c *CachingEmbeddingClient) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	result := make([][]float32, len(texts))
+	keys := make([]string, len(texts))
+	missingIdx := make(map[string][]int) // cache key -> every input index sharing it
+	var missKeys []string                // unique miss keys, first-seen order
+
+	for i, text := range texts {
+		key := c.cacheKey(text)
+		keys[i] = key
+
+		vec, ok, err := c.cache.Get(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("embedding: cache get: %w", err)
+		}
+		if ok {
+			atomic.AddInt64(&c.hits, 1)
+			result[i] = vec
+			continue
+		}
+
+		if _, seen := missingIdx[key]; !seen {
+			missKeys = append(missKeys, key)
+		}
+		missingIdx[key] = append(missingIdx[key], i)
+		atomic.AddInt64(&c.misses, 1)
+		atomic.AddInt64(&c.missBytes, int64(len(text)))
+	}
+
+	if len(missKeys) == 0 {
+		return result, nil
+	}
+
+	missTexts := make([]string, len(missKeys))
+	for i, key := range missKeys {
+		missTexts[i] = texts[missingIdx[key][0]]
+	}
+
+	vecs, err := c.embedBatch(ctx, missKeys, missTexts)
+	if err != nil {
+		return nil, err
+	}
+	for i, key := range missKeys {
+		for _, idx := range missingIdx[key] {
+			result[idx] = vecs[i]
+		}
+	}
+
+	return result, nil
+}
+
+// embedBatch fetches keys/texts' vectors through the singleflight group,
+// keyed by the whole batch, so identical concurrent batches (the common
+// stampede case: several ingestion workers racing on the same document)
+// collapse into a single upstream call instead of one per caller. The
+// winning call also populates the cache before returning.
+//
+// The shared call runs with its cancellation detached from ctx
+// (context.WithoutCancel), since whichever caller happens to be first to
+// enter the singleflight group is otherwise the only one whose context the
+// upstream call honors -- its cancellation would wrongly abort every other
+// concurrent caller waiting on the same batch.
+func (c *CachingEmbeddingClient) embedBatch(ctx context.Context, keys, texts []string) ([][]float32, error) {
+	batchKey := strings.Join(keys, "\x1f")
+
+	v, err, _ := c.group.Do(batchKey, func() (any, error) {
+		vecs, err := c.client.Embed(context.WithoutCancel(ctx), texts)
+		if err != nil {
+			return nil, err
+		}
+		if len(vecs) != len(texts) {
+			return nil, fmt.Errorf("upstream returned %d vectors for %d texts", len(vecs), len(texts))
+		}
+		for i, key := range keys {
+			if err := c.cache.Set(ctx, key, vecs[i]); err != nil {
+				return nil, fmt.Errorf("cache set: %w", err)
+			}
+		}
+		return vecs, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([][]float32), nil
+}
+
+// cacheKey hashes the wrapped client's model name together with text, so
+// changing the configured model invalidates every entry instead of serving
+// stale vectors computed by a different model.
+func (c *CachingEmbeddingClient) cacheKey(text string) string {
+	h := sha256.Sum256([]byte(c.client.Name() + "\x00" + text))
+	return hex.EncodeToString(h[:])
+}
+
+// Stats snapshots the cache's hit/miss counters.
+func (c *CachingEmbeddingClient) Stats() CacheStats {
+	return CacheStats{
+		Hits:      atomic.LoadInt64(&c.hits),
+		Misses:    atomic.LoadInt64(&c.misses),
+		MissBytes: atomic.LoadInt64(&c.missBytes),
+	}
+}
+
+// lruEntry is one LRUCache slot.
+type lruEntry struct {
+	key    string
+	vector []float32
+}
+
+// LRUCache is Cache's default in-memory backend: a bounded
+// least-recently-used map. capacity <= 0 means unbounded; tests that don't
+// care about eviction can use that, but NewCachingEmbeddingClient's
+// nil-cache default always passes a bounded capacity.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+}
+
+// NewLRUCache returns an empty LRUCache holding at most capacity entries
+// (unbounded if capacity <= 0), evicting the least recently used entry once
+// full.
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (l *LRUCache) Get(ctx context.Context, key string) ([]float32, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elem, ok := l.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	l.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry).vector, true, nil
+}
+
+func (l *LRUCache) Set(ctx context.Context, key string, vector []float32) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if elem, ok := l.items[key]; ok {
+		elem.Value.(*lruEntry).vector = vector
+		l.order.MoveToFront(elem)
+		return nil
+	}
+
+	elem := l.order.PushFront(&lruEntry{key: key, vector: vector})
+	l.items[key] = elem
+
+	if l.capacity > 0 && l.order.Len() > l.capacity {
+		if oldest := l.order.Back(); oldest != nil {
+			l.order.Remove(oldest)
+			delete(l.items, oldest.Value.(*lruEntry).key)
+		}
+	}
+	return nil
+}
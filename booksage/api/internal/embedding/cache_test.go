@@ -0,0 +1,157 @@
+package embedding
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// countingEmbeddingClient implements repository.EmbeddingClient, returning a
+// vector derived from each text's length and recording how many times Embed
+// was called with how many texts, so tests can assert on cache behavior
+// without inspecting CachingEmbeddingClient internals.
+type countingEmbeddingClient struct {
+	name  string
+	calls int64
+
+	mu       sync.Mutex
+	texts    []string
+	blockFor chan struct{} // if non-nil, Embed waits on it before returning
+}
+
+func (c *countingEmbeddingClient) Name() string {
+	if c.name != "" {
+		return c.name
+	}
+	return "mock"
+}
+
+func (c *countingEmbeddingClient) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	atomic.AddInt64(&c.calls, 1)
+
+	c.mu.Lock()
+	c.texts = append(c.texts, texts...)
+	c.mu.Unlock()
+
+	if c.blockFor != nil {
+		<-c.blockFor
+	}
+
+	out := make([][]float32, len(texts))
+	for i, t := range texts {
+		out[i] = []float32{float32(len(t))}
+	}
+	return out, nil
+}
+
+func TestCachingEmbeddingClient_PreservesOrderOnPartialMiss(t *testing.T) {
+	upstream := &countingEmbeddingClient{}
+	cache := NewCachingEmbeddingClient(upstream, nil)
+
+	if _, err := cache.Embed(context.Background(), []string{"aa", "bbb"}); err != nil {
+		t.Fatalf("priming Embed: %v", err)
+	}
+
+	vecs, err := cache.Embed(context.Background(), []string{"aa", "new", "bbb"})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if len(vecs) != 3 {
+		t.Fatalf("expected 3 vectors, got %d", len(vecs))
+	}
+	if vecs[0][0] != 2 || vecs[1][0] != 3 || vecs[2][0] != 3 {
+		t.Errorf("expected vectors matching input order [aa new bbb], got %v", vecs)
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 2 {
+		t.Errorf("expected 2 hits (aa, bbb) across both calls, got %d", stats.Hits)
+	}
+	if stats.Misses != 3 {
+		t.Errorf("expected 3 misses (aa, bbb first call, new second call), got %d", stats.Misses)
+	}
+}
+
+func TestCachingEmbeddingClient_CacheHitSkipsUpstream(t *testing.T) {
+	upstream := &countingEmbeddingClient{}
+	cache := NewCachingEmbeddingClient(upstream, nil)
+
+	if _, err := cache.Embed(context.Background(), []string{"hello"}); err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if _, err := cache.Embed(context.Background(), []string{"hello"}); err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+
+	if calls := atomic.LoadInt64(&upstream.calls); calls != 1 {
+		t.Errorf("expected exactly 1 upstream call for a repeated text, got %d", calls)
+	}
+}
+
+func TestCachingEmbeddingClient_ModelNameInvalidatesEntries(t *testing.T) {
+	upstream := &countingEmbeddingClient{name: "model-a"}
+	cache := NewCachingEmbeddingClient(upstream, NewLRUCache(0))
+
+	if _, err := cache.Embed(context.Background(), []string{"hello"}); err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+
+	upstream.name = "model-b"
+	if _, err := cache.Embed(context.Background(), []string{"hello"}); err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+
+	if calls := atomic.LoadInt64(&upstream.calls); calls != 2 {
+		t.Errorf("expected changing the model name to force a second upstream call, got %d calls", calls)
+	}
+}
+
+func TestCachingEmbeddingClient_SingleflightCollapsesConcurrentMisses(t *testing.T) {
+	upstream := &countingEmbeddingClient{blockFor: make(chan struct{})}
+	cache := NewCachingEmbeddingClient(upstream, nil)
+
+	const concurrency = 5
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			if _, err := cache.Embed(context.Background(), []string{"shared"}); err != nil {
+				t.Errorf("Embed: %v", err)
+			}
+		}()
+	}
+
+	close(upstream.blockFor)
+	wg.Wait()
+
+	if calls := atomic.LoadInt64(&upstream.calls); calls != 1 {
+		t.Errorf("expected concurrent identical misses to collapse into 1 upstream call, got %d", calls)
+	}
+}
+
+func TestLRUCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewLRUCache(2)
+	ctx := context.Background()
+
+	_ = cache.Set(ctx, "a", []float32{1})
+	_ = cache.Set(ctx, "b", []float32{2})
+	if _, ok, _ := cache.Get(ctx, "a"); !ok {
+		t.Fatalf("expected \"a\" to still be cached")
+	}
+
+	// "a" was just touched, so "b" is now the least recently used and
+	// should be evicted by adding a third entry.
+	_ = cache.Set(ctx, "c", []float32{3})
+
+	if _, ok, _ := cache.Get(ctx, "b"); ok {
+		t.Errorf("expected \"b\" to have been evicted")
+	}
+	if _, ok, _ := cache.Get(ctx, "a"); !ok {
+		t.Errorf("expected \"a\" to survive eviction")
+	}
+	if _, ok, _ := cache.Get(ctx, "c"); !ok {
+		t.Errorf("expected \"c\" to be cached")
+	}
+}
@@ -0,0 +1,56 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/booksage/booksage-api/internal/domain/repository"
+	pb "github.com/booksage/booksage-api/internal/pb/booksage/v1"
+	"github.com/hashicorp/go-hclog"
+)
+
+// grpcEmbeddingClient adapts the generated pb.EmbeddingServiceClient --
+// a single unary Embed(ctx, *pb.EmbedRequest) call against the Python ML
+// worker -- to repository.EmbeddingClient, the interface Batcher and
+// CachingEmbeddingClient are built against.
+type grpcEmbeddingClient struct {
+	client pb.EmbeddingServiceClient
+}
+
+// NewGRPCEmbeddingClient wraps client as a repository.EmbeddingClient.
+func NewGRPCEmbeddingClient(client pb.EmbeddingServiceClient) repository.EmbeddingClient {
+	return &grpcEmbeddingClient{client: client}
+}
+
+// Embed sends texts to the ML worker in a single Embed call and converts
+// its dense vectors back into [][]float32, preserving input order.
+func (c *grpcEmbeddingClient) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	resp, err := c.client.Embed(ctx, &pb.EmbedRequest{Texts: texts})
+	if err != nil {
+		return nil, fmt.Errorf("ml worker embed: %w", err)
+	}
+	vectors := resp.GetVectors()
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("ml worker returned %d vectors for %d texts", len(vectors), len(texts))
+	}
+	out := make([][]float32, len(vectors))
+	for i, v := range vectors {
+		out[i] = v.GetValues()
+	}
+	return out, nil
+}
+
+// Name identifies this client in logs/metrics/cache keys.
+func (c *grpcEmbeddingClient) Name() string {
+	return "ml-worker"
+}
+
+// NewCachedBatcher wraps mlWorker in a content-addressed CachingEmbeddingClient
+// (cache may be nil to take the default in-memory LRU) and then in a Batcher,
+// so callers get the full embed pipeline -- grpc worker, cache, batching --
+// without wiring each layer by hand. This is the pipeline
+// cmd/booksage-api/main.go runs in production.
+func NewCachedBatcher(mlWorker pb.EmbeddingServiceClient, cache Cache, maxTokensPerBatch, maxConcurrentBatches int, logger hclog.Logger) *Batcher {
+	cached := NewCachingEmbeddingClient(NewGRPCEmbeddingClient(mlWorker), cache)
+	return NewBatcher(cached, maxTokensPerBatch, maxConcurrentBatches, logger)
+}
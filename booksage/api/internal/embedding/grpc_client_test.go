@@ -0,0 +1,43 @@
+package embedding
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/booksage/booksage-api/internal/pb/booksage/v1"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc"
+)
+
+// countingMLWorker implements pb.EmbeddingServiceClient, recording how many
+// times Embed was called so tests can assert the cache in front of it is
+// actually doing its job.
+type countingMLWorker struct {
+	calls int
+}
+
+func (w *countingMLWorker) Embed(ctx context.Context, in *pb.EmbedRequest, opts ...grpc.CallOption) (*pb.EmbedResponse, error) {
+	w.calls++
+	vectors := make([]*pb.DenseVector, len(in.GetTexts()))
+	for i, t := range in.GetTexts() {
+		vectors[i] = &pb.DenseVector{Values: []float32{float32(len(t))}}
+	}
+	return &pb.EmbedResponse{Vectors: vectors}, nil
+}
+
+func TestNewCachedBatcher_CachesAcrossCalls(t *testing.T) {
+	worker := &countingMLWorker{}
+	batcher := NewCachedBatcher(worker, nil, 1000, 1, hclog.NewNullLogger())
+
+	texts := []string{"repeated text"}
+	if _, _, err := batcher.GenerateEmbeddingsBatched(context.Background(), texts, "dense", "retrieval"); err != nil {
+		t.Fatalf("first call failed: %v", err)
+	}
+	if _, _, err := batcher.GenerateEmbeddingsBatched(context.Background(), texts, "dense", "retrieval"); err != nil {
+		t.Fatalf("second call failed: %v", err)
+	}
+
+	if worker.calls != 1 {
+		t.Errorf("expected the ML worker to be called once (second call should hit cache), got %d calls", worker.calls)
+	}
+}
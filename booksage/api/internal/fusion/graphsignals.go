@@ -0,0 +1,266 @@
+package fusion
+
+import "sort"
+
+// GraphSignals holds graph-derived relevance signals for one chunk node,
+// computed relative to a set of seed nodes (the IDs of whichever results
+// the graph engine itself returned for this query). PageRank is a
+// personalized PageRank restarting at the seeds; TraversalDistance is the
+// fewest hops from any seed to this node over the document's reading-order
+// adjacency (-1 if unreachable or there were no seeds); DegreeCentrality
+// and BetweennessCentrality are computed over the whole adjacency graph
+// fetched for the query.
+type GraphSignals struct {
+	PageRank              float32
+	TraversalDistance     int
+	DegreeCentrality      float32
+	BetweennessCentrality float32
+}
+
+// graphRelevanceScore collapses a GraphSignals into the single scalar
+// SkylineRanker compares as its second Pareto axis, weighting personalized
+// PageRank heaviest since it's the signal most directly tied to the
+// query's own seed nodes, then traversal proximity, then the two
+// whole-graph centrality measures.
+func graphRelevanceScore(g GraphSignals) float32 {
+	proximity := float32(0)
+	if g.TraversalDistance >= 0 {
+		proximity = 1 / float32(1+g.TraversalDistance)
+	}
+	return 0.4*g.PageRank + 0.3*proximity + 0.15*g.DegreeCentrality + 0.15*g.BetweennessCentrality
+}
+
+// computeGraphSignals is the shared graph-metrics core attachGraphSignals
+// calls into once it has the query's chunk adjacency loaded; it doesn't
+// know where adj came from. adj maps a node ID to the IDs it has an edge
+// to. known is every node ID actually present in the fetched subgraph,
+// including ones with no edges (an isolated chunk). seeds are the node IDs
+// to restart PageRank/BFS from; targets are the node IDs to score, and any
+// not present in known are omitted from the result.
+func computeGraphSignals(adj map[string][]string, known map[string]struct{}, seeds, targets []string) map[string]GraphSignals {
+	if len(targets) == 0 {
+		return map[string]GraphSignals{}
+	}
+
+	pagerank := personalizedPageRank(adj, known, seeds)
+	distances := multiSourceBFS(adj, seeds)
+	degree := degreeCentrality(adj, known)
+	betweenness := betweennessCentrality(adj, known)
+
+	out := make(map[string]GraphSignals, len(targets))
+	for _, id := range targets {
+		if _, ok := known[id]; !ok {
+			continue
+		}
+		dist, reachable := distances[id]
+		if !reachable {
+			dist = -1
+		}
+		out[id] = GraphSignals{
+			PageRank:              pagerank[id],
+			TraversalDistance:     dist,
+			DegreeCentrality:      degree[id],
+			BetweennessCentrality: betweenness[id],
+		}
+	}
+	return out
+}
+
+// personalizedPageRank runs power iteration with restart concentrated on
+// seeds (uniform restart if there are no seeds), converging when the total
+// per-node change drops below pageRankTolerance or after pageRankMaxIters
+// rounds, whichever comes first.
+const (
+	pageRankDamping   = 0.85
+	pageRankTolerance = 1e-6
+	pageRankMaxIters  = 100
+)
+
+func personalizedPageRank(adj map[string][]string, nodes map[string]struct{}, seeds []string) map[string]float32 {
+	if len(nodes) == 0 {
+		return map[string]float32{}
+	}
+
+	restart := make(map[string]float64)
+	seedSet := make(map[string]struct{})
+	for _, s := range seeds {
+		if _, ok := nodes[s]; ok {
+			seedSet[s] = struct{}{}
+		}
+	}
+	if len(seedSet) > 0 {
+		for s := range seedSet {
+			restart[s] = 1.0 / float64(len(seedSet))
+		}
+	} else {
+		for n := range nodes {
+			restart[n] = 1.0 / float64(len(nodes))
+		}
+	}
+
+	rank := make(map[string]float64, len(nodes))
+	for n := range nodes {
+		rank[n] = restart[n]
+	}
+
+	for iter := 0; iter < pageRankMaxIters; iter++ {
+		next := make(map[string]float64, len(nodes))
+		for n := range nodes {
+			next[n] = (1 - pageRankDamping) * restart[n]
+		}
+		for from, tos := range adj {
+			if len(tos) == 0 {
+				continue
+			}
+			share := pageRankDamping * rank[from] / float64(len(tos))
+			for _, to := range tos {
+				next[to] += share
+			}
+		}
+
+		var delta float64
+		for n := range nodes {
+			d := next[n] - rank[n]
+			if d < 0 {
+				d = -d
+			}
+			delta += d
+		}
+		rank = next
+		if delta < pageRankTolerance {
+			break
+		}
+	}
+
+	out := make(map[string]float32, len(rank))
+	for n, v := range rank {
+		out[n] = float32(v)
+	}
+	return out
+}
+
+// multiSourceBFS returns, for every node reachable from seeds by following
+// an edge, the fewest hops from any seed. A node absent from the result
+// was unreachable.
+func multiSourceBFS(adj map[string][]string, seeds []string) map[string]int {
+	dist := make(map[string]int)
+	queue := make([]string, 0, len(seeds))
+	for _, s := range seeds {
+		if _, seen := dist[s]; seen {
+			continue
+		}
+		dist[s] = 0
+		queue = append(queue, s)
+	}
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, next := range adj[node] {
+			if _, seen := dist[next]; seen {
+				continue
+			}
+			dist[next] = dist[node] + 1
+			queue = append(queue, next)
+		}
+	}
+	return dist
+}
+
+// degreeCentrality is each node's degree (ChunkDocAdjacency's edges are
+// already bidirectional, so in- and out-degree coincide), normalized by
+// the largest possible degree in the graph (n-1).
+func degreeCentrality(adj map[string][]string, nodes map[string]struct{}) map[string]float32 {
+	deg := make(map[string]int, len(nodes))
+	for from, tos := range adj {
+		deg[from] += len(tos)
+	}
+
+	n := len(nodes)
+	maxDeg := float64(n - 1)
+	out := make(map[string]float32, n)
+	for node := range nodes {
+		if maxDeg <= 0 {
+			out[node] = 0
+			continue
+		}
+		out[node] = float32(float64(deg[node]) / maxDeg)
+	}
+	return out
+}
+
+// betweennessCentrality runs Brandes' algorithm (unweighted) to score how
+// often each node sits on a shortest path between two others, normalized
+// into [0, 1]. It's O(V*E), which is fine at the node counts a single
+// document's chunk adjacency produces, but isn't meant for a whole-corpus
+// graph.
+func betweennessCentrality(adj map[string][]string, nodes map[string]struct{}) map[string]float32 {
+	centrality := make(map[string]float64, len(nodes))
+	for n := range nodes {
+		centrality[n] = 0
+	}
+
+	for _, s := range sortedNodeKeys(nodes) {
+		stack := make([]string, 0, len(nodes))
+		pred := make(map[string][]string, len(nodes))
+		sigma := make(map[string]float64, len(nodes))
+		dist := make(map[string]int, len(nodes))
+		for n := range nodes {
+			sigma[n] = 0
+			dist[n] = -1
+		}
+		sigma[s] = 1
+		dist[s] = 0
+
+		queue := []string{s}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			stack = append(stack, v)
+			for _, w := range adj[v] {
+				if dist[w] < 0 {
+					dist[w] = dist[v] + 1
+					queue = append(queue, w)
+				}
+				if dist[w] == dist[v]+1 {
+					sigma[w] += sigma[v]
+					pred[w] = append(pred[w], v)
+				}
+			}
+		}
+
+		delta := make(map[string]float64, len(nodes))
+		for i := len(stack) - 1; i >= 0; i-- {
+			w := stack[i]
+			for _, v := range pred[w] {
+				delta[v] += (sigma[v] / sigma[w]) * (1 + delta[w])
+			}
+			if w != s {
+				centrality[w] += delta[w]
+			}
+		}
+	}
+
+	n := len(nodes)
+	norm := float64(1)
+	if n > 2 {
+		norm = 1 / float64((n-1)*(n-2))
+	}
+	out := make(map[string]float32, n)
+	for node, v := range centrality {
+		out[node] = float32(v * norm)
+	}
+	return out
+}
+
+// sortedNodeKeys gives betweennessCentrality a deterministic iteration
+// order over the node set, since Brandes' algorithm's result doesn't
+// depend on it but test output stability does.
+func sortedNodeKeys(nodes map[string]struct{}) []string {
+	keys := make([]string, 0, len(nodes))
+	for n := range nodes {
+		keys = append(keys, n)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,6 +1,19 @@
 package fusion
 
-import "strings"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/booksage/booksage-api/internal/embedding"
+	"github.com/booksage/booksage-api/internal/llm"
+)
 
 // QueryIntent categorizes the cognitive nature of a user query.
 type QueryIntent string
@@ -13,26 +26,60 @@ const (
 	IntentGeneral      QueryIntent = "general"
 )
 
-// IntentClassifier categorizes user queries using keyword heuristics.
-// In production this could be replaced with an LLM-based classifier.
-type IntentClassifier struct{}
+// Classifier assigns a QueryIntent to a user query, plus a confidence score
+// per intent (summing to ~1) that RouteOperator.GetBlendedWeights uses to
+// blend engine weights for ambiguous queries instead of snapping to a
+// single bucket. FusionRetriever calls it once per Retrieve.
+type Classifier interface {
+	Classify(ctx context.Context, query string) (QueryIntent, map[QueryIntent]float32, error)
+}
+
+// allIntents lists every QueryIntent a Classifier can return, used to seed
+// a zeroed confidence map before filling in the one(s) a classifier is
+// actually confident about.
+var allIntents = []QueryIntent{IntentSummary, IntentDefinition, IntentRelationship, IntentComparison, IntentGeneral}
+
+// zeroedIntentScores returns a confidence map with every QueryIntent
+// present at 0, so a Classifier only has to set the intent(s) it actually
+// scored rather than populate the whole map by hand.
+func zeroedIntentScores() map[QueryIntent]float32 {
+	scores := make(map[QueryIntent]float32, len(allIntents))
+	for _, intent := range allIntents {
+		scores[intent] = 0
+	}
+	return scores
+}
+
+// KeywordIntentClassifier is the original keyword-heuristic classifier.
+// LearnedIntentClassifier keeps it around as a fallback for when embedding
+// the query isn't possible at all (no embedder configured, backend down),
+// so Retrieve always gets some intent rather than erroring. Its confidence
+// is always 1 for the matched intent and 0 elsewhere, since keyword
+// matching doesn't have a notion of degrees of confidence.
+type KeywordIntentClassifier struct{}
 
-// Classify determines the intent of a query using keyword matching.
-func (c *IntentClassifier) Classify(query string) QueryIntent {
+// Classify determines the intent of a query using keyword matching. It
+// never errors.
+func (c *KeywordIntentClassifier) Classify(_ context.Context, query string) (QueryIntent, map[QueryIntent]float32, error) {
 	q := strings.ToLower(query)
 
+	var intent QueryIntent
 	switch {
 	case containsAny(q, "summary", "summarize", "overview", "about"):
-		return IntentSummary
+		intent = IntentSummary
 	case containsAny(q, "definition", "define", "what is", "meaning"):
-		return IntentDefinition
+		intent = IntentDefinition
 	case containsAny(q, "relationship", "connect", "between", "how does"):
-		return IntentRelationship
+		intent = IntentRelationship
 	case containsAny(q, "compare", "difference", "vs", "versus"):
-		return IntentComparison
+		intent = IntentComparison
 	default:
-		return IntentGeneral
+		intent = IntentGeneral
 	}
+
+	scores := zeroedIntentScores()
+	scores[intent] = 1
+	return intent, scores, nil
 }
 
 // containsAny checks if s contains any of the given substrings.
@@ -45,51 +92,715 @@ func containsAny(s string, subs ...string) bool {
 	return false
 }
 
+// intentMarginThreshold is the minimum cosine-similarity gap between a
+// query's nearest and second-nearest prototype centroid for
+// LearnedIntentClassifier to trust the embedding tier's top-1 pick. Below
+// it, the margin is too thin to be confident, so Classify pays for an LLM
+// call instead of guessing.
+const intentMarginThreshold = 0.05
+
+// intentPrototype is one labeled example query LearnedIntentClassifier
+// embeds up front to seed its per-intent centroids, before any feedback
+// has arrived to refine them.
+type intentPrototype struct {
+	query  string
+	intent QueryIntent
+}
+
+// defaultIntentPrototypes mirror the keyword groups KeywordIntentClassifier
+// matched on, so the embedding tier starts out roughly where the heuristic
+// it replaces did.
+var defaultIntentPrototypes = []intentPrototype{
+	{"Summarize this chapter for me", IntentSummary},
+	{"Give me an overview of this book", IntentSummary},
+	{"What is this section about", IntentSummary},
+	{"What is the definition of entropy", IntentDefinition},
+	{"Define recursion", IntentDefinition},
+	{"What does this term mean", IntentDefinition},
+	{"How does character A relate to character B", IntentRelationship},
+	{"What is the connection between these two events", IntentRelationship},
+	{"How does X affect Y", IntentRelationship},
+	{"Compare these two approaches", IntentComparison},
+	{"What is the difference between A and B", IntentComparison},
+	{"A versus B", IntentComparison},
+}
+
+// LearnedIntentClassifier is a two-tier QueryIntent classifier: it embeds
+// the query via embedder and picks the nearest labeled-prototype centroid
+// (cheap, no LLM call), falling back to an LLM classification routed
+// through llm.TaskSimpleKeywordExtraction only when the embedding tier's
+// top-1 margin over the runner-up is too thin to trust.
+type LearnedIntentClassifier struct {
+	embedder *embedding.Batcher
+	router   *llm.Router
+	fallback Classifier
+
+	mu        sync.RWMutex
+	centroids map[QueryIntent][]float32
+}
+
+// NewLearnedIntentClassifier creates a classifier that embeds via embedder
+// and falls back to an LLM routed through router. Either may be nil; a nil
+// embedder makes Classify defer to the keyword fallback unconditionally, and
+// a nil router just means a thin-margin query keeps its embedding-tier pick
+// instead of consulting an LLM.
+func NewLearnedIntentClassifier(embedder *embedding.Batcher, router *llm.Router) *LearnedIntentClassifier {
+	return &LearnedIntentClassifier{
+		embedder: embedder,
+		router:   router,
+		fallback: &KeywordIntentClassifier{},
+	}
+}
+
+// Classify embeds query, finds its nearest prototype centroid, and returns
+// that centroid's intent (plus a softmax confidence distribution over all
+// centroids) if the margin over the runner-up is confident enough;
+// otherwise it asks an LLM to classify directly. Any failure along the way
+// (no embedder, embedding error, centroid seeding error) falls back to
+// keyword matching rather than blocking retrieval on a classifier tier. It
+// never returns an error itself: every failure mode degrades to a cheaper
+// tier instead.
+func (c *LearnedIntentClassifier) Classify(ctx context.Context, query string) (QueryIntent, map[QueryIntent]float32, error) {
+	if c.embedder == nil {
+		return c.fallback.Classify(ctx, query)
+	}
+
+	if err := c.ensureCentroids(ctx); err != nil {
+		log.Printf("[Fusion] Intent classifier: failed to seed centroids, falling back to keyword match: %v", err)
+		return c.fallback.Classify(ctx, query)
+	}
+
+	vec, err := c.embedQuery(ctx, query)
+	if err != nil {
+		log.Printf("[Fusion] Intent classifier: failed to embed query, falling back to keyword match: %v", err)
+		return c.fallback.Classify(ctx, query)
+	}
+
+	best, scores, margin := c.nearestCentroid(vec)
+	if margin >= intentMarginThreshold {
+		return best, scores, nil
+	}
+
+	log.Printf("[Fusion] Intent classifier: top-1 margin %.3f below threshold %.3f for %q, consulting LLM", margin, intentMarginThreshold, query)
+	if intent, ok := c.classifyWithLLM(ctx, query); ok {
+		llmScores := zeroedIntentScores()
+		llmScores[intent] = 1
+		return intent, llmScores, nil
+	}
+	return best, scores, nil
+}
+
+// ensureCentroids embeds defaultIntentPrototypes and averages each intent's
+// vectors into a centroid, the first time Classify is called. Later calls
+// reuse the cached result without re-embedding. A seeding failure is not
+// cached: a transient embedder outage at startup shouldn't lock Classify
+// into the keyword fallback for the rest of the process's life, so the next
+// call just tries seedCentroids again.
+func (c *LearnedIntentClassifier) ensureCentroids(ctx context.Context) error {
+	c.mu.RLock()
+	seeded := c.centroids != nil
+	c.mu.RUnlock()
+	if seeded {
+		return nil
+	}
+	return c.seedCentroids(ctx)
+}
+
+func (c *LearnedIntentClassifier) seedCentroids(ctx context.Context) error {
+	texts := make([]string, len(defaultIntentPrototypes))
+	for i, p := range defaultIntentPrototypes {
+		texts[i] = p.query
+	}
+	results, _, err := c.embedder.GenerateEmbeddingsBatched(ctx, texts, "dense", "query")
+	if err != nil {
+		return fmt.Errorf("embedding intent prototypes: %w", err)
+	}
+
+	sums := map[QueryIntent][]float32{}
+	counts := map[QueryIntent]int{}
+	for i, r := range results {
+		if r == nil || r.GetDense() == nil {
+			continue
+		}
+		intent := defaultIntentPrototypes[i].intent
+		sums[intent] = addVectors(sums[intent], r.GetDense().GetValues())
+		counts[intent]++
+	}
+
+	centroids := make(map[QueryIntent][]float32, len(sums))
+	for intent, sum := range sums {
+		if counts[intent] == 0 {
+			continue
+		}
+		centroids[intent] = scaleVector(sum, 1/float32(counts[intent]))
+	}
+
+	c.mu.Lock()
+	c.centroids = centroids
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *LearnedIntentClassifier) embedQuery(ctx context.Context, query string) ([]float32, error) {
+	results, _, err := c.embedder.GenerateEmbeddingsBatched(ctx, []string{query}, "dense", "query")
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 || results[0].GetDense() == nil {
+		return nil, fmt.Errorf("no embedding returned for query")
+	}
+	return results[0].GetDense().GetValues(), nil
+}
+
+// nearestCentroid returns the intent whose centroid is most cosine-similar
+// to vec, a softmax confidence distribution derived from every centroid's
+// similarity to vec, and the margin between the top similarity and the
+// runner-up's (0 if there's no runner-up to compare against).
+func (c *LearnedIntentClassifier) nearestCentroid(vec []float32) (QueryIntent, map[QueryIntent]float32, float32) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	type scored struct {
+		intent QueryIntent
+		sim    float32
+	}
+	ranked := make([]scored, 0, len(c.centroids))
+	sims := make(map[QueryIntent]float32, len(c.centroids))
+	for intent, centroid := range c.centroids {
+		sim := cosineSimilarity(vec, centroid)
+		ranked = append(ranked, scored{intent, sim})
+		sims[intent] = sim
+	}
+	if len(ranked) == 0 {
+		scores := zeroedIntentScores()
+		scores[IntentGeneral] = 1
+		return IntentGeneral, scores, 0
+	}
+	sort.Slice(ranked, func(i, j int) bool { return ranked[i].sim > ranked[j].sim })
+
+	margin := ranked[0].sim
+	if len(ranked) > 1 {
+		margin -= ranked[1].sim
+	}
+	return ranked[0].intent, softmaxIntentScores(sims), margin
+}
+
+// softmaxIntentScores turns a map of per-intent cosine similarities into a
+// confidence distribution that sums to 1: a clear winner ends up near 1,
+// while a close race stays split across its contenders. Missing intents
+// (e.g. a prototype failed to embed) are left at 0 rather than omitted, so
+// callers can always range over the full allIntents set.
+func softmaxIntentScores(sims map[QueryIntent]float32) map[QueryIntent]float32 {
+	scores := zeroedIntentScores()
+	if len(sims) == 0 {
+		return scores
+	}
+
+	maxSim := float32(math.Inf(-1))
+	for _, sim := range sims {
+		if sim > maxSim {
+			maxSim = sim
+		}
+	}
+
+	exps := make(map[QueryIntent]float32, len(sims))
+	var sum float32
+	for intent, sim := range sims {
+		e := float32(math.Exp(float64(sim - maxSim)))
+		exps[intent] = e
+		sum += e
+	}
+	for intent, e := range exps {
+		scores[intent] = e / sum
+	}
+	return scores
+}
+
+// classifyWithLLM asks the client routed for llm.TaskSimpleKeywordExtraction
+// to pick one of the five QueryIntent labels directly. It reports ok=false
+// if no router is configured, the call fails, or the response doesn't name
+// a recognized intent.
+func (c *LearnedIntentClassifier) classifyWithLLM(ctx context.Context, query string) (QueryIntent, bool) {
+	if c.router == nil {
+		return "", false
+	}
+	client := c.router.RouteLLMTask(ctx, llm.TaskSimpleKeywordExtraction)
+	prompt := fmt.Sprintf(`Classify the following query into exactly one of: summary, definition, relationship, comparison, general.
+Respond with only the single matching word, nothing else.
+
+Query: %s`, query)
+
+	resp, err := client.Generate(ctx, prompt)
+	if err != nil {
+		log.Printf("[Fusion] Intent classifier: LLM fallback failed: %v", err)
+		return "", false
+	}
+
+	resp = strings.ToLower(strings.TrimSpace(resp))
+	for _, intent := range []QueryIntent{IntentSummary, IntentDefinition, IntentRelationship, IntentComparison, IntentGeneral} {
+		if strings.Contains(resp, string(intent)) {
+			return intent, true
+		}
+	}
+	return "", false
+}
+
+// llmIntentCacheTTL bounds how long LLMClassifier trusts a cached
+// classification for a given query before asking the LLM again. Repeated
+// queries within a session (a user refining the same question, a retry
+// after a timeout) are common enough to be worth short-lived caching, but
+// long enough staleness would mean a query classified once during an
+// earlier conversation keeps stale weights for a later, unrelated one.
+const llmIntentCacheTTL = 5 * time.Minute
+
+// llmIntentCacheEntry is one cached LLMClassifier result, expiring expiresAt.
+type llmIntentCacheEntry struct {
+	intent    QueryIntent
+	scores    map[QueryIntent]float32
+	expiresAt time.Time
+}
+
+// LLMClassifier classifies query intent purely via an LLM call routed
+// through llm.TaskSimpleKeywordExtraction (the same cheap local tier
+// LearnedIntentClassifier's fallback uses), prompting for a per-intent
+// confidence score rather than a single label. The base llm.LLMClient
+// interface has no structured-output support, so the prompt always asks for
+// a JSON object as plain text; when the routed backend also implements
+// StructuredLLMClient (currently just LocalOllamaClient, via Ollama's native
+// "format" field), Classify additionally constrains decoding to
+// llmIntentSchema instead of trusting the model to follow the wording
+// unprompted. Either way the response is parsed by parseLLMIntentScores.
+// Results are cached in-process for llmIntentCacheTTL, keyed by the
+// normalized query, so a burst of near-duplicate queries doesn't re-pay the
+// LLM call each time.
+type LLMClassifier struct {
+	router *llm.Router
+
+	mu    sync.Mutex
+	cache map[string]llmIntentCacheEntry
+}
+
+// NewLLMClassifier creates a classifier that routes every query through
+// router. router may not be nil; unlike LearnedIntentClassifier, this type
+// has no cheaper tier to fall back to, so a nil router would make every
+// Classify call fail.
+func NewLLMClassifier(router *llm.Router) *LLMClassifier {
+	return &LLMClassifier{
+		router: router,
+		cache:  make(map[string]llmIntentCacheEntry),
+	}
+}
+
+// llmIntentPrompt asks the model for a JSON object mapping each of the five
+// QueryIntent labels to a confidence between 0 and 1. Confidences don't
+// need to sum to 1 on the wire; normalizeScores below takes care of that,
+// so the prompt doesn't need to police the model's arithmetic.
+const llmIntentPrompt = `Classify the following query's intent. Score your confidence (0 to 1) that the query matches each of these intents:
+- summary: asks for an overview or summary of something
+- definition: asks what a term or concept means
+- relationship: asks how two things connect or relate
+- comparison: asks to compare or contrast two or more things
+- general: none of the above, or a broad/open-ended question
+
+Respond with only a JSON object of the form {"summary": <score>, "definition": <score>, "relationship": <score>, "comparison": <score>, "general": <score>}, nothing else.
+
+Query: %s`
+
+// llmIntentSchema constrains llmIntentPrompt's response to exactly the
+// object shape parseLLMIntentScores expects, for backends that support it
+// (see StructuredLLMClient below) -- the free-text prompt wording above still
+// applies, this just removes the model's latitude to wrap the object in
+// prose or markdown fencing.
+var llmIntentSchema = json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"summary": {"type": "number"},
+		"definition": {"type": "number"},
+		"relationship": {"type": "number"},
+		"comparison": {"type": "number"},
+		"general": {"type": "number"}
+	},
+	"required": ["summary", "definition", "relationship", "comparison", "general"]
+}`)
+
+// StructuredLLMClient is implemented by llm.LLMClient backends that can
+// constrain their output to a JSON schema instead of free text.
+// LLMClassifier type-asserts for it on whatever client the Router selects,
+// falling back to llmIntentPrompt's plain-text-and-parse approach when a
+// backend doesn't support it, mirroring agent.StreamingLLMClient's capability
+// type-assertion pattern.
+type StructuredLLMClient interface {
+	GenerateStructured(ctx context.Context, prompt string, schema json.RawMessage) (string, error)
+}
+
+// Classify asks the LLM routed for llm.TaskSimpleKeywordExtraction to score
+// every intent for query, caching the result for llmIntentCacheTTL. It
+// returns an error if no router is configured, the call fails, or the
+// response can't be parsed as the expected JSON object.
+func (c *LLMClassifier) Classify(ctx context.Context, query string) (QueryIntent, map[QueryIntent]float32, error) {
+	key := normalizeIntentCacheKey(query)
+	if intent, scores, ok := c.cached(key); ok {
+		return intent, scores, nil
+	}
+
+	if c.router == nil {
+		return "", nil, fmt.Errorf("no LLM router configured")
+	}
+
+	client := c.router.RouteLLMTask(ctx, llm.TaskSimpleKeywordExtraction)
+	resp, err := c.generate(ctx, client, query)
+	if err != nil {
+		return "", nil, fmt.Errorf("LLM intent classification: %w", err)
+	}
+
+	scores, err := parseLLMIntentScores(resp)
+	if err != nil {
+		return "", nil, fmt.Errorf("LLM intent classification: %w", err)
+	}
+
+	best := bestIntent(scores)
+	c.store(key, best, scores)
+	return best, scores, nil
+}
+
+// generate calls GenerateStructured with llmIntentSchema when client (or, for
+// an *llm.LLMClientChain, its primary candidate) supports it, falling back to
+// a plain Generate call otherwise.
+func (c *LLMClassifier) generate(ctx context.Context, client llm.LLMClient, query string) (string, error) {
+	chain, isChain := client.(*llm.LLMClientChain)
+	target := client
+	if isChain {
+		target = chain.Primary()
+	}
+
+	structured, ok := target.(StructuredLLMClient)
+	if !ok {
+		return client.Generate(ctx, fmt.Sprintf(llmIntentPrompt, query))
+	}
+
+	resp, err := structured.GenerateStructured(ctx, fmt.Sprintf(llmIntentPrompt, query), llmIntentSchema)
+	if err != nil && isChain {
+		chain.RecordPrimaryFailure()
+		return chain.Rest().Generate(ctx, fmt.Sprintf(llmIntentPrompt, query))
+	}
+	return resp, err
+}
+
+func (c *LLMClassifier) cached(key string) (QueryIntent, map[QueryIntent]float32, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.cache[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return "", nil, false
+	}
+	return entry.intent, entry.scores, true
+}
+
+func (c *LLMClassifier) store(key string, intent QueryIntent, scores map[QueryIntent]float32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evictExpiredLocked()
+	c.cache[key] = llmIntentCacheEntry{intent: intent, scores: scores, expiresAt: time.Now().Add(llmIntentCacheTTL)}
+}
+
+// evictExpiredLocked sweeps out every cache entry whose TTL has already
+// elapsed. Unlike embedding.LRUCache's capacity bound, this cache is sized
+// by recency (llmIntentCacheTTL) rather than a fixed entry count, so it's
+// swept opportunistically on every write instead of evicting on insert --
+// without this, a long-running process classifying a high-cardinality
+// stream of distinct queries would grow the map forever, since expired
+// entries are otherwise only noticed (and never removed) on lookup.
+func (c *LLMClassifier) evictExpiredLocked() {
+	now := time.Now()
+	for key, entry := range c.cache {
+		if now.After(entry.expiresAt) {
+			delete(c.cache, key)
+		}
+	}
+}
+
+// normalizeIntentCacheKey folds a query down to the form LLMClassifier
+// caches on, so trivial whitespace/casing differences between otherwise
+// identical queries still hit the cache.
+func normalizeIntentCacheKey(query string) string {
+	return strings.ToLower(strings.TrimSpace(query))
+}
+
+// parseLLMIntentScores decodes resp as a JSON object of intent->confidence,
+// tolerating surrounding prose by extracting the outermost {...} span (LLMs
+// asked for "only JSON" still sometimes wrap it in a sentence or code
+// fence). Unrecognized keys are ignored; recognized ones are clamped to
+// [0, 1] and normalized to sum to 1.
+func parseLLMIntentScores(resp string) (map[QueryIntent]float32, error) {
+	start := strings.Index(resp, "{")
+	end := strings.LastIndex(resp, "}")
+	if start == -1 || end == -1 || end < start {
+		return nil, fmt.Errorf("no JSON object found in response: %q", resp)
+	}
+
+	var raw map[string]float32
+	if err := json.Unmarshal([]byte(resp[start:end+1]), &raw); err != nil {
+		return nil, fmt.Errorf("decoding response %q: %w", resp, err)
+	}
+
+	scores := zeroedIntentScores()
+	for _, intent := range allIntents {
+		v, ok := raw[string(intent)]
+		if !ok {
+			continue
+		}
+		if v < 0 {
+			v = 0
+		}
+		if v > 1 {
+			v = 1
+		}
+		scores[intent] = v
+	}
+	normalizeIntentScores(scores)
+	return scores, nil
+}
+
+// normalizeIntentScores divides every score by their sum so they read as a
+// probability distribution, matching normalizeWeights' behavior for
+// EngineWeights. A response where nothing scored above 0 (an uncooperative
+// model) is left as all-zero rather than divided by zero.
+func normalizeIntentScores(scores map[QueryIntent]float32) {
+	var total float32
+	for _, v := range scores {
+		total += v
+	}
+	if total == 0 {
+		return
+	}
+	for intent, v := range scores {
+		scores[intent] = v / total
+	}
+}
+
+// bestIntent returns the intent with the highest score, breaking ties by
+// allIntents' order so the result is deterministic. If every intent scored
+// 0 (the LLM returned an all-zero or entirely unrecognized response), it
+// reports IntentGeneral rather than letting the tie-break pick an arbitrary
+// intent that scored no better than the rest -- the same intent
+// GetBlendedWeights falls back to for an all-zero scores map.
+func bestIntent(scores map[QueryIntent]float32) QueryIntent {
+	best := allIntents[0]
+	bestScore := scores[best]
+	for _, intent := range allIntents[1:] {
+		if scores[intent] > bestScore {
+			best = intent
+			bestScore = scores[intent]
+		}
+	}
+	if bestScore == 0 {
+		return IntentGeneral
+	}
+	return best
+}
+
+func addVectors(sum, v []float32) []float32 {
+	if sum == nil {
+		out := make([]float32, len(v))
+		copy(out, v)
+		return out
+	}
+	for i := range sum {
+		if i < len(v) {
+			sum[i] += v[i]
+		}
+	}
+	return sum
+}
+
+func scaleVector(v []float32, factor float32) []float32 {
+	out := make([]float32, len(v))
+	for i, x := range v {
+		out[i] = x * factor
+	}
+	return out
+}
+
+func cosineSimilarity(a, b []float32) float32 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return float32(dot / (math.Sqrt(normA) * math.Sqrt(normB)))
+}
+
 // EngineWeights maps engine source names to their retrieval weights.
 type EngineWeights map[string]float32
 
 // RouteOperator provides intent-driven engine weights for fusion retrieval.
+// It holds two weight tables: fixedWeights is the hard-coded baseline, and
+// learnedWeights is the one UpdateWeights nudges from feedback signals.
+// GetWeights serves whichever table useLearned selects, so an operator can
+// A/B the learned weights against the fixed baseline without restarting.
 type RouteOperator struct {
-	weights map[QueryIntent]EngineWeights
+	mu             sync.RWMutex
+	fixedWeights   map[QueryIntent]EngineWeights
+	learnedWeights map[QueryIntent]EngineWeights
+	useLearned     bool
 }
 
 // NewRouteOperator creates a RouteOperator with default weight mappings.
+// learnedWeights starts as a copy of the same defaults, so toggling
+// SetUseLearnedWeights(true) before any feedback has been processed is a
+// no-op rather than a jump to an empty table.
 func NewRouteOperator() *RouteOperator {
+	fixed := defaultEngineWeights()
 	return &RouteOperator{
-		weights: map[QueryIntent]EngineWeights{
-			IntentSummary: {
-				"graph":  0.20,
-				"tree":   0.70,
-				"vector": 0.10,
-			},
-			IntentDefinition: {
-				"graph":  0.20,
-				"tree":   0.10,
-				"vector": 0.70,
-			},
-			IntentRelationship: {
-				"graph":  0.70,
-				"tree":   0.10,
-				"vector": 0.20,
-			},
-			IntentComparison: {
-				"graph":  0.40,
-				"tree":   0.40,
-				"vector": 0.20,
-			},
-			IntentGeneral: {
-				"graph":  0.34,
-				"tree":   0.33,
-				"vector": 0.33,
-			},
+		fixedWeights:   fixed,
+		learnedWeights: cloneWeightTable(fixed),
+	}
+}
+
+func defaultEngineWeights() map[QueryIntent]EngineWeights {
+	return map[QueryIntent]EngineWeights{
+		IntentSummary: {
+			"graph":  0.20,
+			"tree":   0.70,
+			"vector": 0.10,
+		},
+		IntentDefinition: {
+			"graph":  0.20,
+			"tree":   0.10,
+			"vector": 0.70,
+		},
+		IntentRelationship: {
+			"graph":  0.70,
+			"tree":   0.10,
+			"vector": 0.20,
+		},
+		IntentComparison: {
+			"graph":  0.40,
+			"tree":   0.40,
+			"vector": 0.20,
 		},
+		IntentGeneral: {
+			"graph":  0.34,
+			"tree":   0.33,
+			"vector": 0.33,
+		},
+	}
+}
+
+func cloneWeightTable(table map[QueryIntent]EngineWeights) map[QueryIntent]EngineWeights {
+	out := make(map[QueryIntent]EngineWeights, len(table))
+	for intent, weights := range table {
+		out[intent] = cloneWeights(weights)
 	}
+	return out
 }
 
-// GetWeights returns the engine weights for a given intent.
+func cloneWeights(weights EngineWeights) EngineWeights {
+	clone := make(EngineWeights, len(weights))
+	for source, w := range weights {
+		clone[source] = w
+	}
+	return clone
+}
+
+// GetWeights returns the engine weights for a given intent, from the fixed
+// or learned table depending on the last SetUseLearnedWeights call.
 func (r *RouteOperator) GetWeights(intent QueryIntent) EngineWeights {
-	if w, ok := r.weights[intent]; ok {
-		return w
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	table := r.fixedWeights
+	if r.useLearned {
+		table = r.learnedWeights
+	}
+	w, ok := table[intent]
+	if !ok {
+		w = table[IntentGeneral]
+	}
+	// Copy out from under the lock: UpdateWeights mutates learnedWeights'
+	// maps in place, and callers hold onto the returned EngineWeights well
+	// past this call (Retrieve reads it again inside performWeightedRRF).
+	out := make(EngineWeights, len(w))
+	for source, weight := range w {
+		out[source] = weight
+	}
+	return out
+}
+
+// GetBlendedWeights blends GetWeights' per-intent engine weights by scores,
+// a Classifier's confidence distribution, instead of committing to a
+// single intent's table. A query the classifier is genuinely unsure about
+// (e.g. 50/50 between summary and definition) ends up with engine weights
+// halfway between those two intents' tables rather than snapping to
+// whichever edged out the other. Intents missing from scores are treated
+// as 0. The result is normalized so it sums to 1 like a single intent's
+// table does.
+func (r *RouteOperator) GetBlendedWeights(scores map[QueryIntent]float32) EngineWeights {
+	blended := make(EngineWeights)
+	for intent, score := range scores {
+		if score == 0 {
+			continue
+		}
+		for source, w := range r.GetWeights(intent) {
+			blended[source] += w * score
+		}
+	}
+	if len(blended) == 0 {
+		return r.GetWeights(IntentGeneral)
+	}
+	normalizeWeights(blended)
+	return blended
+}
+
+// SetUseLearnedWeights flips the A/B toggle between RouteOperator's
+// hard-coded fixed weights and the learned table UpdateWeights maintains,
+// so an operator can compare the two live without restarting the process.
+func (r *RouteOperator) SetUseLearnedWeights(use bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.useLearned = use
+}
+
+// UpdateWeights nudges intent's learned weight for source by delta (which
+// may be negative), clamping at zero and renormalizing so the intent's
+// weights still sum to 1. It only ever touches learnedWeights; fixedWeights
+// stays the untouched baseline GetWeights falls back to when the A/B toggle
+// is off.
+func (r *RouteOperator) UpdateWeights(intent QueryIntent, source string, delta float32) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w, ok := r.learnedWeights[intent]
+	if !ok {
+		w = cloneWeights(r.fixedWeights[IntentGeneral])
+	}
+
+	w[source] += delta
+	if w[source] < 0 {
+		w[source] = 0
+	}
+	normalizeWeights(w)
+	r.learnedWeights[intent] = w
+}
+
+func normalizeWeights(w EngineWeights) {
+	var total float32
+	for _, v := range w {
+		total += v
+	}
+	if total == 0 {
+		return
+	}
+	for source, v := range w {
+		w[source] = v / total
 	}
-	return r.weights[IntentGeneral]
 }
@@ -0,0 +1,151 @@
+package fusion
+
+import (
+	"context"
+	"testing"
+
+	"github.com/booksage/booksage-api/internal/llm"
+	"github.com/hashicorp/go-hclog"
+)
+
+// stubLLMClient is a minimal llm.LLMClient that always returns response,
+// counting how many times Generate was called so tests can assert on
+// LLMClassifier's caching behavior.
+type stubLLMClient struct {
+	response string
+	calls    int
+}
+
+func (s *stubLLMClient) Generate(ctx context.Context, prompt string) (string, error) {
+	s.calls++
+	return s.response, nil
+}
+
+func (s *stubLLMClient) Name() string { return "stub" }
+
+func TestKeywordIntentClassifier_Classify(t *testing.T) {
+	c := &KeywordIntentClassifier{}
+	ctx := context.Background()
+
+	cases := map[string]QueryIntent{
+		"Can you summarize this chapter?":      IntentSummary,
+		"What is the definition of recursion?": IntentDefinition,
+		"How does X relate to Y?":              IntentRelationship,
+		"Compare these two approaches":         IntentComparison,
+		"Tell me something interesting":        IntentGeneral,
+	}
+	for query, want := range cases {
+		got, scores, err := c.Classify(ctx, query)
+		if err != nil {
+			t.Fatalf("Classify(%q) returned error: %v", query, err)
+		}
+		if got != want {
+			t.Errorf("Classify(%q) = %s, want %s", query, got, want)
+		}
+		if scores[want] != 1 {
+			t.Errorf("Classify(%q) scores[%s] = %v, want 1", query, want, scores[want])
+		}
+	}
+}
+
+func TestLLMClassifier_Classify_ParsesAndCaches(t *testing.T) {
+	stub := &stubLLMClient{response: `{"summary": 0.1, "definition": 0.8, "relationship": 0.0, "comparison": 0.0, "general": 0.1}`}
+	router := llm.NewRouter(stub, stub, hclog.NewNullLogger())
+
+	c := NewLLMClassifier(router)
+	ctx := context.Background()
+
+	intent, scores, err := c.Classify(ctx, "What is recursion?")
+	if err != nil {
+		t.Fatalf("Classify returned error: %v", err)
+	}
+	if intent != IntentDefinition {
+		t.Errorf("Classify() intent = %s, want %s", intent, IntentDefinition)
+	}
+	if scores[IntentDefinition] <= scores[IntentSummary] {
+		t.Errorf("expected definition to score highest, got %v", scores)
+	}
+
+	if _, _, err := c.Classify(ctx, "WHAT IS RECURSION?  "); err != nil {
+		t.Fatalf("cached Classify returned error: %v", err)
+	}
+	if stub.calls != 1 {
+		t.Errorf("expected the normalized-duplicate query to hit the cache, got %d LLM calls", stub.calls)
+	}
+}
+
+func TestRouteOperator_GetBlendedWeights(t *testing.T) {
+	op := NewRouteOperator()
+	scores := map[QueryIntent]float32{IntentDefinition: 0.5, IntentRelationship: 0.5}
+
+	blended := op.GetBlendedWeights(scores)
+
+	definition := op.GetWeights(IntentDefinition)
+	relationship := op.GetWeights(IntentRelationship)
+	for source := range blended {
+		want := (definition[source] + relationship[source]) / 2
+		if diff := blended[source] - want; diff > 0.01 || diff < -0.01 {
+			t.Errorf("blended[%s] = %v, want ~%v", source, blended[source], want)
+		}
+	}
+}
+
+func TestRouteOperator_GetWeights_FixedByDefault(t *testing.T) {
+	op := NewRouteOperator()
+	weights := op.GetWeights(IntentRelationship)
+	if weights["graph"] != 0.70 {
+		t.Errorf("expected fixed graph weight 0.70 for IntentRelationship, got %v", weights["graph"])
+	}
+}
+
+func TestRouteOperator_UpdateWeights_AffectsOnlyLearned(t *testing.T) {
+	op := NewRouteOperator()
+	op.UpdateWeights(IntentRelationship, "graph", 0.5)
+
+	fixed := op.GetWeights(IntentRelationship)
+	if fixed["graph"] != 0.70 {
+		t.Errorf("UpdateWeights must not mutate fixedWeights, got graph=%v", fixed["graph"])
+	}
+
+	op.SetUseLearnedWeights(true)
+	learned := op.GetWeights(IntentRelationship)
+	if learned["graph"] <= fixed["graph"] {
+		t.Errorf("expected learned graph weight to increase past fixed %v, got %v", fixed["graph"], learned["graph"])
+	}
+
+	var total float32
+	for _, w := range learned {
+		total += w
+	}
+	if total < 0.99 || total > 1.01 {
+		t.Errorf("expected learned weights to renormalize to ~1.0, got %v", total)
+	}
+}
+
+func TestRouteOperator_UpdateWeights_ClampsAtZero(t *testing.T) {
+	op := NewRouteOperator()
+	op.SetUseLearnedWeights(true)
+	op.UpdateWeights(IntentSummary, "vector", -10)
+
+	weights := op.GetWeights(IntentSummary)
+	if weights["vector"] != 0 {
+		t.Errorf("expected vector weight clamped to 0, got %v", weights["vector"])
+	}
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	a := []float32{1, 0, 0}
+	b := []float32{1, 0, 0}
+	if sim := cosineSimilarity(a, b); sim < 0.999 {
+		t.Errorf("expected identical vectors to have similarity ~1, got %v", sim)
+	}
+
+	orthogonal := []float32{0, 1, 0}
+	if sim := cosineSimilarity(a, orthogonal); sim != 0 {
+		t.Errorf("expected orthogonal vectors to have similarity 0, got %v", sim)
+	}
+
+	if sim := cosineSimilarity(a, []float32{1, 0}); sim != 0 {
+		t.Errorf("expected mismatched-length vectors to return 0, got %v", sim)
+	}
+}
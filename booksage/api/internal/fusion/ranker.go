@@ -0,0 +1,223 @@
+package fusion
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Ranker fuses the flat list of per-engine results Retrieve collects into
+// one final ranked list. RRFRanker and SkylineRanker are the two
+// implementations; rankerFor picks between them by classified QueryIntent
+// the same way GetBlendedWeights already blends engine weights by intent.
+type Ranker interface {
+	Rank(ctx context.Context, results []SearchResult, weights EngineWeights) []SearchResult
+}
+
+// RRFRanker fuses results via intent-weighted Reciprocal Rank Fusion: each
+// result's contribution from its Source is weights[Source]/(k+rank) for
+// its 1-based rank within that source's own list, and a result appearing
+// (by identical Content) in multiple sources sums its contributions.
+// Config additionally opts into near-duplicate merging by embedding
+// similarity and an MMR diversity rerank on top of the fused order; its
+// zero value reproduces RRFRanker's original fixed-weight, no-dedup,
+// no-MMR behavior.
+type RRFRanker struct {
+	Config FusionConfig
+}
+
+// FusionConfig tunes RRFRanker beyond plain weighted RRF. The zero value
+// disables both knobs, so existing callers are unaffected.
+type FusionConfig struct {
+	// DedupeThreshold merges two results whose Embedding is populated and
+	// whose cosine similarity is at or above this value, summing their
+	// fused scores and keeping whichever ranked higher. 0 (default)
+	// disables embedding-based merging, leaving the exact-Content dedup
+	// Rank already performs as the only merge.
+	DedupeThreshold float32
+
+	// MMRLambda, when > 0, reranks the fused results with Maximal
+	// Marginal Relevance -- argmax λ·relevance(d) - (1-λ)·maxSim(d,
+	// selected) -- trading relevance for diversity among results whose
+	// Embedding is populated. 0 (default) skips MMR and returns the fused
+	// order unchanged.
+	MMRLambda float32
+}
+
+// rrfK is the RRF constant from the original paper (Cormack et al.),
+// found to perform robustly across very different engines' score scales
+// without any tuning.
+const rrfK = 60.0
+
+// Rank applies intent-weighted Reciprocal Rank Fusion, then Config's
+// near-duplicate merge and MMR rerank if configured.
+func (r *RRFRanker) Rank(ctx context.Context, results []SearchResult, weights EngineWeights) []SearchResult {
+	_, span := tracer.Start(ctx, "rrf.merge", trace.WithAttributes(
+		attribute.Int("rrf.input_count", len(results)),
+	))
+	defer span.End()
+
+	if len(results) == 0 {
+		return results
+	}
+
+	sourceGroups := map[string][]SearchResult{}
+	for _, res := range results {
+		sourceGroups[res.Source] = append(sourceGroups[res.Source], res)
+	}
+
+	rrfScores := map[string]float32{}
+	rrfResult := map[string]SearchResult{}
+	var order []string
+	for source, group := range sourceGroups {
+		weight := weights[source]
+		if weight == 0 {
+			weight = 0.33
+		}
+		for rank, res := range group {
+			score := float32(1.0/(rrfK+float64(rank+1))) * weight
+			key := res.Content
+			if _, exists := rrfResult[key]; !exists {
+				rrfResult[key] = res
+				order = append(order, key)
+			}
+			rrfScores[key] += score
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return rrfScores[order[i]] > rrfScores[order[j]] })
+	order = mergeNearDuplicates(order, rrfResult, rrfScores, r.Config.DedupeThreshold)
+
+	fused := make([]SearchResult, len(order))
+	for i, key := range order {
+		res := rrfResult[key]
+		res.Score = rrfScores[key]
+		fused[i] = res
+	}
+
+	if r.Config.MMRLambda > 0 {
+		fused = mmrRerank(fused, r.Config.MMRLambda)
+	}
+	return fused
+}
+
+// mergeNearDuplicates folds any entry in order whose Embedding is a
+// near-duplicate (cosine similarity >= threshold) of an earlier,
+// still-kept entry's Embedding into that earlier entry, summing their RRF
+// scores in place. Entries missing an Embedding are never merged this
+// way. threshold <= 0 disables the pass entirely, returning order
+// unchanged.
+func mergeNearDuplicates(order []string, byKey map[string]SearchResult, scores map[string]float32, threshold float32) []string {
+	if threshold <= 0 {
+		return order
+	}
+
+	kept := make([]string, 0, len(order))
+	for _, key := range order {
+		merged := false
+		for _, keptKey := range kept {
+			a, b := byKey[key].Embedding, byKey[keptKey].Embedding
+			if len(a) == 0 || len(b) == 0 {
+				continue
+			}
+			if cosineSimilarity(a, b) >= threshold {
+				scores[keptKey] += scores[key]
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			kept = append(kept, key)
+		}
+	}
+	return kept
+}
+
+// mmrRerank reranks fused (already RRF-score descending) via Maximal
+// Marginal Relevance: greedily picks whichever remaining result maximizes
+// λ·relevance(d) - (1-λ)·maxSim(d, selected). relevance(d) is d's fused
+// Score; sim(d, d') is cosine similarity of their Embeddings, treated as 0
+// for any pair missing one, so results without an embedding are ranked on
+// relevance alone.
+func mmrRerank(fused []SearchResult, lambda float32) []SearchResult {
+	if len(fused) <= 1 {
+		return fused
+	}
+
+	remaining := append([]SearchResult(nil), fused...)
+	selected := make([]SearchResult, 0, len(fused))
+
+	for len(remaining) > 0 {
+		bestIdx := 0
+		bestScore := float32(math.Inf(-1))
+		for i, cand := range remaining {
+			var maxSim float32
+			for _, sel := range selected {
+				if s := cosineSimilarity(cand.Embedding, sel.Embedding); s > maxSim {
+					maxSim = s
+				}
+			}
+			mmrScore := lambda*cand.Score - (1-lambda)*maxSim
+			if mmrScore > bestScore {
+				bestScore = mmrScore
+				bestIdx = i
+			}
+		}
+		selected = append(selected, remaining[bestIdx])
+		remaining = append(remaining[:bestIdx], remaining[bestIdx+1:]...)
+	}
+
+	return selected
+}
+
+// SkylineRanker implements Pareto-optimal fusion across two axes: a
+// result's own Score (vector/graph/tree similarity) and its graph-derived
+// relevance (graphRelevanceScore of its Graph signals, attached by
+// FusionRetriever.attachGraphSignals before ranking runs). A result is
+// kept iff no other result beats it on both axes at once, so Skyline
+// tends to return fewer results than RRFRanker but doesn't require
+// picking a fusion formula across engines with incomparable score scales.
+type SkylineRanker struct{}
+
+// Rank keeps every result not Pareto-dominated by another -- beaten on
+// both Score and graph relevance at once -- and sorts the survivors by
+// Score descending. weights is unused; Skyline dominance doesn't blend by
+// engine the way RRF does.
+func (r *SkylineRanker) Rank(ctx context.Context, results []SearchResult, _ EngineWeights) []SearchResult {
+	_, span := tracer.Start(ctx, "skyline.merge", trace.WithAttributes(
+		attribute.Int("skyline.input_count", len(results)),
+	))
+	defer span.End()
+
+	if len(results) <= 1 {
+		return results
+	}
+
+	graphScore := make([]float32, len(results))
+	for i, res := range results {
+		graphScore[i] = graphRelevanceScore(res.Graph)
+	}
+
+	var skyline []SearchResult
+	for i := range results {
+		dominated := false
+		for j := range results {
+			if i == j {
+				continue
+			}
+			if results[j].Score > results[i].Score && graphScore[j] > graphScore[i] {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			skyline = append(skyline, results[i])
+		}
+	}
+
+	sort.SliceStable(skyline, func(i, j int) bool { return skyline[i].Score > skyline[j].Score })
+	return skyline
+}
@@ -2,23 +2,48 @@ package fusion
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"log"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/booksage/booksage-api/internal/database"
+	"github.com/booksage/booksage-api/internal/database/models"
 	"github.com/booksage/booksage-api/internal/embedding"
+	"github.com/booksage/booksage-api/internal/health"
+	"github.com/booksage/booksage-api/internal/llm"
 	neo4jpkg "github.com/booksage/booksage-api/internal/neo4j"
 	qdrantpkg "github.com/booksage/booksage-api/internal/qdrant"
+	"github.com/hashicorp/go-hclog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"golang.org/x/sync/errgroup"
 )
 
+// tracer is package-scoped so every FusionRetriever shares one tracer,
+// matching ingest.SagaOrchestrator's convention.
+var tracer = otel.Tracer("booksage-api/fusion")
+
 // SearchResult represents a common structure for results from different engines.
 type SearchResult struct {
 	ID      string
 	Content string
 	Score   float32
 	Source  string // "graph", "tree", "vector"
+
+	// Graph holds this result's graph-derived relevance signals, attached
+	// by attachGraphSignals before ranking for IntentRelationship queries.
+	// Its zero value (graphRelevanceScore reports 0) is what every result
+	// carries for any other intent.
+	Graph GraphSignals
+
+	// Embedding is this result's own dense vector, when the engine that
+	// produced it already had one on hand (currently only searchTreeDB,
+	// via TreeNode.Embedding). RRFRanker's near-duplicate merge and MMR
+	// rerank skip any result pair where either side's Embedding is empty.
+	Embedding []float32
 }
 
 // FusionRetriever manages concurrent retrieval across multiple data stores.
@@ -26,95 +51,533 @@ type FusionRetriever struct {
 	qdrant     *qdrantpkg.Client
 	neo4j      *neo4jpkg.Client
 	embedder   *embedding.Batcher
-	classifier *IntentClassifier
+	classifier Classifier
 	operator   *RouteOperator
+	logger     hclog.Logger
+
+	feedback              database.IntentFeedbackRepository
+	feedbackMu            sync.Mutex
+	lastFeedbackRecompute time.Time
+
+	vectorBreaker *health.CircuitBreaker
+	graphBreaker  *health.CircuitBreaker
+	treeBreaker   *health.CircuitBreaker
+
+	ranker       Ranker
+	fusionConfig FusionConfig
 }
 
 // NewFusionRetriever creates a new FusionRetriever with real DB clients.
-func NewFusionRetriever(qdrant *qdrantpkg.Client, neo4j *neo4jpkg.Client, embedder *embedding.Batcher) *FusionRetriever {
+// router drives the intent classifier's LLM fallback tier (see
+// LearnedIntentClassifier); it may be nil, in which case a thin-margin
+// query just keeps its embedding-tier pick. logger is named "fusion" and
+// derived from whatever logger the caller injected, rather than reaching
+// for hclog.Default().
+func NewFusionRetriever(qdrant *qdrantpkg.Client, neo4j *neo4jpkg.Client, embedder *embedding.Batcher, router *llm.Router, logger hclog.Logger) *FusionRetriever {
 	return &FusionRetriever{
 		qdrant:     qdrant,
 		neo4j:      neo4j,
 		embedder:   embedder,
-		classifier: &IntentClassifier{},
+		classifier: NewLearnedIntentClassifier(embedder, router),
 		operator:   NewRouteOperator(),
+		logger:     logger.Named("fusion"),
 	}
 }
 
-// Retrieve performs asynchronous parallel requests across engines and ensembles them.
-// Uses intent classification to dynamically weight the fusion.
-func (f *FusionRetriever) Retrieve(ctx context.Context, query string) ([]SearchResult, error) {
-	log.Printf("[Fusion] Starting parallel retrieval for: %s", query)
+// WithIntentFeedbackRepository attaches the store Retrieve writes one
+// IntentFeedback row to per query, and StartIntentFeedbackJob later reads
+// back to recompute RouteOperator's learned weights. Without one, Retrieve
+// and classification both still run exactly the same; nothing is persisted
+// and the feedback job has nothing to do.
+func (f *FusionRetriever) WithIntentFeedbackRepository(repo database.IntentFeedbackRepository) *FusionRetriever {
+	f.feedback = repo
+	return f
+}
+
+// WithClassifier overrides the default LearnedIntentClassifier with the
+// given Classifier, letting main.go configure e.g. an LLMClassifier or a
+// plain KeywordIntentClassifier without reaching into the unexported
+// classifier field directly.
+func (f *FusionRetriever) WithClassifier(classifier Classifier) *FusionRetriever {
+	f.classifier = classifier
+	return f
+}
+
+// WithCircuitBreakers attaches per-engine circuit breakers guarding the
+// Qdrant, Neo4j full-text/graph, and Neo4j RAPTOR-tree calls Retrieve
+// dispatches in parallel. Without one, an engine behaves exactly as
+// before: it's retried every call, even against a store that's already
+// failing every request and just logging "degrading gracefully" forever.
+// With one, Retrieve skips dispatching an open engine's goroutine entirely
+// and renormalizes the remaining engines' weights rather than silently
+// scoring the fused results as if that engine had simply returned nothing.
+func (f *FusionRetriever) WithCircuitBreakers(vector, graph, tree *health.CircuitBreaker) *FusionRetriever {
+	f.vectorBreaker = vector
+	f.graphBreaker = graph
+	f.treeBreaker = tree
+	return f
+}
+
+// WithRanker overrides rankerFor's default intent-driven choice between
+// RRFRanker and SkylineRanker with a single fixed Ranker used for every
+// query. Without one, Retrieve picks per classified intent.
+func (f *FusionRetriever) WithRanker(ranker Ranker) *FusionRetriever {
+	f.ranker = ranker
+	return f
+}
+
+// WithFusionConfig tunes the default RRFRanker's near-duplicate merge and
+// MMR diversity rerank. It has no effect once WithRanker has been called,
+// since the override Ranker is used as-is.
+func (f *FusionRetriever) WithFusionConfig(cfg FusionConfig) *FusionRetriever {
+	f.fusionConfig = cfg
+	return f
+}
 
-	// Classify query intent for weighted fusion
-	intent := f.classifier.Classify(query)
-	weights := f.operator.GetWeights(intent)
-	log.Printf("[Fusion] Intent: %s | Weights: %v", intent, weights)
+// FusionEvent is one event RetrieveStream emits as a fusion round
+// progresses, letting a caller (the query SSE HTTP handler) surface a
+// partial hit as soon as its engine returns instead of waiting on the
+// slowest one before rendering anything. Type is one of
+// "intent_classified", "partial_results" (one per engine, as it
+// completes), or "ranked" (the terminal event, after Skyline/RRF has
+// fused whatever came back before ctx expired).
+//
+// It doesn't report entity/theme-extraction events: no such extraction
+// step exists in this retriever. CoR-style query decomposition lives one
+// layer up, in agent.Generator.
+type FusionEvent struct {
+	Type string
+
+	// Intent is set on "intent_classified".
+	Intent QueryIntent
+
+	// Source and Results are set on "partial_results" and "ranked":
+	// "vector", "graph", or "tree" naming the engine that just completed
+	// for "partial_results", and "" for the fused "ranked" event.
+	Source  string
+	Results []SearchResult
+}
 
-	// Add a global timeout for the entire fusion process.
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
+// RetrieveStream runs the same parallel multi-engine retrieval and ranking
+// as Retrieve, reporting progress on the returned channel as it happens
+// instead of only on return. The channel is closed once the terminal
+// "ranked" event has been sent.
+func (f *FusionRetriever) RetrieveStream(ctx context.Context, query string) (<-chan FusionEvent, error) {
+	events := make(chan FusionEvent, 4)
+	go func() {
+		defer close(events)
 
-	g, ctx := errgroup.WithContext(ctx)
+		ctx, span := tracer.Start(ctx, "retriever.fusion")
+		defer span.End()
 
-	var mu sync.Mutex
-	var allResults []SearchResult
+		f.logger.Info("starting parallel retrieval", "query", query)
 
-	// 1. Vector Engine (Qdrant Dense Search)
-	g.Go(func() error {
-		log.Println("[Fusion] Dispatching Vector Engine request...")
-		docs, err := f.searchVectorDB(ctx, query)
+		// Classify query intent for weighted fusion
+		intent, scores, err := f.classifier.Classify(ctx, query)
 		if err != nil {
-			log.Printf("Warning: Vector DB search failed, degrading gracefully: %v", err)
-			return nil
+			f.logger.Warn("intent classification failed, defaulting to general", "error", err)
+			intent = IntentGeneral
+			scores = map[QueryIntent]float32{IntentGeneral: 1}
+		}
+		weights := f.operator.GetBlendedWeights(scores)
+		f.logger.Debug("classified intent", "intent", string(intent), "weights", weights)
+		span.SetAttributes(
+			attribute.String("retriever.intent", string(intent)),
+			attribute.Float64("retriever.weight.graph", float64(weights["graph"])),
+			attribute.Float64("retriever.weight.tree", float64(weights["tree"])),
+			attribute.Float64("retriever.weight.vector", float64(weights["vector"])),
+		)
+		events <- FusionEvent{Type: "intent_classified", Intent: intent}
+
+		// Engines guarded by an open circuit breaker are dropped before
+		// dispatch rather than left to fail again; their weight is zeroed out
+		// and the remaining engines' weights renormalized so RRF still blends
+		// to a full-weight picture across whichever engines are left, instead
+		// of silently treating the skipped engine as having returned nothing.
+		// This has to happen before recordIntentFeedback below, so the weights
+		// persisted for recomputeEngineWeights to later learn from reflect
+		// what was actually used for this query, not what would have been used
+		// with every engine healthy.
+		dispatchVector := f.engineAllowed(f.vectorBreaker, "vector", weights)
+		dispatchGraph := f.engineAllowed(f.graphBreaker, "graph", weights)
+		dispatchTree := f.engineAllowed(f.treeBreaker, "tree", weights)
+		normalizeWeights(weights)
+
+		f.recordIntentFeedback(ctx, query, intent, weights)
+
+		// Add a global timeout for the entire fusion process.
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		// Vector and graph search both need query's embedding; computing it once
+		// in its own goroutine and having both wait on embedDone halves the
+		// embedding calls per retrieval while keeping all three engines
+		// dispatched in parallel, same as before.
+		var queryVector []float32
+		var embedErr error
+		embedDone := make(chan struct{})
+		embedCtx := ctx
+		go func() {
+			defer close(embedDone)
+			queryVector, embedErr = f.embedQuery(embedCtx, query)
+		}()
+
+		g, ctx := errgroup.WithContext(ctx)
+
+		var mu sync.Mutex
+		var allResults []SearchResult
+
+		// 1. Vector Engine (Qdrant Dense Search)
+		if dispatchVector {
+			g.Go(func() error {
+				vecCtx, vecSpan := tracer.Start(ctx, "retriever.vector")
+				defer vecSpan.End()
+				f.logger.Debug("dispatching vector engine request")
+				<-embedDone
+				if embedErr != nil {
+					f.logger.Warn("query embedding failed, skipping vector search", "error", embedErr)
+					return nil
+				}
+				docs, err := f.searchVectorDB(vecCtx, queryVector)
+				if err != nil {
+					f.logger.Warn("vector db search failed, degrading gracefully", "error", err)
+					vecSpan.RecordError(err)
+					vecSpan.SetStatus(codes.Error, err.Error())
+					recordBreakerOutcome(f.vectorBreaker, err)
+					return nil
+				}
+				recordBreakerOutcome(f.vectorBreaker, nil)
+				vecSpan.SetAttributes(attribute.Int("retriever.result_count", len(docs)))
+				mu.Lock()
+				allResults = append(allResults, docs...)
+				mu.Unlock()
+				events <- FusionEvent{Type: "partial_results", Source: "vector", Results: docs}
+				return nil
+			})
 		}
-		mu.Lock()
-		allResults = append(allResults, docs...)
-		mu.Unlock()
-		return nil
-	})
 
-	// 2. Graph Engine (Neo4j)
-	g.Go(func() error {
-		log.Println("[Fusion] Dispatching Graph Engine request...")
-		docs, err := f.searchGraphDB(ctx, query)
-		if err != nil {
-			log.Printf("Warning: Graph DB search failed, degrading gracefully: %v", err)
-			return nil
+		// 2. Graph Engine (Neo4j)
+		if dispatchGraph {
+			g.Go(func() error {
+				graphCtx, graphSpan := tracer.Start(ctx, "retriever.graph")
+				defer graphSpan.End()
+				f.logger.Debug("dispatching graph engine request")
+				<-embedDone
+				if embedErr != nil {
+					f.logger.Warn("query embedding failed, graph search falling back to full-text only", "error", embedErr)
+				}
+				docs, err := f.searchGraphDB(graphCtx, query, queryVector)
+				if err != nil {
+					f.logger.Warn("graph db search failed, degrading gracefully", "error", err)
+					graphSpan.RecordError(err)
+					graphSpan.SetStatus(codes.Error, err.Error())
+					recordBreakerOutcome(f.graphBreaker, err)
+					return nil
+				}
+				recordBreakerOutcome(f.graphBreaker, nil)
+				graphSpan.SetAttributes(attribute.Int("retriever.result_count", len(docs)))
+				mu.Lock()
+				allResults = append(allResults, docs...)
+				mu.Unlock()
+				events <- FusionEvent{Type: "partial_results", Source: "graph", Results: docs}
+				return nil
+			})
 		}
-		mu.Lock()
-		allResults = append(allResults, docs...)
-		mu.Unlock()
-		return nil
-	})
 
-	// 3. RAPTOR/Tree Engine (placeholder for Phase 3)
-	g.Go(func() error {
-		log.Println("[Fusion] Dispatching Tree/RAPTOR Engine request (stub)...")
-		// RAPTOR tree search will be implemented in Phase 3.
-		return nil
-	})
+		// 3. RAPTOR/Tree Engine
+		if dispatchTree {
+			g.Go(func() error {
+				treeCtx, treeSpan := tracer.Start(ctx, "retriever.tree")
+				defer treeSpan.End()
+				f.logger.Debug("dispatching tree/raptor engine request")
+				<-embedDone
+				if embedErr != nil {
+					f.logger.Warn("query embedding failed, skipping tree search", "error", embedErr)
+					return nil
+				}
+				docs, err := f.searchTreeDB(treeCtx, queryVector)
+				if err != nil {
+					f.logger.Warn("tree db search failed, degrading gracefully", "error", err)
+					treeSpan.RecordError(err)
+					treeSpan.SetStatus(codes.Error, err.Error())
+					recordBreakerOutcome(f.treeBreaker, err)
+					return nil
+				}
+				recordBreakerOutcome(f.treeBreaker, nil)
+				treeSpan.SetAttributes(attribute.Int("retriever.result_count", len(docs)))
+				mu.Lock()
+				allResults = append(allResults, docs...)
+				mu.Unlock()
+				events <- FusionEvent{Type: "partial_results", Source: "tree", Results: docs}
+				return nil
+			})
+		}
+
+		// Every goroutine above degrades to a nil return on its own engine's
+		// failure rather than propagating one, so g.Wait() only ever
+		// surfaces a ctx cancellation -- there's nothing left to rank in
+		// that case either way, so it just falls through to ranking
+		// whatever (possibly nothing) came back before ctx expired.
+		_ = g.Wait()
 
-	if err := g.Wait(); err != nil {
+		ranker := f.rankerFor(intent)
+		if _, skyline := ranker.(*SkylineRanker); skyline {
+			f.attachGraphSignals(ctx, allResults)
+		}
+
+		f.logger.Info("retrieval complete, integrating results", "result_count", len(allResults), "ranker", fmt.Sprintf("%T", ranker))
+		fused := ranker.Rank(ctx, allResults, weights)
+		span.SetAttributes(attribute.Int("retriever.result_count", len(fused)))
+		events <- FusionEvent{Type: "ranked", Results: fused}
+	}()
+	return events, nil
+}
+
+// Retrieve performs asynchronous parallel requests across engines and
+// ensembles them, using intent classification to dynamically weight the
+// fusion. It's a thin wrapper draining RetrieveStream down to its terminal
+// "ranked" event, for callers that only want the final fused list.
+func (f *FusionRetriever) Retrieve(ctx context.Context, query string) ([]SearchResult, error) {
+	events, err := f.RetrieveStream(ctx, query)
+	if err != nil {
 		return nil, err
 	}
+	var ranked []SearchResult
+	for event := range events {
+		if event.Type == "ranked" {
+			ranked = event.Results
+		}
+	}
+	return ranked, nil
+}
 
-	log.Printf("[Fusion] Retrieval complete. Integrating %d total results via weighted RRF...", len(allResults))
-	return f.performWeightedRRF(allResults, weights), nil
+// rankerFor picks SkylineRanker for IntentRelationship queries, where
+// which chunks connect to which matters more than any single blended
+// score, and RRFRanker for every other intent. WithRanker overrides this
+// choice entirely.
+func (f *FusionRetriever) rankerFor(intent QueryIntent) Ranker {
+	if f.ranker != nil {
+		return f.ranker
+	}
+	if intent == IntentRelationship {
+		return &SkylineRanker{}
+	}
+	return &RRFRanker{Config: f.fusionConfig}
 }
 
-// LastIntent returns the intent from the most recent classification (for SSE reporting).
-func (f *FusionRetriever) ClassifyIntent(query string) QueryIntent {
-	return f.classifier.Classify(query)
+// attachGraphSignals populates every result's Graph field with
+// personalized-PageRank/traversal-distance/centrality signals computed
+// over its document's reading-order chunk adjacency, for SkylineRanker's
+// graph-relevance axis. Seeds are whichever results the graph engine
+// itself returned for this query, so a vector- or tree-only hit sitting
+// near a strong graph hit still picks up a meaningful score. It shares
+// the graph engine's own circuit breaker, since both are Neo4j calls
+// against the same dependency: an open breaker just leaves every result's
+// Graph at its zero value, which graphRelevanceScore reports as no signal
+// rather than surfacing an error to the caller.
+func (f *FusionRetriever) attachGraphSignals(ctx context.Context, results []SearchResult) {
+	if f.neo4j == nil || len(results) == 0 {
+		return
+	}
+	if f.graphBreaker != nil && !f.graphBreaker.Allow() {
+		f.logger.Warn("graph circuit breaker is open, skipping graph signal attachment")
+		return
+	}
+
+	ids := make([]string, len(results))
+	var seeds []string
+	for i, r := range results {
+		ids[i] = r.ID
+		if r.Source == "graph" {
+			seeds = append(seeds, r.ID)
+		}
+	}
+
+	adj, known, err := f.neo4j.ChunkDocAdjacency(ctx, ids)
+	if err != nil {
+		f.logger.Warn("graph signal attachment failed, skipping", "error", err)
+		recordBreakerOutcome(f.graphBreaker, err)
+		return
+	}
+	recordBreakerOutcome(f.graphBreaker, nil)
+
+	signals := computeGraphSignals(adj, known, seeds, ids)
+	for i := range results {
+		if s, ok := signals[results[i].ID]; ok {
+			results[i].Graph = s
+		}
+	}
 }
 
-// searchVectorDB queries Qdrant using dense vector similarity.
-func (f *FusionRetriever) searchVectorDB(ctx context.Context, query string) ([]SearchResult, error) {
-	if f.qdrant == nil || f.embedder == nil {
-		return nil, fmt.Errorf("qdrant or embedder not configured")
+// engineAllowed reports whether source's engine should be dispatched this
+// call. A nil breaker (the default when WithCircuitBreakers hasn't been
+// called) always allows. An open breaker deletes source's entry from
+// weights in place -- the caller normalizes weights once after checking
+// all three engines, so the surviving engines' weights still sum to 1
+// instead of the excluded engine silently scoring as if it had returned
+// zero results.
+func (f *FusionRetriever) engineAllowed(breaker *health.CircuitBreaker, source string, weights EngineWeights) bool {
+	if breaker == nil {
+		return true
+	}
+	if breaker.Allow() {
+		return true
+	}
+	f.logger.Warn("engine circuit breaker is open, skipping engine and renormalizing weights", "engine", source)
+	delete(weights, source)
+	return false
+}
+
+// recordBreakerOutcome feeds an engine goroutine's own error (or lack of
+// one) back into its circuit breaker. A nil breaker is a no-op.
+func recordBreakerOutcome(breaker *health.CircuitBreaker, err error) {
+	if breaker == nil {
+		return
+	}
+	if err != nil {
+		breaker.RecordFailure()
+		return
+	}
+	breaker.RecordSuccess()
+}
+
+// ClassifyIntent exposes the configured classifier's top intent for SSE
+// reporting, without the confidence distribution Retrieve uses internally
+// to blend engine weights. A classification failure reports IntentGeneral,
+// the same default Retrieve falls back to.
+func (f *FusionRetriever) ClassifyIntent(ctx context.Context, query string) QueryIntent {
+	intent, _, err := f.classifier.Classify(ctx, query)
+	if err != nil {
+		return IntentGeneral
+	}
+	return intent
+}
+
+// SetUseLearnedWeights flips RouteOperator's A/B toggle between its
+// hard-coded fixed weights and the table recomputeEngineWeights maintains
+// from feedback, so main.go can wire it to a config flag without reaching
+// into the unexported operator field directly.
+func (f *FusionRetriever) SetUseLearnedWeights(use bool) {
+	f.operator.SetUseLearnedWeights(use)
+}
+
+// recordIntentFeedback persists one row per classified query, if a
+// repository has been attached via WithIntentFeedbackRepository. It never
+// returns an error: like SelfRAGCritique.recordCritiqueEvent, a failure to
+// persist feedback should not block or slow down retrieval, so it's logged
+// instead.
+func (f *FusionRetriever) recordIntentFeedback(ctx context.Context, query string, intent QueryIntent, weights EngineWeights) {
+	if f.feedback == nil {
+		return
+	}
+
+	weightsJSON, err := json.Marshal(weights)
+	if err != nil {
+		f.logger.Error("intent feedback: failed to encode weights", "error", err)
+		return
+	}
+
+	event := &models.IntentFeedback{
+		Query:         query,
+		Intent:        string(intent),
+		EngineWeights: string(weightsJSON),
+		CreatedAt:     time.Now(),
+	}
+	if _, err := f.feedback.CreateIntentFeedback(ctx, event); err != nil {
+		f.logger.Error("intent feedback: failed to persist", "error", err)
+	}
+}
+
+// RecordIntentSignal attaches a later outcome signal (e.g. "clicked",
+// "rejected") to a previously recorded IntentFeedback row, so
+// recomputeEngineWeights has something to learn from. It is a no-op
+// returning nil if no IntentFeedbackRepository is attached.
+func (f *FusionRetriever) RecordIntentSignal(ctx context.Context, id int64, signal string) error {
+	if f.feedback == nil {
+		return nil
+	}
+	return f.feedback.RecordIntentSignal(ctx, id, signal)
+}
+
+// StartIntentFeedbackJob launches a background goroutine that periodically
+// recomputes RouteOperator's learned engine weights from IntentFeedback rows
+// recorded since the last run. It mirrors Server.StartUploadJanitor: callers
+// cancel ctx to stop the loop. A nil IntentFeedbackRepository makes this a
+// no-op, since there's nothing to read feedback from.
+func (f *FusionRetriever) StartIntentFeedbackJob(ctx context.Context, interval time.Duration) {
+	if f.feedback == nil || interval <= 0 {
+		return
+	}
+	go f.intentFeedbackLoop(ctx, interval)
+}
+
+func (f *FusionRetriever) intentFeedbackLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			f.recomputeEngineWeights(ctx)
+		}
+	}
+}
+
+// recomputeEngineWeights reads every IntentFeedback row recorded since the
+// last run, nudges RouteOperator's learned weights toward engines whose
+// results got a positive signal and away from engines whose didn't, and
+// advances the since-cursor to now. Rows with no Signal yet (feedback
+// recorded but no outcome observed) are skipped; there's nothing to learn
+// from them yet.
+func (f *FusionRetriever) recomputeEngineWeights(ctx context.Context) {
+	f.feedbackMu.Lock()
+	since := f.lastFeedbackRecompute
+	f.feedbackMu.Unlock()
+
+	events, err := f.feedback.ListIntentFeedbackSince(ctx, since)
+	if err != nil {
+		f.logger.Error("intent feedback job: failed to list feedback", "error", err)
+		return
+	}
+
+	now := time.Now()
+	for _, event := range events {
+		if event.Signal == "" {
+			continue
+		}
+		var weights EngineWeights
+		if err := json.Unmarshal([]byte(event.EngineWeights), &weights); err != nil {
+			f.logger.Warn("intent feedback job: skipping event with bad weights JSON", "event_id", event.ID, "error", err)
+			continue
+		}
+		delta := float32(-0.01)
+		if event.Signal == "clicked" || event.Signal == "accepted" {
+			delta = 0.01
+		}
+		for source := range weights {
+			f.operator.UpdateWeights(QueryIntent(event.Intent), source, delta)
+		}
+	}
+
+	f.feedbackMu.Lock()
+	f.lastFeedbackRecompute = now
+	f.feedbackMu.Unlock()
+
+	if len(events) > 0 {
+		f.logger.Info("intent feedback job: processed events, recomputed learned weights", "event_count", len(events))
+	}
+}
+
+// embedQuery generates query's dense embedding, shared by searchVectorDB and
+// searchGraphDB so Retrieve only pays for it once per call instead of once
+// per engine.
+func (f *FusionRetriever) embedQuery(ctx context.Context, query string) ([]float32, error) {
+	if f.embedder == nil {
+		return nil, fmt.Errorf("embedder not configured")
 	}
 
-	// Generate query embedding
 	embResults, _, err := f.embedder.GenerateEmbeddingsBatched(ctx, []string{query}, "dense", "query")
 	if err != nil {
 		return nil, fmt.Errorf("embedding generation failed: %w", err)
@@ -122,10 +585,19 @@ func (f *FusionRetriever) searchVectorDB(ctx context.Context, query string) ([]S
 	if len(embResults) == 0 || embResults[0].GetDense() == nil {
 		return nil, fmt.Errorf("no embedding result returned")
 	}
+	return embResults[0].GetDense().GetValues(), nil
+}
 
-	queryVector := embResults[0].GetDense().GetValues()
+// searchVectorDB queries Qdrant using dense vector similarity against the
+// already-computed queryVector.
+func (f *FusionRetriever) searchVectorDB(ctx context.Context, queryVector []float32) ([]SearchResult, error) {
+	if f.qdrant == nil || f.embedder == nil {
+		return nil, fmt.Errorf("qdrant or embedder not configured")
+	}
+	if len(queryVector) == 0 {
+		return nil, fmt.Errorf("no query embedding available")
+	}
 
-	// Search Qdrant
 	qdrantResults, err := f.qdrant.Search(ctx, queryVector, 5)
 	if err != nil {
 		return nil, err
@@ -141,17 +613,21 @@ func (f *FusionRetriever) searchVectorDB(ctx context.Context, query string) ([]S
 		})
 	}
 
-	log.Printf("[Fusion] Vector search returned %d results", len(results))
+	f.logger.Debug("vector search returned results", "result_count", len(results))
 	return results, nil
 }
 
-// searchGraphDB queries Neo4j for text-matching Chunk nodes.
-func (f *FusionRetriever) searchGraphDB(ctx context.Context, query string) ([]SearchResult, error) {
+// searchGraphDB queries Neo4j for Chunk nodes matching query by full-text
+// relevance, blended with vector similarity against the already-computed
+// queryVector when one is available -- a missing/failed embedding isn't
+// fatal here, since SearchChunks degrades gracefully to full-text-only
+// relevance when queryVector is empty.
+func (f *FusionRetriever) searchGraphDB(ctx context.Context, query string, queryVector []float32) ([]SearchResult, error) {
 	if f.neo4j == nil {
 		return nil, fmt.Errorf("neo4j not configured")
 	}
 
-	results, err := f.neo4j.SearchChunks(ctx, query, 5)
+	results, err := f.neo4j.SearchChunks(ctx, query, queryVector, 5)
 	if err != nil {
 		return nil, err
 	}
@@ -166,56 +642,90 @@ func (f *FusionRetriever) searchGraphDB(ctx context.Context, query string) ([]Se
 		})
 	}
 
-	log.Printf("[Fusion] Graph search returned %d results", len(out))
+	f.logger.Debug("graph search returned results", "result_count", len(out))
 	return out, nil
 }
 
-// performWeightedRRF applies intent-weighted Reciprocal Rank Fusion.
-func (f *FusionRetriever) performWeightedRRF(results []SearchResult, weights EngineWeights) []SearchResult {
-	if len(results) == 0 {
-		return results
+// treeSearchMaxDepth bounds how many layers searchTreeDB will visit,
+// counting down from the root through ingest.raptorMaxDepth summary
+// levels to the leaf Chunk level at the bottom -- one more than
+// raptorMaxDepth so a maximally deep tree's leaves are still reachable.
+const treeSearchMaxDepth = 6
+
+// treeSearchBeamWidth is how many children searchTreeDB keeps per level; a
+// fixed beam keeps the node count it visits (and thus the result count)
+// bounded regardless of how wide a level's branching factor is.
+const treeSearchBeamWidth = 3
+
+// treeSearchMaxRoots caps how many documents' RAPTOR roots searchTreeDB
+// considers per query, the same way searchVectorDB/searchGraphDB cap
+// their own result counts -- without it, GetRootSummaries' cost grows
+// with the whole corpus instead of with this one query.
+const treeSearchMaxRoots = 20
+
+// searchTreeDB descends a document's RAPTOR tree from its root(s), at each
+// level scoring the current frontier's children by cosine similarity
+// against queryVector and keeping the top treeSearchBeamWidth before
+// fetching their children for the next level. Every node visited along the
+// way -- summaries and the leaf chunks they bottom out at -- is returned as
+// a SearchResult so the fusion step sees both the coarse and fine-grained
+// matches. A document with no RAPTOR tree yet simply has no root summaries,
+// so this returns (nil, nil) rather than an error, letting the fusion
+// degrade gracefully to the vector/graph engines.
+func (f *FusionRetriever) searchTreeDB(ctx context.Context, queryVector []float32) ([]SearchResult, error) {
+	if f.neo4j == nil {
+		return nil, fmt.Errorf("neo4j not configured")
+	}
+	if len(queryVector) == 0 {
+		return nil, fmt.Errorf("no query embedding available")
 	}
 
-	const k = 60.0
-
-	// Group by source for per-engine rankings
-	sourceGroups := map[string][]SearchResult{}
-	for _, r := range results {
-		sourceGroups[r.Source] = append(sourceGroups[r.Source], r)
+	roots, err := f.neo4j.GetRootSummaries(ctx, treeSearchMaxRoots)
+	if err != nil {
+		return nil, err
+	}
+	if len(roots) == 0 {
+		return nil, nil
 	}
 
-	// Calculate weighted RRF scores
-	rrfScores := map[string]float32{}
-	rrfContent := map[string]SearchResult{}
-	for source, group := range sourceGroups {
-		weight := weights[source]
-		if weight == 0 {
-			weight = 0.33
+	var results []SearchResult
+	frontier := roots
+	for depth := 0; depth < treeSearchMaxDepth && len(frontier) > 0; depth++ {
+		type scored struct {
+			node  neo4jpkg.TreeNode
+			score float32
 		}
-		for rank, r := range group {
-			rrfScore := float32(1.0/(k+float64(rank+1))) * weight
-			key := r.Content
-			rrfScores[key] += rrfScore
-			if _, exists := rrfContent[key]; !exists {
-				rrfContent[key] = r
-			}
+		ranked := make([]scored, len(frontier))
+		for i, n := range frontier {
+			ranked[i] = scored{n, cosineSimilarity(queryVector, n.Embedding)}
+		}
+		sort.Slice(ranked, func(i, j int) bool { return ranked[i].score > ranked[j].score })
+		if len(ranked) > treeSearchBeamWidth {
+			ranked = ranked[:treeSearchBeamWidth]
 		}
-	}
 
-	var fused []SearchResult
-	for key, r := range rrfContent {
-		r.Score = rrfScores[key]
-		fused = append(fused, r)
-	}
+		var childIDs []string
+		for _, r := range ranked {
+			results = append(results, SearchResult{
+				ID:        r.node.NodeID,
+				Content:   r.node.Text,
+				Score:     r.score,
+				Source:    "tree",
+				Embedding: r.node.Embedding,
+			})
+			childIDs = append(childIDs, r.node.ChildIDs...)
+		}
 
-	// Sort descending
-	for i := 0; i < len(fused); i++ {
-		for j := i + 1; j < len(fused); j++ {
-			if fused[j].Score > fused[i].Score {
-				fused[i], fused[j] = fused[j], fused[i]
-			}
+		if len(childIDs) == 0 || depth == treeSearchMaxDepth-1 {
+			break
+		}
+		frontier, err = f.neo4j.GetTreeNodes(ctx, childIDs)
+		if err != nil {
+			f.logger.Warn("tree search failed to fetch children, stopping descent", "error", err)
+			break
 		}
 	}
 
-	return fused
+	f.logger.Debug("tree search returned results", "result_count", len(results))
+	return results, nil
 }
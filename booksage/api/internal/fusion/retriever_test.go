@@ -3,11 +3,13 @@ package fusion
 import (
 	"context"
 	"testing"
+
+	"github.com/hashicorp/go-hclog"
 )
 
 func TestRetrieve_NilClients(t *testing.T) {
 	// With nil clients, retrieval should degrade gracefully (no results, no crash)
-	retriever := NewFusionRetriever(nil, nil, nil)
+	retriever := NewFusionRetriever(nil, nil, nil, nil, hclog.NewNullLogger())
 
 	ctx := context.Background()
 	results, err := retriever.Retrieve(ctx, "test query")
@@ -19,16 +21,44 @@ func TestRetrieve_NilClients(t *testing.T) {
 	}
 }
 
-func TestPerformRRF_Empty(t *testing.T) {
-	retriever := NewFusionRetriever(nil, nil, nil)
-	results := retriever.performRRF(nil)
+func TestRetrieveStream_NilClientsEmitsIntentThenRanked(t *testing.T) {
+	retriever := NewFusionRetriever(nil, nil, nil, nil, hclog.NewNullLogger())
+
+	events, err := retriever.RetrieveStream(context.Background(), "test query")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var types []string
+	var ranked []SearchResult
+	for event := range events {
+		types = append(types, event.Type)
+		if event.Type == "ranked" {
+			ranked = event.Results
+		}
+	}
+
+	if len(types) == 0 || types[0] != "intent_classified" {
+		t.Errorf("expected the first event to be intent_classified, got %v", types)
+	}
+	if types[len(types)-1] != "ranked" {
+		t.Errorf("expected the last event to be ranked, got %v", types)
+	}
+	if len(ranked) != 0 {
+		t.Errorf("expected 0 ranked results with nil clients, got %d", len(ranked))
+	}
+}
+
+func TestRRFRanker_Empty(t *testing.T) {
+	ranker := &RRFRanker{}
+	results := ranker.Rank(context.Background(), nil, EngineWeights{})
 	if len(results) != 0 {
 		t.Errorf("Expected 0 results, got %d", len(results))
 	}
 }
 
-func TestPerformRRF_MultiSource(t *testing.T) {
-	retriever := NewFusionRetriever(nil, nil, nil)
+func TestRRFRanker_MultiSource(t *testing.T) {
+	ranker := &RRFRanker{}
 
 	input := []SearchResult{
 		{ID: "v1", Content: "vector result 1", Score: 0.95, Source: "vector"},
@@ -36,7 +66,7 @@ func TestPerformRRF_MultiSource(t *testing.T) {
 		{ID: "g1", Content: "graph result 1", Score: 0.85, Source: "graph"},
 	}
 
-	results := retriever.performRRF(input)
+	results := ranker.Rank(context.Background(), input, EngineWeights{})
 	if len(results) != 3 {
 		t.Errorf("Expected 3 results, got %d", len(results))
 	}
@@ -47,8 +77,8 @@ func TestPerformRRF_MultiSource(t *testing.T) {
 	}
 }
 
-func TestPerformRRF_Dedup(t *testing.T) {
-	retriever := NewFusionRetriever(nil, nil, nil)
+func TestRRFRanker_Dedup(t *testing.T) {
+	ranker := &RRFRanker{}
 
 	// Same content from two sources should be deduplicated
 	input := []SearchResult{
@@ -56,7 +86,7 @@ func TestPerformRRF_Dedup(t *testing.T) {
 		{ID: "g1", Content: "shared content", Score: 0.85, Source: "graph"},
 	}
 
-	results := retriever.performRRF(input)
+	results := ranker.Rank(context.Background(), input, EngineWeights{})
 	if len(results) != 1 {
 		t.Errorf("Expected 1 deduplicated result, got %d", len(results))
 	}
@@ -0,0 +1,138 @@
+package health
+
+import (
+	"sync"
+	"time"
+)
+
+// BreakerState is one of CircuitBreaker's three states.
+type BreakerState string
+
+const (
+	StateClosed   BreakerState = "closed"
+	StateOpen     BreakerState = "open"
+	StateHalfOpen BreakerState = "half_open"
+)
+
+// DefaultFailureThreshold is how many consecutive failures trip a
+// CircuitBreaker created without an explicit threshold.
+const DefaultFailureThreshold = 5
+
+// DefaultCooldown is how long a CircuitBreaker stays Open before allowing
+// a half-open probe through.
+const DefaultCooldown = 30 * time.Second
+
+// CircuitBreaker guards a single dependency call: it trips Open after
+// FailureThreshold consecutive failures, stays Open for Cooldown, then
+// allows exactly one probe call through (HalfOpen) to decide whether to
+// close again or re-open. It's deliberately simpler than a sliding-window
+// breaker (no error-rate math, just a consecutive-failure streak) since
+// that's all FusionRetriever's per-engine "degrading gracefully" fallback
+// and SagaOrchestrator's per-step dependency calls need: a fast, cheap
+// "should I even try this engine/step right now" check.
+type CircuitBreaker struct {
+	name             string
+	failureThreshold int
+	cooldown         time.Duration
+
+	mu             sync.Mutex
+	state          BreakerState
+	consecutiveErr int
+	openedAt       time.Time
+	halfOpenInUse  bool
+}
+
+// NewCircuitBreaker creates a closed breaker. A failureThreshold <= 0
+// falls back to DefaultFailureThreshold; a cooldown <= 0 falls back to
+// DefaultCooldown.
+func NewCircuitBreaker(name string, failureThreshold int, cooldown time.Duration) *CircuitBreaker {
+	if failureThreshold <= 0 {
+		failureThreshold = DefaultFailureThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultCooldown
+	}
+	return &CircuitBreaker{
+		name:             name,
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+		state:            StateClosed,
+	}
+}
+
+// Name returns the breaker's label, e.g. the engine or step it guards.
+func (b *CircuitBreaker) Name() string {
+	return b.name
+}
+
+// Allow reports whether the caller should attempt the guarded call right
+// now. Closed always allows it. Open allows it only once Cooldown has
+// elapsed since it tripped, at which point the breaker moves to HalfOpen
+// and hands out exactly one probe -- callers that lose the race see Open
+// and should short-circuit, same as before the cooldown expired.
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateClosed:
+		return true
+	case StateHalfOpen:
+		if b.halfOpenInUse {
+			return false
+		}
+		b.halfOpenInUse = true
+		return true
+	default: // StateOpen
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = StateHalfOpen
+		b.halfOpenInUse = true
+		return true
+	}
+}
+
+// RecordSuccess closes the breaker and resets its failure streak. A
+// success during HalfOpen is what actually closes an Open breaker again.
+func (b *CircuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.consecutiveErr = 0
+	b.state = StateClosed
+	b.halfOpenInUse = false
+}
+
+// RecordFailure counts a failed call. Closed trips Open once
+// consecutiveErr reaches failureThreshold; a failed HalfOpen probe
+// re-opens immediately regardless of the threshold, since one failure is
+// enough to show the dependency hasn't recovered yet.
+func (b *CircuitBreaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateHalfOpen {
+		b.trip()
+		return
+	}
+
+	b.consecutiveErr++
+	if b.consecutiveErr >= b.failureThreshold {
+		b.trip()
+	}
+}
+
+// trip moves the breaker to Open starting a fresh cooldown. Callers must
+// hold b.mu.
+func (b *CircuitBreaker) trip() {
+	b.state = StateOpen
+	b.openedAt = time.Now()
+	b.halfOpenInUse = false
+}
+
+// State reports the breaker's current state, for /readyz reporting.
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
@@ -0,0 +1,83 @@
+package health
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker_TripsAfterConsecutiveFailures(t *testing.T) {
+	b := NewCircuitBreaker("qdrant", 3, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if !b.Allow() {
+			t.Fatalf("expected Allow() to be true before the threshold is reached")
+		}
+		b.RecordFailure()
+	}
+	if b.State() != StateClosed {
+		t.Fatalf("expected Closed after 2 of 3 failures, got %s", b.State())
+	}
+
+	b.RecordFailure()
+	if b.State() != StateOpen {
+		t.Fatalf("expected Open after 3 consecutive failures, got %s", b.State())
+	}
+	if b.Allow() {
+		t.Fatal("expected Allow() to be false immediately after tripping")
+	}
+}
+
+func TestCircuitBreaker_SuccessResetsStreak(t *testing.T) {
+	b := NewCircuitBreaker("neo4j", 3, time.Minute)
+
+	b.RecordFailure()
+	b.RecordFailure()
+	b.RecordSuccess()
+	b.RecordFailure()
+	b.RecordFailure()
+
+	if b.State() != StateClosed {
+		t.Fatalf("expected the streak to have been reset by RecordSuccess, got %s", b.State())
+	}
+}
+
+func TestCircuitBreaker_HalfOpenAfterCooldown(t *testing.T) {
+	b := NewCircuitBreaker("ollama", 1, 10*time.Millisecond)
+
+	b.RecordFailure() // trips open
+	if b.Allow() {
+		t.Fatal("expected Allow() to be false right after tripping")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if !b.Allow() {
+		t.Fatal("expected the first Allow() after cooldown to let a probe through")
+	}
+	if b.Allow() {
+		t.Fatal("expected a second concurrent Allow() to be refused while the probe is in flight")
+	}
+
+	b.RecordSuccess()
+	if b.State() != StateClosed {
+		t.Fatalf("expected a successful probe to close the breaker, got %s", b.State())
+	}
+	if !b.Allow() {
+		t.Fatal("expected Allow() to be true again once closed")
+	}
+}
+
+func TestCircuitBreaker_FailedProbeReopens(t *testing.T) {
+	b := NewCircuitBreaker("gemini", 1, 10*time.Millisecond)
+
+	b.RecordFailure() // trips open
+	time.Sleep(20 * time.Millisecond)
+
+	if !b.Allow() {
+		t.Fatal("expected the probe to be allowed through after cooldown")
+	}
+	b.RecordFailure()
+	if b.State() != StateOpen {
+		t.Fatalf("expected a failed probe to re-open the breaker, got %s", b.State())
+	}
+}
@@ -0,0 +1,194 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Prober is a dependency that can report whether it's reachable. Each
+// concrete client (qdrant.Client, neo4j.Client, llm.LocalOllamaClient,
+// llm.GeminiClient, bunstore's store) implements it and registers itself
+// into a Registry so /readyz can probe all of them without the server
+// package needing to know each client's concrete type.
+type Prober interface {
+	Name() string
+	Ping(ctx context.Context) error
+}
+
+// errorWindowSpan is how far back ErrorRate looks when computing a
+// component's rolling error rate.
+const errorWindowSpan = 5 * time.Minute
+
+// errorWindow is a rolling record of recent Ping outcomes for one
+// component, used to report an error rate independent of CircuitBreaker's
+// own consecutive-failure streak (a component can be flaky -- failing one
+// ping in three -- without ever tripping a breaker).
+type errorWindow struct {
+	mu     sync.Mutex
+	events []errorWindowEvent
+}
+
+type errorWindowEvent struct {
+	at     time.Time
+	failed bool
+}
+
+func (w *errorWindow) record(failed bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.events = append(w.events, errorWindowEvent{at: time.Now(), failed: failed})
+	w.evictLocked()
+}
+
+// rate returns the fraction of recorded outcomes within errorWindowSpan
+// that failed, or 0 if nothing has been recorded yet.
+func (w *errorWindow) rate() float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.evictLocked()
+	if len(w.events) == 0 {
+		return 0
+	}
+	var failed int
+	for _, e := range w.events {
+		if e.failed {
+			failed++
+		}
+	}
+	return float64(failed) / float64(len(w.events))
+}
+
+func (w *errorWindow) evictLocked() {
+	cutoff := time.Now().Add(-errorWindowSpan)
+	i := 0
+	for ; i < len(w.events); i++ {
+		if w.events[i].at.After(cutoff) {
+			break
+		}
+	}
+	w.events = w.events[i:]
+}
+
+// ComponentStatus is one component's entry in a /readyz response.
+type ComponentStatus struct {
+	Status       string  `json:"status"` // "up" or "down"
+	LatencyMS    float64 `json:"latency_ms"`
+	LastError    string  `json:"last_error,omitempty"`
+	ErrorRate    float64 `json:"error_rate"`
+	BreakerState string  `json:"breaker_state,omitempty"`
+}
+
+// Registry collects every dependency's Prober (and, optionally, the
+// CircuitBreaker guarding calls to it) so a single Check call produces
+// the full /readyz picture.
+type Registry struct {
+	mu       sync.Mutex
+	probers  []Prober
+	windows  map[string]*errorWindow
+	breakers map[string]*CircuitBreaker
+}
+
+// NewRegistry creates an empty Registry; Register and RegisterBreaker add
+// to it as each dependency is constructed.
+func NewRegistry() *Registry {
+	return &Registry{
+		windows:  make(map[string]*errorWindow),
+		breakers: make(map[string]*CircuitBreaker),
+	}
+}
+
+// Register adds a dependency to the set Check pings. Call it once per
+// Prober as each client is constructed, same as FusionRetriever's
+// WithIntentFeedbackRepository pattern for optional dependencies.
+func (r *Registry) Register(p Prober) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.probers = append(r.probers, p)
+	if _, ok := r.windows[p.Name()]; !ok {
+		r.windows[p.Name()] = &errorWindow{}
+	}
+}
+
+// RegisterBreaker attaches a CircuitBreaker so Check reports its state
+// alongside the component it guards. name should match the corresponding
+// Prober.Name() so the two line up in the /readyz response, but a breaker
+// can also be registered standalone for an engine that isn't itself a
+// Prober (e.g. FusionRetriever's per-engine breakers, which guard calls
+// through qdrant.Client/neo4j.Client rather than pinging them directly).
+func (r *Registry) RegisterBreaker(name string, b *CircuitBreaker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.breakers[name] = b
+}
+
+// Check pings every registered Prober concurrently with the given
+// deadline and returns a status keyed by component name. A Ping that
+// doesn't return within timeout counts as a failure with a "context
+// deadline exceeded" LastError, same as any other error.
+func (r *Registry) Check(ctx context.Context, timeout time.Duration) map[string]ComponentStatus {
+	r.mu.Lock()
+	probers := append([]Prober(nil), r.probers...)
+	r.mu.Unlock()
+
+	results := make(map[string]ComponentStatus, len(probers))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, p := range probers {
+		p := p
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status := r.ping(ctx, p, timeout)
+			mu.Lock()
+			results[p.Name()] = status
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	r.mu.Lock()
+	for name, b := range r.breakers {
+		status, ok := results[name]
+		if !ok {
+			status = ComponentStatus{Status: "unknown"}
+		}
+		status.BreakerState = string(b.State())
+		results[name] = status
+	}
+	r.mu.Unlock()
+
+	return results
+}
+
+func (r *Registry) ping(ctx context.Context, p Prober, timeout time.Duration) ComponentStatus {
+	pingCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := p.Ping(pingCtx)
+	latencyMS := float64(time.Since(start).Microseconds()) / 1000
+
+	r.mu.Lock()
+	window := r.windows[p.Name()]
+	r.mu.Unlock()
+
+	status := ComponentStatus{LatencyMS: latencyMS}
+	if err != nil {
+		status.Status = "down"
+		status.LastError = err.Error()
+		if window != nil {
+			window.record(true)
+		}
+	} else {
+		status.Status = "up"
+		if window != nil {
+			window.record(false)
+		}
+	}
+	if window != nil {
+		status.ErrorRate = window.rate()
+	}
+	return status
+}
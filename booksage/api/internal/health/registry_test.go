@@ -0,0 +1,63 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type stubProber struct {
+	name string
+	err  error
+}
+
+func (s *stubProber) Name() string { return s.name }
+func (s *stubProber) Ping(ctx context.Context) error {
+	return s.err
+}
+
+func TestRegistry_Check_ReportsUpAndDown(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&stubProber{name: "qdrant"})
+	r.Register(&stubProber{name: "neo4j", err: errors.New("connection refused")})
+
+	statuses := r.Check(context.Background(), time.Second)
+
+	if statuses["qdrant"].Status != "up" {
+		t.Errorf("expected qdrant to be up, got %+v", statuses["qdrant"])
+	}
+	if statuses["neo4j"].Status != "down" || statuses["neo4j"].LastError != "connection refused" {
+		t.Errorf("expected neo4j to be down with an error, got %+v", statuses["neo4j"])
+	}
+}
+
+func TestRegistry_Check_IncludesBreakerState(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&stubProber{name: "vector"})
+	breaker := NewCircuitBreaker("vector", 1, time.Minute)
+	breaker.RecordFailure()
+	r.RegisterBreaker("vector", breaker)
+
+	statuses := r.Check(context.Background(), time.Second)
+
+	if statuses["vector"].BreakerState != string(StateOpen) {
+		t.Errorf("expected breaker state Open to surface on the component, got %+v", statuses["vector"])
+	}
+}
+
+func TestRegistry_Check_ErrorRateReflectsRecentFailures(t *testing.T) {
+	r := NewRegistry()
+	p := &stubProber{name: "ollama"}
+	r.Register(p)
+
+	r.Check(context.Background(), time.Second) // up
+	p.err = errors.New("timeout")
+	r.Check(context.Background(), time.Second) // down
+	r.Check(context.Background(), time.Second) // down
+
+	statuses := r.Check(context.Background(), time.Second) // down
+	if rate := statuses["ollama"].ErrorRate; rate < 0.74 || rate > 0.76 {
+		t.Errorf("expected an error rate around 0.75 (3/4 down), got %v", rate)
+	}
+}
@@ -0,0 +1,116 @@
+// Package observability wires up the OpenTelemetry tracer/meter providers
+// used to instrument the ingestion pipeline. It is deliberately independent
+// of any business-logic package: callers obtain a *Telemetry at startup and
+// everything else pulls the global otel.Tracer/otel.Meter, so instrumented
+// code never imports an SDK directly.
+package observability
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// Telemetry bundles the tracer and meter providers installed as the global
+// defaults, plus the HTTP handler operators wire up to scrape metrics.
+type Telemetry struct {
+	tracerProvider *sdktrace.TracerProvider
+	meterProvider  *metric.MeterProvider
+	metricsHandler http.Handler
+}
+
+// Config controls how the tracer/meter providers are constructed.
+type Config struct {
+	// ServiceName identifies this process in exported spans, e.g. "booksage-api".
+	ServiceName string
+	// ServiceVersion is attached to every span/metric as a resource attribute.
+	ServiceVersion string
+	// OTLPEndpoint is the host:port of an OTLP/gRPC collector (Jaeger,
+	// Tempo, the OTel Collector, ...) spans should be exported to, e.g.
+	// "localhost:4317". Empty disables the exporter: spans are still
+	// created and can be inspected by anything reading the global tracer,
+	// but nothing ships them anywhere -- useful for local dev without a
+	// collector running.
+	OTLPEndpoint string
+	// OTLPInsecure disables TLS on the OTLP/gRPC connection. Only meant
+	// for talking to a collector on localhost or inside a trusted network.
+	OTLPInsecure bool
+}
+
+// NewTelemetry builds a TracerProvider and a MeterProvider backed by a
+// Prometheus collector, then installs both as the global OpenTelemetry
+// providers. If cfg.OTLPEndpoint is set, the TracerProvider also batches
+// and exports spans to it over gRPC so operators can point BookSage at
+// Jaeger/Tempo without recompiling; the global propagator is set to W3C
+// tracecontext+baggage so an incoming request's traceparent header
+// threads through as the parent of everything BookSage does for it.
+func NewTelemetry(cfg Config) (*Telemetry, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.ServiceVersion(cfg.ServiceVersion),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("observability: failed to build resource: %w", err)
+	}
+
+	tpOpts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+	if cfg.OTLPEndpoint != "" {
+		exporterOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.OTLPEndpoint)}
+		if cfg.OTLPInsecure {
+			exporterOpts = append(exporterOpts, otlptracegrpc.WithInsecure())
+		}
+		spanExporter, err := otlptracegrpc.New(context.Background(), exporterOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("observability: failed to create OTLP exporter: %w", err)
+		}
+		tpOpts = append(tpOpts, sdktrace.WithBatcher(spanExporter))
+	}
+
+	tp := sdktrace.NewTracerProvider(tpOpts...)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	exporter, err := prometheus.New()
+	if err != nil {
+		return nil, fmt.Errorf("observability: failed to create prometheus exporter: %w", err)
+	}
+	mp := metric.NewMeterProvider(metric.WithResource(res), metric.WithReader(exporter))
+	otel.SetMeterProvider(mp)
+
+	return &Telemetry{
+		tracerProvider: tp,
+		meterProvider:  mp,
+		metricsHandler: promhttp.Handler(),
+	}, nil
+}
+
+// MetricsHandler returns the HTTP handler that serves the Prometheus scrape
+// endpoint (typically mounted at /metrics).
+func (t *Telemetry) MetricsHandler() http.Handler {
+	return t.metricsHandler
+}
+
+// Shutdown flushes and releases the tracer/meter providers. It should be
+// called once during graceful shutdown.
+func (t *Telemetry) Shutdown(ctx context.Context) error {
+	if err := t.tracerProvider.Shutdown(ctx); err != nil {
+		return fmt.Errorf("observability: tracer provider shutdown: %w", err)
+	}
+	if err := t.meterProvider.Shutdown(ctx); err != nil {
+		return fmt.Errorf("observability: meter provider shutdown: %w", err)
+	}
+	return nil
+}
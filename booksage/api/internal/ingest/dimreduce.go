@@ -0,0 +1,129 @@
+package ingest
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// reduceDimensions projects vectors into a lower-dimensional space so GMM
+// clustering doesn't have to fight the curse of dimensionality on raw
+// embedding vectors (RAPTOR's published recipe is UMAP followed by GMM).
+// This is a lightweight, dependency-free stand-in for full UMAP: it builds
+// a k-nearest-neighbor graph in the original space, then runs a force-
+// directed layout in the target space that pulls neighbors together and
+// pushes non-neighbors apart. It preserves local neighborhoods well enough
+// for clustering without needing UMAP's fuzzy simplicial set machinery or
+// an external numerics dependency. vectors shorter than targetDim already
+// are returned unchanged.
+func reduceDimensions(vectors [][]float64, targetDim, neighbors int) [][]float64 {
+	n := len(vectors)
+	if n == 0 {
+		return nil
+	}
+	if targetDim >= len(vectors[0]) {
+		return vectors
+	}
+	if neighbors >= n {
+		neighbors = n - 1
+	}
+
+	neighborIdx := make([][]int, n)
+	for i := range vectors {
+		neighborIdx[i] = nearestNeighbors(vectors, i, neighbors)
+	}
+
+	// Deterministic seed: reproducible layouts make the resulting tree
+	// stable across re-ingests of the same document.
+	rng := rand.New(rand.NewSource(42))
+	embedding := make([][]float64, n)
+	for i := range embedding {
+		embedding[i] = make([]float64, targetDim)
+		for d := range embedding[i] {
+			embedding[i][d] = rng.NormFloat64() * 0.01
+		}
+	}
+
+	const iterations = 200
+	const attraction = 1.0
+	const repulsion = 0.01
+	learningRate := 1.0
+
+	for iter := 0; iter < iterations; iter++ {
+		lr := learningRate * (1 - float64(iter)/float64(iterations))
+
+		for i := range embedding {
+			// Attractive force toward this point's neighbors.
+			for _, j := range neighborIdx[i] {
+				applyForce(embedding[i], embedding[j], attraction*lr)
+			}
+			// Repulsive force against a small random sample of non-neighbors,
+			// standing in for UMAP's negative sampling.
+			for s := 0; s < neighbors; s++ {
+				j := rng.Intn(n)
+				if j == i || isNeighbor(neighborIdx[i], j) {
+					continue
+				}
+				applyForce(embedding[i], embedding[j], -repulsion*lr)
+			}
+		}
+	}
+
+	return embedding
+}
+
+// applyForce nudges point a toward (strength > 0) or away from (strength <
+// 0) point b, scaled inversely by distance so nearby points aren't flung
+// apart by a single repulsive step.
+func applyForce(a, b []float64, strength float64) {
+	dist := euclidean(a, b)
+	if dist < 1e-6 {
+		dist = 1e-6
+	}
+	for d := range a {
+		delta := (b[d] - a[d]) / dist * strength
+		a[d] += delta
+	}
+}
+
+func nearestNeighbors(vectors [][]float64, i, k int) []int {
+	type distIdx struct {
+		dist float64
+		idx  int
+	}
+	dists := make([]distIdx, 0, len(vectors)-1)
+	for j := range vectors {
+		if j == i {
+			continue
+		}
+		dists = append(dists, distIdx{dist: euclidean(vectors[i], vectors[j]), idx: j})
+	}
+	sort.Slice(dists, func(a, b int) bool { return dists[a].dist < dists[b].dist })
+
+	if k > len(dists) {
+		k = len(dists)
+	}
+	out := make([]int, k)
+	for idx := 0; idx < k; idx++ {
+		out[idx] = dists[idx].idx
+	}
+	return out
+}
+
+func isNeighbor(neighbors []int, idx int) bool {
+	for _, n := range neighbors {
+		if n == idx {
+			return true
+		}
+	}
+	return false
+}
+
+func euclidean(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		diff := a[i] - b[i]
+		sum += diff * diff
+	}
+	return math.Sqrt(sum)
+}
@@ -0,0 +1,255 @@
+package ingest
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// gaussianComponent is one cluster of a diagonal-covariance Gaussian
+// Mixture Model. Diagonal (rather than full) covariance keeps fitting
+// numerically stable on the small, sparsely-populated clusters a single
+// document's chunks produce, without needing a matrix-inversion library.
+type gaussianComponent struct {
+	weight   float64
+	mean     []float64
+	variance []float64 // diagonal of the covariance matrix
+}
+
+// fitGMM clusters vectors with a Gaussian Mixture Model fit by EM,
+// choosing the number of components k in [1, maxK] by Bayesian Information
+// Criterion -- the same model-selection approach RAPTOR's reference
+// implementation uses so the tree's branching factor adapts to how many
+// natural groups the chunks actually form, instead of a fixed k.
+// It returns the best BIC model's hard cluster assignment per vector, the
+// fitted components (so callers can derive soft posterior-probability
+// memberships via responsibilities), and whether EM converged for that
+// specific k -- not whether any candidate k converged, since the trivial
+// k=1 model converges almost by definition regardless of whether the k
+// BIC actually picked did. A caller that needs a hard guarantee of
+// well-formed clusters should fall back to something else (e.g. k-means)
+// when converged is false rather than trust this result.
+func fitGMM(vectors [][]float64, maxK int) ([]int, int, []gaussianComponent, bool, error) {
+	n := len(vectors)
+	if n == 0 {
+		return nil, 0, nil, false, fmt.Errorf("gmm: no vectors to cluster")
+	}
+	if maxK > n {
+		maxK = n
+	}
+	if maxK < 1 {
+		maxK = 1
+	}
+
+	rng := rand.New(rand.NewSource(42))
+
+	var bestLabels []int
+	var bestComponents []gaussianComponent
+	var bestK int
+	var bestConverged bool
+	bestBIC := math.Inf(1)
+
+	for k := 1; k <= maxK; k++ {
+		components := initComponents(vectors, k, rng)
+		logLikelihood, converged := runEM(vectors, components)
+
+		numParams := float64(k) * float64(1+2*len(vectors[0])) // weight + mean + variance per dim, per component
+		bic := -2*logLikelihood + numParams*math.Log(float64(n))
+
+		if bic < bestBIC {
+			bestBIC = bic
+			bestK = k
+			bestComponents = components
+			bestLabels = assignLabels(vectors, components)
+			bestConverged = converged
+		}
+	}
+
+	return bestLabels, bestK, bestComponents, bestConverged, nil
+}
+
+// responsibilities returns, for each vector, its posterior probability of
+// membership in each component -- the same quantity EM's E-step computes,
+// exposed here so callers can threshold it for RAPTOR's soft cluster
+// assignment (a chunk belonging to more than one summary node).
+func responsibilities(vectors [][]float64, components []gaussianComponent) [][]float64 {
+	out := make([][]float64, len(vectors))
+	for i, v := range vectors {
+		weights := make([]float64, len(components))
+		var total float64
+		for c, comp := range components {
+			weights[c] = comp.weight * gaussianPDF(v, comp.mean, comp.variance)
+			total += weights[c]
+		}
+		if total < 1e-300 {
+			total = 1e-300
+		}
+		for c := range weights {
+			weights[c] /= total
+		}
+		out[i] = weights
+	}
+	return out
+}
+
+func initComponents(vectors [][]float64, k int, rng *rand.Rand) []gaussianComponent {
+	dim := len(vectors[0])
+	components := make([]gaussianComponent, k)
+
+	// k-means++-style seeding: pick the first center at random, then each
+	// subsequent one weighted by squared distance to the nearest center
+	// already chosen, so initial clusters start spread apart.
+	centers := []int{rng.Intn(len(vectors))}
+	for len(centers) < k {
+		weights := make([]float64, len(vectors))
+		var total float64
+		for i, v := range vectors {
+			minDist := math.Inf(1)
+			for _, c := range centers {
+				if d := euclidean(v, vectors[c]); d < minDist {
+					minDist = d
+				}
+			}
+			weights[i] = minDist * minDist
+			total += weights[i]
+		}
+		if total == 0 {
+			centers = append(centers, rng.Intn(len(vectors)))
+			continue
+		}
+		target := rng.Float64() * total
+		var cum float64
+		for i, w := range weights {
+			cum += w
+			if cum >= target {
+				centers = append(centers, i)
+				break
+			}
+		}
+	}
+
+	for i, c := range centers {
+		mean := append([]float64(nil), vectors[c]...)
+		variance := make([]float64, dim)
+		for d := range variance {
+			variance[d] = 1.0 // isotropic until the first M-step updates it
+		}
+		components[i] = gaussianComponent{weight: 1.0 / float64(k), mean: mean, variance: variance}
+	}
+	return components
+}
+
+// runEM iterates Expectation-Maximization to convergence (or a fixed
+// iteration cap) and returns the final data log-likelihood, which fitGMM
+// uses to score this k against the others via BIC, plus whether the
+// log-likelihood actually settled within tolerance before the cap rather
+// than merely being cut off.
+func runEM(vectors [][]float64, components []gaussianComponent) (float64, bool) {
+	const maxIterations = 100
+	const tolerance = 1e-4
+	const minVariance = 1e-6
+
+	n := len(vectors)
+	prevLogLikelihood := math.Inf(-1)
+	converged := false
+
+	for iter := 0; iter < maxIterations; iter++ {
+		// E-step
+		resp := responsibilities(vectors, components)
+		logLikelihood := 0.0
+		for _, v := range vectors {
+			var total float64
+			for _, comp := range components {
+				total += comp.weight * gaussianPDF(v, comp.mean, comp.variance)
+			}
+			if total < 1e-300 {
+				total = 1e-300
+			}
+			logLikelihood += math.Log(total)
+		}
+
+		// M-step
+		for c := range components {
+			var weightSum float64
+			for i := range vectors {
+				weightSum += resp[i][c]
+			}
+			if weightSum < 1e-300 {
+				weightSum = 1e-300
+			}
+
+			mean := make([]float64, len(components[c].mean))
+			for i, v := range vectors {
+				for d, x := range v {
+					mean[d] += resp[i][c] * x
+				}
+			}
+			for d := range mean {
+				mean[d] /= weightSum
+			}
+
+			variance := make([]float64, len(mean))
+			for i, v := range vectors {
+				for d, x := range v {
+					diff := x - mean[d]
+					variance[d] += resp[i][c] * diff * diff
+				}
+			}
+			for d := range variance {
+				variance[d] /= weightSum
+				if variance[d] < minVariance {
+					variance[d] = minVariance
+				}
+			}
+
+			components[c].weight = weightSum / float64(n)
+			components[c].mean = mean
+			components[c].variance = variance
+		}
+
+		if math.Abs(logLikelihood-prevLogLikelihood) < tolerance {
+			prevLogLikelihood = logLikelihood
+			converged = true
+			break
+		}
+		prevLogLikelihood = logLikelihood
+	}
+
+	return prevLogLikelihood, converged
+}
+
+func assignLabels(vectors [][]float64, components []gaussianComponent) []int {
+	labels := make([]int, len(vectors))
+	for i, v := range vectors {
+		best := 0
+		bestProb := math.Inf(-1)
+		for c, comp := range components {
+			prob := math.Log(comp.weight) + gaussianLogPDF(v, comp.mean, comp.variance)
+			if prob > bestProb {
+				bestProb = prob
+				best = c
+			}
+		}
+		labels[i] = best
+	}
+	return labels
+}
+
+// gaussianLogPDF evaluates the log-density of a diagonal multivariate
+// Gaussian; gaussianPDF exponentiates it. Working in log-space during the
+// density comparison in assignLabels avoids needless under/overflow on
+// high-dimensional vectors.
+func gaussianLogPDF(x, mean, variance []float64) float64 {
+	var sum float64
+	logDet := 0.0
+	for d := range x {
+		diff := x[d] - mean[d]
+		sum += (diff * diff) / variance[d]
+		logDet += math.Log(variance[d])
+	}
+	return -0.5 * (sum + logDet + float64(len(x))*math.Log(2*math.Pi))
+}
+
+func gaussianPDF(x, mean, variance []float64) float64 {
+	return math.Exp(gaussianLogPDF(x, mean, variance))
+}
@@ -0,0 +1,237 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// InMemoryVectorStore implements QdrantClient with a map-based nearest
+// neighbor store (cosine similarity over whatever chunks InsertChunks was
+// given), for tests and single-node/demo deployments that don't want to
+// stand up a real Qdrant instance. Unlike MockQdrantClient, which only logs
+// and returns canned answers, this actually stores what it's given and
+// answers DocumentExists/Search from it -- so a test exercising it isn't
+// just exercising the call shape, it's exercising real insert/lookup
+// behavior too.
+type InMemoryVectorStore struct {
+	mu     sync.RWMutex
+	chunks map[string][]memoryChunk // keyed by docID
+}
+
+// memoryChunk is one InsertChunks entry as InMemoryVectorStore retains it:
+// whatever the "id"/"text"/"vector" keys of the chunk map held, pulled out
+// once at insert time rather than re-asserted on every lookup.
+type memoryChunk struct {
+	id     string
+	text   string
+	vector []float32
+}
+
+// NewInMemoryVectorStore creates an empty vector store.
+func NewInMemoryVectorStore() *InMemoryVectorStore {
+	return &InMemoryVectorStore{chunks: make(map[string][]memoryChunk)}
+}
+
+// InsertChunks stores chunks for docID, replacing any that were already
+// there for the same chunk ID. Each chunk is expected to be a
+// map[string]any with "id", "text", and "vector" keys, matching
+// qdrant.Client.InsertChunks' contract.
+func (s *InMemoryVectorStore) InsertChunks(ctx context.Context, docID string, chunks []any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, chunk := range chunks {
+		m, ok := chunk.(map[string]any)
+		if !ok {
+			return fmt.Errorf("chunk %d: expected map[string]any, got %T", i, chunk)
+		}
+
+		id, _ := m["id"].(string)
+		if id == "" {
+			id = fmt.Sprintf("%s-chunk-%d", docID, i)
+		}
+		text, _ := m["text"].(string)
+
+		vector, err := toFloat32Vector(m["vector"])
+		if err != nil {
+			return fmt.Errorf("chunk %d: %w", i, err)
+		}
+
+		s.chunks[docID] = append(upsertChunk(s.chunks[docID], id), memoryChunk{id: id, text: text, vector: vector})
+	}
+	return nil
+}
+
+// upsertChunk drops any existing entry for id from existing, so InsertChunks
+// replaces rather than duplicates a re-inserted chunk.
+func upsertChunk(existing []memoryChunk, id string) []memoryChunk {
+	out := existing[:0]
+	for _, c := range existing {
+		if c.id != id {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// DeleteDocument drops every chunk stored for docID.
+func (s *InMemoryVectorStore) DeleteDocument(ctx context.Context, docID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.chunks, docID)
+	return nil
+}
+
+// DocumentExists reports whether any chunks are stored for docID.
+func (s *InMemoryVectorStore) DocumentExists(ctx context.Context, docID string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.chunks[docID]) > 0, nil
+}
+
+// Search returns up to limit chunks across every document ranked by cosine
+// similarity to query, a much plainer substitute for Qdrant's HNSW index
+// that's fine for the small corpora a demo/single-node deployment or a test
+// actually holds.
+func (s *InMemoryVectorStore) Search(ctx context.Context, query []float32, limit int) ([]SearchHit, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var hits []SearchHit
+	for docID, chunks := range s.chunks {
+		for _, c := range chunks {
+			hits = append(hits, SearchHit{DocID: docID, ChunkID: c.id, Text: c.text, Score: cosineSimilarity(query, c.vector)})
+		}
+	}
+
+	sortSearchHitsDescending(hits)
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+// SearchHit is one InMemoryVectorStore.Search result.
+type SearchHit struct {
+	DocID   string
+	ChunkID string
+	Text    string
+	Score   float32
+}
+
+// sortSearchHitsDescending sorts hits by Score, highest first.
+func sortSearchHitsDescending(hits []SearchHit) {
+	for i := 1; i < len(hits); i++ {
+		for j := i; j > 0 && hits[j].Score > hits[j-1].Score; j-- {
+			hits[j], hits[j-1] = hits[j-1], hits[j]
+		}
+	}
+}
+
+// cosineSimilarity returns the cosine similarity of a and b, or 0 if either
+// is empty or a zero vector (rather than dividing by zero).
+func cosineSimilarity(a, b []float32) float32 {
+	if len(a) == 0 || len(b) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return float32(dot / (math.Sqrt(normA) * math.Sqrt(normB)))
+}
+
+// toFloat32Vector converts a chunk's "vector" payload value into []float32,
+// mirroring qdrant.toFloat32Slice's supported shapes.
+func toFloat32Vector(v any) ([]float32, error) {
+	switch vt := v.(type) {
+	case []float32:
+		return vt, nil
+	case []float64:
+		out := make([]float32, len(vt))
+		for i, f := range vt {
+			out[i] = float32(f)
+		}
+		return out, nil
+	case []any:
+		out := make([]float32, len(vt))
+		for i, elem := range vt {
+			switch n := elem.(type) {
+			case float32:
+				out[i] = n
+			case float64:
+				out[i] = float32(n)
+			default:
+				return nil, fmt.Errorf("element %d: unsupported type %T", i, elem)
+			}
+		}
+		return out, nil
+	case nil:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported vector type %T", v)
+	}
+}
+
+// InMemoryGraphStore implements Neo4jClient with a plain adjacency-list
+// graph, for tests and single-node/demo deployments that don't want to
+// stand up a real Neo4j instance.
+type InMemoryGraphStore struct {
+	mu           sync.RWMutex
+	nodes        map[string][]any // docID -> entity/relationship nodes
+	summaryNodes map[string][]any // docID -> RAPTOR summary nodes
+}
+
+// NewInMemoryGraphStore creates an empty graph store.
+func NewInMemoryGraphStore() *InMemoryGraphStore {
+	return &InMemoryGraphStore{
+		nodes:        make(map[string][]any),
+		summaryNodes: make(map[string][]any),
+	}
+}
+
+// InsertNodesAndEdges appends nodes to docID's adjacency list.
+func (s *InMemoryGraphStore) InsertNodesAndEdges(ctx context.Context, docID string, nodes []any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nodes[docID] = append(s.nodes[docID], nodes...)
+	return nil
+}
+
+// InsertSummaryNodes appends RAPTOR summary nodes to docID's summary set.
+func (s *InMemoryGraphStore) InsertSummaryNodes(ctx context.Context, docID string, nodes []any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.summaryNodes[docID] = append(s.summaryNodes[docID], nodes...)
+	return nil
+}
+
+// HasSummaryNodes reports whether docID has any RAPTOR summary nodes.
+func (s *InMemoryGraphStore) HasSummaryNodes(ctx context.Context, docID string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.summaryNodes[docID]) > 0, nil
+}
+
+// DeleteDocumentNodes drops every node and summary node stored for docID.
+func (s *InMemoryGraphStore) DeleteDocumentNodes(ctx context.Context, docID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.nodes, docID)
+	delete(s.summaryNodes, docID)
+	return nil
+}
+
+// DocumentExists reports whether any nodes are stored for docID.
+func (s *InMemoryGraphStore) DocumentExists(ctx context.Context, docID string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.nodes[docID]) > 0, nil
+}
@@ -0,0 +1,122 @@
+package ingest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/booksage/booksage-api/internal/database/models"
+	"github.com/hashicorp/go-hclog"
+)
+
+func TestInMemoryVectorStore_InsertThenSearch(t *testing.T) {
+	store := NewInMemoryVectorStore()
+
+	err := store.InsertChunks(context.Background(), "doc-1", []any{
+		map[string]any{"id": "c1", "text": "a cat sat on a mat", "vector": []float32{1, 0, 0}},
+		map[string]any{"id": "c2", "text": "a dog ran in the park", "vector": []float32{0, 1, 0}},
+	})
+	if err != nil {
+		t.Fatalf("InsertChunks failed: %v", err)
+	}
+
+	exists, err := store.DocumentExists(context.Background(), "doc-1")
+	if err != nil || !exists {
+		t.Fatalf("expected doc-1 to exist, got exists=%v err=%v", exists, err)
+	}
+
+	hits, err := store.Search(context.Background(), []float32{1, 0, 0}, 1)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(hits) != 1 || hits[0].ChunkID != "c1" {
+		t.Fatalf("expected the closest chunk c1 first, got %+v", hits)
+	}
+
+	if err := store.DeleteDocument(context.Background(), "doc-1"); err != nil {
+		t.Fatalf("DeleteDocument failed: %v", err)
+	}
+	exists, _ = store.DocumentExists(context.Background(), "doc-1")
+	if exists {
+		t.Error("expected doc-1 to no longer exist after DeleteDocument")
+	}
+}
+
+func TestInMemoryVectorStore_InsertReplacesSameChunkID(t *testing.T) {
+	store := NewInMemoryVectorStore()
+	ctx := context.Background()
+
+	mustInsert := func(text string) {
+		if err := store.InsertChunks(ctx, "doc-1", []any{
+			map[string]any{"id": "c1", "text": text, "vector": []float32{1, 0, 0}},
+		}); err != nil {
+			t.Fatalf("InsertChunks failed: %v", err)
+		}
+	}
+	mustInsert("original text")
+	mustInsert("updated text")
+
+	hits, err := store.Search(ctx, []float32{1, 0, 0}, 10)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(hits) != 1 || hits[0].Text != "updated text" {
+		t.Fatalf("expected a single, updated chunk, got %+v", hits)
+	}
+}
+
+func TestInMemoryGraphStore_InsertAndDelete(t *testing.T) {
+	store := NewInMemoryGraphStore()
+	ctx := context.Background()
+
+	if err := store.InsertNodesAndEdges(ctx, "doc-1", []any{"node1", "node2"}); err != nil {
+		t.Fatalf("InsertNodesAndEdges failed: %v", err)
+	}
+	if err := store.InsertSummaryNodes(ctx, "doc-1", []any{"summary1"}); err != nil {
+		t.Fatalf("InsertSummaryNodes failed: %v", err)
+	}
+
+	exists, err := store.DocumentExists(ctx, "doc-1")
+	if err != nil || !exists {
+		t.Fatalf("expected doc-1 to exist, got exists=%v err=%v", exists, err)
+	}
+	hasSummaries, err := store.HasSummaryNodes(ctx, "doc-1")
+	if err != nil || !hasSummaries {
+		t.Fatalf("expected doc-1 to have summary nodes, got %v err=%v", hasSummaries, err)
+	}
+
+	if err := store.DeleteDocumentNodes(ctx, "doc-1"); err != nil {
+		t.Fatalf("DeleteDocumentNodes failed: %v", err)
+	}
+	exists, _ = store.DocumentExists(ctx, "doc-1")
+	if exists {
+		t.Error("expected doc-1 to no longer exist after DeleteDocumentNodes")
+	}
+}
+
+// TestOrchestrator_RunIngestionSagaAgainstInMemoryBackend exercises
+// RunIngestionSaga against InMemoryVectorStore/InMemoryGraphStore instead of
+// the canned-response Mock* stand-ins, so a single-node/demo deployment (or
+// a test that wants real insert/lookup behavior rather than a scripted call
+// count) has a concrete backend it can actually construct an Orchestrator
+// around without a live Qdrant/Neo4j.
+func TestOrchestrator_RunIngestionSagaAgainstInMemoryBackend(t *testing.T) {
+	vectorStore := NewInMemoryVectorStore()
+	graphStore := NewInMemoryGraphStore()
+	docRepo := &MockDocumentRepository{}
+	sagaRepo := &MockSagaRepository{}
+
+	orch := NewOrchestrator(vectorStore, graphStore, docRepo, sagaRepo, hclog.NewNullLogger())
+
+	saga := &models.IngestSaga{ID: 1, DocumentID: 1, Version: 1}
+	chunks := []any{map[string]any{"id": "c1", "text": "hello world", "vector": []float32{1, 0, 0}}}
+	nodes := []any{"node1"}
+
+	if err := orch.RunIngestionSaga(context.Background(), saga, chunks, nodes); err != nil {
+		t.Fatalf("RunIngestionSaga failed: %v", err)
+	}
+
+	exists, err := vectorStore.DocumentExists(context.Background(), "1")
+	if err != nil || !exists {
+		t.Errorf("expected the saga's document to be indexed in the vector store, got exists=%v err=%v", exists, err)
+	}
+}
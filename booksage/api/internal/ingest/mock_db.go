@@ -47,6 +47,15 @@ func (m *MockNeo4jClient) InsertNodesAndEdges(ctx context.Context, docID string,
 	return nil
 }
 
+func (m *MockNeo4jClient) InsertSummaryNodes(ctx context.Context, docID string, nodes []any) error {
+	log.Printf("[MockNeo4j] Inserted %d summary nodes for doc %s", len(nodes), docID)
+	return nil
+}
+
+func (m *MockNeo4jClient) HasSummaryNodes(ctx context.Context, docID string) (bool, error) {
+	return false, nil
+}
+
 func (m *MockNeo4jClient) DeleteDocumentNodes(ctx context.Context, docID string) error {
 	log.Printf("[MockNeo4j] Deleted nodes for doc %s", docID)
 	return nil
@@ -78,6 +87,12 @@ func (m *MockDocumentRepository) GetDocumentByHash(ctx context.Context, hash []b
 func (m *MockDocumentRepository) DeleteDocument(ctx context.Context, id int64) error {
 	return nil
 }
+func (m *MockDocumentRepository) UpdateDocumentHash(ctx context.Context, id int64, hash []byte) error {
+	return nil
+}
+func (m *MockDocumentRepository) ListAllDocuments(ctx context.Context) ([]*models.Document, error) {
+	return nil, nil
+}
 
 // MockSagaRepository
 type MockSagaRepository struct{}
@@ -103,3 +118,6 @@ func (m *MockSagaRepository) UpsertSagaStep(ctx context.Context, step *models.Sa
 func (m *MockSagaRepository) GetSagaSteps(ctx context.Context, sagaID int64) ([]*models.SagaStep, error) {
 	return nil, nil
 }
+func (m *MockSagaRepository) ListAllSagas(ctx context.Context) ([]*models.IngestSaga, error) {
+	return nil, nil
+}
@@ -0,0 +1,383 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+
+	"github.com/booksage/booksage-api/internal/embedding"
+	"github.com/booksage/booksage-api/internal/llm"
+)
+
+const (
+	// raptorMaxDepth bounds the recursive summarization so a pathological
+	// clustering (one that never narrows to a single root) can't loop
+	// forever.
+	raptorMaxDepth = 5
+	// raptorMinLeaves is the fewest chunks BuildTree will bother clustering;
+	// below this there's nothing meaningful to summarize.
+	raptorMinLeaves = 2
+	// raptorTargetClusterSize is the number of members BuildTree aims for
+	// per cluster at each level; cluster count is derived from it rather
+	// than fixed, so a short document gets a shallow, narrow tree and a
+	// long one gets a wider first level.
+	raptorTargetClusterSize = 5
+	// raptorMaxClusters caps how many clusters a single level can split
+	// into, keeping the tree's branching factor bounded regardless of how
+	// many chunks a document has.
+	raptorMaxClusters = 10
+	// raptorKMeansIterations is the fixed number of Lloyd's-algorithm
+	// iterations BuildTree runs per level; the small, low-dimensional
+	// clusters a single document produces converge well before this.
+	raptorKMeansIterations = 25
+	// raptorReducedDims is the target dimensionality reduceDimensions
+	// projects embeddings into before GMM clustering, so BIC's
+	// model-selection search isn't fighting the curse of dimensionality on
+	// raw embedding vectors.
+	raptorReducedDims = 10
+	// raptorNeighbors is the neighborhood size reduceDimensions uses to
+	// build its k-NN graph.
+	raptorNeighbors = 5
+	// raptorMembershipThreshold is the minimum posterior probability for a
+	// chunk to count as a soft member of a cluster, letting one chunk
+	// summarize into more than one parent node. Below 1/raptorMaxClusters a
+	// chunk would need to merely have a nonzero tail probability in a
+	// cluster, not actually lean toward it, to count as a member.
+	raptorMembershipThreshold = 0.1
+	// raptorDimReductionMinNodes is the level size below which BuildTree
+	// skips reduceDimensions and clusters the raw embeddings directly. A
+	// small level doesn't have enough points for the projection's k-NN
+	// graph or force-directed layout to find real structure in, so running
+	// it just adds noise and iteration cost ahead of GMM, which handles a
+	// handful of raw-dimensional vectors fine on its own.
+	raptorDimReductionMinNodes = 2 * raptorReducedDims
+)
+
+// RaptorLeaf is one already-embedded chunk BuildTree clusters and
+// summarizes into a RAPTOR tree. NodeID must match the Chunk node's
+// node_id in Neo4j, since SummaryNode.ChildIDs reference it directly.
+type RaptorLeaf struct {
+	NodeID string
+	Text   string
+	Vector []float32
+}
+
+// SummaryNode is one non-leaf node BuildTree produces, ready for
+// Neo4jClient.InsertSummaryNodes.
+type SummaryNode struct {
+	NodeID    string
+	Text      string
+	Level     int
+	Embedding []float32
+	ChildIDs  []string
+	// ChildWeights is ChildIDs' posterior cluster-membership probability
+	// (child node ID -> weight in [raptorMembershipThreshold, 1]), the soft
+	// assignment GMM clustering produces. A child referenced from more than
+	// one SummaryNode at the same level has a separate weight in each
+	// node's map, since it's a genuinely different membership strength in
+	// each cluster.
+	ChildWeights map[string]float32
+	// ClusterID is this node's cluster index within its level (stable only
+	// within a single BuildTree call, not across documents), so a caller
+	// inspecting a level can tell which summaries came from the same
+	// clustering pass without parsing it back out of NodeID.
+	ClusterID int
+}
+
+// RaptorBuilder clusters a document's chunk embeddings and recursively
+// summarizes each cluster into a RAPTOR tree (https://arxiv.org/abs/2401.18059),
+// stopping at a single root or raptorMaxDepth. Each level's clusters are
+// found by dimensionality-reducing the embeddings (reduceDimensions, a
+// lightweight UMAP stand-in) and soft-clustering the result with a
+// Gaussian Mixture Model whose component count is picked by BIC
+// (fitGMM) -- so a chunk whose posterior membership clears
+// raptorMembershipThreshold in more than one cluster summarizes into more
+// than one parent, the same soft assignment RAPTOR's reference
+// implementation uses. Retrieval doesn't need a separate "collapsed" vs.
+// "tree" traversal helper here: fusion.FusionRetriever.searchTreeDB
+// already walks the persisted tree level-by-level via Neo4j, and RRF
+// fusion already blends its results with the leaf-level vector/graph
+// engines into one flat ranked list, which is what a "collapsed" query
+// mode would otherwise provide.
+type RaptorBuilder struct {
+	router   *llm.Router
+	embedder *embedding.Batcher
+}
+
+// NewRaptorBuilder creates a new RAPTOR builder.
+func NewRaptorBuilder(router *llm.Router, embedder *embedding.Batcher) *RaptorBuilder {
+	return &RaptorBuilder{router: router, embedder: embedder}
+}
+
+// BuildTree clusters leaves into groups, asks the LLM router to summarize
+// each group, embeds the summaries, and repeats on the resulting level
+// until a single root remains or raptorMaxDepth is hit. It returns every
+// non-leaf node created; leaves themselves are assumed to already exist as
+// Chunk nodes from the embedding/indexing steps. A document too small to
+// cluster, or a builder missing its router/embedder, returns (nil, nil) so
+// ingestion can proceed without a tree rather than fail outright.
+func (b *RaptorBuilder) BuildTree(ctx context.Context, docID string, leaves []RaptorLeaf) ([]SummaryNode, error) {
+	if b.router == nil || b.embedder == nil {
+		return nil, nil
+	}
+	if len(leaves) < raptorMinLeaves {
+		return nil, nil
+	}
+
+	type levelNode struct {
+		id     string
+		text   string
+		vector []float32
+	}
+
+	level := make([]levelNode, len(leaves))
+	for i, l := range leaves {
+		level[i] = levelNode{id: l.NodeID, text: l.Text, vector: l.Vector}
+	}
+
+	var summaries []SummaryNode
+	for depth := 1; depth <= raptorMaxDepth && len(level) > 1; depth++ {
+		vectors32 := make([][]float32, len(level))
+		vectors64 := make([][]float64, len(level))
+		for i, n := range level {
+			vectors32[i] = n.vector
+			vectors64[i] = toFloat64Vector(n.vector)
+		}
+		reduced := vectors64
+		if len(level) >= raptorDimReductionMinNodes {
+			reduced = reduceDimensions(vectors64, raptorReducedDims, raptorNeighbors)
+		}
+
+		maxK := raptorMaxClusters
+		if maxK > len(level) {
+			maxK = len(level)
+		}
+		labels, k, components, converged, err := fitGMM(reduced, maxK)
+		if err != nil {
+			log.Printf("[RAPTOR] doc %s: level %d clustering failed, stopping: %v", docID, depth, err)
+			break
+		}
+		if k >= len(level) {
+			// Clustering wouldn't narrow the tree any further at this size.
+			break
+		}
+
+		members := make([][]int, k)
+		weights := make([]map[int]float32, k)
+		for cluster := range weights {
+			weights[cluster] = make(map[int]float32)
+		}
+		if converged {
+			// Soft assignment: a member is attached to every cluster whose
+			// posterior probability clears raptorMembershipThreshold, not
+			// just the single cluster assignLabels picked for BIC scoring.
+			// This is what lets one chunk summarize into more than one
+			// parent node.
+			posteriors := responsibilities(reduced, components)
+			for i, p := range posteriors {
+				for cluster, prob := range p {
+					if prob >= raptorMembershipThreshold || labels[i] == cluster {
+						members[cluster] = append(members[cluster], i)
+						weights[cluster][i] = float32(prob)
+					}
+				}
+			}
+		} else {
+			// EM didn't settle on the BIC-selected k, so its posteriors
+			// aren't trustworthy enough for soft membership -- fall back to
+			// a hard k-means partition instead of summarizing from a
+			// possibly-degenerate mixture fit.
+			log.Printf("[RAPTOR] doc %s: level %d GMM (k=%d) did not converge, falling back to k-means", docID, depth, k)
+			assignments := kMeansCluster(vectors32, k, raptorKMeansIterations)
+			for i, cluster := range assignments {
+				members[cluster] = append(members[cluster], i)
+				weights[cluster][i] = 1.0
+			}
+		}
+
+		var nextLevel []levelNode
+		for cluster, idxs := range members {
+			if len(idxs) == 0 {
+				continue
+			}
+
+			texts := make([]string, len(idxs))
+			childIDs := make([]string, len(idxs))
+			childWeights := make(map[string]float32, len(idxs))
+			for i, idx := range idxs {
+				texts[i] = level[idx].text
+				childIDs[i] = level[idx].id
+				childWeights[level[idx].id] = weights[cluster][idx]
+			}
+
+			summaryText, err := b.summarize(ctx, texts)
+			if err != nil {
+				log.Printf("[RAPTOR] doc %s: level %d cluster %d summarization failed, dropping this branch: %v", docID, depth, cluster, err)
+				continue
+			}
+
+			nodeID := fmt.Sprintf("%s-tree-L%d-C%d", docID, depth, cluster)
+			summaryVector, err := b.embed(ctx, summaryText)
+			if err != nil {
+				log.Printf("[RAPTOR] doc %s: level %d cluster %d: failed to embed summary, dropping this branch: %v", docID, depth, cluster, err)
+				continue
+			}
+
+			summaries = append(summaries, SummaryNode{
+				NodeID:       nodeID,
+				Text:         summaryText,
+				Level:        depth,
+				Embedding:    summaryVector,
+				ChildIDs:     childIDs,
+				ChildWeights: childWeights,
+				ClusterID:    cluster,
+			})
+			nextLevel = append(nextLevel, levelNode{id: nodeID, text: summaryText, vector: summaryVector})
+		}
+
+		if len(nextLevel) == 0 || len(nextLevel) >= len(level) {
+			// No branch survived, or clustering didn't actually narrow the
+			// tree -- stop rather than looping at the same width until
+			// raptorMaxDepth.
+			break
+		}
+		level = nextLevel
+	}
+
+	return summaries, nil
+}
+
+// summarize asks the LLM router's deep-summarization tier to condense texts
+// into one passage.
+func (b *RaptorBuilder) summarize(ctx context.Context, texts []string) (string, error) {
+	prompt := "Summarize the following related passages into a single concise overview that captures their shared meaning:\n\n"
+	for _, t := range texts {
+		prompt += "- " + t + "\n"
+	}
+
+	client := b.router.RouteLLMTask(ctx, llm.TaskDeepSummarization)
+	summary, err := client.Generate(ctx, prompt)
+	if err != nil {
+		return "", fmt.Errorf("raptor: summarization failed: %w", err)
+	}
+	return summary, nil
+}
+
+// embed generates text's dense embedding via the configured Batcher.
+func (b *RaptorBuilder) embed(ctx context.Context, text string) ([]float32, error) {
+	results, _, err := b.embedder.GenerateEmbeddingsBatched(ctx, []string{text}, "dense", "retrieval")
+	if err != nil {
+		return nil, fmt.Errorf("raptor: failed to embed summary: %w", err)
+	}
+	if len(results) == 0 || results[0].GetDense() == nil {
+		return nil, fmt.Errorf("raptor: no embedding result returned")
+	}
+	return results[0].GetDense().GetValues(), nil
+}
+
+// toFloat64Vector converts an embedding to the float64 vectors fitGMM and
+// reduceDimensions operate on (EM's likelihood math needs the extra
+// precision raw float32 embeddings don't have).
+func toFloat64Vector(v []float32) []float64 {
+	out := make([]float64, len(v))
+	for i, x := range v {
+		out[i] = float64(x)
+	}
+	return out
+}
+
+// raptorClusterCount picks a cluster count aiming for
+// raptorTargetClusterSize members each, clamped to [1, raptorMaxClusters]
+// and to n so a level never asks for more clusters than it has members.
+func raptorClusterCount(n int) int {
+	k := n / raptorTargetClusterSize
+	if k < 1 {
+		k = 1
+	}
+	if k > raptorMaxClusters {
+		k = raptorMaxClusters
+	}
+	if k > n {
+		k = n
+	}
+	return k
+}
+
+// kMeansCluster runs Lloyd's algorithm with a fixed random seed (so runs
+// are reproducible) and returns each vector's cluster assignment in
+// [0, k). Centroids are initialized from k distinct randomly-chosen
+// vectors.
+func kMeansCluster(vectors [][]float32, k int, iterations int) []int {
+	n := len(vectors)
+	assignments := make([]int, n)
+	if n == 0 || k <= 0 {
+		return assignments
+	}
+	if k > n {
+		k = n
+	}
+
+	rng := rand.New(rand.NewSource(42))
+	centroidIdx := rng.Perm(n)[:k]
+	centroids := make([][]float32, k)
+	for i, idx := range centroidIdx {
+		centroids[i] = append([]float32(nil), vectors[idx]...)
+	}
+
+	for iter := 0; iter < iterations; iter++ {
+		changed := false
+		for i, v := range vectors {
+			best, bestDist := 0, sqDist(v, centroids[0])
+			for c := 1; c < k; c++ {
+				if d := sqDist(v, centroids[c]); d < bestDist {
+					best, bestDist = c, d
+				}
+			}
+			if assignments[i] != best {
+				assignments[i] = best
+				changed = true
+			}
+		}
+
+		sums := make([][]float64, k)
+		counts := make([]int, k)
+		dim := len(vectors[0])
+		for c := range sums {
+			sums[c] = make([]float64, dim)
+		}
+		for i, v := range vectors {
+			c := assignments[i]
+			counts[c]++
+			for d, x := range v {
+				sums[c][d] += float64(x)
+			}
+		}
+		for c := 0; c < k; c++ {
+			if counts[c] == 0 {
+				continue // keep the previous centroid for an empty cluster
+			}
+			newCentroid := make([]float32, dim)
+			for d := range newCentroid {
+				newCentroid[d] = float32(sums[c][d] / float64(counts[c]))
+			}
+			centroids[c] = newCentroid
+		}
+
+		if !changed {
+			break
+		}
+	}
+
+	return assignments
+}
+
+// sqDist returns the squared Euclidean distance between a and b.
+func sqDist(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		d := float64(a[i]) - float64(b[i])
+		sum += d * d
+	}
+	return sum
+}
@@ -0,0 +1,237 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/booksage/booksage-api/internal/embedding"
+	"github.com/booksage/booksage-api/internal/llm"
+	"github.com/hashicorp/go-hclog"
+)
+
+// mockLLMClient implements llm.LLMClient for testing.
+type mockLLMClient struct {
+	resp string
+}
+
+func (m *mockLLMClient) Generate(ctx context.Context, prompt string) (string, error) {
+	return m.resp, nil
+}
+func (m *mockLLMClient) Name() string { return "mock" }
+
+// mockEmbeddingClient returns a fixed vector per text, keyed on whether the
+// text mentions "animal" or "finance", so the two topics land in
+// well-separated regions of the vector space and k-means has an obvious
+// cluster structure to find instead of noise.
+type mockEmbeddingClient struct{}
+
+func (m *mockEmbeddingClient) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, t := range texts {
+		switch {
+		case strings.Contains(t, "animal"):
+			vectors[i] = []float32{1, 0, float32(i) * 0.01}
+		case strings.Contains(t, "finance"):
+			vectors[i] = []float32{0, 1, float32(i) * 0.01}
+		default:
+			vectors[i] = []float32{1, 1, float32(i) * 0.01}
+		}
+	}
+	return vectors, nil
+}
+func (m *mockEmbeddingClient) Name() string { return "mock" }
+
+func newTestRaptorBuilder() *RaptorBuilder {
+	router := llm.NewRouter(&mockLLMClient{resp: "Summary of group"}, &mockLLMClient{resp: "Summary of group"}, hclog.NewNullLogger())
+	batcher := embedding.NewBatcher(&mockEmbeddingClient{}, 8000, 4, hclog.NewNullLogger())
+	return NewRaptorBuilder(router, batcher)
+}
+
+func TestRaptorBuilder_BuildTree(t *testing.T) {
+	builder := newTestRaptorBuilder()
+	leaves := []RaptorLeaf{
+		{NodeID: "c1", Text: "animal shelter intake rules", Vector: []float32{1, 0, 0}},
+		{NodeID: "c2", Text: "animal feeding schedule", Vector: []float32{1, 0, 0.01}},
+		{NodeID: "c3", Text: "animal vaccination records", Vector: []float32{1, 0, 0.02}},
+		{NodeID: "c4", Text: "finance quarterly report", Vector: []float32{0, 1, 0}},
+		{NodeID: "c5", Text: "finance audit checklist", Vector: []float32{0, 1, 0.01}},
+		{NodeID: "c6", Text: "finance tax filing", Vector: []float32{0, 1, 0.02}},
+	}
+
+	summaries, err := builder.BuildTree(context.Background(), "doc1", leaves)
+	if err != nil {
+		t.Fatalf("BuildTree returned error: %v", err)
+	}
+	if len(summaries) == 0 {
+		t.Fatal("expected at least one summary node")
+	}
+
+	seen := map[string]bool{}
+	for _, s := range summaries {
+		if s.NodeID == "" {
+			t.Error("summary node missing NodeID")
+		}
+		if len(s.ChildIDs) == 0 {
+			t.Errorf("summary %s has no child IDs", s.NodeID)
+		}
+		if len(s.Embedding) == 0 {
+			t.Errorf("summary %s missing embedding", s.NodeID)
+		}
+		seen[s.NodeID] = true
+	}
+
+	// The root summary (the last one produced) should eventually reference
+	// every leaf, directly or transitively, but at minimum every leaf must
+	// show up as a child of *some* summary.
+	referenced := map[string]bool{}
+	for _, s := range summaries {
+		for _, id := range s.ChildIDs {
+			referenced[id] = true
+		}
+	}
+	for _, l := range leaves {
+		if !referenced[l.NodeID] {
+			t.Errorf("leaf %s was never referenced by any summary", l.NodeID)
+		}
+	}
+}
+
+func TestRaptorBuilder_BuildTree_TooFewLeaves(t *testing.T) {
+	builder := newTestRaptorBuilder()
+	summaries, err := builder.BuildTree(context.Background(), "doc1", []RaptorLeaf{
+		{NodeID: "c1", Text: "only chunk", Vector: []float32{1, 0, 0}},
+	})
+	if err != nil {
+		t.Fatalf("BuildTree returned error: %v", err)
+	}
+	if summaries != nil {
+		t.Errorf("expected no summaries for a single leaf, got %d", len(summaries))
+	}
+}
+
+func TestRaptorBuilder_BuildTree_MissingDependencies(t *testing.T) {
+	builder := NewRaptorBuilder(nil, nil)
+	leaves := []RaptorLeaf{
+		{NodeID: "c1", Text: "a", Vector: []float32{1, 0, 0}},
+		{NodeID: "c2", Text: "b", Vector: []float32{0, 1, 0}},
+	}
+	summaries, err := builder.BuildTree(context.Background(), "doc1", leaves)
+	if err != nil {
+		t.Fatalf("BuildTree returned error: %v", err)
+	}
+	if summaries != nil {
+		t.Errorf("expected no summaries when router/embedder are nil, got %d", len(summaries))
+	}
+}
+
+// TestRaptorBuilder_BuildTree_StableAcrossRuns asserts that BuildTree's
+// GMM clustering (seeded deterministically in fitGMM/reduceDimensions)
+// produces the same cluster membership -- including every soft
+// (multi-parent) edge -- across repeated runs on the same input, and that
+// a big enough synthetic corpus actually forms more than one tree layer
+// rather than collapsing straight to a single summary.
+func TestRaptorBuilder_BuildTree_StableAcrossRuns(t *testing.T) {
+	var leaves []RaptorLeaf
+	topics := []string{"animal", "finance"}
+	for _, topic := range topics {
+		for i := 0; i < 6; i++ {
+			leaves = append(leaves, RaptorLeaf{
+				NodeID: fmt.Sprintf("%s-%d", topic, i),
+				Text:   fmt.Sprintf("%s passage %d", topic, i),
+				Vector: []float32{0, 0, float32(i) * 0.01},
+			})
+		}
+	}
+	for i := range leaves {
+		if strings.Contains(leaves[i].Text, "animal") {
+			leaves[i].Vector[0] = 1
+		} else {
+			leaves[i].Vector[1] = 1
+		}
+	}
+
+	run := func() []SummaryNode {
+		builder := newTestRaptorBuilder()
+		summaries, err := builder.BuildTree(context.Background(), "doc1", leaves)
+		if err != nil {
+			t.Fatalf("BuildTree returned error: %v", err)
+		}
+		return summaries
+	}
+
+	summariesA := run()
+	summariesB := run()
+
+	levelsA, levelsB := map[int]bool{}, map[int]bool{}
+	for _, s := range summariesA {
+		levelsA[s.Level] = true
+	}
+	for _, s := range summariesB {
+		levelsB[s.Level] = true
+	}
+	if len(levelsA) < 2 {
+		t.Errorf("expected a 12-leaf, two-topic corpus to form more than one tree layer, got levels %v", levelsA)
+	}
+
+	// A leaf can legitimately summarize into more than one parent under
+	// soft clustering, so membership is the full set of (parent, child,
+	// weight) triples, not a single child->parent lookup -- collapsing it
+	// to the latter would silently drop all but one parent per child and
+	// miss exactly the divergence this test exists to catch.
+	memberSet := func(summaries []SummaryNode) map[string]float32 {
+		set := make(map[string]float32)
+		for _, s := range summaries {
+			for _, childID := range s.ChildIDs {
+				set[s.NodeID+"->"+childID] = s.ChildWeights[childID]
+			}
+		}
+		return set
+	}
+
+	membersA, membersB := memberSet(summariesA), memberSet(summariesB)
+	if len(membersA) != len(membersB) {
+		t.Fatalf("expected identical membership edge counts across runs, got %d vs %d", len(membersA), len(membersB))
+	}
+	for pair, weight := range membersA {
+		if membersB[pair] != weight {
+			t.Errorf("expected membership edge %q with weight %v to be stable across runs, got %v in the second run", pair, weight, membersB[pair])
+		}
+	}
+}
+
+func TestRaptorClusterCount(t *testing.T) {
+	tests := []struct {
+		n    int
+		want int
+	}{
+		{n: 1, want: 1},
+		{n: 5, want: 1},
+		{n: 6, want: 1},
+		{n: 10, want: 2},
+		{n: 1000, want: raptorMaxClusters},
+	}
+	for _, tt := range tests {
+		if got := raptorClusterCount(tt.n); got != tt.want {
+			t.Errorf("raptorClusterCount(%d) = %d, want %d", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestKMeansCluster(t *testing.T) {
+	vectors := [][]float32{
+		{0, 0}, {0, 0.1}, {0, 0.2},
+		{10, 0}, {10, 0.1}, {10, 0.2},
+	}
+	assignments := kMeansCluster(vectors, 2, raptorKMeansIterations)
+	if assignments[0] != assignments[1] || assignments[1] != assignments[2] {
+		t.Errorf("expected first three vectors in the same cluster, got %v", assignments[:3])
+	}
+	if assignments[3] != assignments[4] || assignments[4] != assignments[5] {
+		t.Errorf("expected last three vectors in the same cluster, got %v", assignments[3:])
+	}
+	if assignments[0] == assignments[3] {
+		t.Error("expected the two well-separated groups to land in different clusters")
+	}
+}
@@ -2,13 +2,34 @@ package ingest
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"sort"
+	"strings"
 
 	"github.com/booksage/booksage-api/internal/database"
 	"github.com/booksage/booksage-api/internal/database/models"
+	"github.com/booksage/booksage-api/internal/health"
+	"github.com/hashicorp/go-hclog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// tracer is package-scoped so every Orchestrator shares one tracer,
+// matching usecase/ingest.SagaOrchestrator's convention.
+var tracer = otel.Tracer("booksage-api/ingest")
+
+// ErrDuplicateContent is returned by AttachHash when the hash computed
+// from a streamed upload turns out to match a document some other, already
+// completed saga ingested first. The caller should abort rather than let
+// two sagas race to index identical content.
+var ErrDuplicateContent = errors.New("duplicate content discovered mid-stream")
+
 // QdrantClient defines the interface for Vector DB operations
 type QdrantClient interface {
 	InsertChunks(ctx context.Context, docID string, chunks []any) error
@@ -19,6 +40,8 @@ type QdrantClient interface {
 // Neo4jClient defines the interface for Graph DB operations
 type Neo4jClient interface {
 	InsertNodesAndEdges(ctx context.Context, docID string, nodes []any) error
+	InsertSummaryNodes(ctx context.Context, docID string, nodes []any) error
+	HasSummaryNodes(ctx context.Context, docID string) (bool, error)
 	DeleteDocumentNodes(ctx context.Context, docID string) error
 	DocumentExists(ctx context.Context, docID string) (bool, error)
 }
@@ -29,24 +52,91 @@ type Orchestrator struct {
 	neo4j    Neo4jClient
 	docRepo  database.DocumentRepository
 	sagaRepo database.SagaRepository
+	raptor   *RaptorBuilder
+	logger   hclog.Logger
+
+	qdrantBreaker *health.CircuitBreaker
+	neo4jBreaker  *health.CircuitBreaker
+
+	compensators map[models.IngestStep]StepCompensator
 }
 
-// NewOrchestrator creates a new ingestion orchestrator.
-func NewOrchestrator(q QdrantClient, n Neo4jClient, dr database.DocumentRepository, sr database.SagaRepository) *Orchestrator {
+// NewOrchestrator creates a new ingestion orchestrator. logger is named
+// "saga" and derived from whatever logger the caller injected, rather than
+// reaching for hclog.Default(), so every ingestion log line is attributable
+// to this subsystem regardless of which process wired it up.
+func NewOrchestrator(q QdrantClient, n Neo4jClient, dr database.DocumentRepository, sr database.SagaRepository, logger hclog.Logger) *Orchestrator {
 	return &Orchestrator{
 		qdrant:   q,
 		neo4j:    n,
 		docRepo:  dr,
 		sagaRepo: sr,
+		logger:   logger.Named("saga"),
+	}
+}
+
+// WithRaptorBuilder attaches the builder RunIngestionSaga uses to construct
+// a RAPTOR summary tree after indexing. Without one, ingestion still runs
+// exactly the same; no tree is built and searchTreeDB has nothing to find
+// for the document.
+func (o *Orchestrator) WithRaptorBuilder(raptor *RaptorBuilder) *Orchestrator {
+	o.raptor = raptor
+	return o
+}
+
+// WithCircuitBreakers attaches per-store circuit breakers guarding the
+// Qdrant embedding step and the Neo4j indexing step. Without one, the
+// corresponding step behaves exactly as before: it's attempted every time,
+// even against a store that's already failing every request. With one, an
+// open breaker short-circuits the step before ever touching the store, and
+// the step's own success or failure feeds back into that breaker's streak.
+func (o *Orchestrator) WithCircuitBreakers(qdrant, neo4j *health.CircuitBreaker) *Orchestrator {
+	o.qdrantBreaker = qdrant
+	o.neo4jBreaker = neo4j
+	return o
+}
+
+// StepCompensator undoes one completed saga step's effect on a store,
+// keyed off the ingested document's ID rather than the step's own row --
+// all it needs to remove is whatever that step's insert call wrote.
+type StepCompensator func(ctx context.Context, docID string) error
+
+// WithCompensators overrides or extends the per-step compensators
+// CompensateSaga invokes, beyond the qdrant/neo4j defaults built in from
+// o.qdrant and o.neo4j. Primarily useful in tests that want to observe or
+// fail a specific step's compensation in isolation.
+func (o *Orchestrator) WithCompensators(compensators map[models.IngestStep]StepCompensator) *Orchestrator {
+	o.compensators = compensators
+	return o
+}
+
+// stepCompensators returns the compensators CompensateSaga walks, falling
+// back to deleting from Qdrant for StepEmbedding and from Neo4j for
+// StepIndexing when WithCompensators hasn't been called.
+func (o *Orchestrator) stepCompensators() map[models.IngestStep]StepCompensator {
+	if o.compensators != nil {
+		return o.compensators
+	}
+	return map[models.IngestStep]StepCompensator{
+		models.StepEmbedding: func(ctx context.Context, docID string) error { return o.qdrant.DeleteDocument(ctx, docID) },
+		models.StepIndexing:  func(ctx context.Context, docID string) error { return o.neo4j.DeleteDocumentNodes(ctx, docID) },
 	}
 }
 
-// StartOrResumeIngestion prepares or resumes an ingestion saga.
+// StartOrResumeIngestion prepares or resumes an ingestion saga. doc.FileHash
+// may be empty: a single-pass upload only finishes hashing once it has
+// streamed the whole file to the parser, so it starts the saga first and
+// attaches the hash afterwards via AttachHash. In that case the dedup check
+// below is skipped and happens later instead.
 func (o *Orchestrator) StartOrResumeIngestion(ctx context.Context, doc *models.Document) (*models.IngestSaga, error) {
-	// 1. Check if document exists by hash
-	existingDoc, err := o.docRepo.GetDocumentByHash(ctx, doc.FileHash)
-	if err != nil && err != database.ErrNotFound {
-		return nil, err
+	// 1. Check if document exists by hash, when the hash is already known.
+	var existingDoc *models.Document
+	if len(doc.FileHash) > 0 {
+		var err error
+		existingDoc, err = o.docRepo.GetDocumentByHash(ctx, doc.FileHash)
+		if err != nil && err != database.ErrNotFound {
+			return nil, err
+		}
 	}
 
 	if existingDoc != nil {
@@ -90,90 +180,514 @@ func (o *Orchestrator) StartOrResumeIngestion(ctx context.Context, doc *models.D
 	return saga, nil
 }
 
-// RunIngestionSaga executes the dual-database ingestion with compensating transactions.
-// It tracks progress in the SagaRepository.
+// RunIngestionSaga executes the dual-database ingestion with compensating
+// transactions, tracking progress in the SagaRepository. It doubles as the
+// resume path: on entry it loads the saga's prior SagaStep rows (there's
+// nothing to load for a brand-new saga) so a step already marked Completed
+// is skipped, and a Failed or Processing step (the latter assumed to be a
+// crashed writer, since nothing marks it Completed if the process dies
+// mid-write) is retried. See runSagaStep for the per-step skip/retry/
+// reconciliation logic.
 func (o *Orchestrator) RunIngestionSaga(ctx context.Context, saga *models.IngestSaga, chunks []any, graphNodes []any) error {
-	log.Printf("[Saga Orchestrator] Starting ingestion saga ID: %d", saga.ID)
+	ctx, span := tracer.Start(ctx, "ingest.saga", trace.WithAttributes(
+		attribute.Int64("saga.id", saga.ID),
+		attribute.Int64("document.id", saga.DocumentID),
+	))
+	defer span.End()
+
+	o.logger.Info("starting ingestion saga", "saga_id", saga.ID, "doc_id", saga.DocumentID)
 	strID := fmt.Sprintf("%d", saga.DocumentID)
 
+	priorSteps, err := o.sagaRepo.GetSagaSteps(ctx, saga.ID)
+	if err != nil {
+		return fmt.Errorf("loading prior saga steps: %w", err)
+	}
+	latestByName := make(map[models.IngestStep]*models.SagaStep, len(priorSteps))
+	for _, s := range priorSteps {
+		latestByName[s.Name] = s
+	}
+
 	// Update to PROCESSING
 	if err := o.sagaRepo.UpdateSagaStatus(ctx, saga.ID, saga.Version, models.SagaStatusProcessing, models.StepEmbedding, ""); err != nil {
 		return err
 	}
 	saga.Version++
 
-	// Step: Embedding/Vector Store (Simplified for now as existing code does chunks)
-	step := &models.SagaStep{
-		SagaID: saga.ID,
-		Name:   models.StepEmbedding,
-		Status: models.SagaStatusProcessing,
+	// replayMetadata snapshots the chunks and graph nodes this attempt was
+	// given, JSON-encoded onto the embedding step's row, so ReplaySagaFrom
+	// can redrive a crashed saga without the caller needing to have kept
+	// them around -- mirroring usecase/ingest.SagaOrchestrator's replayInput.
+	// Skipped when the step is already verified complete: runSagaStep won't
+	// touch the row in that case, so there's nothing to persist it onto.
+	embeddingPrior := latestByName[models.StepEmbedding]
+	replayMetadata := ""
+	if embeddingPrior == nil || embeddingPrior.Status != models.SagaStatusCompleted {
+		if encoded, err := json.Marshal(replayPayload{Chunks: chunks, GraphNodes: graphNodes}); err != nil {
+			o.logger.Error("failed to encode replay payload", "saga_id", saga.ID, "error", err)
+		} else {
+			replayMetadata = string(encoded)
+		}
 	}
-	stepID, _ := o.sagaRepo.UpsertSagaStep(ctx, step)
-	step.ID = stepID
 
-	log.Printf("[Saga - Step Embedding] Inserting %d chunks into Qdrant", len(chunks))
-	if err := o.qdrant.InsertChunks(ctx, strID, chunks); err != nil {
+	o.logger.Info("inserting chunks into qdrant", "saga_id", saga.ID, "saga_step", "embedding", "chunk_count", len(chunks))
+	if err := o.runGuardedStep(ctx, saga, models.StepEmbedding, latestByName[models.StepEmbedding], o.qdrantBreaker, replayMetadata,
+		func(ctx context.Context) (bool, error) { return o.qdrant.DocumentExists(ctx, strID) },
+		func(ctx context.Context) error { return o.qdrant.InsertChunks(ctx, strID, chunks) },
+	); err != nil {
 		if statusErr := o.sagaRepo.UpdateSagaStatus(ctx, saga.ID, saga.Version, models.SagaStatusFailed, models.StepEmbedding, err.Error()); statusErr != nil {
-			log.Printf("[Saga] Failed to update saga status: %v", statusErr)
+			o.logger.Error("failed to update saga status", "saga_id", saga.ID, "error", statusErr)
+		}
+		wrapped := fmt.Errorf("qdrant insertion failed: %w", err)
+		span.RecordError(wrapped)
+		span.SetStatus(codes.Error, wrapped.Error())
+		return wrapped
+	}
+
+	o.logger.Info("inserting nodes into neo4j", "saga_id", saga.ID, "saga_step", "indexing", "node_count", len(graphNodes))
+	if err := o.runGuardedStep(ctx, saga, models.StepIndexing, latestByName[models.StepIndexing], o.neo4jBreaker, "",
+		func(ctx context.Context) (bool, error) { return o.neo4j.DocumentExists(ctx, strID) },
+		func(ctx context.Context) error { return o.neo4j.InsertNodesAndEdges(ctx, strID, graphNodes) },
+	); err != nil {
+		o.logger.Warn("neo4j insertion failed, compensating", "saga_id", saga.ID, "saga_step", "indexing", "doc_id", strID)
+
+		if statusErr := o.sagaRepo.UpdateSagaStatus(ctx, saga.ID, saga.Version, models.SagaStatusFailed, models.StepIndexing, err.Error()); statusErr != nil {
+			o.logger.Error("failed to update saga status", "saga_id", saga.ID, "error", statusErr)
+		}
+
+		if compErr := o.compensateDocument(ctx, strID); compErr != nil {
+			span.AddEvent("saga.compensation_failed", trace.WithAttributes(
+				attribute.String("document.id", strID),
+				attribute.String("error", compErr.Error()),
+			))
+			o.logger.Error("compensation failed", "doc_id", strID, "error", compErr)
+		} else {
+			span.AddEvent("saga.compensated", trace.WithAttributes(attribute.String("document.id", strID)))
+		}
+
+		wrapped := fmt.Errorf("neo4j insertion failed, transaction rolled back: %w", err)
+		span.RecordError(wrapped)
+		span.SetStatus(codes.Error, wrapped.Error())
+		return wrapped
+	}
+
+	// Building the RAPTOR summary tree is best-effort: a failure here
+	// doesn't unwind the saga, since the chunks and graph nodes it summarizes
+	// already landed successfully -- the document is just missing tree-level
+	// search until the next ingestion of it tries again.
+	if o.raptor != nil {
+		if err := o.buildRaptorTree(ctx, strID, graphNodes); err != nil {
+			o.logger.Warn("raptor tree build failed (non-fatal)", "saga_id", saga.ID, "error", err)
+		}
+	}
+
+	// Final status
+	if err := o.sagaRepo.UpdateSagaStatus(ctx, saga.ID, saga.Version, models.SagaStatusCompleted, models.StepIndexing, ""); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	o.logger.Info("ingestion completed successfully", "saga_id", saga.ID)
+	return nil
+}
+
+// buildRaptorTree extracts the embedded leaves out of graphNodes (the same
+// nodes InsertNodesAndEdges just persisted as Chunk nodes) and asks
+// o.raptor to cluster and summarize them, persisting whatever summary
+// nodes it produces. A node without a usable embedding is skipped rather
+// than failing the whole build, since not every ingestion attaches one.
+// If docID already has a tree (a saga retried after this step already
+// succeeded, e.g. a crash between here and the final status update), it's
+// left alone rather than rebuilt, since re-summarizing and re-embedding is
+// wasted work and would leave the tree with a fresh, non-deterministic set
+// of summary texts for no benefit.
+func (o *Orchestrator) buildRaptorTree(ctx context.Context, docID string, graphNodes []any) error {
+	if exists, err := o.neo4j.HasSummaryNodes(ctx, docID); err != nil {
+		o.logger.Warn("raptor tree existence check failed, attempting build anyway", "doc_id", docID, "error", err)
+	} else if exists {
+		return nil
+	}
+
+	leaves := make([]RaptorLeaf, 0, len(graphNodes))
+	for _, n := range graphNodes {
+		m, ok := n.(map[string]any)
+		if !ok {
+			continue
+		}
+		nodeID, _ := m["id"].(string)
+		text, _ := m["text"].(string)
+		vec, ok := m["embedding"].([]float32)
+		if !ok || nodeID == "" || len(vec) == 0 {
+			continue
+		}
+		leaves = append(leaves, RaptorLeaf{NodeID: nodeID, Text: text, Vector: vec})
+	}
+	if len(leaves) == 0 {
+		return nil
+	}
+
+	summaries, err := o.raptor.BuildTree(ctx, docID, leaves)
+	if err != nil {
+		return fmt.Errorf("building raptor tree: %w", err)
+	}
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	nodes := make([]any, len(summaries))
+	for i, s := range summaries {
+		nodes[i] = map[string]any{
+			"id":            s.NodeID,
+			"text":          s.Text,
+			"level":         s.Level,
+			"embedding":     s.Embedding,
+			"child_ids":     s.ChildIDs,
+			"child_weights": s.ChildWeights,
+			"cluster_id":    s.ClusterID,
 		}
+	}
+	if err := o.neo4j.InsertSummaryNodes(ctx, docID, nodes); err != nil {
+		return fmt.Errorf("persisting summary nodes: %w", err)
+	}
+	return nil
+}
+
+// runGuardedStep wraps runSagaStep with an optional circuit breaker: when
+// breaker is open, the step is short-circuited before ever touching the
+// store, so a saga retrying against an already-failing dependency doesn't
+// pile on more load; otherwise runSagaStep runs as usual and its outcome
+// feeds back into breaker's consecutive-failure streak. A nil breaker
+// (the default when WithCircuitBreakers hasn't been called) makes this
+// identical to calling runSagaStep directly.
+func (o *Orchestrator) runGuardedStep(ctx context.Context, saga *models.IngestSaga, name models.IngestStep, prior *models.SagaStep, breaker *health.CircuitBreaker, metadata string, exists func(context.Context) (bool, error), insert func(context.Context) error) error {
+	if breaker != nil && !breaker.Allow() {
+		return fmt.Errorf("%s circuit breaker is open, short-circuiting step %v", breaker.Name(), name)
+	}
+
+	err := o.runSagaStep(ctx, saga, name, prior, metadata, exists, insert)
+	if breaker != nil {
+		if err != nil {
+			breaker.RecordFailure()
+		} else {
+			breaker.RecordSuccess()
+		}
+	}
+	return err
+}
+
+// runSagaStep runs a single ingestion step (the Qdrant embedding insert or
+// the Neo4j indexing insert), skipping the write entirely when it's
+// already landed. prior is the most recently recorded SagaStep row for
+// this step name, or nil for a brand-new saga. exists lets the step
+// reconcile against the target store directly rather than trusting
+// prior.Status alone: a writer that crashed between the store write and
+// the Completed status update would otherwise double-insert, and
+// conversely a step merely recorded Failed or Processing might actually
+// have landed right before the crash. metadata, if non-empty, is persisted
+// onto the step row for later replay (see ReplaySagaFrom); an empty
+// metadata falls back to whatever prior already had recorded, so a retry
+// doesn't lose a payload snapshot a previous attempt already captured.
+func (o *Orchestrator) runSagaStep(ctx context.Context, saga *models.IngestSaga, name models.IngestStep, prior *models.SagaStep, metadata string, exists func(context.Context) (bool, error), insert func(context.Context) error) (err error) {
+	ctx, span := tracer.Start(ctx, "saga.step."+stepName(name), trace.WithAttributes(
+		attribute.Int64("saga.id", saga.ID),
+	))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	step := &models.SagaStep{SagaID: saga.ID, Name: name, Metadata: metadata}
+	if prior != nil {
+		step.ID = prior.ID
+		if step.Metadata == "" {
+			step.Metadata = prior.Metadata
+		}
+	}
+
+	if prior != nil && prior.Status == models.SagaStatusCompleted {
+		found, err := exists(ctx)
+		if err != nil {
+			return fmt.Errorf("reconciling step %v: %w", name, err)
+		}
+		if found {
+			o.logger.Debug("step already completed and verified in store, skipping", "saga_step", stepName(name), "saga_id", saga.ID)
+			return nil
+		}
+		o.logger.Warn("step marked completed but missing from store, re-inserting", "saga_step", stepName(name), "saga_id", saga.ID)
+	} else if prior != nil {
+		o.logger.Info("checking store before retrying step", "saga_step", stepName(name), "saga_id", saga.ID, "prior_attempt_id", prior.AttemptID, "prior_status", prior.Status)
+		found, err := exists(ctx)
+		if err != nil {
+			return fmt.Errorf("reconciling step %v: %w", name, err)
+		}
+		if found {
+			o.logger.Info("found in store despite non-completed step record, marking completed without re-inserting", "saga_step", stepName(name), "saga_id", saga.ID)
+			step.Status = models.SagaStatusCompleted
+			step.AttemptID = prior.AttemptID
+			_, err := o.sagaRepo.UpsertSagaStep(ctx, step)
+			return err
+		}
+	}
+
+	attemptID, err := generateAttemptID()
+	if err != nil {
+		return fmt.Errorf("generating attempt id for step %v: %w", name, err)
+	}
+	step.Status = models.SagaStatusProcessing
+	step.AttemptID = attemptID
+	stepID, err := o.sagaRepo.UpsertSagaStep(ctx, step)
+	if err != nil {
+		return fmt.Errorf("recording step %v attempt: %w", name, err)
+	}
+	step.ID = stepID
+
+	if err := insert(ctx); err != nil {
 		step.Status = models.SagaStatusFailed
 		step.ErrorLog = err.Error()
 		if _, stepErr := o.sagaRepo.UpsertSagaStep(ctx, step); stepErr != nil {
-			log.Printf("[Saga] Failed to upsert saga step: %v", stepErr)
+			o.logger.Error("failed to upsert saga step", "saga_step", stepName(name), "saga_id", saga.ID, "error", stepErr)
 		}
-		return fmt.Errorf("qdrant insertion failed: %w", err)
+		return err
 	}
 
 	step.Status = models.SagaStatusCompleted
 	if _, stepErr := o.sagaRepo.UpsertSagaStep(ctx, step); stepErr != nil {
-		log.Printf("[Saga] Failed to upsert saga step: %v", stepErr)
+		o.logger.Error("failed to upsert saga step", "saga_step", stepName(name), "saga_id", saga.ID, "error", stepErr)
+	}
+	return nil
+}
+
+// Compensate rolls back a saga's writes across both stores, keyed off its
+// document ID. It's exported so it can be invoked out-of-band against a
+// saga stuck in Processing or Failed, e.g. from an operator tool, in
+// addition to the indexing-failure path above. Deleting a document that
+// was never written to a given store is expected to be a no-op there, so
+// it's safe to call even when only one store actually has data to remove.
+func (o *Orchestrator) Compensate(ctx context.Context, sagaID int64) error {
+	saga, err := o.sagaRepo.GetSagaByID(ctx, sagaID)
+	if err != nil {
+		return err
 	}
+	return o.compensateDocument(ctx, fmt.Sprintf("%d", saga.DocumentID))
+}
 
-	// Step: Indexing/Graph Store
-	step = &models.SagaStep{
-		SagaID: saga.ID,
-		Name:   models.StepIndexing,
-		Status: models.SagaStatusProcessing,
+func (o *Orchestrator) compensateDocument(ctx context.Context, strID string) error {
+	var failures []string
+	if err := o.qdrant.DeleteDocument(ctx, strID); err != nil {
+		failures = append(failures, fmt.Sprintf("qdrant: %v", err))
 	}
-	stepID, _ = o.sagaRepo.UpsertSagaStep(ctx, step)
-	step.ID = stepID
+	if err := o.neo4j.DeleteDocumentNodes(ctx, strID); err != nil {
+		failures = append(failures, fmt.Sprintf("neo4j: %v", err))
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("compensation failed for doc %s: %s", strID, strings.Join(failures, "; "))
+	}
+	return nil
+}
 
-	log.Printf("[Saga - Step Indexing] Inserting %d nodes into Neo4j", len(graphNodes))
-	if err := o.neo4j.InsertNodesAndEdges(ctx, strID, graphNodes); err != nil {
-		log.Printf("[Saga - Rollback] Neo4j insertion failed for saga %d. Compensating Qdrant...", saga.ID)
+// replayPayload is the JSON shape runSagaStep persists onto the embedding
+// step's Metadata column, so ReplaySagaFrom can re-invoke RunIngestionSaga
+// with the exact chunks and graph nodes an earlier attempt was given.
+// usecase/ingest.SagaOrchestrator records the same information for its own,
+// separately compiled Orchestrator via its own replayInput type.
+type replayPayload struct {
+	Chunks     []any `json:"chunks"`
+	GraphNodes []any `json:"graph_nodes"`
+}
 
-		// Update state
-		if statusErr := o.sagaRepo.UpdateSagaStatus(ctx, saga.ID, saga.Version, models.SagaStatusFailed, models.StepIndexing, err.Error()); statusErr != nil {
-			log.Printf("[Saga] Failed to update saga status: %v", statusErr)
+// replayPayloadFromSteps looks for a persisted replayPayload among steps,
+// which is only ever recorded on the embedding step (see RunIngestionSaga).
+// A saga that predates this mechanism, or whose encoding failed at the
+// time, has no Metadata to decode and reports ok=false.
+func replayPayloadFromSteps(steps []*models.SagaStep) (payload replayPayload, ok bool) {
+	for _, s := range steps {
+		if s.Name != models.StepEmbedding || s.Metadata == "" {
+			continue
 		}
-		step.Status = models.SagaStatusFailed
-		step.ErrorLog = err.Error()
-		if _, stepErr := o.sagaRepo.UpsertSagaStep(ctx, step); stepErr != nil {
-			log.Printf("[Saga] Failed to upsert saga step: %v", stepErr)
+		if err := json.Unmarshal([]byte(s.Metadata), &payload); err != nil {
+			return replayPayload{}, false
 		}
+		return payload, true
+	}
+	return replayPayload{}, false
+}
+
+// CompensateSaga rolls back every step sagaID completed, in reverse
+// chronological order, invoking the registered StepCompensator for each
+// (see stepCompensators), then deletes the saga's Document row -- the
+// complete undo of everything StartOrResumeIngestion and RunIngestionSaga
+// wrote for it. Each step's outcome is recorded on its own SagaStep row via
+// CompensationStatus/CompensationError, so a retry after a partial failure
+// only re-attempts whatever didn't already succeed. The Document row is
+// only deleted once every step has been compensated; if any step failed,
+// CompensateSaga returns early so a retry can find and undo the rest first,
+// rather than deleting the document out from under data still left behind
+// in a store.
+func (o *Orchestrator) CompensateSaga(ctx context.Context, sagaID int64) error {
+	saga, err := o.sagaRepo.GetSagaByID(ctx, sagaID)
+	if err != nil {
+		return fmt.Errorf("loading saga %d: %w", sagaID, err)
+	}
+	strID := fmt.Sprintf("%d", saga.DocumentID)
+
+	steps, err := o.sagaRepo.GetSagaSteps(ctx, sagaID)
+	if err != nil {
+		return fmt.Errorf("loading steps for saga %d: %w", sagaID, err)
+	}
+	sort.Slice(steps, func(i, j int) bool { return steps[i].CreatedAt.After(steps[j].CreatedAt) })
 
-		// Compensation: Rollback the Qdrant insertion
-		if compErr := o.qdrant.DeleteDocument(ctx, strID); compErr != nil {
-			log.Printf("[Saga - CRITICAL ALERT] Compensation failed! docID: %s. Error: %v", strID, compErr)
+	compensators := o.stepCompensators()
+	var failures []string
+	for _, step := range steps {
+		if step.Status != models.SagaStatusCompleted || step.CompensationStatus == models.SagaStatusCompleted {
+			continue
+		}
+		compensator, ok := compensators[step.Name]
+		if !ok {
+			continue
 		}
 
-		return fmt.Errorf("neo4j insertion failed, transaction rolled back: %w", err)
+		o.logger.Info("undoing step", "saga_step", stepName(step.Name), "saga_id", sagaID)
+		if err := compensator(ctx, strID); err != nil {
+			step.CompensationStatus = models.SagaStatusFailed
+			step.CompensationError = err.Error()
+			failures = append(failures, fmt.Sprintf("step %v: %v", step.Name, err))
+		} else {
+			step.CompensationStatus = models.SagaStatusCompleted
+			step.CompensationError = ""
+		}
+		if _, upsertErr := o.sagaRepo.UpsertSagaStep(ctx, step); upsertErr != nil {
+			failures = append(failures, fmt.Sprintf("step %v: recording compensation: %v", step.Name, upsertErr))
+		}
 	}
 
-	step.Status = models.SagaStatusCompleted
-	if _, stepErr := o.sagaRepo.UpsertSagaStep(ctx, step); stepErr != nil {
-		log.Printf("[Saga] Failed to upsert saga step: %v", stepErr)
+	if len(failures) > 0 {
+		return fmt.Errorf("compensation of saga %d failed: %s", sagaID, strings.Join(failures, "; "))
 	}
 
-	// Final status
-	if err := o.sagaRepo.UpdateSagaStatus(ctx, saga.ID, saga.Version, models.SagaStatusCompleted, models.StepIndexing, ""); err != nil {
-		return err
+	if err := o.docRepo.DeleteDocument(ctx, saga.DocumentID); err != nil {
+		return fmt.Errorf("compensation of saga %d succeeded but deleting its document row failed: %w", sagaID, err)
+	}
+	return o.sagaRepo.UpdateSagaStatus(ctx, sagaID, saga.Version, models.SagaStatusFailed, saga.CurrentStep, "compensated")
+}
+
+// ReplaySagaFrom redrives sagaID starting at step: every persisted SagaStep
+// row at or after step is reset to Pending so RunIngestionSaga's usual
+// skip-if-completed reconciliation redoes it, while steps before it are
+// left alone and skipped as usual. The chunks and graph nodes to replay
+// with are recovered from the payload snapshot runSagaStep persists onto
+// the embedding step, so a crashed worker doesn't need to have kept them.
+func (o *Orchestrator) ReplaySagaFrom(ctx context.Context, sagaID int64, step models.IngestStep) error {
+	saga, err := o.sagaRepo.GetSagaByID(ctx, sagaID)
+	if err != nil {
+		return fmt.Errorf("loading saga %d: %w", sagaID, err)
+	}
+
+	steps, err := o.sagaRepo.GetSagaSteps(ctx, sagaID)
+	if err != nil {
+		return fmt.Errorf("loading steps for saga %d: %w", sagaID, err)
+	}
+
+	payload, ok := replayPayloadFromSteps(steps)
+	if !ok {
+		return fmt.Errorf("saga %d has no persisted replay payload to resume from", sagaID)
+	}
+
+	for _, s := range steps {
+		if s.Name < step {
+			continue
+		}
+		s.Status = models.SagaStatusPending
+		if _, err := o.sagaRepo.UpsertSagaStep(ctx, s); err != nil {
+			return fmt.Errorf("resetting step %v for replay: %w", s.Name, err)
+		}
 	}
 
-	log.Printf("[Saga Orchestrator] Ingestion completed successfully for saga: %d", saga.ID)
+	return o.RunIngestionSaga(ctx, saga, payload.Chunks, payload.GraphNodes)
+}
+
+// Recover finds every ingestion saga left in a non-terminal state --
+// SagaStatusPending or SagaStatusProcessing -- meaning the process that
+// owned it crashed or was killed before reaching SagaStatusCompleted or
+// SagaStatusFailed, and redrives each one from the start via
+// ReplaySagaFrom. runSagaStep's own skip-if-verified check against the
+// actual qdrant/neo4j state means replaying from the beginning is safe
+// even for a saga that was most of the way done.
+//
+// SagaStatusFailed sagas are left alone: that status only follows an
+// explicit, logged failure (with compensation already run for the
+// indexing step), so auto-retrying it here would silently paper over a
+// real error instead of surfacing it for an operator to look at.
+//
+// A saga that fails to replay is logged and skipped rather than aborting
+// the whole pass, so one bad saga can't hold up every other document's
+// ingestion from resuming at startup.
+func (o *Orchestrator) Recover(ctx context.Context) error {
+	sagas, err := o.sagaRepo.ListAllSagas(ctx)
+	if err != nil {
+		return fmt.Errorf("listing sagas for recovery: %w", err)
+	}
+
+	var recovered, failed int
+	for _, saga := range sagas {
+		if saga.Status != models.SagaStatusPending && saga.Status != models.SagaStatusProcessing {
+			continue
+		}
+		o.logger.Info("recovering interrupted saga", "saga_id", saga.ID, "doc_id", saga.DocumentID, "status", saga.Status)
+		if err := o.ReplaySagaFrom(ctx, saga.ID, models.StepEmbedding); err != nil {
+			o.logger.Error("failed to recover saga", "saga_id", saga.ID, "doc_id", saga.DocumentID, "error", err)
+			failed++
+			continue
+		}
+		recovered++
+	}
+
+	o.logger.Info("saga recovery complete", "recovered", recovered, "failed", failed)
 	return nil
 }
 
+// stepName renders an IngestStep as the lowercase word runSagaStep's span
+// name and log lines use, so "saga.step.embedding" reads naturally in a
+// trace viewer instead of "saga.step.2".
+func stepName(step models.IngestStep) string {
+	switch step {
+	case models.StepParsing:
+		return "parsing"
+	case models.StepChunking:
+		return "chunking"
+	case models.StepEmbedding:
+		return "embedding"
+	case models.StepIndexing:
+		return "indexing"
+	default:
+		return fmt.Sprintf("step_%d", int(step))
+	}
+}
+
+// generateAttemptID returns a random 16-byte hex string identifying one
+// attempt at a saga step, analogous to generateUploadID in the server
+// package.
+func generateAttemptID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// GetDocumentByHash looks up a document by its content hash directly,
+// without requiring a saga to already exist for it. Callers that only need
+// to know whether a given digest has been ingested (e.g. a HEAD check
+// before uploading) should use this instead of GetDocumentStatus, which
+// fails with ErrNotFound if the document exists but no saga was ever
+// created for it.
+func (o *Orchestrator) GetDocumentByHash(ctx context.Context, hash []byte) (*models.Document, error) {
+	return o.docRepo.GetDocumentByHash(ctx, hash)
+}
+
 // GetDocumentStatus retrieves the status of a document by its hash
 func (o *Orchestrator) GetDocumentStatus(ctx context.Context, hash []byte) (*models.IngestSaga, error) {
 	doc, err := o.docRepo.GetDocumentByHash(ctx, hash)
@@ -185,3 +699,32 @@ func (o *Orchestrator) GetDocumentStatus(ctx context.Context, hash []byte) (*mod
 	}
 	return o.sagaRepo.GetLatestSagaByDocumentID(ctx, doc.ID)
 }
+
+// AttachHash records the content hash of saga's document once the caller
+// has finished streaming it and only now knows it (see ingestFile, which
+// tees the upload into a sha256 hasher as it streams instead of reading the
+// file twice). If the hash already belongs to some other document, that
+// means a duplicate upload raced this one and won; the saga is aborted
+// rather than proceeding to index content that's already indexed.
+func (o *Orchestrator) AttachHash(ctx context.Context, sagaID int64, hash []byte) (*models.IngestSaga, error) {
+	saga, err := o.sagaRepo.GetSagaByID(ctx, sagaID)
+	if err != nil {
+		return nil, err
+	}
+
+	existingDoc, err := o.docRepo.GetDocumentByHash(ctx, hash)
+	if err != nil && err != database.ErrNotFound {
+		return nil, err
+	}
+	if existingDoc != nil && existingDoc.ID != saga.DocumentID {
+		if abortErr := o.sagaRepo.UpdateSagaStatus(ctx, saga.ID, saga.Version, models.SagaStatusFailed, saga.CurrentStep, "duplicate content discovered mid-stream"); abortErr != nil {
+			o.logger.Error("failed to abort saga after duplicate hash", "saga_id", saga.ID, "error", abortErr)
+		}
+		return nil, ErrDuplicateContent
+	}
+
+	if err := o.docRepo.UpdateDocumentHash(ctx, saga.DocumentID, hash); err != nil {
+		return nil, err
+	}
+	return saga, nil
+}
@@ -2,42 +2,86 @@ package ingest
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 
 	"github.com/booksage/booksage-api/internal/database/models"
+	"github.com/hashicorp/go-hclog"
 )
 
 type mockQdrant struct {
-	insertErr error
-	deleteErr error
-	deleted   bool
+	insertErr   error
+	deleteErr   error
+	deleted     bool
+	existsVal   bool
+	existsErr   error
+	insertCalls int
+	deleteCalls int
 }
 
 func (m *mockQdrant) InsertChunks(ctx context.Context, docID string, chunks []any) error {
+	m.insertCalls++
 	return m.insertErr
 }
 func (m *mockQdrant) DeleteDocument(ctx context.Context, docID string) error {
 	m.deleted = true
+	m.deleteCalls++
 	return m.deleteErr
 }
 func (m *mockQdrant) DocumentExists(ctx context.Context, docID string) (bool, error) {
-	return false, nil
+	return m.existsVal, m.existsErr
 }
 
 type mockNeo4j struct {
-	insertErr error
-	deleteErr error
+	insertErr   error
+	deleteErr   error
+	deleted     bool
+	existsVal   bool
+	existsErr   error
+	insertCalls int
+	deleteCalls int
 }
 
 func (m *mockNeo4j) InsertNodesAndEdges(ctx context.Context, docID string, nodes []any) error {
+	m.insertCalls++
 	return m.insertErr
 }
+func (m *mockNeo4j) InsertSummaryNodes(ctx context.Context, docID string, nodes []any) error {
+	return nil
+}
+func (m *mockNeo4j) HasSummaryNodes(ctx context.Context, docID string) (bool, error) {
+	return false, nil
+}
 func (m *mockNeo4j) DeleteDocumentNodes(ctx context.Context, docID string) error {
+	m.deleted = true
+	m.deleteCalls++
 	return m.deleteErr
 }
 func (m *mockNeo4j) DocumentExists(ctx context.Context, docID string) (bool, error) {
-	return false, nil
+	return m.existsVal, m.existsErr
+}
+
+// stepSagaRepository returns a fixed set of prior SagaStep rows from
+// GetSagaSteps and records every UpsertSagaStep call, so resume tests can
+// assert exactly what got (re-)written without a real database.
+type stepSagaRepository struct {
+	MockSagaRepository
+	priorSteps []*models.SagaStep
+	upserts    []*models.SagaStep
+}
+
+func (s *stepSagaRepository) GetSagaSteps(ctx context.Context, sagaID int64) ([]*models.SagaStep, error) {
+	return s.priorSteps, nil
+}
+
+func (s *stepSagaRepository) UpsertSagaStep(ctx context.Context, step *models.SagaStep) (int64, error) {
+	cp := *step
+	s.upserts = append(s.upserts, &cp)
+	if step.ID == 0 {
+		return int64(len(s.upserts)), nil
+	}
+	return step.ID, nil
 }
 
 func TestSaga_Success(t *testing.T) {
@@ -45,7 +89,7 @@ func TestSaga_Success(t *testing.T) {
 	n := &mockNeo4j{}
 	docRepo := &MockDocumentRepository{}
 	sagaRepo := &MockSagaRepository{}
-	orch := NewOrchestrator(q, n, docRepo, sagaRepo)
+	orch := NewOrchestrator(q, n, docRepo, sagaRepo, hclog.NewNullLogger())
 
 	err := orch.RunIngestionSaga(context.Background(), &models.IngestSaga{ID: 1, DocumentID: 1, Version: 1}, []any{"chunk1"}, []any{"node1"})
 	if err != nil {
@@ -62,7 +106,7 @@ func TestSaga_QdrantFails(t *testing.T) {
 	n := &mockNeo4j{}
 	docRepo := &MockDocumentRepository{}
 	sagaRepo := &MockSagaRepository{}
-	orch := NewOrchestrator(q, n, docRepo, sagaRepo)
+	orch := NewOrchestrator(q, n, docRepo, sagaRepo, hclog.NewNullLogger())
 
 	err := orch.RunIngestionSaga(context.Background(), &models.IngestSaga{ID: 1, DocumentID: 1, Version: 1}, []any{"chunk1"}, []any{"node1"})
 	if err == nil {
@@ -83,7 +127,7 @@ func TestSaga_Neo4jFails_CompensatesQdrant(t *testing.T) {
 	n := &mockNeo4j{insertErr: errors.New("neo4j error")}
 	docRepo := &MockDocumentRepository{}
 	sagaRepo := &MockSagaRepository{}
-	orch := NewOrchestrator(q, n, docRepo, sagaRepo)
+	orch := NewOrchestrator(q, n, docRepo, sagaRepo, hclog.NewNullLogger())
 
 	err := orch.RunIngestionSaga(context.Background(), &models.IngestSaga{ID: 1, DocumentID: 1, Version: 1}, []any{"chunk1"}, []any{"node1"})
 	if err == nil {
@@ -100,7 +144,7 @@ func TestSaga_Neo4jFails_CompensationFails(t *testing.T) {
 	n := &mockNeo4j{insertErr: errors.New("neo4j error")}
 	docRepo := &MockDocumentRepository{}
 	sagaRepo := &MockSagaRepository{}
-	orch := NewOrchestrator(q, n, docRepo, sagaRepo)
+	orch := NewOrchestrator(q, n, docRepo, sagaRepo, hclog.NewNullLogger())
 
 	err := orch.RunIngestionSaga(context.Background(), &models.IngestSaga{ID: 1, DocumentID: 1, Version: 1}, []any{"chunk1"}, []any{"node1"})
 	if err == nil {
@@ -117,7 +161,7 @@ func TestStartOrResumeIngestion_NewDocument(t *testing.T) {
 	n := &mockNeo4j{}
 	docRepo := &MockDocumentRepository{}
 	sagaRepo := &MockSagaRepository{}
-	orch := NewOrchestrator(q, n, docRepo, sagaRepo)
+	orch := NewOrchestrator(q, n, docRepo, sagaRepo, hclog.NewNullLogger())
 
 	doc := &models.Document{
 		FileHash: []byte{0xAA, 0xBB}, // Not 0xF1, so mock returns nil (new doc)
@@ -144,7 +188,7 @@ func TestStartOrResumeIngestion_AlreadyIngested(t *testing.T) {
 	n := &mockNeo4j{}
 	docRepo := &MockDocumentRepository{}
 	sagaRepo := &MockSagaRepository{}
-	orch := NewOrchestrator(q, n, docRepo, sagaRepo)
+	orch := NewOrchestrator(q, n, docRepo, sagaRepo, hclog.NewNullLogger())
 
 	// 0xF1 triggers mock to return existing doc with ID=100
 	// ID=100 triggers mock saga repo to return completed saga
@@ -172,7 +216,7 @@ func TestStartOrResumeIngestion_ExistingDocNoSaga(t *testing.T) {
 	// so the saga repo returns nil (no existing saga)
 	docRepo := &mockDocRepoWithID{id: 50}
 	sagaRepo := &MockSagaRepository{}
-	orch := NewOrchestrator(q, n, docRepo, sagaRepo)
+	orch := NewOrchestrator(q, n, docRepo, sagaRepo, hclog.NewNullLogger())
 
 	doc := &models.Document{
 		FileHash: []byte{0xF1, 0x01},
@@ -188,12 +232,62 @@ func TestStartOrResumeIngestion_ExistingDocNoSaga(t *testing.T) {
 	}
 }
 
+func TestAttachHash_Success(t *testing.T) {
+	q := &mockQdrant{}
+	n := &mockNeo4j{}
+	docRepo := &hashTrackingDocRepository{}
+	sagaRepo := &MockSagaRepository{}
+	orch := NewOrchestrator(q, n, docRepo, sagaRepo, hclog.NewNullLogger())
+
+	saga, err := orch.AttachHash(context.Background(), 1, []byte{0xAA, 0xBB})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if saga == nil {
+		t.Fatal("Expected saga, got nil")
+	}
+	if docRepo.lastHash == nil {
+		t.Fatal("Expected UpdateDocumentHash to be called")
+	}
+}
+
+func TestAttachHash_DuplicateAbortsSaga(t *testing.T) {
+	q := &mockQdrant{}
+	n := &mockNeo4j{}
+	// 0xF1 makes MockDocumentRepository.GetDocumentByHash return an
+	// existing document (ID 100), which doesn't match the saga's
+	// DocumentID (0, per MockSagaRepository.GetSagaByID).
+	docRepo := &MockDocumentRepository{}
+	sagaRepo := &MockSagaRepository{}
+	orch := NewOrchestrator(q, n, docRepo, sagaRepo, hclog.NewNullLogger())
+
+	saga, err := orch.AttachHash(context.Background(), 1, []byte{0xF1, 0x00})
+	if !errors.Is(err, ErrDuplicateContent) {
+		t.Fatalf("Expected ErrDuplicateContent, got %v", err)
+	}
+	if saga != nil {
+		t.Errorf("Expected no saga on duplicate, got %v", saga)
+	}
+}
+
+// hashTrackingDocRepository records the hash passed to UpdateDocumentHash
+// so tests can assert it without a real database.
+type hashTrackingDocRepository struct {
+	MockDocumentRepository
+	lastHash []byte
+}
+
+func (m *hashTrackingDocRepository) UpdateDocumentHash(ctx context.Context, id int64, hash []byte) error {
+	m.lastHash = hash
+	return nil
+}
+
 func TestGetDocumentStatus(t *testing.T) {
 	q := &mockQdrant{}
 	n := &mockNeo4j{}
 	docRepo := &MockDocumentRepository{}
 	sagaRepo := &MockSagaRepository{}
-	orch := NewOrchestrator(q, n, docRepo, sagaRepo)
+	orch := NewOrchestrator(q, n, docRepo, sagaRepo, hclog.NewNullLogger())
 
 	// 0xF1 hash → doc ID 100 → completed saga
 	saga, err := orch.GetDocumentStatus(context.Background(), []byte{0xF1, 0x00})
@@ -228,3 +322,259 @@ func (m *mockDocRepoWithID) GetDocumentByHash(ctx context.Context, hash []byte)
 func (m *mockDocRepoWithID) DeleteDocument(ctx context.Context, id int64) error {
 	return nil
 }
+func (m *mockDocRepoWithID) UpdateDocumentHash(ctx context.Context, id int64, hash []byte) error {
+	return nil
+}
+func (m *mockDocRepoWithID) ListAllDocuments(ctx context.Context) ([]*models.Document, error) {
+	return nil, nil
+}
+
+func TestRunIngestionSaga_SkipsCompletedStepsVerifiedInStore(t *testing.T) {
+	q := &mockQdrant{existsVal: true}
+	n := &mockNeo4j{existsVal: true}
+	docRepo := &MockDocumentRepository{}
+	sagaRepo := &stepSagaRepository{priorSteps: []*models.SagaStep{
+		{ID: 10, SagaID: 1, Name: models.StepEmbedding, Status: models.SagaStatusCompleted, AttemptID: "prior-embed"},
+		{ID: 11, SagaID: 1, Name: models.StepIndexing, Status: models.SagaStatusCompleted, AttemptID: "prior-index"},
+	}}
+	orch := NewOrchestrator(q, n, docRepo, sagaRepo, hclog.NewNullLogger())
+
+	err := orch.RunIngestionSaga(context.Background(), &models.IngestSaga{ID: 1, DocumentID: 1, Version: 1}, []any{"chunk1"}, []any{"node1"})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if q.insertCalls != 0 || n.insertCalls != 0 {
+		t.Errorf("Expected no re-insertion for already-completed, verified steps; got qdrant=%d neo4j=%d", q.insertCalls, n.insertCalls)
+	}
+}
+
+func TestRunIngestionSaga_RetriesFailedStepMissingFromStore(t *testing.T) {
+	q := &mockQdrant{existsVal: false}
+	n := &mockNeo4j{existsVal: false}
+	docRepo := &MockDocumentRepository{}
+	sagaRepo := &stepSagaRepository{priorSteps: []*models.SagaStep{
+		{ID: 10, SagaID: 1, Name: models.StepEmbedding, Status: models.SagaStatusFailed, AttemptID: "prior-embed"},
+	}}
+	orch := NewOrchestrator(q, n, docRepo, sagaRepo, hclog.NewNullLogger())
+
+	err := orch.RunIngestionSaga(context.Background(), &models.IngestSaga{ID: 1, DocumentID: 1, Version: 1}, []any{"chunk1"}, []any{"node1"})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if q.insertCalls != 1 {
+		t.Errorf("Expected the failed step to be retried once, got %d calls", q.insertCalls)
+	}
+}
+
+func TestRunIngestionSaga_ReconcilesCrashedStepFoundInStore(t *testing.T) {
+	q := &mockQdrant{existsVal: true}
+	n := &mockNeo4j{existsVal: true}
+	docRepo := &MockDocumentRepository{}
+	sagaRepo := &stepSagaRepository{priorSteps: []*models.SagaStep{
+		{ID: 10, SagaID: 1, Name: models.StepEmbedding, Status: models.SagaStatusProcessing, AttemptID: "crashed-attempt"},
+	}}
+	orch := NewOrchestrator(q, n, docRepo, sagaRepo, hclog.NewNullLogger())
+
+	err := orch.RunIngestionSaga(context.Background(), &models.IngestSaga{ID: 1, DocumentID: 1, Version: 1}, []any{"chunk1"}, []any{"node1"})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if q.insertCalls != 0 {
+		t.Errorf("Expected no re-insertion once the store confirms the crashed attempt landed, got %d calls", q.insertCalls)
+	}
+
+	var reconciled bool
+	for _, s := range sagaRepo.upserts {
+		if s.ID == 10 && s.Name == models.StepEmbedding && s.Status == models.SagaStatusCompleted && s.AttemptID == "crashed-attempt" {
+			reconciled = true
+		}
+	}
+	if !reconciled {
+		t.Errorf("Expected the crashed step row to be marked Completed without a new AttemptID, upserts: %+v", sagaRepo.upserts)
+	}
+}
+
+// docDeleteTrackingRepository records the ID passed to DeleteDocument so
+// tests can assert CompensateSaga reached the document row, not just the
+// two stores.
+type docDeleteTrackingRepository struct {
+	MockDocumentRepository
+	deletedID int64
+	deleted   bool
+}
+
+func (m *docDeleteTrackingRepository) DeleteDocument(ctx context.Context, id int64) error {
+	m.deletedID = id
+	m.deleted = true
+	return nil
+}
+
+func TestCompensateSaga_UndoesCompletedStepsAndDeletesDocument(t *testing.T) {
+	q := &mockQdrant{}
+	n := &mockNeo4j{}
+	docRepo := &docDeleteTrackingRepository{}
+	sagaRepo := &stepSagaRepository{priorSteps: []*models.SagaStep{
+		{ID: 10, SagaID: 1, Name: models.StepEmbedding, Status: models.SagaStatusCompleted},
+		{ID: 11, SagaID: 1, Name: models.StepIndexing, Status: models.SagaStatusCompleted},
+	}}
+	orch := NewOrchestrator(q, n, docRepo, sagaRepo, hclog.NewNullLogger())
+
+	if err := orch.CompensateSaga(context.Background(), 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if q.deleteCalls != 1 || n.deleteCalls != 1 {
+		t.Errorf("Expected one compensation call per store, got qdrant=%d neo4j=%d", q.deleteCalls, n.deleteCalls)
+	}
+	if !docRepo.deleted {
+		t.Errorf("Expected the document row to be deleted")
+	}
+
+	for _, s := range sagaRepo.upserts {
+		if s.CompensationStatus != models.SagaStatusCompleted {
+			t.Errorf("Expected step %v compensation to be recorded Completed, got %v", s.Name, s.CompensationStatus)
+		}
+	}
+}
+
+func TestCompensateSaga_SkipsAlreadyCompensatedStep(t *testing.T) {
+	q := &mockQdrant{}
+	n := &mockNeo4j{}
+	docRepo := &docDeleteTrackingRepository{}
+	sagaRepo := &stepSagaRepository{priorSteps: []*models.SagaStep{
+		{ID: 10, SagaID: 1, Name: models.StepEmbedding, Status: models.SagaStatusCompleted, CompensationStatus: models.SagaStatusCompleted},
+		{ID: 11, SagaID: 1, Name: models.StepIndexing, Status: models.SagaStatusCompleted},
+	}}
+	orch := NewOrchestrator(q, n, docRepo, sagaRepo, hclog.NewNullLogger())
+
+	if err := orch.CompensateSaga(context.Background(), 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if q.deleteCalls != 0 {
+		t.Errorf("Expected the already-compensated embedding step not to be re-compensated, got %d calls", q.deleteCalls)
+	}
+	if n.deleteCalls != 1 {
+		t.Errorf("Expected the indexing step to still be compensated, got %d calls", n.deleteCalls)
+	}
+}
+
+func TestCompensateSaga_RecordsFailureAndReturnsError(t *testing.T) {
+	q := &mockQdrant{deleteErr: errors.New("qdrant delete failed")}
+	n := &mockNeo4j{}
+	docRepo := &docDeleteTrackingRepository{}
+	sagaRepo := &stepSagaRepository{priorSteps: []*models.SagaStep{
+		{ID: 10, SagaID: 1, Name: models.StepEmbedding, Status: models.SagaStatusCompleted},
+	}}
+	orch := NewOrchestrator(q, n, docRepo, sagaRepo, hclog.NewNullLogger())
+
+	err := orch.CompensateSaga(context.Background(), 1)
+	if err == nil {
+		t.Fatal("Expected error when a step's compensator fails")
+	}
+
+	var recorded *models.SagaStep
+	for _, s := range sagaRepo.upserts {
+		if s.ID == 10 {
+			recorded = s
+		}
+	}
+	if recorded == nil || recorded.CompensationStatus != models.SagaStatusFailed || recorded.CompensationError == "" {
+		t.Errorf("Expected the failed step's compensation status and error to be recorded, got %+v", recorded)
+	}
+}
+
+func TestReplaySagaFrom_RedrivesFromPersistedPayload(t *testing.T) {
+	q := &mockQdrant{}
+	n := &mockNeo4j{}
+	docRepo := &MockDocumentRepository{}
+	payload, err := json.Marshal(replayPayload{Chunks: []any{"chunk1"}, GraphNodes: []any{"node1"}})
+	if err != nil {
+		t.Fatalf("failed to encode fixture payload: %v", err)
+	}
+	sagaRepo := &stepSagaRepository{priorSteps: []*models.SagaStep{
+		{ID: 10, SagaID: 1, Name: models.StepEmbedding, Status: models.SagaStatusFailed, Metadata: string(payload)},
+	}}
+	orch := NewOrchestrator(q, n, docRepo, sagaRepo, hclog.NewNullLogger())
+
+	if err := orch.ReplaySagaFrom(context.Background(), 1, models.StepEmbedding); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if q.insertCalls != 1 {
+		t.Errorf("Expected the embedding step to be redriven once, got %d calls", q.insertCalls)
+	}
+	if n.insertCalls != 1 {
+		t.Errorf("Expected the indexing step to run as part of the replayed saga, got %d calls", n.insertCalls)
+	}
+}
+
+func TestReplaySagaFrom_NoPersistedPayload_ReturnsError(t *testing.T) {
+	q := &mockQdrant{}
+	n := &mockNeo4j{}
+	docRepo := &MockDocumentRepository{}
+	sagaRepo := &stepSagaRepository{priorSteps: []*models.SagaStep{
+		{ID: 10, SagaID: 1, Name: models.StepEmbedding, Status: models.SagaStatusFailed},
+	}}
+	orch := NewOrchestrator(q, n, docRepo, sagaRepo, hclog.NewNullLogger())
+
+	if err := orch.ReplaySagaFrom(context.Background(), 1, models.StepEmbedding); err == nil {
+		t.Fatal("Expected an error when no replay payload was ever persisted")
+	}
+}
+
+// listSagaRepository returns a fixed ListAllSagas result and a fixed
+// persisted replay payload for every saga's steps, so Recover tests can
+// drive it without a real database.
+type listSagaRepository struct {
+	stepSagaRepository
+	sagas []*models.IngestSaga
+}
+
+func (s *listSagaRepository) ListAllSagas(ctx context.Context) ([]*models.IngestSaga, error) {
+	return s.sagas, nil
+}
+
+func TestRecover_RedrivesOnlyNonTerminalSagas(t *testing.T) {
+	q := &mockQdrant{}
+	n := &mockNeo4j{}
+	docRepo := &MockDocumentRepository{}
+	payload, err := json.Marshal(replayPayload{Chunks: []any{"chunk1"}, GraphNodes: []any{"node1"}})
+	if err != nil {
+		t.Fatalf("failed to encode fixture payload: %v", err)
+	}
+	sagaRepo := &listSagaRepository{
+		stepSagaRepository: stepSagaRepository{priorSteps: []*models.SagaStep{
+			{ID: 10, SagaID: 1, Name: models.StepEmbedding, Status: models.SagaStatusFailed, Metadata: string(payload)},
+		}},
+		sagas: []*models.IngestSaga{
+			{ID: 1, DocumentID: 1, Version: 1, Status: models.SagaStatusProcessing},
+			{ID: 2, DocumentID: 2, Version: 1, Status: models.SagaStatusPending},
+			{ID: 3, DocumentID: 3, Version: 1, Status: models.SagaStatusCompleted},
+			{ID: 4, DocumentID: 4, Version: 1, Status: models.SagaStatusFailed},
+		},
+	}
+	orch := NewOrchestrator(q, n, docRepo, sagaRepo, hclog.NewNullLogger())
+
+	if err := orch.Recover(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Only the Pending and Processing sagas (IDs 1 and 2) should have been
+	// redriven; Completed and Failed are left untouched.
+	if q.insertCalls != 2 {
+		t.Errorf("Expected 2 sagas to be redriven, got %d embedding insert calls", q.insertCalls)
+	}
+}
+
+func TestCompensate_DeletesFromBothStores(t *testing.T) {
+	q := &mockQdrant{}
+	n := &mockNeo4j{}
+	docRepo := &MockDocumentRepository{}
+	sagaRepo := &MockSagaRepository{} // GetSagaByID returns DocumentID 0 for any id
+	orch := NewOrchestrator(q, n, docRepo, sagaRepo, hclog.NewNullLogger())
+
+	if err := orch.Compensate(context.Background(), 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !q.deleted || !n.deleted {
+		t.Errorf("Expected both stores to be compensated, qdrant=%v neo4j=%v", q.deleted, n.deleted)
+	}
+}
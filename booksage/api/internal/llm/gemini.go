@@ -6,10 +6,11 @@ import (
 	"log"
 
 	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
-// GeminiClient implements repository.LLMClient.
+// GeminiClient implements LLMClient.
 type GeminiClient struct {
 	client *genai.Client
 	model  *genai.GenerativeModel
@@ -51,6 +52,52 @@ func (c *GeminiClient) Generate(ctx context.Context, prompt string) (string, err
 	return text, nil
 }
 
+// GenerateStream sends prompt to Gemini with its native streaming API and
+// emits each response part as a Token, mirroring
+// LocalOllamaClient.GenerateStream's channel contract so agent.Generator's
+// StreamingLLMClient type assertion works the same way regardless of which
+// backend a task was routed to. Both channels are closed once the stream
+// ends or ctx is cancelled; errs carries at most one error.
+func (c *GeminiClient) GenerateStream(ctx context.Context, prompt string) (<-chan Token, <-chan error) {
+	tokens := make(chan Token)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(tokens)
+		defer close(errs)
+
+		log.Printf("[Gemini] ☁️ Streaming request to Gemini 1.5 Pro...")
+
+		iter := c.model.GenerateContentStream(ctx, genai.Text(prompt))
+		for {
+			resp, err := iter.Next()
+			if err == iterator.Done {
+				return
+			}
+			if err != nil {
+				errs <- fmt.Errorf("gemini stream failed: %w", err)
+				return
+			}
+
+			text, err := extractText(resp)
+			if err != nil {
+				// A chunk with no text part (e.g. only a function call) isn't
+				// an error worth aborting the stream over; just skip it.
+				continue
+			}
+
+			select {
+			case tokens <- Token{Text: text}:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return tokens, errs
+}
+
 func extractText(resp *genai.GenerateContentResponse) (string, error) {
 	if resp == nil || len(resp.Candidates) == 0 {
 		return "", fmt.Errorf("no candidates returned from gemini")
@@ -69,6 +116,17 @@ func (c *GeminiClient) Name() string {
 	return "Gemini 1.5 Pro (Cloud)"
 }
 
+// Ping reports whether the Gemini API is reachable, satisfying
+// health.Prober. CountTokens hits the same endpoint family as
+// Generate/GenerateStream without actually running generation, so it's a
+// cheap proxy for "is the API (and this API key) working right now."
+func (c *GeminiClient) Ping(ctx context.Context) error {
+	if _, err := c.model.CountTokens(ctx, genai.Text("ping")); err != nil {
+		return fmt.Errorf("gemini ping failed: %w", err)
+	}
+	return nil
+}
+
 func (c *GeminiClient) Close() error {
 	return c.client.Close()
 }
@@ -77,7 +135,6 @@ func (c *GeminiClient) Close() error {
 func (c *GeminiClient) Embed(ctx context.Context, texts []string) ([][]float32, error) {
 	log.Printf("[Gemini] ☁️ Generating embeddings for %d texts...", len(texts))
 
-	// Gemini Pro is typically used for text, but for embeddings we use a specialized model
 	em := c.client.EmbeddingModel("text-embedding-004")
 
 	batch := em.NewBatch()
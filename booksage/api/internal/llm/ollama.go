@@ -1,6 +1,7 @@
 package llm
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -8,8 +9,20 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"time"
 )
 
+// streamIdleTimeout bounds how long GenerateStream waits between response
+// chunks before aborting. It's independent of ctx's own deadline, so a
+// stalled model doesn't wedge a caller (e.g. an SSE handler) whose context
+// has no deadline of its own.
+const streamIdleTimeout = 30 * time.Second
+
+// maxStreamLineSize bounds a single NDJSON line GenerateStream will buffer.
+// Ollama's final "done":true chunk carries the full token-context array,
+// which can exceed bufio.Scanner's 64KB default for long generations.
+const maxStreamLineSize = 10 * 1024 * 1024
+
 // LocalOllamaClient implements LLMClient by calling a local Ollama server.
 type LocalOllamaClient struct {
 	host  string
@@ -31,13 +44,22 @@ func NewLocalOllamaClient(host string, model string) *LocalOllamaClient {
 }
 
 type ollamaRequest struct {
-	Model  string `json:"model"`
-	Prompt string `json:"prompt"`
-	Stream bool   `json:"stream"`
+	Model  string          `json:"model"`
+	Prompt string          `json:"prompt"`
+	Stream bool            `json:"stream"`
+	Format json.RawMessage `json:"format,omitempty"`
 }
 
 type ollamaResponse struct {
 	Response string `json:"response"`
+	Done     bool   `json:"done"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Token is a single incremental chunk of generated text emitted by
+// GenerateStream.
+type Token struct {
+	Text string
 }
 
 // Generate sends a prompt to the local Ollama instance.
@@ -85,3 +107,201 @@ func (c *LocalOllamaClient) Generate(ctx context.Context, prompt string) (string
 func (c *LocalOllamaClient) Name() string {
 	return fmt.Sprintf("Ollama (%s) [Local]", c.model)
 }
+
+// GenerateStructured sends prompt to the local Ollama instance with schema
+// set as Ollama's native "format" field, which constrains decoding so the
+// response is valid JSON matching schema rather than merely asking for it in
+// the prompt text and hoping. It returns the raw JSON response bytes; the
+// caller is responsible for unmarshalling into whatever shape schema
+// describes.
+func (c *LocalOllamaClient) GenerateStructured(ctx context.Context, prompt string, schema json.RawMessage) (string, error) {
+	log.Printf("[Ollama] 🏠 Sending structured request to Local Ollama (%s)...", c.model)
+
+	apiURL := fmt.Sprintf("%s/api/generate", c.host)
+
+	reqBody, err := json.Marshal(ollamaRequest{
+		Model:  c.model,
+		Prompt: prompt,
+		Stream: false,
+		Format: schema,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal ollama structured request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return "", fmt.Errorf("failed to create ollama structured request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("ollama structured request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("ollama returned error status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var ollamaResp ollamaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&ollamaResp); err != nil {
+		return "", fmt.Errorf("failed to decode ollama structured response: %w", err)
+	}
+
+	log.Printf("[Ollama] 🏠 Structured response received from local model.")
+	return ollamaResp.Response, nil
+}
+
+// Ping reports whether the local Ollama server is reachable, satisfying
+// health.Prober. It hits /api/tags rather than /api/generate since listing
+// installed models doesn't need to load one into memory.
+func (c *LocalOllamaClient) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.host+"/api/tags", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create ollama ping request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("ollama ping failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ollama ping returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// GenerateStream sends prompt to the local Ollama instance with streaming
+// enabled and emits each newline-delimited JSON chunk of Ollama's response
+// as a Token. A timer reset on every chunk cancels the request if
+// streamIdleTimeout elapses with no new bytes, so a stalled model can't
+// wedge a caller whose ctx carries no deadline of its own. Both channels are
+// closed once the stream ends (the final chunk has "done": true), ctx is
+// cancelled, or the idle timeout fires; errs carries at most one error.
+// Callers that don't need incremental output can use Generate instead.
+func (c *LocalOllamaClient) GenerateStream(ctx context.Context, prompt string) (<-chan Token, <-chan error) {
+	tokens := make(chan Token)
+	errs := make(chan error, 1)
+
+	reqCtx, cancel := context.WithCancel(ctx)
+
+	idleExceeded := make(chan struct{})
+	timer := time.AfterFunc(streamIdleTimeout, func() {
+		close(idleExceeded)
+		cancel()
+	})
+	resetIdle := func() {
+		if timer.Stop() {
+			timer.Reset(streamIdleTimeout)
+		}
+	}
+
+	go func() {
+		defer close(tokens)
+		defer close(errs)
+		defer cancel()
+		defer timer.Stop()
+
+		apiURL := fmt.Sprintf("%s/api/generate", c.host)
+
+		reqBody, err := json.Marshal(ollamaRequest{
+			Model:  c.model,
+			Prompt: prompt,
+			Stream: true,
+		})
+		if err != nil {
+			errs <- fmt.Errorf("failed to marshal ollama stream request: %w", err)
+			return
+		}
+
+		req, err := http.NewRequestWithContext(reqCtx, "POST", apiURL, bytes.NewBuffer(reqBody))
+		if err != nil {
+			errs <- fmt.Errorf("failed to create ollama stream request: %w", err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			select {
+			case <-idleExceeded:
+				errs <- fmt.Errorf("ollama stream timed out after %s with no activity", streamIdleTimeout)
+			default:
+				errs <- fmt.Errorf("ollama stream request failed: %w", err)
+			}
+			return
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			errs <- fmt.Errorf("ollama returned error status %d: %s", resp.StatusCode, string(body))
+			return
+		}
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLineSize)
+		for scanner.Scan() {
+			if reqCtx.Err() != nil {
+				select {
+				case <-idleExceeded:
+					errs <- fmt.Errorf("ollama stream idle for %s, aborting", streamIdleTimeout)
+				default:
+					errs <- ctx.Err()
+				}
+				return
+			}
+			resetIdle()
+
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+
+			var chunk ollamaResponse
+			if err := json.Unmarshal(line, &chunk); err != nil {
+				errs <- fmt.Errorf("failed to decode ollama stream chunk: %w", err)
+				return
+			}
+
+			if chunk.Error != "" {
+				errs <- fmt.Errorf("ollama stream returned error: %s", chunk.Error)
+				return
+			}
+
+			if chunk.Response != "" {
+				select {
+				case tokens <- Token{Text: chunk.Response}:
+				case <-idleExceeded:
+					errs <- fmt.Errorf("ollama stream idle for %s, aborting", streamIdleTimeout)
+					return
+				case <-ctx.Done():
+					errs <- ctx.Err()
+					return
+				}
+			}
+
+			if chunk.Done {
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			errs <- fmt.Errorf("ollama stream read failed: %w", err)
+			return
+		}
+
+		// The scanner ran out of input without ever seeing a "done": true
+		// chunk, meaning Ollama closed the connection mid-generation (e.g. a
+		// model crash) rather than finishing cleanly -- that's a failure,
+		// not a quietly truncated success.
+		errs <- fmt.Errorf("ollama stream ended unexpectedly before completion")
+	}()
+
+	return tokens, errs
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestLocalOllamaClient_Generate_Success(t *testing.T) {
@@ -93,3 +94,92 @@ func TestLocalOllamaClient_Defaults(t *testing.T) {
 		t.Errorf("expected default model, got %s", client.model)
 	}
 }
+
+// TestLocalOllamaClient_GenerateStream_PartialChunk writes a single NDJSON
+// line in two separate, flushed writes, split mid-token, to confirm
+// GenerateStream's scanner reassembles it into one chunk instead of
+// misparsing (or erroring on) the first half.
+func TestLocalOllamaClient_GenerateStream_PartialChunk(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+		w.WriteHeader(http.StatusOK)
+
+		line, err := json.Marshal(ollamaResponse{Response: "hello", Done: false})
+		if err != nil {
+			panic(err)
+		}
+		split := len(line) / 2
+
+		_, _ = w.Write(line[:split])
+		flusher.Flush()
+		_, _ = w.Write(line[split:])
+		_, _ = w.Write([]byte("\n"))
+		flusher.Flush()
+
+		last, err := json.Marshal(ollamaResponse{Response: "", Done: true})
+		if err != nil {
+			panic(err)
+		}
+		_, _ = w.Write(last)
+		_, _ = w.Write([]byte("\n"))
+		flusher.Flush()
+	}))
+	defer ts.Close()
+
+	client := NewLocalOllamaClient(ts.URL, "test-model")
+
+	tokens, errs := client.GenerateStream(context.Background(), "test prompt")
+
+	var received []Token
+	for tok := range tokens {
+		received = append(received, tok)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(received) != 1 || received[0].Text != "hello" {
+		t.Fatalf("expected a single reassembled token %q, got %v", "hello", received)
+	}
+}
+
+// TestLocalOllamaClient_GenerateStream_ContextCancellation confirms that
+// cancelling ctx mid-stream stops GenerateStream promptly, closing tokens
+// and delivering ctx.Err() on errs, rather than blocking on an unconsumed
+// send or an Ollama server that never finishes.
+func TestLocalOllamaClient_GenerateStream_ContextCancellation(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+		w.WriteHeader(http.StatusOK)
+
+		for i := 0; i < 100; i++ {
+			chunk, err := json.Marshal(ollamaResponse{Response: "word ", Done: false})
+			if err != nil {
+				panic(err)
+			}
+			if _, err := w.Write(append(chunk, '\n')); err != nil {
+				return
+			}
+			flusher.Flush()
+			time.Sleep(10 * time.Millisecond)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewLocalOllamaClient(ts.URL, "test-model")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tokens, errs := client.GenerateStream(ctx, "test prompt")
+
+	<-tokens
+	cancel()
+
+	for range tokens {
+		// drain until GenerateStream's goroutine observes the cancellation
+		// and closes the channel.
+	}
+
+	if err := <-errs; err == nil {
+		t.Fatal("expected a context cancellation error, got nil")
+	}
+}
@@ -2,7 +2,49 @@ package llm
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/booksage/booksage-api/internal/resilience"
+	"github.com/hashicorp/go-hclog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer and meter are package-scoped so every Router shares one set of
+// instruments, matching ingest.SagaOrchestrator's convention.
+var tracer = otel.Tracer("booksage-api/llm")
+
+var (
+	meter = otel.Meter("booksage-api/llm")
+
+	// routeOutcomeCounter counts each candidate attempt a routed Generate
+	// call makes, by client name and outcome ("success"/"failure"), so
+	// operators can see a provider degrading (rising failure share) and
+	// how often the fallback chain has to reach past the first candidate.
+	routeOutcomeCounter, _ = meter.Int64Counter(
+		"booksage.llm.route.outcome",
+		metric.WithDescription("Count of LLM Generate attempts routed through llm.Router, by client and outcome"),
+	)
+	// routeLatencyHistogram tracks how long each candidate attempt took,
+	// including any retries Execute absorbed before giving up or succeeding.
+	routeLatencyHistogram, _ = meter.Float64Histogram(
+		"booksage.llm.route.duration",
+		metric.WithDescription("Duration of a single routed LLM Generate attempt, by client"),
+		metric.WithUnit("s"),
+	)
+	// routeBreakerRejectedCounter counts attempts short-circuited by an open
+	// circuit breaker before the client was ever called, so operators can
+	// tell "client is failing" (routeOutcomeCounter failures) apart from
+	// "client is being skipped because it already failed too much."
+	routeBreakerRejectedCounter, _ = meter.Int64Counter(
+		"booksage.llm.route.breaker_rejected",
+		metric.WithDescription("Count of routed Generate attempts rejected by an open circuit breaker, by client"),
+	)
 )
 
 // TaskType defines the cognitive category of the LLM workload.
@@ -25,40 +67,507 @@ type LLMClient interface {
 	Name() string
 }
 
-// Router determines the appropriate LLMClient based on the task's cognitive requirements.
+// StreamingLLMClient is implemented by LLMClient backends that can emit
+// tokens incrementally instead of returning the full response in one shot
+// (GeminiClient, LocalOllamaClient). It's declared here rather than only in
+// whatever package first needed it, so RouteStreamingLLMTask and
+// LLMClientChain.GenerateStream can capability-check for it themselves
+// instead of every caller re-declaring the same type assertion.
+type StreamingLLMClient interface {
+	GenerateStream(ctx context.Context, prompt string) (<-chan Token, <-chan error)
+}
+
+// Priority nudges how RouteLLMTaskWithContext orders a task's remaining
+// candidates once unfit/unhealthy ones are dropped. The default,
+// PriorityNormal, prefers the cheapest candidate first; PriorityLatencySensitive
+// prefers the fastest, for callers (an interactive chat turn, say) that
+// would rather pay more than make a user wait.
+type Priority int
+
+const (
+	PriorityNormal Priority = iota
+	PriorityLatencySensitive
+)
+
+// RoutingHint carries the per-call context RouteLLMTaskWithContext uses to
+// pick among a task's candidates. PromptTokens filters out candidates whose
+// MaxInputTokens is declared and smaller than the prompt; Priority breaks
+// ties among whatever's left.
+type RoutingHint struct {
+	PromptTokens int
+	Priority     Priority
+}
+
+// candidate is one resolved routing option for a TaskType: the backend
+// client plus the attributes RouteLLMTaskWithContext needs to decide
+// whether it fits a hint and how to order the fallback chain.
+type candidate struct {
+	client LLMClient
+
+	// maxInputTokens is the candidate's context window; 0 means "no
+	// declared limit," so it's never filtered out on token count.
+	maxInputTokens int
+	// costPer1KTokens and expectedP95LatencyMS drive ordering among
+	// candidates that all fit the hint.
+	costPer1KTokens      float64
+	expectedP95LatencyMS int
+
+	// breaker trips after candidateFailureThreshold consecutive failures
+	// (each one already having absorbed its own retries, see retrier) and
+	// stays open for candidateOpenDuration before probing again, so a dead
+	// backend drops out of the fallback order instead of being retried (and
+	// failing) on every request.
+	breaker *resilience.CircuitBreaker
+	// retrier retries a single candidate attempt with exponential backoff
+	// and jitter before counting it as a failure against breaker, so an
+	// isolated transient error doesn't trip the breaker or fail the chain
+	// over to the next (likely more expensive) candidate.
+	retrier *resilience.Retrier
+}
+
+// candidateFailureThreshold and candidateOpenDuration tune how quickly a
+// misbehaving candidate drops out of consideration and how long it stays
+// out before being tried again. candidateRetry* tune the backoff a single
+// candidate gets before that failure counts against the threshold above.
+const (
+	candidateFailureThreshold = 3
+	candidateOpenDuration     = 30 * time.Second
+
+	candidateRetryMaxRetries = 2
+	candidateRetryBaseDelay  = 50 * time.Millisecond
+	candidateRetryCapDelay   = 2 * time.Second
+)
+
+// newCandidateResilience builds the breaker+retrier pair every candidate
+// gets, so selectCandidates and LLMClientChain.Generate never have to
+// nil-check them.
+func newCandidateResilience() (*resilience.CircuitBreaker, *resilience.Retrier) {
+	return resilience.NewCircuitBreaker(candidateFailureThreshold, candidateOpenDuration),
+		resilience.NewRetrier(candidateRetryMaxRetries, candidateRetryBaseDelay, candidateRetryCapDelay, nil)
+}
+
+// Router determines the appropriate LLMClient(s) based on the task's
+// cognitive requirements. With no RouterConfig it falls back to the
+// original hard-coded local-vs-Gemini switch (ADR-006); NewRouterWithConfig
+// layers a declarative, per-TaskType candidate policy on top so operators
+// can rebalance heavy tasks across more than two backends without
+// recompiling.
 type Router struct {
 	localClient  LLMClient
 	geminiClient LLMClient
+
+	// named resolves a RouterConfig candidate's Client name to the actual
+	// LLMClient instance, populated from localClient/geminiClient plus
+	// whatever WithNamedClient registers.
+	named map[string]LLMClient
+
+	// policy overrides the hard-coded default switch for any TaskType it
+	// lists, built by NewRouterWithConfig from a RouterConfig.
+	policy map[TaskType][]*candidate
+
+	// defaultCandidates holds one persistent candidate (and so one
+	// persistent breaker+retrier pair) per client name, reused across every
+	// TaskType that falls through to the hard-coded default switch instead
+	// of policy. Without this, a task whose breaker trips wouldn't stay
+	// tripped: candidatesFor would otherwise have to build a fresh,
+	// zero-state candidate for that client on every single call.
+	defaultCandidates map[string]*candidate
+
+	// logger is named "llm-router" and derived from whatever logger the
+	// caller injected, rather than reaching for hclog.Default(), so routing
+	// decisions are attributable to this subsystem in aggregated logs.
+	logger hclog.Logger
 }
 
-// NewRouter initializes the LLM router with the specified backend clients.
-func NewRouter(local LLMClient, gemini LLMClient) *Router {
-	return &Router{
+// NewRouter initializes the LLM router with the specified backend clients,
+// using the original hard-coded local-vs-Gemini switch for every task.
+func NewRouter(local LLMClient, gemini LLMClient, logger hclog.Logger) *Router {
+	r := &Router{
 		localClient:  local,
 		geminiClient: gemini,
+		named: map[string]LLMClient{
+			local.Name():  local,
+			gemini.Name(): gemini,
+		},
+		logger: logger.Named("llm-router"),
 	}
+	r.defaultCandidates = buildDefaultCandidates(r.named)
+	return r
 }
 
-// RouteLLMTask evaluates the cognitive load required and routes to the optimal backend (ADR-006).
-func (r *Router) RouteLLMTask(task TaskType) LLMClient {
-	var selected LLMClient
-	var icon string
+// NewRouterWithConfig builds a Router whose RouteLLMTaskWithContext
+// decisions follow cfg for any TaskType it lists, falling back to the
+// hard-coded default switch for tasks cfg doesn't mention. named maps
+// every client name cfg's candidates may reference (including "local" and
+// "gemini" if cfg uses those) to the actual client instance -- this is how
+// an operator-supplied backend (a self-hosted vLLM endpoint, say) joins
+// the pool without the router needing to know its concrete type.
+func NewRouterWithConfig(local, gemini LLMClient, named map[string]LLMClient, cfg *RouterConfig, logger hclog.Logger) (*Router, error) {
+	r := &Router{
+		localClient:  local,
+		geminiClient: gemini,
+		named:        named,
+		policy:       make(map[TaskType][]*candidate),
+		logger:       logger.Named("llm-router"),
+	}
+	if r.named == nil {
+		r.named = make(map[string]LLMClient)
+	}
+	r.named[local.Name()] = local
+	r.named[gemini.Name()] = gemini
+	r.defaultCandidates = buildDefaultCandidates(r.named)
+
+	if cfg == nil {
+		return r, nil
+	}
+
+	for task, specs := range cfg.Tasks {
+		candidates := make([]*candidate, 0, len(specs))
+		for _, spec := range specs {
+			client, ok := r.named[spec.Client]
+			if !ok {
+				return nil, fmt.Errorf("llm: router config references unknown client %q for task %q", spec.Client, task)
+			}
+			breaker, retrier := newCandidateResilience()
+			candidates = append(candidates, &candidate{
+				client:               client,
+				maxInputTokens:       spec.MaxInputTokens,
+				costPer1KTokens:      spec.CostPer1KTokens,
+				expectedP95LatencyMS: spec.ExpectedP95LatencyMS,
+				breaker:              breaker,
+				retrier:              retrier,
+			})
+		}
+		r.policy[task] = candidates
+	}
+	return r, nil
+}
 
+// RouteLLMTask routes task to a single backend using the hard-coded default
+// switch, with no hint-based filtering or multi-candidate fallback. It's a
+// convenience for callers that don't need RouteLLMTaskWithContext's policy
+// engine.
+func (r *Router) RouteLLMTask(ctx context.Context, task TaskType) LLMClient {
+	return r.RouteLLMTaskWithContext(ctx, task, RoutingHint{})
+}
+
+// RouteStreamingLLMTask routes task exactly like RouteLLMTaskWithContext,
+// then streams prompt through the resulting chain via GenerateStream. It
+// centralizes the primary-candidate-then-rest-of-chain fallback that every
+// streaming caller needs (agent.Generator's streamGenerate used to
+// duplicate this inline) so it only has to be gotten right once.
+func (r *Router) RouteStreamingLLMTask(ctx context.Context, task TaskType, hint RoutingHint, prompt string) (<-chan Token, <-chan error) {
+	chain := r.RouteLLMTaskWithContext(ctx, task, hint)
+	return chain.GenerateStream(ctx, prompt)
+}
+
+// RouteLLMTaskWithContext evaluates the cognitive load required by task and
+// returns an LLMClientChain ordered by hint: candidates whose declared
+// MaxInputTokens can't fit hint.PromptTokens, or whose circuit is currently
+// open, are dropped (unless that would empty the chain entirely, in which
+// case the full candidate list is kept as a last resort); what's left is
+// sorted cheapest-first, or fastest-first when hint.Priority is
+// PriorityLatencySensitive. The returned chain transparently falls back to
+// the next candidate if an earlier one's Generate call fails.
+func (r *Router) RouteLLMTaskWithContext(ctx context.Context, task TaskType, hint RoutingHint) *LLMClientChain {
+	_, span := tracer.Start(ctx, "router.route", trace.WithAttributes(
+		attribute.String("router.task_type", string(task)),
+	))
+	defer span.End()
+
+	candidates := r.candidatesFor(task)
+	candidates, lastResort := selectCandidates(candidates, hint)
+
+	names := make([]string, len(candidates))
+	for i, c := range candidates {
+		names[i] = c.client.Name()
+	}
+
+	span.SetAttributes(attribute.StringSlice("router.candidates", names))
+	r.logger.Info("routing task", "task", string(task), "candidates", names)
+
+	return &LLMClientChain{task: task, candidates: candidates, bypassBreaker: lastResort, logger: r.logger}
+}
+
+// candidatesFor returns task's configured candidate list, or a single-entry
+// list built from the hard-coded default switch if RouterConfig didn't
+// cover task.
+func (r *Router) candidatesFor(task TaskType) []*candidate {
+	if candidates, ok := r.policy[task]; ok {
+		return candidates
+	}
+
+	var selected LLMClient
 	switch task {
 	case TaskEmbedding, TaskSimpleKeywordExtraction:
-		// Send high volume or simple tasks to local models (e.g. Ollama/ColBERT) within the cluster.
 		selected = r.localClient
-		icon = "🏠"
 	case TaskAgenticReasoning, TaskDeepSummarization, TaskMultimodalParsing:
-		// Send tasks needing complex reasoning, huge 2M context windows, or vision capabilities to Gemini API.
 		selected = r.geminiClient
-		icon = "☁️"
 	default:
-		// Fallback to local for safety and cost if unspecified
 		selected = r.localClient
-		icon = "🏠"
 	}
+	return []*candidate{r.defaultCandidates[selected.Name()]}
+}
+
+// buildDefaultCandidates builds one persistent candidate per entry in named,
+// each wrapping its own breaker+retrier pair that outlives any single
+// RouteLLMTaskWithContext call.
+func buildDefaultCandidates(named map[string]LLMClient) map[string]*candidate {
+	candidates := make(map[string]*candidate, len(named))
+	for name, client := range named {
+		breaker, retrier := newCandidateResilience()
+		candidates[name] = &candidate{client: client, breaker: breaker, retrier: retrier}
+	}
+	return candidates
+}
+
+// selectCandidates drops candidates that don't fit hint (unless that would
+// leave nothing) and sorts the rest by hint.Priority. The second return
+// value reports whether every candidate was filtered out and the full list
+// was kept as a last resort -- LLMClientChain.Generate uses it to bypass
+// breaker gating for that chain, since a candidate only made it back into a
+// "last resort" list because nothing else was left to try, and an open
+// breaker rejecting it too would turn "try anyway" into "fail without
+// trying."
+func selectCandidates(candidates []*candidate, hint RoutingHint) ([]*candidate, bool) {
+	fit := make([]*candidate, 0, len(candidates))
+	for _, c := range candidates {
+		if c.maxInputTokens > 0 && hint.PromptTokens > c.maxInputTokens {
+			continue
+		}
+		if c.breaker != nil && c.breaker.IsOpen() {
+			continue
+		}
+		fit = append(fit, c)
+	}
+	lastResort := len(fit) == 0 && len(candidates) > 0
+	if lastResort {
+		fit = candidates
+	}
+
+	sorted := make([]*candidate, len(fit))
+	copy(sorted, fit)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if hint.Priority == PriorityLatencySensitive {
+			return sorted[i].expectedP95LatencyMS < sorted[j].expectedP95LatencyMS
+		}
+		return sorted[i].costPer1KTokens < sorted[j].costPer1KTokens
+	})
+	return sorted, lastResort
+}
+
+// LLMClientChain is an ordered set of candidate clients for one task,
+// selected and ordered by RouteLLMTaskWithContext. It implements LLMClient
+// itself so existing callers that just need something to call Generate on
+// don't need to change, while Generate transparently retries the next
+// candidate if an earlier one errors.
+type LLMClientChain struct {
+	task       TaskType
+	candidates []*candidate
+
+	// bypassBreaker is set when selectCandidates had to fall back to the
+	// full candidate list because every one of them was filtered out (all
+	// breakers open, say). In that case a candidate's breaker already
+	// rejected it once as a routing decision; gating Generate's attempt
+	// behind the same breaker would turn "try anyway, there's nothing
+	// better" into "fail without ever calling the client."
+	bypassBreaker bool
+
+	// logger is inherited from the Router that built this chain, so a
+	// fallback logged mid-Generate is still attributable to "llm-router".
+	logger hclog.Logger
+}
+
+// Generate tries each candidate in order, returning the first successful
+// response. Each attempt is itself retried with backoff through the
+// candidate's retrier before being recorded as a single success/failure
+// against its breaker, so an isolated transient error doesn't trip the
+// breaker or fail the chain over to the next (likely more expensive)
+// candidate. A breaker that's already open short-circuits straight to
+// ErrCircuitOpen without calling the client at all -- unless bypassBreaker
+// is set, in which case the call is attempted anyway and the outcome still
+// recorded against the breaker.
+func (c *LLMClientChain) Generate(ctx context.Context, prompt string) (string, error) {
+	if len(c.candidates) == 0 {
+		return "", fmt.Errorf("llm: no candidates available for this task")
+	}
+
+	var lastErr error
+	for _, cand := range c.candidates {
+		client := cand.client
+		start := time.Now()
+
+		var resp string
+		attempt := func() error {
+			return cand.retrier.Execute(ctx, func() error {
+				var genErr error
+				resp, genErr = client.Generate(ctx, prompt)
+				return genErr
+			})
+		}
+		var err error
+		if c.bypassBreaker {
+			err = attempt()
+			if err != nil {
+				cand.breaker.RecordFailure()
+			} else {
+				cand.breaker.RecordSuccess()
+			}
+		} else {
+			err = cand.breaker.Execute(attempt)
+		}
+
+		duration := time.Since(start).Seconds()
+		attrs := metric.WithAttributes(
+			attribute.String("client", client.Name()), attribute.String("task", string(c.task)),
+		)
+		routeLatencyHistogram.Record(ctx, duration, attrs)
+
+		if err == nil {
+			routeOutcomeCounter.Add(ctx, 1, metric.WithAttributes(
+				attribute.String("client", client.Name()), attribute.String("task", string(c.task)), attribute.String("outcome", "success"),
+			))
+			return resp, nil
+		}
+
+		if errors.Is(err, resilience.ErrCircuitOpen) {
+			routeBreakerRejectedCounter.Add(ctx, 1, attrs)
+		}
+		routeOutcomeCounter.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("client", client.Name()), attribute.String("task", string(c.task)), attribute.String("outcome", "failure"),
+		))
+		c.logger.Warn("candidate failed, falling back", "client", client.Name(), "task", string(c.task), "error", err)
+		lastErr = fmt.Errorf("%s: %w", client.Name(), err)
+	}
+	return "", fmt.Errorf("llm: all candidates exhausted, last error: %w", lastErr)
+}
+
+// GenerateStream streams prompt through the chain's primary candidate if it
+// implements StreamingLLMClient, falling back to a single blocking Generate
+// call across the chain's remaining candidates if the primary errors before
+// emitting any tokens -- once a token has been emitted, the stream can't be
+// un-sent to retry on the next candidate, so a later error is surfaced as
+// whatever GenerateStream produced instead. If the primary candidate
+// doesn't support streaming at all, the whole chain is run through
+// Generate and its result delivered as a single token, so a caller can
+// treat both paths identically. Both channels are closed once the chain is
+// exhausted; errs carries at most one error.
+func (c *LLMClientChain) GenerateStream(ctx context.Context, prompt string) (<-chan Token, <-chan error) {
+	tokens := make(chan Token)
+	errs := make(chan error, 1)
+
+	if len(c.candidates) == 0 {
+		go func() {
+			defer close(tokens)
+			defer close(errs)
+			errs <- fmt.Errorf("llm: no candidates available for this task")
+		}()
+		return tokens, errs
+	}
+
+	streaming, ok := c.candidates[0].client.(StreamingLLMClient)
+	if !ok {
+		go func() {
+			defer close(tokens)
+			defer close(errs)
+			resp, err := c.Generate(ctx, prompt)
+			if err != nil {
+				errs <- err
+				return
+			}
+			tokens <- Token{Text: resp}
+		}()
+		return tokens, errs
+	}
+
+	primaryTokens, primaryErrs := streaming.GenerateStream(ctx, prompt)
+	go func() {
+		defer close(tokens)
+		defer close(errs)
+
+		var streamed bool
+		for tok := range primaryTokens {
+			streamed = true
+			tokens <- tok
+		}
+		err := <-primaryErrs
+		if err == nil {
+			return
+		}
+		if streamed {
+			errs <- err
+			return
+		}
+
+		c.RecordPrimaryFailure()
+		resp, fbErr := c.Rest().Generate(ctx, prompt)
+		if fbErr != nil {
+			errs <- fbErr
+			return
+		}
+		tokens <- Token{Text: resp}
+	}()
+	return tokens, errs
+}
+
+// Name returns the first (most preferred) candidate's name, matching what a
+// direct RouteLLMTask caller would have seen before chains existed.
+func (c *LLMClientChain) Name() string {
+	if len(c.candidates) == 0 {
+		return "none"
+	}
+	return c.candidates[0].client.Name()
+}
+
+// Primary returns the chain's first (most preferred) candidate directly, for
+// callers that need to type-assert a capability interface (e.g.
+// agent.StreamingLLMClient) the chain itself can't forward without knowing
+// about it.
+func (c *LLMClientChain) Primary() LLMClient {
+	if len(c.candidates) == 0 {
+		return nil
+	}
+	return c.candidates[0].client
+}
+
+// RecordPrimaryFailure marks the chain's primary candidate as having just
+// failed, for a caller (like streamGenerate) that bypassed Generate to talk
+// to Primary() directly and so needs to report the failure back into that
+// candidate's breaker itself.
+func (c *LLMClientChain) RecordPrimaryFailure() {
+	if len(c.candidates) == 0 {
+		return
+	}
+	c.candidates[0].breaker.RecordFailure()
+}
+
+// Rest returns a chain holding every candidate after the primary, for a
+// caller that handled the primary itself and wants to fail over onto the
+// remaining candidates via Generate.
+func (c *LLMClientChain) Rest() *LLMClientChain {
+	if len(c.candidates) <= 1 {
+		return &LLMClientChain{task: c.task, bypassBreaker: c.bypassBreaker, logger: c.logger}
+	}
+	return &LLMClientChain{task: c.task, candidates: c.candidates[1:], bypassBreaker: c.bypassBreaker, logger: c.logger}
+}
 
-	log.Printf("[Router] 🛤️  Routing task '%s' to %s %s", task, icon, selected.Name())
-	return selected
+// BackendHealth is one client's point-in-time health snapshot, as reported
+// by Router.Healthz.
+type BackendHealth struct {
+	Name  string
+	State resilience.State
+}
+
+// Healthz returns a point-in-time snapshot of every client's circuit breaker
+// state, keyed by client name, so the API can surface it as an
+// operator-facing health endpoint. Only named clients with a persistent
+// breaker (defaultCandidates) are reported; a policy-only candidate's
+// breaker is scoped to its own RouteLLMTaskWithContext call and has no
+// standing health to report between routing decisions.
+func (r *Router) Healthz() map[string]BackendHealth {
+	out := make(map[string]BackendHealth, len(r.defaultCandidates))
+	for name, cand := range r.defaultCandidates {
+		out[name] = BackendHealth{Name: name, State: cand.breaker.CurrentState()}
+	}
+	return out
 }
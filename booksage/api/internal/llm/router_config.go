@@ -0,0 +1,59 @@
+package llm
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RouterConfig is a declarative routing policy: for each TaskType, an
+// ordered list of candidate backends with the attributes
+// RouteLLMTaskWithContext needs to filter and order them. Pass it to
+// NewRouterWithConfig to rebalance heavy tasks across providers (or add new
+// ones, like a self-hosted vLLM endpoint) without recompiling.
+type RouterConfig struct {
+	Tasks map[TaskType][]CandidateConfig `yaml:"tasks"`
+}
+
+// CandidateConfig names one backend client (resolved against the map
+// NewRouterWithConfig is given) plus its routing attributes.
+type CandidateConfig struct {
+	// Client is the name the client's Name() method returns, or a key
+	// registered in the named map passed to NewRouterWithConfig.
+	Client string `yaml:"client"`
+	// MaxInputTokens is this candidate's context window. 0 means
+	// unspecified: the candidate is never filtered out on prompt size.
+	MaxInputTokens int `yaml:"max_input_tokens"`
+	// CostPer1KTokens drives the default cheapest-first ordering.
+	CostPer1KTokens float64 `yaml:"cost_per_1k_tokens"`
+	// ExpectedP95LatencyMS drives ordering when a RoutingHint asks for
+	// PriorityLatencySensitive instead.
+	ExpectedP95LatencyMS int `yaml:"expected_p95_latency_ms"`
+}
+
+// LoadRouterConfig reads and parses a RouterConfig from a YAML file at
+// path, e.g.:
+//
+//	tasks:
+//	  agentic_reasoning:
+//	    - client: gemini
+//	      cost_per_1k_tokens: 0.0025
+//	      max_input_tokens: 2000000
+//	      expected_p95_latency_ms: 4000
+//	    - client: vllm-local
+//	      cost_per_1k_tokens: 0
+//	      max_input_tokens: 32000
+//	      expected_p95_latency_ms: 1200
+func LoadRouterConfig(path string) (*RouterConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("llm: failed to read router config %s: %w", path, err)
+	}
+
+	var cfg RouterConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("llm: failed to parse router config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
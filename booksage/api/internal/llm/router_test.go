@@ -2,17 +2,25 @@ package llm_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/booksage/booksage-api/internal/llm"
+	"github.com/booksage/booksage-api/internal/resilience"
+	"github.com/hashicorp/go-hclog"
 )
 
-// mockClient implements the LLMClient interface for testing.
+// mockClient implements the LLMClient interface for testing. A non-nil err
+// makes every Generate call fail, for exercising fallback/breaker behavior.
 type mockClient struct {
 	name string
+	err  error
 }
 
 func (m *mockClient) Generate(ctx context.Context, prompt string) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
 	return "Mock response from: " + m.name, nil
 }
 
@@ -24,7 +32,7 @@ func TestLLMRouter(t *testing.T) {
 	localMock := &mockClient{name: "local_ollama"}
 	geminiMock := &mockClient{name: "gemini_api"}
 
-	router := llm.NewRouter(localMock, geminiMock)
+	router := llm.NewRouter(localMock, geminiMock, hclog.NewNullLogger())
 
 	tests := []struct {
 		name         string
@@ -65,16 +73,39 @@ func TestLLMRouter(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			client := router.RouteLLMTask(tt.taskType)
-
-			mock, ok := client.(*mockClient)
-			if !ok {
-				t.Fatalf("Expected client to be of type *mockClient")
-			}
+			client := router.RouteLLMTaskWithContext(context.Background(), tt.taskType, llm.RoutingHint{})
 
-			if mock.name != tt.expectedName {
-				t.Errorf("For Task %s, expected router to select %s but got %s", tt.taskType, tt.expectedName, mock.name)
+			if client.Name() != tt.expectedName {
+				t.Errorf("For Task %s, expected router to select %s but got %s", tt.taskType, tt.expectedName, client.Name())
 			}
 		})
 	}
 }
+
+// TestLLMRouter_Healthz verifies Healthz reports each default candidate's
+// breaker state, and that a failed Generate call trips it from closed to
+// open once candidateFailureThreshold is reached.
+func TestLLMRouter_Healthz(t *testing.T) {
+	localMock := &mockClient{name: "local_ollama", err: errors.New("boom")}
+	geminiMock := &mockClient{name: "gemini_api"}
+
+	router := llm.NewRouter(localMock, geminiMock, hclog.NewNullLogger())
+
+	health := router.Healthz()
+	if health["local_ollama"].State != resilience.StateClosed {
+		t.Fatalf("expected local_ollama to start closed, got %v", health["local_ollama"].State)
+	}
+
+	for i := 0; i < 3; i++ {
+		chain := router.RouteLLMTaskWithContext(context.Background(), llm.TaskEmbedding, llm.RoutingHint{})
+		_, _ = chain.Generate(context.Background(), "prompt")
+	}
+
+	health = router.Healthz()
+	if health["local_ollama"].State != resilience.StateOpen {
+		t.Errorf("expected local_ollama breaker to be open after repeated failures, got %v", health["local_ollama"].State)
+	}
+	if health["gemini_api"].State != resilience.StateClosed {
+		t.Errorf("expected gemini_api to remain closed, got %v", health["gemini_api"].State)
+	}
+}
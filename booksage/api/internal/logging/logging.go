@@ -0,0 +1,27 @@
+// Package logging builds the process-wide hclog.Logger every subsystem
+// constructor (ingest.NewOrchestrator, fusion.NewFusionRetriever,
+// agent.NewGenerator, embedding.NewBatcher, llm.NewRouter, ...) is handed at
+// wiring time. Subsystems never reach for hclog.Default(): each constructor
+// derives its own named child (logger.Named("saga"), .Named("fusion"), ...)
+// from the logger it's given, so every log line is attributable to the
+// subsystem that emitted it without a global mutable logger anywhere.
+package logging
+
+import (
+	"os"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// New builds the root logger for the API process. Output is human-readable
+// text by default; setting BS_LOG_FORMAT=json switches to structured JSON
+// lines, for deployments that feed logs into an aggregator rather than a
+// terminal.
+func New() hclog.Logger {
+	return hclog.New(&hclog.LoggerOptions{
+		Name:       "booksage-api",
+		Level:      hclog.Info,
+		Output:     os.Stderr,
+		JSONFormat: os.Getenv("BS_LOG_FORMAT") == "json",
+	})
+}
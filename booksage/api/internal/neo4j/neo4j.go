@@ -4,16 +4,44 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
+	"sort"
+	"strings"
 
 	"github.com/neo4j/neo4j-go-driver/v6/neo4j"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
+// tracer is package-scoped so every Client shares one tracer, matching
+// ingest.SagaOrchestrator's convention.
+var tracer = otel.Tracer("booksage-api/neo4j")
+
+// defaultVectorDimensions matches the embedding size internal/qdrant's
+// collection is provisioned with, so chunk_vec can compare the same
+// vectors Qdrant stores.
+const defaultVectorDimensions = 768
+
+// defaultHybridAlpha weights SearchChunks' vector score against its
+// full-text score when both ran: 0.5 treats them as equally important,
+// matching RRFRanker's equal-weight-by-default convention elsewhere in
+// this codebase.
+const defaultHybridAlpha = 0.5
+
 // Client implements the ingest.Neo4jClient interface using the official Neo4j Go driver.
 type Client struct {
 	driver neo4j.Driver
+
+	// HybridAlpha weights SearchChunks' normalized vector score (alpha)
+	// against its normalized full-text score (1-alpha) when a query
+	// embedding is supplied. NewClient sets it to defaultHybridAlpha;
+	// callers can adjust it directly afterward.
+	HybridAlpha float64
 }
 
-// NewClient creates a new Neo4j client and verifies connectivity.
+// NewClient creates a new Neo4j client, verifies connectivity, and ensures
+// the full-text and vector indexes SearchChunks relies on exist.
 func NewClient(ctx context.Context, uri, user, password string) (*Client, error) {
 	driver, err := neo4j.NewDriver(uri, neo4j.BasicAuth(user, password, ""))
 	if err != nil {
@@ -28,8 +56,51 @@ func NewClient(ctx context.Context, uri, user, password string) (*Client, error)
 		return nil, fmt.Errorf("failed to verify Neo4j connectivity at %s: %w", uri, err)
 	}
 
+	client := &Client{driver: driver, HybridAlpha: defaultHybridAlpha}
+	if err := client.ensureIndexes(ctx); err != nil {
+		if closeErr := driver.Close(ctx); closeErr != nil {
+			log.Printf("[Neo4j] Warning: failed to close driver after index setup failure: %v", closeErr)
+		}
+		return nil, err
+	}
+
 	log.Printf("[Neo4j] Connected to %s as %s", uri, user)
-	return &Client{driver: driver}, nil
+	return client, nil
+}
+
+// ensureIndexes creates the full-text and vector indexes chunk_fts and
+// chunk_vec if they don't already exist, so SearchChunks always has both to
+// query against.
+func (c *Client) ensureIndexes(ctx context.Context) error {
+	ftsQuery := `CREATE FULLTEXT INDEX chunk_fts IF NOT EXISTS FOR (c:Chunk) ON EACH [c.text]`
+	if _, err := neo4j.ExecuteQuery(ctx, c.driver, ftsQuery, nil,
+		neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase("")); err != nil {
+		return fmt.Errorf("failed to create chunk_fts full-text index: %w", err)
+	}
+
+	// vector.dimensions can't be passed as a query parameter inside
+	// OPTIONS, so it's interpolated directly -- defaultVectorDimensions is
+	// a fixed constant, never user input.
+	vecQuery := fmt.Sprintf(`
+		CREATE VECTOR INDEX chunk_vec IF NOT EXISTS
+		FOR (c:Chunk) ON (c.embedding)
+		OPTIONS {indexConfig: {`+"`vector.dimensions`"+`: %d, `+"`vector.similarity_function`"+`: 'cosine'}}
+	`, defaultVectorDimensions)
+	if _, err := neo4j.ExecuteQuery(ctx, c.driver, vecQuery, nil,
+		neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase("")); err != nil {
+		return fmt.Errorf("failed to create chunk_vec vector index: %w", err)
+	}
+
+	// CREATE ... IF NOT EXISTS only registers the index; it doesn't wait for
+	// population. Without this, a query arriving right after a fresh
+	// deployment can hit chunk_fts/chunk_vec while still POPULATING.
+	awaitQuery := `CALL db.awaitIndexes(300)`
+	if _, err := neo4j.ExecuteQuery(ctx, c.driver, awaitQuery, nil,
+		neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase("")); err != nil {
+		return fmt.Errorf("failed waiting for chunk_fts/chunk_vec indexes to come online: %w", err)
+	}
+
+	return nil
 }
 
 // InsertNodesAndEdges creates a Document root node and Chunk child nodes in Neo4j.
@@ -81,15 +152,40 @@ func (c *Client) InsertNodesAndEdges(ctx context.Context, docID string, nodes []
 			pageNumber = int(pn)
 		}
 
+		// embedding stays a true nil interface{} (rather than a typed nil/empty
+		// []float64) when the node has none, so the driver encodes it as
+		// Cypher null -- the FOREACH guard below checks IS NOT NULL, and a
+		// typed empty slice would satisfy that check and wipe out any
+		// previously-stored embedding for this node_id.
+		var embedding any
+		if raw, ok := m["embedding"]; ok {
+			vec, err := toFloat32Slice(raw)
+			if err != nil {
+				return fmt.Errorf("node %d: embedding: %w", i, err)
+			}
+			if len(vec) > 0 {
+				vec64 := make([]float64, len(vec))
+				for j, f := range vec {
+					vec64[j] = float64(f)
+				}
+				embedding = vec64
+			}
+		}
+
 		nodeParams = append(nodeParams, map[string]any{
 			"node_id":     nodeID,
 			"doc_id":      docID,
 			"text":        text,
 			"node_type":   nodeType,
 			"page_number": pageNumber,
+			"embedding":   embedding,
 		})
 	}
 
+	// c.embedding is only set when n.embedding is non-null -- a node
+	// without one (anything inserted before chunk_vec existed, or a node
+	// type that never gets embedded) keeps whatever value, if any, it
+	// already had rather than being overwritten with null.
 	chunkQuery := `
 		UNWIND $nodes AS n
 		MERGE (c:Chunk {node_id: n.node_id})
@@ -97,6 +193,9 @@ func (c *Client) InsertNodesAndEdges(ctx context.Context, docID string, nodes []
 		    c.text = n.text,
 		    c.node_type = n.node_type,
 		    c.page_number = n.page_number
+		FOREACH (_ IN CASE WHEN n.embedding IS NOT NULL THEN [1] ELSE [] END |
+		    SET c.embedding = n.embedding
+		)
 		WITH c, n
 		MATCH (d:Document {doc_id: n.doc_id})
 		MERGE (d)-[:HAS_CHUNK]->(c)
@@ -115,12 +214,298 @@ func (c *Client) InsertNodesAndEdges(ctx context.Context, docID string, nodes []
 	return nil
 }
 
-// DeleteDocumentNodes deletes all nodes belonging to a document.
+// InsertSummaryNodes persists a RAPTOR tree's non-leaf nodes as Summary
+// nodes, linked by :SUMMARIZES edges to the children (Chunk or lower-level
+// Summary nodes, matched by node_id regardless of label) listed in each
+// node's "child_ids". Each node is expected to be a map[string]any with
+// "id", "text", "level", "embedding", and "child_ids" keys, matching
+// ingest.RaptorBuilder's output.
+func (c *Client) InsertSummaryNodes(ctx context.Context, docID string, nodes []any) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	var nodeParams []map[string]any
+	for i, node := range nodes {
+		m, ok := node.(map[string]any)
+		if !ok {
+			return fmt.Errorf("summary node %d: expected map[string]any, got %T", i, node)
+		}
+
+		nodeID, _ := m["id"].(string)
+		if nodeID == "" {
+			return fmt.Errorf("summary node %d: missing id", i)
+		}
+		text, _ := m["text"].(string)
+
+		level := 0
+		switch lv := m["level"].(type) {
+		case int:
+			level = lv
+		case int32:
+			level = int(lv)
+		case int64:
+			level = int(lv)
+		case float64:
+			level = int(lv)
+		}
+
+		vec, err := toFloat32Slice(m["embedding"])
+		if err != nil {
+			return fmt.Errorf("summary node %d: embedding: %w", i, err)
+		}
+		embedding := make([]float64, len(vec))
+		for j, f := range vec {
+			embedding[j] = float64(f)
+		}
+
+		childIDs := toStringSlice(m["child_ids"])
+
+		nodeParams = append(nodeParams, map[string]any{
+			"node_id":   nodeID,
+			"doc_id":    docID,
+			"text":      text,
+			"level":     level,
+			"embedding": embedding,
+			"child_ids": childIDs,
+		})
+	}
+
+	query := `
+		UNWIND $nodes AS n
+		MERGE (s:Summary {node_id: n.node_id})
+		SET s.doc_id = n.doc_id,
+		    s.text = n.text,
+		    s.level = n.level,
+		    s.embedding = n.embedding,
+		    s.child_ids = n.child_ids
+		WITH s, n
+		UNWIND n.child_ids AS child_id
+		MATCH (child {node_id: child_id})
+		MERGE (s)-[:SUMMARIZES]->(child)
+	`
+	if _, err := neo4j.ExecuteQuery(ctx, c.driver, query,
+		map[string]any{"nodes": nodeParams},
+		neo4j.EagerResultTransformer,
+		neo4j.ExecuteQueryWithDatabase(""),
+	); err != nil {
+		return fmt.Errorf("neo4j summary node insertion failed for doc %s: %w", docID, err)
+	}
+
+	log.Printf("[Neo4j] Inserted %d Summary nodes for doc %s", len(nodeParams), docID)
+	return nil
+}
+
+// HasSummaryNodes reports whether docID already has a RAPTOR tree built,
+// so a saga retried after the tree-build step already succeeded can skip
+// rebuilding it rather than re-summarizing and re-embedding on every retry.
+func (c *Client) HasSummaryNodes(ctx context.Context, docID string) (bool, error) {
+	query := `MATCH (s:Summary {doc_id: $doc_id}) RETURN count(s) AS cnt LIMIT 1`
+
+	result, err := neo4j.ExecuteQuery(ctx, c.driver, query,
+		map[string]any{"doc_id": docID},
+		neo4j.EagerResultTransformer,
+		neo4j.ExecuteQueryWithDatabase(""),
+	)
+	if err != nil {
+		return false, fmt.Errorf("neo4j summary existence check failed for doc %s: %w", docID, err)
+	}
+	if len(result.Records) == 0 {
+		return false, nil
+	}
+
+	cnt, _, err := neo4j.GetRecordValue[int64](result.Records[0], "cnt")
+	if err != nil {
+		return false, fmt.Errorf("neo4j result parse failed: %w", err)
+	}
+	return cnt > 0, nil
+}
+
+// GetRootSummaries returns up to limit Summary nodes, across all documents,
+// that have no incoming :SUMMARIZES edge -- i.e. the root of each
+// document's RAPTOR tree. A document with no tree yet (too few chunks, or
+// ingested before RAPTOR support existed) simply contributes none, so
+// searchTreeDB degrades to no tree results rather than erroring. limit
+// keeps this bounded as the corpus grows, the same way SearchChunks/
+// vectorSearch cap their own result counts.
+func (c *Client) GetRootSummaries(ctx context.Context, limit int) ([]TreeNode, error) {
+	ctx, span := tracer.Start(ctx, "neo4j.get_root_summaries")
+	defer span.End()
+	span.SetAttributes(attribute.Int("neo4j.limit", limit))
+
+	query := `
+		MATCH (root:Summary)
+		WHERE NOT (()-[:SUMMARIZES]->(root))
+		RETURN root.node_id AS node_id, root.text AS text, root.embedding AS embedding, root.child_ids AS child_ids
+		LIMIT $limit
+	`
+	result, err := neo4j.ExecuteQuery(ctx, c.driver, query, map[string]any{"limit": limit},
+		neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, fmt.Errorf("neo4j root summary lookup failed: %w", err)
+	}
+	nodes, err := treeNodesFromRecords(result.Records)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("neo4j.results", len(nodes)))
+	return nodes, nil
+}
+
+// GetTreeNodes fetches the RAPTOR tree nodes identified by ids, whether
+// Summary (non-leaf, with child_ids to keep descending into) or Chunk
+// (leaf, no child_ids). Missing ids are silently omitted rather than
+// erroring, since a stale child_id referencing a node removed by
+// DeleteDocumentNodes shouldn't break traversal of the rest of the tree.
+func (c *Client) GetTreeNodes(ctx context.Context, ids []string) ([]TreeNode, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	ctx, span := tracer.Start(ctx, "neo4j.get_tree_nodes")
+	defer span.End()
+	span.SetAttributes(attribute.Int("neo4j.requested_ids", len(ids)))
+
+	query := `
+		UNWIND $ids AS id
+		MATCH (n {node_id: id})
+		RETURN n.node_id AS node_id, n.text AS text, n.embedding AS embedding, n.child_ids AS child_ids
+	`
+	result, err := neo4j.ExecuteQuery(ctx, c.driver,
+		query, map[string]any{"ids": ids},
+		neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, fmt.Errorf("neo4j tree node lookup failed: %w", err)
+	}
+	nodes, err := treeNodesFromRecords(result.Records)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("neo4j.results", len(nodes)))
+	return nodes, nil
+}
+
+// TreeNode is one RAPTOR tree node (Summary or leaf Chunk) as returned by
+// GetRootSummaries/GetTreeNodes. ChildIDs is empty for a leaf Chunk.
+type TreeNode struct {
+	NodeID    string
+	Text      string
+	Embedding []float32
+	ChildIDs  []string
+}
+
+func treeNodesFromRecords(records []*neo4j.Record) ([]TreeNode, error) {
+	nodes := make([]TreeNode, 0, len(records))
+	for _, record := range records {
+		nodeID, _, _ := neo4j.GetRecordValue[string](record, "node_id")
+		text, _, _ := neo4j.GetRecordValue[string](record, "text")
+
+		var embedding []float32
+		if raw, found := record.Get("embedding"); found && raw != nil {
+			vec, err := toFloat32Slice(raw)
+			if err != nil {
+				return nil, fmt.Errorf("tree node %s: embedding: %w", nodeID, err)
+			}
+			embedding = vec
+		}
+
+		var childIDs []string
+		if raw, found := record.Get("child_ids"); found && raw != nil {
+			childIDs = toStringSlice(raw)
+		}
+
+		nodes = append(nodes, TreeNode{NodeID: nodeID, Text: text, Embedding: embedding, ChildIDs: childIDs})
+	}
+	return nodes, nil
+}
+
+// ChunkDocAdjacency fetches the reading-order structure of every document
+// that owns at least one of ids, for fusion.SkylineRanker's graph-derived
+// relevance axis. adj maps a chunk's node_id to its immediate neighbors --
+// the chunks immediately before and after it, by page_number, within the
+// same document -- in both directions, so personalized PageRank can
+// distribute rank across a document the same way it would an undirected
+// proximity graph. known is every chunk node_id considered, across every
+// document touched, including ones not in ids (a chunk that only exists to
+// complete another's adjacency chain still needs to be in the node set
+// centrality and PageRank normalize over). Chunks with no page_number set
+// keep Neo4j's own returned order, since nothing else orders them.
+func (c *Client) ChunkDocAdjacency(ctx context.Context, ids []string) (adj map[string][]string, known map[string]struct{}, err error) {
+	adj = map[string][]string{}
+	known = map[string]struct{}{}
+	if len(ids) == 0 {
+		return adj, known, nil
+	}
+
+	query := `
+		UNWIND $ids AS id
+		MATCH (c:Chunk {node_id: id})
+		WITH DISTINCT c.doc_id AS doc_id
+		MATCH (d:Document {doc_id: doc_id})-[:HAS_CHUNK]->(chunk:Chunk)
+		RETURN doc_id, chunk.node_id AS node_id, chunk.page_number AS page_number
+		ORDER BY doc_id, page_number
+	`
+	result, err := neo4j.ExecuteQuery(ctx, c.driver, query, map[string]any{"ids": ids},
+		neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""))
+	if err != nil {
+		return nil, nil, fmt.Errorf("neo4j chunk adjacency lookup failed: %w", err)
+	}
+
+	var docOrder []string
+	var currentDoc string
+	for _, record := range result.Records {
+		docID, _, _ := neo4j.GetRecordValue[string](record, "doc_id")
+		nodeID, _, _ := neo4j.GetRecordValue[string](record, "node_id")
+		if docID != currentDoc && len(docOrder) > 0 {
+			chainAdjacency(docOrder, adj)
+			docOrder = docOrder[:0]
+		}
+		currentDoc = docID
+		known[nodeID] = struct{}{}
+		docOrder = append(docOrder, nodeID)
+	}
+	if len(docOrder) > 0 {
+		chainAdjacency(docOrder, adj)
+	}
+
+	return adj, known, nil
+}
+
+// chainAdjacency links each consecutive pair in order with a bidirectional
+// edge in adj, building the reading-order proximity chain ChunkDocAdjacency
+// returns for one document.
+func chainAdjacency(order []string, adj map[string][]string) {
+	for i := 0; i < len(order)-1; i++ {
+		a, b := order[i], order[i+1]
+		adj[a] = append(adj[a], b)
+		adj[b] = append(adj[b], a)
+	}
+}
+
+// DeleteDocumentNodes deletes all nodes belonging to a document, including
+// any Summary nodes InsertSummaryNodes built on top of its chunks.
 func (c *Client) DeleteDocumentNodes(ctx context.Context, docID string) error {
+	// The two OPTIONAL MATCHes are independent patterns (Summary nodes
+	// aren't reachable from Document by a graph traversal), so they're
+	// collected separately before the delete instead of matched side by
+	// side -- matching them side by side would cross-join every chunk with
+	// every summary node first.
 	query := `
 		MATCH (d:Document {doc_id: $doc_id})
 		OPTIONAL MATCH (d)-[:HAS_CHUNK]->(c:Chunk)
-		DETACH DELETE c, d
+		WITH d, collect(c) AS chunks
+		OPTIONAL MATCH (s:Summary {doc_id: $doc_id})
+		WITH d, chunks, collect(s) AS summaries
+		FOREACH (c IN chunks | DETACH DELETE c)
+		FOREACH (s IN summaries | DETACH DELETE s)
+		DETACH DELETE d
 	`
 
 	_, err := neo4j.ExecuteQuery(ctx, c.driver, query,
@@ -132,7 +517,7 @@ func (c *Client) DeleteDocumentNodes(ctx context.Context, docID string) error {
 		return fmt.Errorf("neo4j delete failed for doc %s: %w", docID, err)
 	}
 
-	log.Printf("[Neo4j] Deleted Document + Chunk nodes for doc %s", docID)
+	log.Printf("[Neo4j] Deleted Document + Chunk + Summary nodes for doc %s", docID)
 	return nil
 }
 
@@ -161,42 +546,219 @@ func (c *Client) DocumentExists(ctx context.Context, docID string) (bool, error)
 	return cnt > 0, nil
 }
 
-// SearchChunks performs a text search on Chunk nodes using keyword matching.
-// Returns up to `limit` results.
-func (c *Client) SearchChunks(ctx context.Context, query string, limit int) ([]ChunkSearchResult, error) {
+// SearchChunks searches Chunk nodes by full-text relevance against query,
+// blended with vector similarity against queryEmbedding when one is
+// supplied (nil/empty skips the vector leg and returns full-text results
+// alone). Each leg's raw Neo4j score is min-max normalized to [0, 1] before
+// blending, since full-text and vector similarity scores aren't on
+// comparable scales; a chunk found by only one leg is scored on that leg
+// alone. Returns up to `limit` results ordered by blended score descending.
+func (c *Client) SearchChunks(ctx context.Context, query string, queryEmbedding []float32, limit int) ([]ChunkSearchResult, error) {
+	ctx, span := tracer.Start(ctx, "neo4j.search_chunks")
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int("neo4j.limit", limit),
+		attribute.Bool("neo4j.has_embedding", len(queryEmbedding) > 0),
+	)
+
+	textScores, textRows, err := c.fulltextSearch(ctx, query, limit)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	var vecScores map[string]float64
+	rows := textRows
+	if len(queryEmbedding) > 0 {
+		var vecRows map[string]chunkRow
+		vecScores, vecRows, err = c.vectorSearch(ctx, queryEmbedding, limit)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+		for id, row := range vecRows {
+			if _, ok := rows[id]; !ok {
+				rows[id] = row
+			}
+		}
+	}
+
+	textScores = normalizeScores(textScores)
+	vecScores = normalizeScores(vecScores)
+
+	alpha := c.HybridAlpha
+	var out []ChunkSearchResult
+	for id, row := range rows {
+		textScore, hasText := textScores[id]
+		vecScore, hasVec := vecScores[id]
+
+		var blended float64
+		switch {
+		case hasText && hasVec:
+			blended = alpha*vecScore + (1-alpha)*textScore
+		case hasVec:
+			blended = vecScore
+		default:
+			blended = textScore
+		}
+
+		out = append(out, ChunkSearchResult{
+			NodeID:     row.nodeID,
+			Text:       row.text,
+			DocID:      row.docID,
+			PageNumber: row.pageNumber,
+			Score:      float32(blended),
+		})
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	span.SetAttributes(attribute.Int("neo4j.results", len(out)))
+	return out, nil
+}
+
+// chunkRow is a Chunk node's identifying fields, shared by fulltextSearch
+// and vectorSearch so SearchChunks can merge their results by node ID.
+type chunkRow struct {
+	nodeID     string
+	text       string
+	docID      string
+	pageNumber int32
+}
+
+// fulltextSearch runs the chunk_fts full-text index query, returning each
+// matching node's raw relevance score and row keyed by node ID.
+func (c *Client) fulltextSearch(ctx context.Context, query string, limit int) (map[string]float64, map[string]chunkRow, error) {
 	cypher := `
-		MATCH (c:Chunk)
-		WHERE c.text CONTAINS $query
-		RETURN c.node_id AS node_id, c.text AS text, c.doc_id AS doc_id, c.page_number AS page_number
+		CALL db.index.fulltext.queryNodes('chunk_fts', $query) YIELD node, score
+		RETURN node.node_id AS node_id, node.text AS text, node.doc_id AS doc_id,
+		       node.page_number AS page_number, score
 		LIMIT $limit
 	`
 
 	result, err := neo4j.ExecuteQuery(ctx, c.driver, cypher,
-		map[string]any{"query": query, "limit": limit},
+		map[string]any{"query": escapeLuceneQuery(query), "limit": limit},
 		neo4j.EagerResultTransformer,
 		neo4j.ExecuteQueryWithDatabase(""),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("neo4j search failed: %w", err)
+		return nil, nil, fmt.Errorf("neo4j full-text search failed: %w", err)
 	}
 
-	var out []ChunkSearchResult
+	scores := make(map[string]float64, len(result.Records))
+	rows := make(map[string]chunkRow, len(result.Records))
 	for _, record := range result.Records {
-		nodeID, _, _ := neo4j.GetRecordValue[string](record, "node_id")
-		text, _, _ := neo4j.GetRecordValue[string](record, "text")
-		docID, _, _ := neo4j.GetRecordValue[string](record, "doc_id")
-		pageNumber, _, _ := neo4j.GetRecordValue[int64](record, "page_number")
+		row, score := chunkRowFromRecord(record)
+		scores[row.nodeID] = score
+		rows[row.nodeID] = row
+	}
+	return scores, rows, nil
+}
 
-		out = append(out, ChunkSearchResult{
-			NodeID:     nodeID,
-			Text:       text,
-			DocID:      docID,
-			PageNumber: int32(pageNumber),
-			Score:      0.5, // Fixed score for text match (no ranking in CONTAINS)
-		})
+// vectorSearch runs the chunk_vec vector index query, returning each
+// matching node's raw cosine similarity score and row keyed by node ID.
+func (c *Client) vectorSearch(ctx context.Context, queryEmbedding []float32, limit int) (map[string]float64, map[string]chunkRow, error) {
+	vec := make([]float64, len(queryEmbedding))
+	for i, f := range queryEmbedding {
+		vec[i] = float64(f)
 	}
 
-	return out, nil
+	cypher := `
+		CALL db.index.vector.queryNodes('chunk_vec', $k, $vec) YIELD node, score
+		RETURN node.node_id AS node_id, node.text AS text, node.doc_id AS doc_id,
+		       node.page_number AS page_number, score
+		LIMIT $limit
+	`
+
+	result, err := neo4j.ExecuteQuery(ctx, c.driver, cypher,
+		map[string]any{"k": limit, "vec": vec, "limit": limit},
+		neo4j.EagerResultTransformer,
+		neo4j.ExecuteQueryWithDatabase(""),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("neo4j vector search failed: %w", err)
+	}
+
+	scores := make(map[string]float64, len(result.Records))
+	rows := make(map[string]chunkRow, len(result.Records))
+	for _, record := range result.Records {
+		row, score := chunkRowFromRecord(record)
+		scores[row.nodeID] = score
+		rows[row.nodeID] = row
+	}
+	return scores, rows, nil
+}
+
+// luceneSpecialChars are the characters Lucene's query parser (backing
+// db.index.fulltext.queryNodes) treats as syntax. The old CONTAINS-based
+// search never failed on arbitrary user text, so these are escaped with a
+// backslash to restore that behavior instead of throwing a parse error.
+const luceneSpecialChars = `+-&&||!(){}[]^"~*?:\/`
+
+// escapeLuceneQuery backslash-escapes every Lucene special character in
+// query so it's always treated as a plain-text search term.
+func escapeLuceneQuery(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+	for _, r := range query {
+		if strings.ContainsRune(luceneSpecialChars, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// chunkRowFromRecord reads the node_id/text/doc_id/page_number/score columns
+// fulltextSearch and vectorSearch both project, shared so they stay in sync.
+func chunkRowFromRecord(record *neo4j.Record) (chunkRow, float64) {
+	nodeID, _, _ := neo4j.GetRecordValue[string](record, "node_id")
+	text, _, _ := neo4j.GetRecordValue[string](record, "text")
+	docID, _, _ := neo4j.GetRecordValue[string](record, "doc_id")
+	pageNumber, _, _ := neo4j.GetRecordValue[int64](record, "page_number")
+	score, _, _ := neo4j.GetRecordValue[float64](record, "score")
+
+	return chunkRow{
+		nodeID:     nodeID,
+		text:       text,
+		docID:      docID,
+		pageNumber: int32(pageNumber),
+	}, score
+}
+
+// normalizeScores min-max rescales scores' values into [0, 1]. A nil/empty
+// map, or one with zero spread, is returned unchanged (zero spread would
+// otherwise divide by zero); every entry maps to 1 in that case since
+// there's nothing to distinguish them by.
+func normalizeScores(scores map[string]float64) map[string]float64 {
+	if len(scores) == 0 {
+		return scores
+	}
+
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, s := range scores {
+		if s < min {
+			min = s
+		}
+		if s > max {
+			max = s
+		}
+	}
+
+	spread := max - min
+	out := make(map[string]float64, len(scores))
+	for id, s := range scores {
+		if spread == 0 {
+			out[id] = 1
+			continue
+		}
+		out[id] = (s - min) / spread
+	}
+	return out
 }
 
 // ChunkSearchResult represents a search result from Neo4j.
@@ -208,6 +770,70 @@ type ChunkSearchResult struct {
 	Score      float32
 }
 
+// toFloat32Slice converts various numeric slice types to []float32,
+// mirroring internal/infrastructure/backend's helper since chunk embeddings
+// arrive the same way here.
+func toFloat32Slice(v any) ([]float32, error) {
+	switch vt := v.(type) {
+	case []float32:
+		return vt, nil
+	case []float64:
+		out := make([]float32, len(vt))
+		for i, f := range vt {
+			out[i] = float32(f)
+		}
+		return out, nil
+	case []any:
+		out := make([]float32, len(vt))
+		for i, elem := range vt {
+			switch n := elem.(type) {
+			case float32:
+				out[i] = n
+			case float64:
+				out[i] = float32(n)
+			default:
+				return nil, fmt.Errorf("element %d: unsupported type %T", i, elem)
+			}
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("unsupported vector type %T", v)
+	}
+}
+
+// toStringSlice coerces a Neo4j driver value for a string-list property
+// (either already []string, or []any as the driver returns it when read
+// back from a query result) into []string, dropping any non-string element
+// rather than failing -- used for child_ids, where a stray non-string entry
+// shouldn't break traversal of the rest of the list.
+func toStringSlice(v any) []string {
+	switch vt := v.(type) {
+	case []string:
+		return vt
+	case []any:
+		out := make([]string, 0, len(vt))
+		for _, elem := range vt {
+			if s, ok := elem.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+// Ping reports whether Neo4j is reachable, satisfying health.Prober. It
+// reuses the same VerifyConnectivity call NewClient makes up front.
+func (c *Client) Ping(ctx context.Context) error {
+	return c.driver.VerifyConnectivity(ctx)
+}
+
+// Name identifies this client in a health.Registry.
+func (c *Client) Name() string {
+	return "neo4j"
+}
+
 // Close closes the underlying Neo4j driver.
 func (c *Client) Close(ctx context.Context) error {
 	return c.driver.Close(ctx)
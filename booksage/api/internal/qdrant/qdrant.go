@@ -2,22 +2,50 @@ package qdrant
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/binary"
 	"fmt"
 	"log"
 
+	"github.com/google/uuid"
 	pb "github.com/qdrant/go-client/qdrant"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
+// tracer is package-scoped so every Client shares one tracer, matching
+// ingest.SagaOrchestrator's convention.
+var tracer = otel.Tracer("booksage-api/qdrant")
+
+// defaultIDNamespace is the root UUID namespace DeterministicID derives
+// per-document namespaces from when a Client isn't given one of its own via
+// WithIDNamespace. It has no meaning beyond being a fixed, arbitrary UUID --
+// any deployment that cares about stable IDs across a fresh BookSage
+// install should pin its own via WithIDNamespace instead of relying on this.
+var defaultIDNamespace = uuid.MustParse("a3f1d9e2-5b7c-4e1a-9c3d-2f6b8a1e4d70")
+
 // Client implements the ingest.QdrantClient interface using the official Qdrant Go SDK.
 type Client struct {
-	client     *pb.Client
-	collection string
+	client      *pb.Client
+	collection  string
+	idNamespace uuid.UUID
+	cfg         CollectionConfig
 }
 
-// NewClient creates a new Qdrant client and ensures the target collection exists.
+// NewClient creates a new Qdrant client and ensures the target collection
+// exists, using DefaultCollectionConfig. It is a thin wrapper around
+// NewClientWithConfig kept for existing callers that don't need to tune
+// vector size, HNSW, quantization, or sharding.
 func NewClient(host string, port int, collection string) (*Client, error) {
+	return NewClientWithConfig(host, port, collection, DefaultCollectionConfig())
+}
+
+// NewClientWithConfig creates a new Qdrant client and ensures the target
+// collection exists, provisioning it according to cfg if it doesn't. If
+// the collection already exists, its dense vector size is validated
+// against cfg.VectorSize and NewClientWithConfig fails with a clear error
+// on mismatch rather than returning a Client that would silently upsert
+// incompatible-dimension vectors into it.
+func NewClientWithConfig(host string, port int, collection string, cfg CollectionConfig) (*Client, error) {
 	client, err := pb.NewClient(&pb.Config{
 		Host: host,
 		Port: port,
@@ -27,8 +55,10 @@ func NewClient(host string, port int, collection string) (*Client, error) {
 	}
 
 	c := &Client{
-		client:     client,
-		collection: collection,
+		client:      client,
+		collection:  collection,
+		idNamespace: defaultIDNamespace,
+		cfg:         cfg,
 	}
 
 	// Ensure collection exists (create if not)
@@ -40,24 +70,135 @@ func NewClient(host string, port int, collection string) (*Client, error) {
 	return c, nil
 }
 
-// ensureCollection creates the collection if it does not already exist.
+// WithIDNamespace overrides the root UUID namespace DeterministicID derives
+// per-document namespaces from. Deployments that migrated from the old
+// integer point IDs should pin the same root namespace they ran the
+// migration with, so re-ingesting a document that already exists produces
+// the same point IDs instead of silently duplicating it.
+func (c *Client) WithIDNamespace(root uuid.UUID) *Client {
+	c.idNamespace = root
+	return c
+}
+
+// Named vectors in the collection schema. denseVectorName holds the
+// ordinary dense embedding (what Search/InsertChunks used exclusively
+// before hybrid retrieval was added), sparseVectorName holds BM25/SPLADE
+// term weights, and colbertVectorName holds per-token ColBERT embeddings
+// used only as a late-interaction rerank stage.
+const (
+	denseVectorName   = "dense"
+	sparseVectorName  = "sparse"
+	colbertVectorName = "colbert"
+)
+
+// HNSWConfig tunes the HNSW graph Qdrant builds over a collection's dense
+// vectors. Leaving both fields zero lets Qdrant apply its own defaults.
+type HNSWConfig struct {
+	// M is the number of edges per node in the graph; higher values trade
+	// memory and build time for recall.
+	M uint64
+	// EFConstruct is the size of the dynamic candidate list used while
+	// building the graph; higher values trade build time for recall.
+	EFConstruct uint64
+}
+
+// ScalarQuantizationConfig enables int8 scalar quantization, trading a
+// small amount of recall for roughly a 4x reduction in vector memory.
+type ScalarQuantizationConfig struct {
+	// AlwaysRAM keeps the quantized vectors in RAM even when OnDiskPayload
+	// (or a quantization config in general) would otherwise let Qdrant
+	// page them from disk -- useful when the collection is too large for
+	// full-precision-in-RAM but still latency-sensitive.
+	AlwaysRAM bool
+}
+
+// ProductQuantizationConfig enables product quantization, trading more
+// recall than scalar quantization for a larger memory reduction -- the
+// right tradeoff once a collection's embeddings no longer fit in RAM even
+// at int8.
+type ProductQuantizationConfig struct {
+	AlwaysRAM bool
+}
+
+// CollectionConfig controls how ensureCollection provisions (or validates)
+// the Qdrant collection: dense vector size/distance, HNSW graph tuning,
+// optional quantization, and storage/sharding placement. Use
+// DefaultCollectionConfig for the 768-dim MiniLM-class defaults
+// ensureCollection hardcoded before this was configurable.
+type CollectionConfig struct {
+	VectorSize uint64
+	Distance   pb.Distance
+
+	HNSWConfig *HNSWConfig
+
+	// At most one of ScalarQuantization/ProductQuantization should be set;
+	// if both are, ScalarQuantization takes precedence.
+	ScalarQuantization  *ScalarQuantizationConfig
+	ProductQuantization *ProductQuantizationConfig
+
+	OnDiskPayload     bool
+	ShardNumber       uint32
+	ReplicationFactor uint32
+}
+
+// DefaultCollectionConfig mirrors ensureCollection's original hardcoded
+// behavior: a 768-dim cosine dense vector (a common dimension for
+// MiniLM-class embedding models), Qdrant's own HNSW/quantization/sharding
+// defaults, and in-memory payload.
+func DefaultCollectionConfig() CollectionConfig {
+	return CollectionConfig{
+		VectorSize: 768,
+		Distance:   pb.Distance_Cosine,
+	}
+}
+
+// ensureCollection creates the collection if it does not already exist,
+// according to c.cfg. If the collection already exists, its dense vector
+// size is instead validated against c.cfg.VectorSize so a misconfigured
+// deployment fails fast with a clear error rather than silently upserting
+// incompatible-dimension vectors into it.
 func (c *Client) ensureCollection(ctx context.Context) error {
 	exists, err := c.client.CollectionExists(ctx, c.collection)
 	if err != nil {
 		return err
 	}
 	if exists {
-		return nil
+		info, err := c.client.GetCollectionInfo(ctx, c.collection)
+		if err != nil {
+			return fmt.Errorf("failed to inspect existing collection %q: %w", c.collection, err)
+		}
+		return c.validateVectorSize(info)
 	}
 
-	// Create collection with a reasonable default vector size.
-	// 768 is a common dimension for many embedding models (e.g. all-MiniLM-L6-v2).
+	// Three named vectors per point: "dense" for cosine similarity search
+	// (the only vector pre-existing collections have), "sparse" for
+	// BM25/SPLADE term weights fused into dense via RRF during
+	// HybridSearch's prefetch stage, and "colbert" -- a MaxSim multi-vector
+	// -- used only as a late-interaction rerank stage over the fused
+	// prefetch results.
 	err = c.client.CreateCollection(ctx, &pb.CreateCollection{
 		CollectionName: c.collection,
-		VectorsConfig: pb.NewVectorsConfig(&pb.VectorParams{
-			Size:     768,
-			Distance: pb.Distance_Cosine,
+		VectorsConfig: pb.NewVectorsConfigMap(map[string]*pb.VectorParams{
+			denseVectorName: {
+				Size:     c.cfg.VectorSize,
+				Distance: c.cfg.Distance,
+			},
+			colbertVectorName: {
+				Size:     c.cfg.VectorSize,
+				Distance: c.cfg.Distance,
+				MultivectorConfig: &pb.MultiVectorConfig{
+					Comparator: pb.MultiVectorComparator_MaxSim,
+				},
+			},
+		}),
+		SparseVectorsConfig: pb.NewSparseVectorsConfig(map[string]*pb.SparseVectorParams{
+			sparseVectorName: {},
 		}),
+		HnswConfig:         c.cfg.hnswConfigDiff(),
+		QuantizationConfig: c.cfg.quantizationConfig(),
+		OnDiskPayload:      pb.PtrOf(c.cfg.OnDiskPayload),
+		ShardNumber:        nonZeroUint32Ptr(c.cfg.ShardNumber),
+		ReplicationFactor:  nonZeroUint32Ptr(c.cfg.ReplicationFactor),
 	})
 	if err != nil {
 		return err
@@ -72,14 +213,234 @@ func (c *Client) ensureCollection(ctx context.Context) error {
 	return nil
 }
 
-// createPayloadIndex creates keyword indexes on frequently filtered payload fields.
+// validateVectorSize checks that an existing collection's dense vector
+// size matches c.cfg.VectorSize, returning a descriptive error on mismatch
+// instead of letting callers silently upsert incompatible-dimension
+// vectors into it. Collections provisioned before chunk10-1 (a single
+// unnamed vector rather than the "dense"/"sparse"/"colbert" named-vector
+// schema) are accepted too, so upgrading a running deployment onto this
+// client doesn't brick it at startup; InsertChunks still writes the named
+// schema going forward, and MigrateIntegerIDsToUUID carries points across
+// whichever schema they were written in. Run EnsureNamedVectorSchema to
+// reprovision a legacy collection once HybridSearch support is needed.
+func (c *Client) validateVectorSize(info *pb.CollectionInfo) error {
+	vectorsConfig := info.GetConfig().GetParams().GetVectorsConfig()
+
+	if params, ok := vectorsConfig.GetParamsMap().GetMap()[denseVectorName]; ok {
+		if params.GetSize() != c.cfg.VectorSize {
+			return fmt.Errorf("collection %q vector size mismatch: collection has %d, configured %d", c.collection, params.GetSize(), c.cfg.VectorSize)
+		}
+		return nil
+	}
+
+	if legacy := vectorsConfig.GetParams(); legacy != nil {
+		if legacy.GetSize() != c.cfg.VectorSize {
+			return fmt.Errorf("collection %q vector size mismatch: collection has %d, configured %d", c.collection, legacy.GetSize(), c.cfg.VectorSize)
+		}
+		log.Printf("[Qdrant] Collection %q uses the pre-chunk10-1 single-vector schema; HybridSearch/sparse/colbert are unavailable until it is reprovisioned via EnsureNamedVectorSchema", c.collection)
+		return nil
+	}
+
+	return fmt.Errorf("collection %q has no %q vector configured", c.collection, denseVectorName)
+}
+
+// migrationAliasArgs returns the (aliasName, collectionName) pair
+// EnsureNamedVectorSchema passes to CreateAlias -- the SDK takes the alias
+// first and the collection it points at second, so swapping the original
+// name onto the migrated collection means aliasing collection (the
+// just-freed original name) to tmpCollection (the migrated data), not the
+// other way around.
+func migrationAliasArgs(collection, tmpCollection string) (alias, target string) {
+	return collection, tmpCollection
+}
+
+// EnsureNamedVectorSchema migrates a collection still on the pre-chunk10-1
+// single-vector schema onto the named dense/sparse/colbert schema
+// ensureCollection provisions for new collections. Qdrant has no in-place
+// "add named vectors to an existing unnamed-vector collection" operation,
+// so this recreates the collection under a temporary name, re-upserts
+// every point's dense vector (the only vector a legacy collection has)
+// under the "dense" name, then swaps it into place with CreateAlias/
+// DeleteCollection. Sparse/ColBERT vectors are not backfilled -- re-ingest
+// or re-embed existing documents to populate them.
+func (c *Client) EnsureNamedVectorSchema(ctx context.Context) error {
+	info, err := c.client.GetCollectionInfo(ctx, c.collection)
+	if err != nil {
+		return fmt.Errorf("failed to inspect collection %q: %w", c.collection, err)
+	}
+	legacy := info.GetConfig().GetParams().GetVectorsConfig().GetParams()
+	if legacy == nil {
+		// Already on the named-vector schema (or doesn't exist yet, in
+		// which case ensureCollection will provision it correctly).
+		return nil
+	}
+
+	tmpCollection := c.collection + "_chunk10_1_migration"
+	if err := c.client.CreateCollection(ctx, &pb.CreateCollection{
+		CollectionName: tmpCollection,
+		VectorsConfig: pb.NewVectorsConfigMap(map[string]*pb.VectorParams{
+			denseVectorName: {
+				Size:     legacy.GetSize(),
+				Distance: legacy.GetDistance(),
+			},
+			colbertVectorName: {
+				Size:     legacy.GetSize(),
+				Distance: legacy.GetDistance(),
+				MultivectorConfig: &pb.MultiVectorConfig{
+					Comparator: pb.MultiVectorComparator_MaxSim,
+				},
+			},
+		}),
+		SparseVectorsConfig: pb.NewSparseVectorsConfig(map[string]*pb.SparseVectorParams{
+			sparseVectorName: {},
+		}),
+	}); err != nil {
+		return fmt.Errorf("failed to create migration collection %q: %w", tmpCollection, err)
+	}
+
+	var offset *pb.PointId
+	migrated := 0
+	for {
+		scrollReq := &pb.ScrollPoints{
+			CollectionName: c.collection,
+			Limit:          pb.PtrOf(uint32(256)),
+			WithPayload:    pb.NewWithPayload(true),
+			WithVectors:    pb.NewWithVectors(true),
+		}
+		if offset != nil {
+			scrollReq.Offset = offset
+		}
+		page, err := c.client.Scroll(ctx, scrollReq)
+		if err != nil {
+			return fmt.Errorf("qdrant scroll failed during named-vector migration: %w", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		points := make([]*pb.PointStruct, 0, len(page))
+		for _, point := range page {
+			offset = point.Id
+			points = append(points, &pb.PointStruct{
+				Id: point.Id,
+				Vectors: pb.NewVectorsMap(map[string]*pb.Vector{
+					denseVectorName: vectorFromOutput(point.Vectors.GetVector()),
+				}),
+				Payload: point.Payload,
+			})
+		}
+		if _, err := c.client.Upsert(ctx, &pb.UpsertPoints{
+			CollectionName: tmpCollection,
+			Points:         points,
+		}); err != nil {
+			return fmt.Errorf("qdrant upsert failed during named-vector migration: %w", err)
+		}
+		migrated += len(points)
+	}
+
+	if err := c.client.DeleteCollection(ctx, c.collection); err != nil {
+		return fmt.Errorf("failed to drop legacy collection %q: %w", c.collection, err)
+	}
+	alias, target := migrationAliasArgs(c.collection, tmpCollection)
+	if err := c.client.CreateAlias(ctx, alias, target); err != nil {
+		return fmt.Errorf("failed to alias %q to %q: %w", c.collection, tmpCollection, err)
+	}
+
+	log.Printf("[Qdrant] Migrated collection %q to the named-vector schema (%d points)", c.collection, migrated)
+	return nil
+}
+
+// hnswConfigDiff translates HNSWConfig into the wire-level HnswConfigDiff,
+// or nil to let Qdrant apply its own defaults.
+func (cfg CollectionConfig) hnswConfigDiff() *pb.HnswConfigDiff {
+	if cfg.HNSWConfig == nil {
+		return nil
+	}
+	return &pb.HnswConfigDiff{
+		M:           pb.PtrOf(cfg.HNSWConfig.M),
+		EfConstruct: pb.PtrOf(cfg.HNSWConfig.EFConstruct),
+	}
+}
+
+// quantizationConfig translates ScalarQuantization/ProductQuantization
+// into the wire-level QuantizationConfig, or nil if neither is set.
+func (cfg CollectionConfig) quantizationConfig() *pb.QuantizationConfig {
+	if cfg.ScalarQuantization != nil {
+		return pb.NewQuantizationScalar(&pb.ScalarQuantization{
+			Type:      pb.QuantizationType_Int8,
+			AlwaysRam: pb.PtrOf(cfg.ScalarQuantization.AlwaysRAM),
+		})
+	}
+	if cfg.ProductQuantization != nil {
+		return pb.NewQuantizationProduct(&pb.ProductQuantization{
+			Compression: pb.CompressionRatio_x16,
+			AlwaysRam:   pb.PtrOf(cfg.ProductQuantization.AlwaysRAM),
+		})
+	}
+	return nil
+}
+
+// nonZeroUint32Ptr returns nil for a zero value so CreateCollection falls
+// back to Qdrant's own default shard/replication settings instead of
+// explicitly requesting zero shards or zero replicas.
+func nonZeroUint32Ptr(v uint32) *uint32 {
+	if v == 0 {
+		return nil
+	}
+	return pb.PtrOf(v)
+}
+
+// vectorFromOutput converts a server-returned VectorOutput back into the
+// Vector shape Upsert expects, so a scrolled/migrated point can be
+// re-upserted unchanged regardless of whether it came back dense, sparse
+// or colbert-style multi-vector.
+func vectorFromOutput(vo *pb.VectorOutput) *pb.Vector {
+	switch {
+	case vo.GetDense() != nil:
+		return pb.NewVectorDense(vo.GetDense().GetData())
+	case vo.GetSparse() != nil:
+		return pb.NewVectorSparse(vo.GetSparse().GetIndices(), vo.GetSparse().GetValues())
+	case vo.GetMultiDense() != nil:
+		vectors := make([][]float32, len(vo.GetMultiDense().GetVectors()))
+		for i, v := range vo.GetMultiDense().GetVectors() {
+			vectors[i] = v.GetData()
+		}
+		return pb.NewVectorMulti(vectors)
+	default:
+		return pb.NewVector(vo.GetData()...)
+	}
+}
+
+// vectorsFromOutputMap applies vectorFromOutput across a named-vector map.
+func vectorsFromOutputMap(named map[string]*pb.VectorOutput) map[string]*pb.Vector {
+	out := make(map[string]*pb.Vector, len(named))
+	for name, vo := range named {
+		out[name] = vectorFromOutput(vo)
+	}
+	return out
+}
+
+// createPayloadIndex creates indexes on the payload fields SearchWithFilter
+// filters against, so those filters stay fast as a collection grows instead
+// of falling back to a full scan.
 func (c *Client) createPayloadIndex(ctx context.Context) error {
-	_, err := c.client.CreateFieldIndex(ctx, &pb.CreateFieldIndexCollection{
-		CollectionName: c.collection,
-		FieldName:      "doc_id",
-		FieldType:      pb.PtrOf(pb.FieldType_FieldTypeKeyword),
-	})
-	return err
+	indexes := []struct {
+		field     string
+		fieldType pb.FieldType
+	}{
+		{"doc_id", pb.FieldType_FieldTypeKeyword},
+		{"page_number", pb.FieldType_FieldTypeInteger},
+		{"type", pb.FieldType_FieldTypeKeyword},
+	}
+	for _, idx := range indexes {
+		if _, err := c.client.CreateFieldIndex(ctx, &pb.CreateFieldIndexCollection{
+			CollectionName: c.collection,
+			FieldName:      idx.field,
+			FieldType:      pb.PtrOf(idx.fieldType),
+		}); err != nil {
+			return fmt.Errorf("field %q: %w", idx.field, err)
+		}
+	}
+	return nil
 }
 
 // InsertChunks upserts embedding chunks into the Qdrant collection.
@@ -114,10 +475,14 @@ func (c *Client) InsertChunks(ctx context.Context, docID string, chunks []any) e
 			return fmt.Errorf("chunk %d: %w", i, err)
 		}
 
-		// Build payload
+		// Build payload. chunk_id is kept alongside doc_id so that
+		// MigrateIntegerIDsToUUID (and any future re-indexing) can
+		// recompute the same DeterministicID without needing to reverse
+		// the point ID itself.
 		payload := map[string]any{
-			"doc_id": docID,
-			"text":   text,
+			"doc_id":   docID,
+			"chunk_id": chunkID,
+			"text":     text,
 		}
 		if pageNum, ok := m["page_number"]; ok {
 			payload["page_number"] = pageNum
@@ -126,12 +491,32 @@ func (c *Client) InsertChunks(ctx context.Context, docID string, chunks []any) e
 			payload["type"] = chunkType
 		}
 
-		// Generate a deterministic numeric ID from the chunk string ID
-		pointID := deterministicID(chunkID)
+		// Namespace the deterministic ID by document so that chunks from
+		// different documents (or different sources feeding the same
+		// collection) can never collide, even if their raw chunk IDs do.
+		pointID := c.DeterministicID(docID, chunkID)
+
+		namedVectors := map[string]*pb.Vector{
+			denseVectorName: pb.NewVector(vector...),
+		}
+		if sparseRaw, ok := m["sparse_vector"]; ok {
+			sparse, err := toSparseVector(sparseRaw)
+			if err != nil {
+				return fmt.Errorf("chunk %d: sparse_vector: %w", i, err)
+			}
+			namedVectors[sparseVectorName] = sparse
+		}
+		if colbertRaw, ok := m["colbert_vectors"]; ok {
+			colbert, err := toColbertVectors(colbertRaw)
+			if err != nil {
+				return fmt.Errorf("chunk %d: colbert_vectors: %w", i, err)
+			}
+			namedVectors[colbertVectorName] = colbert
+		}
 
 		points = append(points, &pb.PointStruct{
-			Id:      pb.NewIDNum(pointID),
-			Vectors: pb.NewVectors(vector...),
+			Id:      pb.NewID(pointID.String()),
+			Vectors: pb.NewVectorsMap(namedVectors),
 			Payload: pb.NewValueMap(payload),
 		})
 	}
@@ -173,15 +558,246 @@ func (c *Client) DeleteDocument(ctx context.Context, docID string) error {
 // Search performs a dense vector similarity search in the collection.
 // Returns up to `limit` results with their text payloads and scores.
 func (c *Client) Search(ctx context.Context, queryVector []float32, limit uint64) ([]SearchResult, error) {
+	ctx, span := tracer.Start(ctx, "qdrant.search")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("qdrant.collection", c.collection),
+		attribute.Int64("qdrant.limit", int64(limit)),
+	)
+
 	results, err := c.client.Query(ctx, &pb.QueryPoints{
 		CollectionName: c.collection,
 		Query:          pb.NewQuery(queryVector...),
+		Using:          pb.PtrOf(denseVectorName),
 		Limit:          pb.PtrOf(limit),
 		WithPayload:    pb.NewWithPayload(true),
 	})
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return nil, fmt.Errorf("qdrant search failed: %w", err)
 	}
+	span.SetAttributes(attribute.Int("qdrant.results", len(results)))
+
+	var out []SearchResult
+	for _, point := range results {
+		text := ""
+		docID := ""
+		pageNum := int32(0)
+
+		if val, ok := point.Payload["text"]; ok {
+			text = val.GetStringValue()
+		}
+		if val, ok := point.Payload["doc_id"]; ok {
+			docID = val.GetStringValue()
+		}
+		if val, ok := point.Payload["page_number"]; ok {
+			pageNum = int32(val.GetIntegerValue())
+		}
+
+		out = append(out, SearchResult{
+			ID:         point.Id.GetUuid(),
+			Text:       text,
+			DocID:      docID,
+			PageNumber: pageNum,
+			Score:      point.Score,
+		})
+	}
+
+	return out, nil
+}
+
+// SearchFilter restricts SearchWithFilter to a subset of the collection.
+// A zero-value field is left unconstrained; DocIDs and ChunkTypes are
+// OR'd within themselves and AND'd against each other and against
+// PageRange, matching the Must/Should shape Qdrant's Filter already uses
+// elsewhere in this package.
+type SearchFilter struct {
+	// DocIDs restricts results to points whose doc_id is in this set. A
+	// single ID is the common "search within this book" case; more than
+	// one supports searching across a user-chosen set of books.
+	DocIDs []string
+	// PageRange restricts results to points whose page_number falls in
+	// [PageRange[0], PageRange[1]], inclusive.
+	PageRange *[2]int32
+	// ChunkTypes restricts results to points whose type is in this set
+	// (e.g. "text", "table", "image_caption").
+	ChunkTypes []string
+	// ScoreThreshold drops results scoring below this, applied by Qdrant
+	// itself rather than filtered client-side after the fact.
+	ScoreThreshold *float32
+}
+
+// toFilter translates a SearchFilter into a pb.Filter, or nil if every
+// field is left unconstrained.
+func (f SearchFilter) toFilter() *pb.Filter {
+	var must []*pb.Condition
+
+	if len(f.DocIDs) > 0 {
+		must = append(must, pb.NewMatchKeywords("doc_id", f.DocIDs...))
+	}
+	if f.PageRange != nil {
+		must = append(must, pb.NewRange("page_number", &pb.Range{
+			Gte: pb.PtrOf(float64(f.PageRange[0])),
+			Lte: pb.PtrOf(float64(f.PageRange[1])),
+		}))
+	}
+	if len(f.ChunkTypes) > 0 {
+		must = append(must, pb.NewMatchKeywords("type", f.ChunkTypes...))
+	}
+
+	if len(must) == 0 {
+		return nil
+	}
+	return &pb.Filter{Must: must}
+}
+
+// SearchWithFilter performs a dense vector similarity search restricted to
+// filter, letting callers scope retrieval to one or more documents, a page
+// range, and/or a set of chunk types -- e.g. "search only within this
+// book" or "only table chunks across these three books".
+func (c *Client) SearchWithFilter(ctx context.Context, queryVector []float32, limit uint64, filter SearchFilter) ([]SearchResult, error) {
+	query := &pb.QueryPoints{
+		CollectionName: c.collection,
+		Query:          pb.NewQuery(queryVector...),
+		Using:          pb.PtrOf(denseVectorName),
+		Limit:          pb.PtrOf(limit),
+		Filter:         filter.toFilter(),
+		ScoreThreshold: filter.ScoreThreshold,
+		WithPayload:    pb.NewWithPayload(true),
+	}
+
+	results, err := c.client.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("qdrant filtered search failed: %w", err)
+	}
+
+	var out []SearchResult
+	for _, point := range results {
+		text := ""
+		docID := ""
+		pageNum := int32(0)
+
+		if val, ok := point.Payload["text"]; ok {
+			text = val.GetStringValue()
+		}
+		if val, ok := point.Payload["doc_id"]; ok {
+			docID = val.GetStringValue()
+		}
+		if val, ok := point.Payload["page_number"]; ok {
+			pageNum = int32(val.GetIntegerValue())
+		}
+
+		out = append(out, SearchResult{
+			ID:         point.Id.GetUuid(),
+			Text:       text,
+			DocID:      docID,
+			PageNumber: pageNum,
+			Score:      point.Score,
+		})
+	}
+
+	return out, nil
+}
+
+// ScrollDocument lists a single document's chunks a page at a time,
+// ordered however Qdrant's scroll cursor naturally returns them -- not
+// page_number order -- for UI features that browse a book's chunks without
+// needing a full similarity search. cursor is the opaque offset returned
+// by the previous call; pass "" for the first page. The returned cursor
+// is "" once there are no more chunks.
+func (c *Client) ScrollDocument(ctx context.Context, docID string, cursor string, limit uint32) ([]SearchResult, string, error) {
+	req := &pb.ScrollPoints{
+		CollectionName: c.collection,
+		Filter: &pb.Filter{
+			Must: []*pb.Condition{
+				pb.NewMatch("doc_id", docID),
+			},
+		},
+		Limit:       pb.PtrOf(limit),
+		WithPayload: pb.NewWithPayload(true),
+	}
+	if cursor != "" {
+		req.Offset = pb.NewID(cursor)
+	}
+
+	page, err := c.client.Scroll(ctx, req)
+	if err != nil {
+		return nil, "", fmt.Errorf("qdrant scroll failed for doc %s: %w", docID, err)
+	}
+
+	out := make([]SearchResult, 0, len(page))
+	for _, point := range page {
+		text := ""
+		pageNum := int32(0)
+
+		if val, ok := point.Payload["text"]; ok {
+			text = val.GetStringValue()
+		}
+		if val, ok := point.Payload["page_number"]; ok {
+			pageNum = int32(val.GetIntegerValue())
+		}
+
+		out = append(out, SearchResult{
+			ID:         point.Id.GetUuid(),
+			Text:       text,
+			DocID:      docID,
+			PageNumber: pageNum,
+		})
+	}
+
+	nextCursor := ""
+	if uint32(len(page)) == limit {
+		nextCursor = page[len(page)-1].Id.GetUuid()
+	}
+
+	return out, nextCursor, nil
+}
+
+// hybridPrefetchMultiplier widens the candidate set each prefetch branch
+// pulls before RRF fusion and ColBERT rerank narrow it back down to limit,
+// the same over-fetch-then-rerank shape fusion.Retriever's Skyline/RRF
+// stages already use ahead of MMR.
+const hybridPrefetchMultiplier = 4
+
+// HybridSearch runs a two-stage Qdrant query: a prefetch stage that fuses
+// dense cosine similarity and sparse (BM25/SPLADE) term-weight search via
+// Reciprocal Rank Fusion, followed by a final rerank stage that re-scores
+// the fused candidates using ColBERT late-interaction (MaxSim over the
+// "colbert" multi-vector). Returns up to `limit` fused, reranked results.
+func (c *Client) HybridSearch(ctx context.Context, dense []float32, sparse map[uint32]float32, colbert [][]float32, limit uint64) ([]SearchResult, error) {
+	prefetchLimit := limit * hybridPrefetchMultiplier
+
+	sparseIndices, sparseValues := sparseVectorToIndicesValues(sparse)
+
+	results, err := c.client.Query(ctx, &pb.QueryPoints{
+		CollectionName: c.collection,
+		Prefetch: []*pb.PrefetchQuery{
+			{
+				Prefetch: []*pb.PrefetchQuery{
+					{
+						Query: pb.NewQuery(dense...),
+						Using: pb.PtrOf(denseVectorName),
+						Limit: pb.PtrOf(prefetchLimit),
+					},
+					{
+						Query: pb.NewQuerySparse(sparseIndices, sparseValues),
+						Using: pb.PtrOf(sparseVectorName),
+						Limit: pb.PtrOf(prefetchLimit),
+					},
+				},
+				Query: pb.NewQueryFusion(pb.Fusion_RRF),
+				Limit: pb.PtrOf(prefetchLimit),
+			},
+		},
+		Query:       pb.NewQueryMulti(colbert),
+		Using:       pb.PtrOf(colbertVectorName),
+		Limit:       pb.PtrOf(limit),
+		WithPayload: pb.NewWithPayload(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("qdrant hybrid search failed: %w", err)
+	}
 
 	var out []SearchResult
 	for _, point := range results {
@@ -200,7 +816,7 @@ func (c *Client) Search(ctx context.Context, queryVector []float32, limit uint64
 		}
 
 		out = append(out, SearchResult{
-			ID:         fmt.Sprintf("%d", point.Id.GetNum()),
+			ID:         point.Id.GetUuid(),
 			Text:       text,
 			DocID:      docID,
 			PageNumber: pageNum,
@@ -238,15 +854,213 @@ func (c *Client) DocumentExists(ctx context.Context, docID string) (bool, error)
 	return len(result) > 0, nil
 }
 
+// Ping reports whether Qdrant is reachable and the configured collection
+// still exists, satisfying health.Prober. It reuses CollectionExists
+// rather than a dedicated health RPC since that's the cheapest call that
+// actually proves both the connection and the collection are good.
+func (c *Client) Ping(ctx context.Context) error {
+	exists, err := c.client.CollectionExists(ctx, c.collection)
+	if err != nil {
+		return fmt.Errorf("qdrant ping failed: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("qdrant collection %q does not exist", c.collection)
+	}
+	return nil
+}
+
+// Name identifies this client in a health.Registry.
+func (c *Client) Name() string {
+	return "qdrant"
+}
+
 // Close closes the underlying Qdrant gRPC connection.
 func (c *Client) Close() error {
 	return c.client.Close()
 }
 
-// deterministicID generates a deterministic uint64 from a string key using SHA256.
-func deterministicID(key string) uint64 {
-	h := sha256.Sum256([]byte(key))
-	return binary.BigEndian.Uint64(h[:8])
+// DeterministicID computes a stable UUID v5 (RFC 4122, SHA-1 based) point ID
+// for key, scoped to namespace. namespace is first hashed into its own
+// sub-namespace of c.idNamespace, so two callers that pick the same
+// namespace string (e.g. the same doc_id from two different BookSource
+// adapters) still can't collide with an unrelated client that configured a
+// different root namespace via WithIDNamespace -- and, within one client,
+// colliding raw keys across namespaces can't collide either. This replaces
+// the old deterministicID(key string) uint64, which truncated a SHA-256
+// digest to 64 bits and had no namespacing at all.
+func (c *Client) DeterministicID(namespace, key string) uuid.UUID {
+	sourceNamespace := uuid.NewSHA1(c.idNamespace, []byte(namespace))
+	return uuid.NewSHA1(sourceNamespace, []byte(key))
+}
+
+// MigrateIntegerIDsToUUID re-indexes a collection that still uses the old
+// deterministicID uint64 point IDs into the new DeterministicID UUID
+// scheme. It scrolls the collection in pages, recomputes each point's UUID
+// from its doc_id/chunk_id payload fields (written by InsertChunks since
+// the switch to UUID IDs), upserts it under the new ID, and deletes the
+// stale integer-keyed point. Run it once, offline, against a collection
+// before pointing a new deployment at it; it is not called from any
+// request path.
+func (c *Client) MigrateIntegerIDsToUUID(ctx context.Context) (int, error) {
+	migrated := 0
+	var offset *pb.PointId
+
+	for {
+		scrollReq := &pb.ScrollPoints{
+			CollectionName: c.collection,
+			Limit:          pb.PtrOf(uint32(256)),
+			WithPayload:    pb.NewWithPayload(true),
+			WithVectors:    pb.NewWithVectors(true),
+		}
+		if offset != nil {
+			scrollReq.Offset = offset
+		}
+
+		page, err := c.client.Scroll(ctx, scrollReq)
+		if err != nil {
+			return migrated, fmt.Errorf("qdrant scroll failed during migration: %w", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+
+		for _, point := range page {
+			offset = point.Id
+
+			// Already a UUID point -- nothing to migrate.
+			if point.Id.GetUuid() != "" {
+				continue
+			}
+
+			docID := ""
+			chunkID := ""
+			if val, ok := point.Payload["doc_id"]; ok {
+				docID = val.GetStringValue()
+			}
+			if val, ok := point.Payload["chunk_id"]; ok {
+				chunkID = val.GetStringValue()
+			}
+			if docID == "" || chunkID == "" {
+				log.Printf("[Qdrant] Skipping migration of point %d: missing doc_id/chunk_id payload", point.Id.GetNum())
+				continue
+			}
+
+			newID := c.DeterministicID(docID, chunkID)
+
+			// A collection provisioned before chunk10-1 stores a single
+			// unnamed dense vector per point; one reprovisioned since
+			// stores named vectors ("dense", and optionally "sparse"/
+			// "colbert"). Re-upsert whichever shape this point actually
+			// has instead of assuming the legacy single-vector schema.
+			var newVectors *pb.Vectors
+			if named := point.Vectors.GetVectors(); named != nil {
+				newVectors = pb.NewVectorsMap(vectorsFromOutputMap(named.GetVectors()))
+			} else {
+				newVectors = pb.NewVectors(point.Vectors.GetVector().GetData()...)
+			}
+
+			_, err = c.client.Upsert(ctx, &pb.UpsertPoints{
+				CollectionName: c.collection,
+				Points: []*pb.PointStruct{{
+					Id:      pb.NewID(newID.String()),
+					Vectors: newVectors,
+					Payload: point.Payload,
+				}},
+			})
+			if err != nil {
+				return migrated, fmt.Errorf("qdrant upsert failed for migrated point %d: %w", point.Id.GetNum(), err)
+			}
+
+			_, err = c.client.Delete(ctx, &pb.DeletePoints{
+				CollectionName: c.collection,
+				Points: &pb.PointsSelector{
+					PointsSelectorOneOf: &pb.PointsSelector_Points{
+						Points: &pb.PointsIdsList{Ids: []*pb.PointId{point.Id}},
+					},
+				},
+			})
+			if err != nil {
+				return migrated, fmt.Errorf("qdrant delete failed for old point %d: %w", point.Id.GetNum(), err)
+			}
+
+			migrated++
+		}
+	}
+
+	log.Printf("[Qdrant] Migrated %d points from integer IDs to UUIDs in collection %q", migrated, c.collection)
+	return migrated, nil
+}
+
+// toSparseVector converts a chunk's "sparse_vector" payload value
+// (map[uint32]float32, or the map[string]any/map[any]any shapes that come
+// back from decoded JSON) into a Qdrant sparse Vector.
+func toSparseVector(v any) (*pb.Vector, error) {
+	switch vt := v.(type) {
+	case map[uint32]float32:
+		return pb.NewVectorSparse(sparseVectorToIndicesValues(vt)), nil
+	case map[string]any:
+		indices := make([]uint32, 0, len(vt))
+		values := make([]float32, 0, len(vt))
+		for k, raw := range vt {
+			idx, err := parseUint32(k)
+			if err != nil {
+				return nil, fmt.Errorf("index %q: %w", k, err)
+			}
+			val, ok := raw.(float64)
+			if !ok {
+				return nil, fmt.Errorf("index %q: unsupported value type %T", k, raw)
+			}
+			indices = append(indices, idx)
+			values = append(values, float32(val))
+		}
+		return pb.NewVectorSparse(indices, values), nil
+	default:
+		return nil, fmt.Errorf("unsupported sparse vector type %T", v)
+	}
+}
+
+// sparseVectorToIndicesValues splits a sparse term-weight map into the
+// parallel indices/values slices Qdrant's sparse vector wire format expects.
+func sparseVectorToIndicesValues(m map[uint32]float32) ([]uint32, []float32) {
+	indices := make([]uint32, 0, len(m))
+	values := make([]float32, 0, len(m))
+	for idx, val := range m {
+		indices = append(indices, idx)
+		values = append(values, val)
+	}
+	return indices, values
+}
+
+// parseUint32 parses a decimal string index, as produced when a sparse
+// vector arrives via JSON (where map keys are always strings).
+func parseUint32(s string) (uint32, error) {
+	var idx uint32
+	if _, err := fmt.Sscanf(s, "%d", &idx); err != nil {
+		return 0, err
+	}
+	return idx, nil
+}
+
+// toColbertVectors converts a chunk's "colbert_vectors" payload value
+// ([][]float32, or the []any-of-[]any shape decoded JSON produces) into a
+// Qdrant multi-vector for the ColBERT late-interaction rerank stage.
+func toColbertVectors(v any) (*pb.Vector, error) {
+	switch vt := v.(type) {
+	case [][]float32:
+		return pb.NewVectorMulti(vt), nil
+	case []any:
+		out := make([][]float32, len(vt))
+		for i, elem := range vt {
+			tokenVec, err := toFloat32Slice(elem)
+			if err != nil {
+				return nil, fmt.Errorf("token %d: %w", i, err)
+			}
+			out[i] = tokenVec
+		}
+		return pb.NewVectorMulti(out), nil
+	default:
+		return nil, fmt.Errorf("unsupported colbert vectors type %T", v)
+	}
 }
 
 // toFloat32Slice converts various numeric slice types to []float32.
@@ -0,0 +1,310 @@
+package qdrant
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func newTestClient() *Client {
+	return &Client{idNamespace: defaultIDNamespace, cfg: DefaultCollectionConfig()}
+}
+
+func TestCollectionConfig_HNSWConfigDiff_NilWhenUnset(t *testing.T) {
+	cfg := DefaultCollectionConfig()
+	if diff := cfg.hnswConfigDiff(); diff != nil {
+		t.Errorf("expected nil HnswConfigDiff, got %+v", diff)
+	}
+}
+
+func TestCollectionConfig_HNSWConfigDiff_Set(t *testing.T) {
+	cfg := DefaultCollectionConfig()
+	cfg.HNSWConfig = &HNSWConfig{M: 32, EFConstruct: 200}
+
+	diff := cfg.hnswConfigDiff()
+	if diff == nil {
+		t.Fatal("expected non-nil HnswConfigDiff")
+	}
+	if diff.GetM() != 32 || diff.GetEfConstruct() != 200 {
+		t.Errorf("unexpected HnswConfigDiff: m=%d ef_construct=%d", diff.GetM(), diff.GetEfConstruct())
+	}
+}
+
+func TestCollectionConfig_QuantizationConfig_NilWhenUnset(t *testing.T) {
+	cfg := DefaultCollectionConfig()
+	if q := cfg.quantizationConfig(); q != nil {
+		t.Errorf("expected nil QuantizationConfig, got %+v", q)
+	}
+}
+
+func TestCollectionConfig_QuantizationConfig_ScalarTakesPrecedence(t *testing.T) {
+	cfg := DefaultCollectionConfig()
+	cfg.ScalarQuantization = &ScalarQuantizationConfig{AlwaysRAM: true}
+	cfg.ProductQuantization = &ProductQuantizationConfig{AlwaysRAM: true}
+
+	if cfg.quantizationConfig() == nil {
+		t.Fatal("expected non-nil QuantizationConfig")
+	}
+}
+
+func TestNonZeroUint32Ptr(t *testing.T) {
+	if p := nonZeroUint32Ptr(0); p != nil {
+		t.Errorf("expected nil for zero value, got %v", *p)
+	}
+	if p := nonZeroUint32Ptr(3); p == nil || *p != 3 {
+		t.Errorf("expected pointer to 3, got %v", p)
+	}
+}
+
+func TestDeterministicID_Deterministic(t *testing.T) {
+	c := newTestClient()
+
+	id1 := c.DeterministicID("doc-1", "chunk-0")
+	id2 := c.DeterministicID("doc-1", "chunk-0")
+	if id1 != id2 {
+		t.Errorf("expected deterministic: %s != %s", id1, id2)
+	}
+}
+
+func TestDeterministicID_DifferentKeysDiffer(t *testing.T) {
+	c := newTestClient()
+
+	id1 := c.DeterministicID("doc-1", "chunk-0")
+	id2 := c.DeterministicID("doc-1", "chunk-1")
+	if id1 == id2 {
+		t.Error("expected different IDs for different keys")
+	}
+}
+
+func TestDeterministicID_NamespacesKeysSeparately(t *testing.T) {
+	c := newTestClient()
+
+	// Same raw key under two different namespaces (documents) must not collide.
+	id1 := c.DeterministicID("doc-1", "chunk-0")
+	id2 := c.DeterministicID("doc-2", "chunk-0")
+	if id1 == id2 {
+		t.Error("expected different IDs for the same key in different namespaces")
+	}
+}
+
+func TestDeterministicID_RootNamespaceChangesOutput(t *testing.T) {
+	c1 := newTestClient()
+	c2 := &Client{idNamespace: uuid.New()}
+
+	if c1.DeterministicID("doc-1", "chunk-0") == c2.DeterministicID("doc-1", "chunk-0") {
+		t.Error("expected different root namespaces to produce different IDs")
+	}
+}
+
+func TestDeterministicID_IsUUIDv5(t *testing.T) {
+	c := newTestClient()
+
+	id := c.DeterministicID("doc-1", "chunk-0")
+	if id.Version() != 5 {
+		t.Errorf("expected UUID version 5, got %d", id.Version())
+	}
+}
+
+// TestDeterministicID_NoCollisionsAcrossMillions is a fuzz-style stress test:
+// it generates 10M synthetic (namespace, key) pairs and asserts that none of
+// their DeterministicID outputs collide. It replaces the old
+// TestDeterministicID, which only compared two hand-picked inputs and so
+// never would have caught the 64-bit truncation collisions the previous
+// uint64-based deterministicID was exposed to.
+func TestDeterministicID_NoCollisionsAcrossMillions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping 10M-key collision sweep in -short mode")
+	}
+
+	const (
+		docs        = 1_000
+		chunksPerDoc = 10_000
+	)
+
+	c := newTestClient()
+	seen := make(map[uuid.UUID]struct{}, docs*chunksPerDoc)
+
+	for d := 0; d < docs; d++ {
+		namespace := fmt.Sprintf("doc-%d", d)
+		for i := 0; i < chunksPerDoc; i++ {
+			key := fmt.Sprintf("%s-chunk-%d", namespace, i)
+			id := c.DeterministicID(namespace, key)
+			if _, dup := seen[id]; dup {
+				t.Fatalf("collision detected for namespace=%q key=%q -> %s", namespace, key, id)
+			}
+			seen[id] = struct{}{}
+		}
+	}
+}
+
+func TestToFloat32Slice_Float32(t *testing.T) {
+	input := []float32{1.0, 2.0, 3.0}
+	result, err := toFloat32Slice(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result) != 3 || result[0] != 1.0 || result[1] != 2.0 || result[2] != 3.0 {
+		t.Errorf("unexpected result: %v", result)
+	}
+}
+
+func TestToFloat32Slice_Float64(t *testing.T) {
+	input := []float64{1.1, 2.2, 3.3}
+	result, err := toFloat32Slice(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result) != 3 {
+		t.Errorf("expected 3 elements, got %d", len(result))
+	}
+	if result[0] != float32(1.1) {
+		t.Errorf("expected %v, got %v", float32(1.1), result[0])
+	}
+}
+
+func TestToFloat32Slice_AnyFloat64(t *testing.T) {
+	input := []any{float64(1.0), float64(2.0), float64(3.0)}
+	result, err := toFloat32Slice(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result) != 3 {
+		t.Errorf("expected 3 elements, got %d", len(result))
+	}
+}
+
+func TestToFloat32Slice_AnyFloat32(t *testing.T) {
+	input := []any{float32(1.0), float32(2.0)}
+	result, err := toFloat32Slice(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result) != 2 {
+		t.Errorf("expected 2 elements, got %d", len(result))
+	}
+}
+
+func TestToFloat32Slice_UnsupportedType(t *testing.T) {
+	_, err := toFloat32Slice("not a slice")
+	if err == nil {
+		t.Fatal("expected error for unsupported type")
+	}
+}
+
+func TestToFloat32Slice_AnyUnsupportedElement(t *testing.T) {
+	input := []any{float64(1.0), "not a number"}
+	_, err := toFloat32Slice(input)
+	if err == nil {
+		t.Fatal("expected error for unsupported element type")
+	}
+}
+
+func TestSearchFilter_ToFilter_Empty(t *testing.T) {
+	var f SearchFilter
+	if got := f.toFilter(); got != nil {
+		t.Errorf("expected nil filter for zero-value SearchFilter, got %+v", got)
+	}
+}
+
+func TestSearchFilter_ToFilter_DocIDs(t *testing.T) {
+	f := SearchFilter{DocIDs: []string{"doc-1", "doc-2"}}
+	filter := f.toFilter()
+	if filter == nil || len(filter.Must) != 1 {
+		t.Fatalf("expected exactly one Must condition, got %+v", filter)
+	}
+}
+
+func TestSearchFilter_ToFilter_CombinesConditions(t *testing.T) {
+	pages := [2]int32{10, 20}
+	f := SearchFilter{
+		DocIDs:     []string{"doc-1"},
+		PageRange:  &pages,
+		ChunkTypes: []string{"text", "table"},
+	}
+	filter := f.toFilter()
+	if filter == nil || len(filter.Must) != 3 {
+		t.Fatalf("expected 3 Must conditions, got %+v", filter)
+	}
+}
+
+func TestSparseVectorToIndicesValues(t *testing.T) {
+	m := map[uint32]float32{3: 0.5, 7: 0.25}
+	indices, values := sparseVectorToIndicesValues(m)
+	if len(indices) != 2 || len(values) != 2 {
+		t.Fatalf("expected 2 indices/values, got %d/%d", len(indices), len(values))
+	}
+	seen := make(map[uint32]float32, 2)
+	for i, idx := range indices {
+		seen[idx] = values[i]
+	}
+	if seen[3] != 0.5 || seen[7] != 0.25 {
+		t.Errorf("unexpected indices/values: %v", seen)
+	}
+}
+
+func TestToSparseVector_MapUint32(t *testing.T) {
+	_, err := toSparseVector(map[uint32]float32{1: 0.1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestToSparseVector_DecodedJSONMap(t *testing.T) {
+	_, err := toSparseVector(map[string]any{"1": float64(0.1), "42": float64(0.9)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestToSparseVector_UnsupportedType(t *testing.T) {
+	_, err := toSparseVector("not a sparse vector")
+	if err == nil {
+		t.Fatal("expected error for unsupported type")
+	}
+}
+
+func TestToColbertVectors_TypedSlice(t *testing.T) {
+	_, err := toColbertVectors([][]float32{{1, 2}, {3, 4}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestToColbertVectors_DecodedJSONSlice(t *testing.T) {
+	input := []any{
+		[]any{float64(1), float64(2)},
+		[]any{float64(3), float64(4)},
+	}
+	_, err := toColbertVectors(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestToColbertVectors_UnsupportedType(t *testing.T) {
+	_, err := toColbertVectors("not a multi-vector")
+	if err == nil {
+		t.Fatal("expected error for unsupported type")
+	}
+}
+
+func TestToFloat32Slice_Empty(t *testing.T) {
+	result, err := toFloat32Slice([]float32{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result) != 0 {
+		t.Errorf("expected empty slice, got %v", result)
+	}
+}
+
+func TestMigrationAliasArgs_SwapsOriginalNameOntoMigratedCollection(t *testing.T) {
+	alias, target := migrationAliasArgs("books", "books_chunk10_1_migration")
+	if alias != "books" {
+		t.Errorf("expected alias %q (the freed original name), got %q", "books", alias)
+	}
+	if target != "books_chunk10_1_migration" {
+		t.Errorf("expected target %q (the migrated collection), got %q", "books_chunk10_1_migration", target)
+	}
+}
@@ -0,0 +1,160 @@
+// Package resilience holds reusable fault-tolerance primitives (circuit
+// breakers, retries) shared by callers that talk to unreliable external
+// dependencies, starting with llm.Router's per-client fallback chains.
+//
+// This is deliberately separate from infrastructure/resilience's sliding-
+// window CircuitBreaker, which backs the legacy infrastructure/llm.Router
+// and retriever.FusionRetriever: that package is frozen at its own import
+// boundary (repository.LLMClient/TaskType) and isn't meant to pick up new
+// dependents, so a consecutive-failure breaker simple enough for a single
+// candidate's retry loop lives here instead.
+package resilience
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// State is one of CircuitBreaker's three states.
+type State int
+
+const (
+	StateClosed State = iota
+	StateOpen
+	StateHalfOpen
+)
+
+// ErrCircuitOpen is returned by Execute when the breaker is open (or
+// half-open and already has a probe in flight).
+var ErrCircuitOpen = errors.New("resilience: circuit breaker is open")
+
+// CircuitBreaker trips Open after maxFailures consecutive failures, stays
+// Open for timeout, then allows exactly one probe call through (HalfOpen) to
+// decide whether to close again or re-open. It's a consecutive-failure
+// streak rather than a sliding error-rate window -- the same tradeoff
+// health.CircuitBreaker makes -- since a single bad streak is all a
+// per-candidate LLM fallback needs to notice before routing elsewhere.
+type CircuitBreaker struct {
+	maxFailures int
+	timeout     time.Duration
+
+	mu            sync.Mutex
+	state         State
+	failures      int
+	openedAt      time.Time
+	halfOpenInUse bool
+}
+
+// NewCircuitBreaker creates a closed breaker that trips after maxFailures
+// consecutive failures and stays open for timeout before probing again.
+func NewCircuitBreaker(maxFailures int, timeout time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		maxFailures: maxFailures,
+		timeout:     timeout,
+		state:       StateClosed,
+	}
+}
+
+// Execute runs fn if the breaker allows it, returning ErrCircuitOpen without
+// calling fn otherwise. fn's outcome is recorded against the breaker's state.
+func (cb *CircuitBreaker) Execute(fn func() error) error {
+	if !cb.Allow() {
+		return ErrCircuitOpen
+	}
+
+	err := fn()
+	if err != nil {
+		cb.RecordFailure()
+		return err
+	}
+	cb.RecordSuccess()
+	return nil
+}
+
+// Allow reports whether a call should be attempted right now, transitioning
+// Open to HalfOpen and handing out one probe slot once timeout has elapsed.
+// Exposed separately from Execute for callers (like llm.LLMClientChain's
+// streaming bypass) that drive the underlying call themselves and report the
+// outcome back via RecordSuccess/RecordFailure.
+func (cb *CircuitBreaker) Allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case StateClosed:
+		return true
+	case StateHalfOpen:
+		if cb.halfOpenInUse {
+			return false
+		}
+		cb.halfOpenInUse = true
+		return true
+	default: // StateOpen
+		if time.Since(cb.openedAt) < cb.timeout {
+			return false
+		}
+		cb.state = StateHalfOpen
+		cb.halfOpenInUse = true
+		return true
+	}
+}
+
+// RecordSuccess closes the breaker and resets its failure streak.
+func (cb *CircuitBreaker) RecordSuccess() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.failures = 0
+	cb.state = StateClosed
+	cb.halfOpenInUse = false
+}
+
+// RecordFailure counts a failed call, tripping Open once failures reaches
+// maxFailures; a failed HalfOpen probe re-opens immediately regardless of
+// the threshold.
+func (cb *CircuitBreaker) RecordFailure() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == StateHalfOpen {
+		cb.tripLocked()
+		return
+	}
+
+	cb.failures++
+	if cb.failures >= cb.maxFailures {
+		cb.tripLocked()
+	}
+}
+
+func (cb *CircuitBreaker) tripLocked() {
+	cb.state = StateOpen
+	cb.openedAt = time.Now()
+	cb.halfOpenInUse = false
+}
+
+// CurrentState returns the breaker's current state, exactly as last left by
+// Allow/RecordSuccess/RecordFailure -- it's a read, not a probe, so an Open
+// breaker whose timeout has already elapsed is still reported as Open until
+// something actually calls Allow (e.g. via Execute) to perform that
+// transition. Callers that want "is this breaker currently blocking calls"
+// without that caveat should use IsOpen instead.
+func (cb *CircuitBreaker) CurrentState() State {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state
+}
+
+// IsOpen reports whether the breaker is currently blocking calls, treating
+// an Open breaker whose timeout has elapsed as no longer blocking even
+// though CurrentState would still report StateOpen. It never mutates state
+// itself (unlike Allow, which transitions Open to HalfOpen and hands out
+// the single probe slot) so repeated, read-only callers -- like a router
+// deciding whether to offer a candidate at all -- don't race each other for
+// that slot; the actual transition still only happens when something calls
+// Allow to make a real attempt.
+func (cb *CircuitBreaker) IsOpen() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state == StateOpen && time.Since(cb.openedAt) < cb.timeout
+}
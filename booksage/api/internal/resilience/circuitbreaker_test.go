@@ -63,6 +63,26 @@ func TestCircuitBreaker_HalfOpenAfterTimeout(t *testing.T) {
 	}
 }
 
+func TestCircuitBreaker_IsOpenClearsOnceTimeoutElapses(t *testing.T) {
+	cb := NewCircuitBreaker(1, 50*time.Millisecond)
+	testErr := errors.New("fail")
+
+	_ = cb.Execute(func() error { return testErr })
+	if !cb.IsOpen() {
+		t.Fatalf("expected IsOpen immediately after tripping")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if cb.IsOpen() {
+		t.Errorf("expected IsOpen to clear once the timeout elapses")
+	}
+	// IsOpen is read-only: checking it repeatedly shouldn't itself trigger
+	// the Open->HalfOpen transition or consume the single probe slot.
+	if cb.CurrentState() != StateOpen {
+		t.Errorf("expected CurrentState to still report Open until something actually calls Allow, got %d", cb.CurrentState())
+	}
+}
+
 func TestCircuitBreaker_HalfOpenFailure(t *testing.T) {
 	cb := NewCircuitBreaker(1, 50*time.Millisecond)
 	testErr := errors.New("fail")
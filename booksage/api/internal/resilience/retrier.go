@@ -0,0 +1,105 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// Retrier retries a function with exponential backoff and full jitter. It is
+// a sibling to CircuitBreaker: a flaky call is typically wrapped in a
+// Retrier first (so transient blips don't count against the breaker) and the
+// whole thing guarded by a CircuitBreaker (so a sustained outage still fails
+// fast instead of being hammered with retries on every request).
+type Retrier struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	CapDelay   time.Duration
+
+	// IsRetryable decides whether an error should be retried. Defaults to
+	// DefaultIsRetryable (retry everything except a cancelled/expired ctx)
+	// when nil.
+	IsRetryable func(error) bool
+}
+
+// NewRetrier builds a Retrier with the given bounds. A nil isRetryable falls
+// back to DefaultIsRetryable.
+func NewRetrier(maxRetries int, base, cap time.Duration, isRetryable func(error) bool) *Retrier {
+	if isRetryable == nil {
+		isRetryable = DefaultIsRetryable
+	}
+	return &Retrier{
+		MaxRetries:  maxRetries,
+		BaseDelay:   base,
+		CapDelay:    cap,
+		IsRetryable: isRetryable,
+	}
+}
+
+// Execute runs fn, retrying on retryable errors with exponential backoff and
+// full jitter: nextDelay = rand(0, min(CapDelay, BaseDelay*2^attempt)).
+// Retrying stops as soon as MaxRetries is exhausted, fn returns a
+// non-retryable error, ctx is cancelled, or fn returns ErrCircuitOpen (which
+// is never retried, since a tripped breaker won't recover within a single
+// call's retry loop).
+func (r *Retrier) Execute(ctx context.Context, fn func() error) error {
+	isRetryable := r.IsRetryable
+	if isRetryable == nil {
+		isRetryable = DefaultIsRetryable
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= r.MaxRetries; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		if errors.Is(err, ErrCircuitOpen) {
+			return err
+		}
+		if !isRetryable(err) || attempt == r.MaxRetries {
+			break
+		}
+
+		timer := time.NewTimer(r.nextDelay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return lastErr
+}
+
+// nextDelay computes rand(0, min(CapDelay, BaseDelay*2^attempt)).
+func (r *Retrier) nextDelay(attempt int) time.Duration {
+	maxDelay := r.CapDelay
+	if r.BaseDelay > 0 && attempt < 62 {
+		if scaled := r.BaseDelay << uint(attempt); scaled > 0 && scaled < maxDelay {
+			maxDelay = scaled
+		}
+	}
+	if maxDelay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(maxDelay)))
+}
+
+// DefaultIsRetryable retries every error except a cancelled or deadline-
+// exceeded context, which signal the caller gave up rather than a transient
+// backend failure. It can't tell a permanent failure (bad API key, malformed
+// request) from a transient one (a dropped connection) apart, because
+// callers like llm.LLMClient.Generate don't return a structured error type
+// to key off of -- pass a caller-specific IsRetryable once one of those
+// callers grows one.
+func DefaultIsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
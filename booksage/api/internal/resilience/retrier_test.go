@@ -0,0 +1,89 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetrier_SucceedsWithoutRetry(t *testing.T) {
+	r := NewRetrier(3, time.Millisecond, 10*time.Millisecond, nil)
+	calls := 0
+	err := r.Execute(context.Background(), func() error {
+		calls++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestRetrier_RetriesTransientErrorUntilSuccess(t *testing.T) {
+	r := NewRetrier(3, time.Millisecond, 10*time.Millisecond, nil)
+	testErr := errors.New("transient")
+	calls := 0
+	err := r.Execute(context.Background(), func() error {
+		calls++
+		if calls < 3 {
+			return testErr
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected eventual success, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetrier_StopsAfterMaxRetries(t *testing.T) {
+	r := NewRetrier(2, time.Millisecond, 10*time.Millisecond, nil)
+	testErr := errors.New("persistent")
+	calls := 0
+	err := r.Execute(context.Background(), func() error {
+		calls++
+		return testErr
+	})
+	if !errors.Is(err, testErr) {
+		t.Fatalf("expected persistent error, got %v", err)
+	}
+	if calls != 3 { // initial attempt + 2 retries
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetrier_DoesNotRetryCircuitOpen(t *testing.T) {
+	r := NewRetrier(3, time.Millisecond, 10*time.Millisecond, nil)
+	calls := 0
+	err := r.Execute(context.Background(), func() error {
+		calls++
+		return ErrCircuitOpen
+	})
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call (no retry on open breaker), got %d", calls)
+	}
+}
+
+func TestRetrier_DoesNotRetryNonRetryableError(t *testing.T) {
+	testErr := errors.New("permanent")
+	r := NewRetrier(3, time.Millisecond, 10*time.Millisecond, func(err error) bool { return false })
+	calls := 0
+	err := r.Execute(context.Background(), func() error {
+		calls++
+		return testErr
+	})
+	if !errors.Is(err, testErr) {
+		t.Fatalf("expected permanent error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call (no retry), got %d", calls)
+	}
+}
@@ -5,25 +5,81 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"log"
 	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/booksage/booksage-api/internal/agent"
 	"github.com/booksage/booksage-api/internal/database"
 	"github.com/booksage/booksage-api/internal/database/models"
 	"github.com/booksage/booksage-api/internal/embedding"
+	"github.com/booksage/booksage-api/internal/health"
+	"github.com/booksage/booksage-api/internal/infrastructure/observability"
 	"github.com/booksage/booksage-api/internal/ingest"
 	pb "github.com/booksage/booksage-api/internal/pb/booksage/v1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// tracer and meter are package-scoped so every Server shares one set of
+// instruments, matching ingest.SagaOrchestrator's convention.
+var tracer = otel.Tracer("booksage-api/server")
+
+var (
+	meter = otel.Meter("booksage-api/server")
+
+	// queryTTFBHistogram records time-to-first-byte for /api/v1/query: the
+	// gap between the request arriving and the first GeneratorEvent being
+	// flushed to the client, the latency figure that actually matters for
+	// a streaming endpoint.
+	queryTTFBHistogram, _ = meter.Float64Histogram(
+		"booksage.query.ttfb",
+		metric.WithDescription("Time to first streamed event for /api/v1/query"),
+		metric.WithUnit("s"),
+	)
+	// queryTokensHistogram records how many answer_token events a query
+	// produced, a proxy for output length/cost since LLMClient doesn't
+	// expose a real token count.
+	queryTokensHistogram, _ = meter.Int64Histogram(
+		"booksage.query.tokens",
+		metric.WithDescription("Number of answer_token events streamed per /api/v1/query call"),
+	)
+)
+
+// sseHeartbeatInterval is how often handleQuery sends a ": ping\n\n"
+// comment frame to keep intermediaries (proxies, load balancers) from
+// closing an SSE connection they think has gone idle.
+const sseHeartbeatInterval = 15 * time.Second
+
+// readyzTimeout bounds how long handleReadyz's Registry.Check call waits
+// on any single dependency's Ping before counting it as down, so a wedged
+// dependency doesn't make /readyz itself hang.
+const readyzTimeout = 3 * time.Second
+
 // Server holds the dependencies for the HTTP API server
 type Server struct {
 	generator    *agent.Generator
 	embedBatcher *embedding.Batcher
 	parserClient pb.DocumentParserServiceClient
 	ingestSaga   *ingest.Orchestrator // Handled locally for now, typically injected via DI module
+	uploadRepo   database.UploadRepository
+	uploadDir    string
+	telemetry    *observability.Telemetry
+	critiqueRepo database.CritiqueRepository
+	health       *health.Registry
+
+	sseBuffersMu sync.Mutex
+	sseBuffers   map[string]*sseRingBuffer
 }
 
 // NewServer initializes a new API server with the required dependencies
@@ -33,9 +89,47 @@ func NewServer(gen *agent.Generator, embed *embedding.Batcher, parser pb.Documen
 		embedBatcher: embed,
 		parserClient: parser,
 		ingestSaga:   saga,
+		uploadDir:    os.TempDir(),
 	}
 }
 
+// WithUploadRepository attaches the resumable-upload session store and the
+// directory its temp files live in. It's optional: a Server built without
+// one still serves /api/v1/ingest, it just can't accept chunked uploads
+// under /api/v1/ingest/uploads.
+func (s *Server) WithUploadRepository(repo database.UploadRepository, uploadDir string) *Server {
+	s.uploadRepo = repo
+	s.uploadDir = uploadDir
+	return s
+}
+
+// WithTelemetry attaches the process's OpenTelemetry bundle so
+// RegisterRoutes can mount its Prometheus scrape handler at /metrics. A
+// Server built without one still traces every request against the global
+// TracerProvider/MeterProvider (observability.NewTelemetry installs those
+// as process-wide defaults); it just has nothing to serve /metrics with.
+func (s *Server) WithTelemetry(t *observability.Telemetry) *Server {
+	s.telemetry = t
+	return s
+}
+
+// WithCritiqueRepository attaches the audit log GET /api/v1/critiques
+// streams from. A Server built without one serves a 503 for that route
+// rather than a misleading empty stream.
+func (s *Server) WithCritiqueRepository(repo database.CritiqueRepository) *Server {
+	s.critiqueRepo = repo
+	return s
+}
+
+// WithHealthRegistry attaches the health.Registry RegisterRoutes mounts at
+// /readyz. A Server built without one still serves /healthz (it's a plain
+// liveness check, not a dependency probe), but /readyz reports every
+// component unknown rather than actually pinging anything.
+func (s *Server) WithHealthRegistry(r *health.Registry) *Server {
+	s.health = r
+	return s
+}
+
 // RegisterRoutes registers all API endpoints with a new ServeMux
 func (s *Server) RegisterRoutes() *http.ServeMux {
 	mux := http.NewServeMux()
@@ -45,8 +139,20 @@ func (s *Server) RegisterRoutes() *http.ServeMux {
 	mux.HandleFunc("POST /api/v1/query", s.handleQuery)
 	mux.HandleFunc("POST /api/v1/ingest", s.handleIngest)
 	mux.HandleFunc("GET /api/v1/ingest/status", s.handleIngestStatusByHash)
+	mux.HandleFunc("POST /api/v1/ingest/uploads", s.handleCreateUpload)
+	mux.HandleFunc("HEAD /api/v1/ingest/uploads/{upload_id}", s.handleUploadProgress)
+	mux.HandleFunc("PATCH /api/v1/ingest/uploads/{upload_id}", s.handleUploadChunk)
+	mux.HandleFunc("PUT /api/v1/ingest/uploads/{upload_id}", s.handleCompleteUpload)
 	mux.HandleFunc("GET /api/v1/documents/{document_id}/status", s.handleDocumentStatus)
-	mux.HandleFunc("HEAD /api/v1/documents/{document_id}", s.handleDocumentExist)
+	mux.HandleFunc("HEAD /api/v1/documents/{digest}", s.handleDocumentExist)
+	mux.HandleFunc("GET /api/v1/critiques", s.handleListCritiques)
+
+	mux.HandleFunc("GET /healthz", s.handleHealthz)
+	mux.HandleFunc("GET /readyz", s.handleReadyz)
+
+	if s.telemetry != nil {
+		mux.Handle("GET /metrics", s.telemetry.MetricsHandler())
+	}
 
 	return mux
 }
@@ -55,11 +161,31 @@ type QueryRequest struct {
 	Query     string         `json:"query"`
 	SessionID string         `json:"session_id,omitempty"`
 	Filters   map[string]any `json:"filters,omitempty"`
+
+	// Reflect opts into agent.Generator.GenerateAnswerWithReflection's
+	// iterative Self-RAG loop (query rewriting, answer revision, widened
+	// retrieval on a NoSupport verdict) instead of GenerateAnswer's single
+	// critique-and-regenerate-once pass. It trades a single extra
+	// request/response round trip of latency for a better-grounded answer.
+	Reflect bool `json:"reflect,omitempty"`
 }
 
 // Removed static QueryResponse as we use SSE now
 
 func (s *Server) handleQuery(w http.ResponseWriter, r *http.Request) {
+	requestStart := time.Now()
+
+	// Extract whatever traceparent/baggage the caller sent so this
+	// request's span (and everything it causes -- the critique loop, the
+	// saga DB writes) nests under the caller's own trace instead of
+	// starting a disconnected one.
+	propagated := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	ctx, querySpan := tracer.Start(propagated, "http.query", trace.WithAttributes(
+		attribute.String("http.method", r.Method),
+		attribute.String("http.route", "/api/v1/query"),
+	))
+	defer querySpan.End()
+
 	var req QueryRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid request payload", http.StatusBadRequest)
@@ -82,22 +208,80 @@ func (s *Server) handleQuery(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
 
+	_, _ = fmt.Fprintf(w, "retry: %d\n\n", sseRetryMillis)
+	flusher.Flush()
+
+	// A reconnecting client sends back the last event ID it saw; replay
+	// whatever it missed from this session's ring buffer before resuming
+	// the live stream.
+	if req.SessionID != "" {
+		if lastSeq, err := strconv.ParseInt(r.Header.Get("Last-Event-ID"), 10, 64); err == nil {
+			for _, ev := range s.sseBufferFor(req.SessionID).Since(lastSeq) {
+				writeSSEFrame(w, ev)
+			}
+			flusher.Flush()
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	eventStream := make(chan agent.GeneratorEvent)
+	if req.Reflect {
+		go s.generator.GenerateAnswerWithReflection(ctx, req.Query, eventStream)
+	} else {
+		go s.generator.GenerateAnswer(ctx, req.Query, eventStream)
+	}
 
-	// Start generation in a goroutine
-	go s.generator.GenerateAnswer(r.Context(), req.Query, eventStream)
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
 
-	// Consume and stream events
-	for event := range eventStream {
-		data, err := json.Marshal(event)
-		if err != nil {
-			log.Printf("[Server] Failed to marshal event: %v", err)
-			continue
+	var ttfbRecorded bool
+	var answerTokens int64
+	defer func() { queryTokensHistogram.Record(ctx, answerTokens) }()
+
+	for {
+		select {
+		case event, ok := <-eventStream:
+			if !ok {
+				return
+			}
+			if !ttfbRecorded {
+				queryTTFBHistogram.Record(ctx, time.Since(requestStart).Seconds())
+				ttfbRecorded = true
+			}
+			if event.Type == "answer_token" {
+				answerTokens++
+			}
+			if req.SessionID != "" {
+				s.sseBufferFor(req.SessionID).Append(event)
+			}
+			writeSSEFrame(w, event)
+			flusher.Flush()
+
+		case <-heartbeat.C:
+			_, _ = fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+
+		case <-ctx.Done():
+			// Client disconnected or the request was otherwise cancelled;
+			// cancel() (deferred above) already signals the generator
+			// goroutine to stop via its own ctx.Err() checks.
+			return
 		}
+	}
+}
 
-		_, _ = fmt.Fprintf(w, "data: %s\n\n", string(data))
-		flusher.Flush()
+// writeSSEFrame writes event as a proper SSE frame: its Type as the
+// "event:" field, its Seq as the "id:" field (so Last-Event-ID resume
+// works), and its JSON encoding as "data:".
+func writeSSEFrame(w http.ResponseWriter, event agent.GeneratorEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("[Server] Failed to marshal event: %v", err)
+		return
 	}
+	_, _ = fmt.Fprintf(w, "event: %s\nid: %d\ndata: %s\n\n", event.Type, event.Seq, data)
 }
 
 func (s *Server) handleIngest(w http.ResponseWriter, r *http.Request) {
@@ -119,33 +303,44 @@ func (s *Server) handleIngest(w http.ResponseWriter, r *http.Request) {
 	metadataStr := r.FormValue("metadata")
 	log.Printf("[Server] Received ingest request for %s (size: %d, metadata: %s)", header.Filename, header.Size, metadataStr)
 
-	// Calculate SHA-256 hash for deduplication
-	hash := sha256.New()
-	if _, err := io.Copy(hash, file); err != nil {
-		http.Error(w, "Failed to calculate hash", http.StatusInternalServerError)
-		return
-	}
-	fileHash := hash.Sum(nil)
-	_, _ = file.Seek(0, io.SeekStart) // Reset file pointer
+	// The SHA-256 is computed once, as the file streams to the parser
+	// below, instead of hashing it here and then re-reading it from the
+	// start: knownHash == nil tells ingestFile to tee the stream into its
+	// own hasher rather than trust a pre-computed digest.
+	s.ingestFile(w, r, file, header.Filename, header.Header.Get("Content-Type"), header.Size, nil)
+}
 
-	// Initialize document model
+// ingestFile runs the shared "document is fully on disk, go parse and index
+// it" pipeline: start or resume the saga, stream the bytes to the parser
+// worker, then embed and run the saga asynchronously. handleIngest and
+// handleCompleteUpload both read the full file a different way (multipart
+// part vs. committed upload temp file) but converge here.
+//
+// knownHash carries the content hash when the caller already verified it
+// (handleCompleteUpload, against the client's declared digest). When it's
+// nil, the hash isn't known yet: the upload is streamed to the parser
+// through a TeeReader that hashes it in the same pass, and the resulting
+// digest is attached to the saga's document afterwards via AttachHash
+// rather than upfront, so a multipart upload no longer has to be read
+// twice just to compute a hash it could get for free while streaming.
+func (s *Server) ingestFile(w http.ResponseWriter, r *http.Request, file io.Reader, filename, mimeType string, size int64, knownHash []byte) {
 	docModel := &models.Document{
-		FileHash: fileHash,
-		Title:    header.Filename,
-		FilePath: header.Filename, // In a real app, this would be the actual storage path
-		FileSize: header.Size,
-		MimeType: header.Header.Get("Content-Type"),
+		FileHash: knownHash,
+		Title:    filename,
+		FilePath: filename, // In a real app, this would be the actual storage path
+		FileSize: size,
+		MimeType: mimeType,
 	}
 
 	// Prepare or resume ingestion saga
 	saga, err := s.ingestSaga.StartOrResumeIngestion(r.Context(), docModel)
 	if err != nil {
-		log.Printf("[Server] Ingestion check failed for %x: %v", fileHash, err)
+		log.Printf("[Server] Ingestion check failed for %x: %v", knownHash, err)
 		// Check if it's "already ingested" error
-		if err.Error() == fmt.Sprintf("document already ingested: %x", fileHash) {
+		if err.Error() == fmt.Sprintf("document already ingested: %x", knownHash) {
 			w.WriteHeader(http.StatusConflict)
 			_ = json.NewEncoder(w).Encode(map[string]string{
-				"hash":   fmt.Sprintf("%x", fileHash),
+				"hash":   fmt.Sprintf("%x", knownHash),
 				"status": "completed",
 			})
 			return
@@ -162,13 +357,27 @@ func (s *Server) handleIngest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 1. Send metadata
+	// 1. Send metadata. The parser needs a document ID before the first
+	// chunk, but when the hash isn't known yet neither is the final
+	// document the saga will settle on, so it gets a provisional,
+	// client-generated ID here; the worker just needs something stable to
+	// tag this stream's chunks with.
+	provisionalID := fmt.Sprintf("%d", saga.DocumentID)
+	if knownHash == nil {
+		id, err := generateProvisionalDocumentID()
+		if err != nil {
+			log.Printf("[Server] Failed to generate provisional document ID: %v", err)
+			http.Error(w, "Failed to initialize ingestion", http.StatusInternalServerError)
+			return
+		}
+		provisionalID = id
+	}
 	if err := stream.Send(&pb.ParseRequest{
 		Payload: &pb.ParseRequest_Metadata{
 			Metadata: &pb.DocumentMetadata{
-				Filename:   header.Filename,
-				FileType:   header.Header.Get("Content-Type"),
-				DocumentId: fmt.Sprintf("%d", saga.DocumentID),
+				Filename:   filename,
+				FileType:   mimeType,
+				DocumentId: provisionalID,
 			},
 		},
 	}); err != nil {
@@ -177,10 +386,18 @@ func (s *Server) handleIngest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 2. Stream chunks (1MB chunks)
+	// 2. Stream chunks (1MB chunks), hashing as we go if the hash isn't
+	// already known.
+	var hasher hash.Hash
+	reader := file
+	if knownHash == nil {
+		hasher = sha256.New()
+		reader = io.TeeReader(file, hasher)
+	}
+
 	buffer := make([]byte, 1024*1024)
 	for {
-		n, err := file.Read(buffer)
+		n, err := reader.Read(buffer)
 		if n > 0 {
 			if sendErr := stream.Send(&pb.ParseRequest{
 				Payload: &pb.ParseRequest_ChunkData{
@@ -202,7 +419,39 @@ func (s *Server) handleIngest(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// 3. Receive response from worker
+	// 3. Send the trailing digest frame, then attach the now-known hash
+	// to the saga's document, aborting if a duplicate shows up mid-stream.
+	digest := knownHash
+	if hasher != nil {
+		digest = hasher.Sum(nil)
+	}
+	if err := stream.Send(&pb.ParseRequest{
+		Payload: &pb.ParseRequest_Digest{
+			Digest: digest,
+		},
+	}); err != nil {
+		log.Printf("[Server] Failed to send digest: %v", err)
+		http.Error(w, "Internal error sending data", http.StatusInternalServerError)
+		return
+	}
+
+	if hasher != nil {
+		if _, err := s.ingestSaga.AttachHash(r.Context(), saga.ID, digest); err != nil {
+			if errors.Is(err, ingest.ErrDuplicateContent) {
+				w.WriteHeader(http.StatusConflict)
+				_ = json.NewEncoder(w).Encode(map[string]string{
+					"hash":   fmt.Sprintf("%x", digest),
+					"status": "completed",
+				})
+				return
+			}
+			log.Printf("[Server] Failed to attach hash to saga %d: %v", saga.ID, err)
+			http.Error(w, "Failed to finalize ingestion", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// 4. Receive response from worker
 	resp, err := stream.CloseAndRecv()
 	if err != nil {
 		log.Printf("[Server] Worker returned error: %v", err)
@@ -212,7 +461,7 @@ func (s *Server) handleIngest(w http.ResponseWriter, r *http.Request) {
 
 	log.Printf("[Server] Successfully parsed document %s. Received %d elements.", resp.DocumentId, len(resp.Documents))
 
-	// 4. Generate embeddings and run ingestion saga asynchronously
+	// 5. Generate embeddings and run ingestion saga asynchronously
 	go func(parsedResp *pb.ParseResponse) {
 		ctx := context.Background() // Use an independent background context for the async job
 
@@ -245,15 +494,21 @@ func (s *Server) handleIngest(w http.ResponseWriter, r *http.Request) {
 			chunks = append(chunks, chunk)
 		}
 
-		// Prepare Neo4j Nodes (with enriched metadata)
+		// Prepare Neo4j Nodes (with enriched metadata). Each node's embedding
+		// comes from the corresponding embResults entry so chunk_vec has
+		// something to query against.
 		var graphNodes []any
 		for i, doc := range parsedResp.Documents {
-			graphNodes = append(graphNodes, map[string]any{
+			node := map[string]any{
 				"id":          fmt.Sprintf("%s-node-%d", parsedResp.DocumentId, i),
 				"text":        doc.Content,
 				"type":        doc.Type,
 				"page_number": int(doc.PageNumber),
-			})
+			}
+			if i < len(embResults) {
+				node["embedding"] = embResults[i].GetDense().GetValues()
+			}
+			graphNodes = append(graphNodes, node)
 		}
 
 		// Run the Saga Orchestrator
@@ -344,17 +599,121 @@ func (s *Server) handleIngestStatusByHash(w http.ResponseWriter, r *http.Request
 	})
 }
 
-// handleDocumentExist is used for the HEAD request to check if a document is already indexed.
+// handleDocumentExist answers HEAD /api/v1/documents/{digest}, mirroring a
+// registry's HEAD /v2/<name>/blobs/<digest>: it lets an ingest client check
+// whether a document is already indexed before uploading it, without
+// transferring any bytes.
 func (s *Server) handleDocumentExist(w http.ResponseWriter, r *http.Request) {
-	docID := r.PathValue("document_id")
-	if docID == "" {
-		w.WriteHeader(http.StatusBadRequest)
+	digest, err := parseDigestParam(r.PathValue("digest"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	doc, err := s.ingestSaga.GetDocumentByHash(r.Context(), digest)
+	if err != nil {
+		if err == database.ErrNotFound {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		log.Printf("[Server] Failed checking document existence for %x: %v", digest, err)
+		http.Error(w, "Failed to check document existence", http.StatusInternalServerError)
 		return
 	}
 
-	// This should be updated to use hash in a real scenario, but keeping path param for now
-	// For now, let's assume docID here is a hex hash for demonstration if possible,
-	// or we just return 404 until we have a better mapping.
-	w.WriteHeader(http.StatusNotImplemented)
-	_, _ = w.Write([]byte("HEAD by ID not implemented, use status check by hash"))
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", doc.FileSize))
+	w.Header().Set("ETag", fmt.Sprintf(`"sha256:%s"`, hex.EncodeToString(digest)))
+	w.Header().Set("X-Booksage-Document-Id", fmt.Sprintf("%d", doc.ID))
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleListCritiques answers GET /api/v1/critiques?since=<RFC3339>, streaming
+// every CritiqueEvent recorded at or after since as newline-delimited JSON so
+// a caller can build offline evaluation dashboards, diff verdicts across
+// model versions, or feed a booksage critique-replay run without waiting for
+// the whole result set to buffer.
+func (s *Server) handleListCritiques(w http.ResponseWriter, r *http.Request) {
+	if s.critiqueRepo == nil {
+		http.Error(w, "Critique audit log is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	sinceStr := r.URL.Query().Get("since")
+	if sinceStr == "" {
+		http.Error(w, "Query parameter 'since' (RFC3339 timestamp) is required", http.StatusBadRequest)
+		return
+	}
+	since, err := time.Parse(time.RFC3339, sinceStr)
+	if err != nil {
+		http.Error(w, "Invalid 'since': must be an RFC3339 timestamp", http.StatusBadRequest)
+		return
+	}
+
+	events, err := s.critiqueRepo.ListCritiqueEventsSince(r.Context(), since)
+	if err != nil {
+		log.Printf("[Server] Failed to list critique events since %s: %v", sinceStr, err)
+		http.Error(w, "Failed to list critique events", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			log.Printf("[Server] Failed to encode critique event %d: %v", event.ID, err)
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+// handleHealthz is a liveness check: it reports the process is up and
+// serving, without touching any dependency. A load balancer or orchestrator
+// should use this to decide whether to restart the process, not whether to
+// route traffic to it -- that's what /readyz is for.
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok"))
+}
+
+// handleReadyz is a readiness check: it pings every dependency registered
+// via WithHealthRegistry (Qdrant, Neo4j, the LLM backends, the saga store)
+// with a short deadline and reports each one's status, latency, rolling
+// error rate, and circuit breaker state as JSON. It returns 503 if any
+// component is down, since a caller load-balancing on this endpoint should
+// stop routing traffic here until every dependency recovers.
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if s.health == nil {
+		http.Error(w, "Health registry is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	statuses := s.health.Check(r.Context(), readyzTimeout)
+
+	// A "down" component fails readiness. A standalone breaker with no
+	// matching Prober (e.g. FusionRetriever's tree-engine breaker, which
+	// guards calls through neo4j.Client rather than pinging something of
+	// its own) reports "unknown" rather than "up" or "down" -- that's not
+	// itself a readiness failure, just a component with no direct ping.
+	allUp := true
+	for _, status := range statuses {
+		if status.Status == "down" {
+			allUp = false
+			break
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if allUp {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(statuses); err != nil {
+		log.Printf("[Server] Failed to encode readyz response: %v", err)
+	}
 }
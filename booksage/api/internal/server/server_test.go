@@ -2,14 +2,100 @@ package server
 
 import (
 	"bytes"
+	"context"
+	"encoding/hex"
 	"encoding/json"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"github.com/booksage/booksage-api/internal/agent"
+	"github.com/booksage/booksage-api/internal/database"
+	"github.com/booksage/booksage-api/internal/database/models"
+	"github.com/booksage/booksage-api/internal/ingest"
+	"github.com/booksage/booksage-api/internal/llm"
+	pb "github.com/booksage/booksage-api/internal/pb/booksage/v1"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
+// fakeParseStream is a no-op grpc.ClientStreamingClient[ParseRequest,
+// ParseResponse] standing in for the real parser worker: every chunk/digest
+// Send succeeds and CloseAndRecv hands back a response with no parsed
+// elements, which keeps ingestFile's downstream embedding step a no-op.
+type fakeParseStream struct {
+	ctx context.Context
+}
+
+func (f *fakeParseStream) Send(*pb.ParseRequest) error { return nil }
+func (f *fakeParseStream) CloseAndRecv() (*pb.ParseResponse, error) {
+	return &pb.ParseResponse{DocumentId: "doc-1"}, nil
+}
+func (f *fakeParseStream) Header() (metadata.MD, error) { return nil, nil }
+func (f *fakeParseStream) Trailer() metadata.MD         { return nil }
+func (f *fakeParseStream) CloseSend() error             { return nil }
+func (f *fakeParseStream) Context() context.Context     { return f.ctx }
+func (f *fakeParseStream) SendMsg(m any) error           { return nil }
+func (f *fakeParseStream) RecvMsg(m any) error           { return nil }
+
+// fakeParserClient is a pb.DocumentParserServiceClient that never touches the
+// network, for exercising handleIngest without a running parser worker.
+type fakeParserClient struct{}
+
+func (f *fakeParserClient) Parse(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[pb.ParseRequest, pb.ParseResponse], error) {
+	return &fakeParseStream{ctx: ctx}, nil
+}
+
+// conflictDocRepository is a database.DocumentRepository whose
+// GetDocumentByHash always reports a different, already-ingested document,
+// for exercising the mid-stream duplicate-content path in ingestFile.
+type conflictDocRepository struct{}
+
+func (m *conflictDocRepository) CreateDocument(ctx context.Context, doc *models.Document) (int64, error) {
+	return 1, nil
+}
+func (m *conflictDocRepository) GetDocumentByID(ctx context.Context, id int64) (*models.Document, error) {
+	return &models.Document{ID: id}, nil
+}
+func (m *conflictDocRepository) GetDocumentByHash(ctx context.Context, hash []byte) (*models.Document, error) {
+	return &models.Document{ID: 100, FileHash: hash}, nil
+}
+func (m *conflictDocRepository) DeleteDocument(ctx context.Context, id int64) error { return nil }
+func (m *conflictDocRepository) UpdateDocumentHash(ctx context.Context, id int64, hash []byte) error {
+	return nil
+}
+func (m *conflictDocRepository) ListAllDocuments(ctx context.Context) ([]*models.Document, error) {
+	return nil, nil
+}
+
+// newTestIngestServer wires a real ingest.Orchestrator (against docRepo and
+// the shared Mock Qdrant/Neo4j/Saga repositories) plus fakeParserClient, so
+// handleIngest can run its full saga-start/stream/attach-hash path in tests.
+func newTestIngestServer(docRepo database.DocumentRepository) *Server {
+	orchestrator := ingest.NewOrchestrator(
+		ingest.NewMockQdrantClient(),
+		ingest.NewMockNeo4jClient(),
+		docRepo,
+		&ingest.MockSagaRepository{},
+		hclog.NewNullLogger(),
+	)
+	return NewServer(nil, nil, &fakeParserClient{}, orchestrator)
+}
+
+type sseMockLLMClient struct {
+	name string
+	resp string
+}
+
+func (m *sseMockLLMClient) Generate(ctx context.Context, prompt string) (string, error) {
+	return m.resp, nil
+}
+func (m *sseMockLLMClient) Name() string { return m.name }
+
 func TestHandleQuery_InvalidPayload(t *testing.T) {
 	s := NewServer(nil, nil, nil, nil)
 	ts := httptest.NewServer(s.RegisterRoutes())
@@ -66,8 +152,81 @@ func TestHandleQuery_NonFlusher(t *testing.T) {
 	}
 }
 
+func newTestQueryServer(resp string) *Server {
+	local := &sseMockLLMClient{name: "local", resp: "keyword"}
+	gemini := &sseMockLLMClient{name: "gemini", resp: resp}
+	router := llm.NewRouter(local, gemini, hclog.NewNullLogger())
+	gen := agent.NewGenerator(router, nil, hclog.NewNullLogger())
+	return NewServer(gen, nil, nil, nil)
+}
+
+func TestHandleQuery_SSEFraming(t *testing.T) {
+	s := newTestQueryServer("Final reasoned answer")
+	ts := httptest.NewServer(s.RegisterRoutes())
+	defer ts.Close()
+
+	body, _ := json.Marshal(QueryRequest{Query: "test query"})
+	resp, err := http.Post(ts.URL+"/api/v1/query", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("req failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	raw, _ := io.ReadAll(resp.Body)
+	out := string(raw)
+
+	if !strings.HasPrefix(out, "retry: 3000\n\n") {
+		t.Errorf("expected output to start with retry hint, got %q", out)
+	}
+	if !strings.Contains(out, "event: answer\n") {
+		t.Errorf("expected an \"event: answer\" frame, got %q", out)
+	}
+	if !strings.Contains(out, "data: {\"seq\"") {
+		t.Errorf("expected data frames carrying the seq field, got %q", out)
+	}
+	if !strings.Contains(out, "Final reasoned answer") {
+		t.Errorf("expected the final answer content in the stream, got %q", out)
+	}
+}
+
+func TestHandleQuery_LastEventIDReplay(t *testing.T) {
+	s := newTestQueryServer("Final reasoned answer")
+
+	buf := s.sseBufferFor("session-1")
+	buf.Append(agent.GeneratorEvent{Seq: 1, Type: "reasoning", Content: "first"})
+	buf.Append(agent.GeneratorEvent{Seq: 2, Type: "reasoning", Content: "second"})
+	buf.Append(agent.GeneratorEvent{Seq: 3, Type: "answer", Content: "third"})
+
+	ts := httptest.NewServer(s.RegisterRoutes())
+	defer ts.Close()
+
+	body, _ := json.Marshal(QueryRequest{Query: "test query", SessionID: "session-1"})
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/api/v1/query", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Last-Event-ID", "1")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("req failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	raw, _ := io.ReadAll(resp.Body)
+	out := string(raw)
+
+	if !strings.Contains(out, "id: 2\n") || !strings.Contains(out, "second") {
+		t.Errorf("expected replay of event 2, got %q", out)
+	}
+	if !strings.Contains(out, "id: 3\n") || !strings.Contains(out, "third") {
+		t.Errorf("expected replay of event 3, got %q", out)
+	}
+	if strings.Contains(out, "first") {
+		t.Errorf("did not expect replay of event 1 (already seen), got %q", out)
+	}
+}
+
 func TestHandleIngest(t *testing.T) {
-	s := NewServer(nil, nil, nil, nil)
+	s := newTestIngestServer(&ingest.MockDocumentRepository{})
 	ts := httptest.NewServer(s.RegisterRoutes())
 	defer ts.Close()
 
@@ -92,7 +251,7 @@ func TestHandleIngest(t *testing.T) {
 
 	var b2 bytes.Buffer
 	w2 := multipart.NewWriter(&b2)
-	fw, err := w2.CreateFormFile("file", "new-book.txt") // Use "new-" prefix to satisfy mock existance check
+	fw, err := w2.CreateFormFile("file", "new-book.txt")
 	if err != nil {
 		t.Fatalf("failed to create file field: %v", err)
 	}
@@ -112,13 +271,15 @@ func TestHandleIngest(t *testing.T) {
 }
 
 func TestHandleIngest_Conflict(t *testing.T) {
-	s := NewServer(nil, nil, nil, nil)
+	// conflictDocRepository reports a duplicate for any hash, so AttachHash
+	// aborts the saga with ErrDuplicateContent once streaming finishes.
+	s := newTestIngestServer(&conflictDocRepository{})
 	ts := httptest.NewServer(s.RegisterRoutes())
 	defer ts.Close()
 
 	var b bytes.Buffer
 	w := multipart.NewWriter(&b)
-	fw, err := w.CreateFormFile("file", "registered.txt") // Without "new-" prefix it should conflict in mock
+	fw, err := w.CreateFormFile("file", "registered.txt")
 	if err != nil {
 		t.Fatalf("failed to create file field: %v", err)
 	}
@@ -156,3 +317,100 @@ func TestHandleDocumentStatus(t *testing.T) {
 		t.Errorf("expected doc ID 123, got %v", data["document_id"])
 	}
 }
+
+// docByHashRepository is a minimal database.DocumentRepository that only
+// knows how to answer GetDocumentByHash, for exercising handleDocumentExist
+// without a real database.
+type docByHashRepository struct {
+	docs map[string]*models.Document
+}
+
+func (m *docByHashRepository) CreateDocument(ctx context.Context, doc *models.Document) (int64, error) {
+	return 0, nil
+}
+func (m *docByHashRepository) GetDocumentByID(ctx context.Context, id int64) (*models.Document, error) {
+	return nil, database.ErrNotFound
+}
+func (m *docByHashRepository) GetDocumentByHash(ctx context.Context, hash []byte) (*models.Document, error) {
+	doc, ok := m.docs[hex.EncodeToString(hash)]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return doc, nil
+}
+func (m *docByHashRepository) DeleteDocument(ctx context.Context, id int64) error { return nil }
+func (m *docByHashRepository) UpdateDocumentHash(ctx context.Context, id int64, hash []byte) error {
+	return nil
+}
+func (m *docByHashRepository) ListAllDocuments(ctx context.Context) ([]*models.Document, error) {
+	return nil, nil
+}
+
+func newTestDocumentServer(docs map[string]*models.Document) *Server {
+	orchestrator := ingest.NewOrchestrator(
+		ingest.NewMockQdrantClient(),
+		ingest.NewMockNeo4jClient(),
+		&docByHashRepository{docs: docs},
+		&ingest.MockSagaRepository{},
+		hclog.NewNullLogger(),
+	)
+	return NewServer(nil, nil, nil, orchestrator)
+}
+
+func TestHandleDocumentExist_Found(t *testing.T) {
+	digest := "a3f5"
+	raw, _ := hex.DecodeString(digest)
+	s := newTestDocumentServer(map[string]*models.Document{
+		digest: {ID: 42, FileHash: raw, FileSize: 1024},
+	})
+	ts := httptest.NewServer(s.RegisterRoutes())
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodHead, ts.URL+"/api/v1/documents/sha256:"+digest, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("req failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 OK, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Content-Length") != "1024" {
+		t.Errorf("expected Content-Length 1024, got %q", resp.Header.Get("Content-Length"))
+	}
+	if want := `"sha256:` + digest + `"`; resp.Header.Get("ETag") != want {
+		t.Errorf("expected ETag %q, got %q", want, resp.Header.Get("ETag"))
+	}
+	if resp.Header.Get("X-Booksage-Document-Id") != "42" {
+		t.Errorf("expected X-Booksage-Document-Id 42, got %q", resp.Header.Get("X-Booksage-Document-Id"))
+	}
+}
+
+func TestHandleDocumentExist_NotFound(t *testing.T) {
+	s := newTestDocumentServer(map[string]*models.Document{})
+	ts := httptest.NewServer(s.RegisterRoutes())
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodHead, ts.URL+"/api/v1/documents/sha256:deadbeef", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("req failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 Not Found, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandleDocumentExist_MalformedDigest(t *testing.T) {
+	s := newTestDocumentServer(map[string]*models.Document{})
+	ts := httptest.NewServer(s.RegisterRoutes())
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodHead, ts.URL+"/api/v1/documents/not-a-digest", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("req failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 Bad Request, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"sync"
+
+	"github.com/booksage/booksage-api/internal/agent"
+)
+
+const (
+	// sseRetryMillis is sent as the SSE "retry:" hint so a client's
+	// EventSource waits a sane amount of time before reconnecting instead
+	// of hammering us immediately after a drop.
+	sseRetryMillis = 3000
+	// maxSSEReplayEvents bounds how many past events a session's ring
+	// buffer keeps; a reconnecting client only needs to catch up on what
+	// it missed during a brief disconnect, not the whole conversation.
+	maxSSEReplayEvents = 256
+)
+
+// sseRingBuffer holds the last maxSSEReplayEvents events emitted for one
+// SSE session, so a client reconnecting with Last-Event-ID can replay
+// whatever it missed before the live stream resumes.
+type sseRingBuffer struct {
+	mu     sync.Mutex
+	events []agent.GeneratorEvent
+}
+
+func newSSERingBuffer() *sseRingBuffer {
+	return &sseRingBuffer{}
+}
+
+// Append records ev, evicting the oldest event once the buffer is full.
+func (b *sseRingBuffer) Append(ev agent.GeneratorEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.events = append(b.events, ev)
+	if len(b.events) > maxSSEReplayEvents {
+		b.events = b.events[len(b.events)-maxSSEReplayEvents:]
+	}
+}
+
+// Since returns every buffered event with a sequence number greater than
+// lastSeq, in emission order.
+func (b *sseRingBuffer) Since(lastSeq int64) []agent.GeneratorEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var out []agent.GeneratorEvent
+	for _, ev := range b.events {
+		if ev.Seq > lastSeq {
+			out = append(out, ev)
+		}
+	}
+	return out
+}
+
+// sseBufferFor returns the ring buffer for sessionID, creating one on first
+// use.
+func (s *Server) sseBufferFor(sessionID string) *sseRingBuffer {
+	s.sseBuffersMu.Lock()
+	defer s.sseBuffersMu.Unlock()
+	if s.sseBuffers == nil {
+		s.sseBuffers = make(map[string]*sseRingBuffer)
+	}
+	buf, ok := s.sseBuffers[sessionID]
+	if !ok {
+		buf = newSSERingBuffer()
+		s.sseBuffers[sessionID] = buf
+	}
+	return buf
+}
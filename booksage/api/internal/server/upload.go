@@ -0,0 +1,334 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/booksage/booksage-api/internal/database"
+	"github.com/booksage/booksage-api/internal/database/models"
+)
+
+// handleCreateUpload begins a resumable upload, mirroring the Docker
+// Registry blob-upload protocol: it hands back an opaque upload ID the
+// client addresses all subsequent requests to, via both a Location header
+// (so a dumb HTTP client can just follow it) and a Docker-Upload-UUID
+// header (so a client that already knows the convention can skip parsing
+// the Location path).
+func (s *Server) handleCreateUpload(w http.ResponseWriter, r *http.Request) {
+	if s.uploadRepo == nil {
+		http.Error(w, "Resumable uploads are not configured", http.StatusNotImplemented)
+		return
+	}
+
+	id, err := generateUploadID()
+	if err != nil {
+		log.Printf("[Server] Failed to generate upload ID: %v", err)
+		http.Error(w, "Failed to start upload", http.StatusInternalServerError)
+		return
+	}
+	tempPath := filepath.Join(s.uploadDir, "ingest-upload-"+id)
+
+	f, err := os.OpenFile(tempPath, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		log.Printf("[Server] Failed to create upload temp file %s: %v", tempPath, err)
+		http.Error(w, "Failed to start upload", http.StatusInternalServerError)
+		return
+	}
+	_ = f.Close()
+
+	upload := &models.UploadSession{ID: id, TempPath: tempPath, Offset: 0}
+	if err := s.uploadRepo.CreateUpload(r.Context(), upload); err != nil {
+		log.Printf("[Server] Failed to record upload session %s: %v", id, err)
+		http.Error(w, "Failed to start upload", http.StatusInternalServerError)
+		return
+	}
+
+	location := fmt.Sprintf("/api/v1/ingest/uploads/%s", id)
+	w.Header().Set("Location", location)
+	w.Header().Set("Docker-Upload-UUID", id)
+	w.Header().Set("Range", "0-0")
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// handleUploadProgress reports how many bytes the server has durably
+// received for id, so a client resuming after a dropped connection knows
+// where to seek its source reader to before its next PATCH.
+func (s *Server) handleUploadProgress(w http.ResponseWriter, r *http.Request) {
+	upload, ok := s.lookupUpload(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Docker-Upload-UUID", upload.ID)
+	w.Header().Set("Range", fmt.Sprintf("0-%d", upload.Offset))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleUploadChunk appends a PATCHed chunk to the upload's temp file.
+// Content-Range must start exactly at the offset the server last reported,
+// so a client that retries from stale state gets a clear 409 rather than
+// silently corrupting the assembled file.
+func (s *Server) handleUploadChunk(w http.ResponseWriter, r *http.Request) {
+	upload, ok := s.lookupUpload(w, r)
+	if !ok {
+		return
+	}
+
+	start, _, err := parseContentRange(r.Header.Get("Content-Range"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid Content-Range: %v", err), http.StatusBadRequest)
+		return
+	}
+	if start != upload.Offset {
+		http.Error(w, fmt.Sprintf("Upload offset mismatch: expected %d, got %d", upload.Offset, start), http.StatusConflict)
+		return
+	}
+
+	f, err := os.OpenFile(upload.TempPath, os.O_WRONLY, 0o644)
+	if err != nil {
+		log.Printf("[Server] Failed to open upload temp file %s: %v", upload.TempPath, err)
+		http.Error(w, "Failed to resume upload", http.StatusInternalServerError)
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := f.Seek(start, io.SeekStart); err != nil {
+		http.Error(w, "Failed to seek upload temp file", http.StatusInternalServerError)
+		return
+	}
+	written, err := io.Copy(f, r.Body)
+	if err != nil {
+		log.Printf("[Server] Failed to write upload chunk for %s: %v", upload.ID, err)
+		http.Error(w, "Failed to write upload chunk", http.StatusInternalServerError)
+		return
+	}
+
+	newOffset := start + written
+	if err := s.uploadRepo.UpdateUploadOffset(r.Context(), upload.ID, newOffset); err != nil {
+		log.Printf("[Server] Failed to persist upload offset for %s: %v", upload.ID, err)
+		http.Error(w, "Failed to persist upload progress", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Docker-Upload-UUID", upload.ID)
+	w.Header().Set("Range", fmt.Sprintf("0-%d", newOffset))
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// handleCompleteUpload verifies the assembled file against the digest the
+// client declares, then hands it off to the same saga + parser pipeline
+// handleIngest uses for a single-request upload.
+func (s *Server) handleCompleteUpload(w http.ResponseWriter, r *http.Request) {
+	upload, ok := s.lookupUpload(w, r)
+	if !ok {
+		return
+	}
+
+	expectedDigest, err := parseDigestParam(r.URL.Query().Get("digest"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f, err := os.Open(upload.TempPath)
+	if err != nil {
+		log.Printf("[Server] Failed to open completed upload %s: %v", upload.TempPath, err)
+		http.Error(w, "Failed to finalize upload", http.StatusInternalServerError)
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		http.Error(w, "Failed to hash uploaded content", http.StatusInternalServerError)
+		return
+	}
+	actualDigest := hash.Sum(nil)
+	if hex.EncodeToString(actualDigest) != hex.EncodeToString(expectedDigest) {
+		http.Error(w, "Uploaded content does not match declared digest", http.StatusBadRequest)
+		return
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		http.Error(w, "Failed to finalize upload", http.StatusInternalServerError)
+		return
+	}
+
+	// Claim the upload ID only once the digest is confirmed good, so a
+	// client that retries this PUT after fixing a bad ?digest= param isn't
+	// permanently locked out by its own earlier mistake. This still closes
+	// the race it's meant for: two concurrent PUTs with a valid, matching
+	// digest can't both slip past this point and ingest the same upload
+	// twice.
+	claimed, err := s.uploadRepo.ClaimUpload(r.Context(), upload.ID)
+	if err != nil {
+		log.Printf("[Server] Failed to claim upload %s: %v", upload.ID, err)
+		http.Error(w, "Failed to finalize upload", http.StatusInternalServerError)
+		return
+	}
+	if !claimed {
+		http.Error(w, "Upload is already being completed", http.StatusConflict)
+		return
+	}
+
+	filename := r.URL.Query().Get("filename")
+	if filename == "" {
+		filename = upload.ID
+	}
+	mimeType := r.URL.Query().Get("media_type")
+
+	// The ingest pipeline streams the file to the parser worker on its own
+	// schedule, so the temp file (and its DB row) can only be cleaned up
+	// once that's done rather than right after this handler returns.
+	defer func() {
+		if err := s.uploadRepo.DeleteUpload(context.Background(), upload.ID); err != nil {
+			log.Printf("[Server] Failed to delete completed upload session %s: %v", upload.ID, err)
+		}
+		if err := os.Remove(upload.TempPath); err != nil && !os.IsNotExist(err) {
+			log.Printf("[Server] Failed to remove upload temp file %s: %v", upload.TempPath, err)
+		}
+	}()
+
+	s.ingestFile(w, r, f, filename, mimeType, size, actualDigest)
+}
+
+// lookupUpload resolves the {upload_id} path value and writes the
+// appropriate error response (400/404) if it can't be found, returning ok=
+// false so the caller can just return.
+func (s *Server) lookupUpload(w http.ResponseWriter, r *http.Request) (*models.UploadSession, bool) {
+	if s.uploadRepo == nil {
+		http.Error(w, "Resumable uploads are not configured", http.StatusNotImplemented)
+		return nil, false
+	}
+
+	id := r.PathValue("upload_id")
+	if id == "" {
+		http.Error(w, "Upload ID required", http.StatusBadRequest)
+		return nil, false
+	}
+
+	upload, err := s.uploadRepo.GetUpload(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			http.Error(w, "Upload not found", http.StatusNotFound)
+			return nil, false
+		}
+		log.Printf("[Server] Failed to look up upload %s: %v", id, err)
+		http.Error(w, "Failed to look up upload", http.StatusInternalServerError)
+		return nil, false
+	}
+	return upload, true
+}
+
+// generateUploadID returns a random 16-byte hex string to address an
+// in-progress upload by, analogous to blobstore.uploadIDFor but unkeyed
+// from any digest since the digest isn't known until the final PUT.
+func generateUploadID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// generateProvisionalDocumentID returns a random 16-byte hex string a
+// single-pass ingest can hand the parser worker before the saga's real
+// document ID is settled, analogous to generateUploadID.
+func generateProvisionalDocumentID() (string, error) {
+	return generateUploadID()
+}
+
+// parseContentRange parses the "<start>-<end>" form PATCH chunks use
+// (not the standard "bytes=<start>-<end>" HTTP Range syntax), per the
+// Docker Registry blob-upload protocol this endpoint mirrors.
+func parseContentRange(header string) (start, end int64, err error) {
+	if header == "" {
+		return 0, 0, errors.New("Content-Range header is required")
+	}
+	parts := strings.SplitN(header, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("malformed Content-Range %q", header)
+	}
+	start, err = strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed Content-Range start %q", parts[0])
+	}
+	end, err = strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed Content-Range end %q", parts[1])
+	}
+	return start, end, nil
+}
+
+// parseDigestParam parses a "sha256:<hex>" digest query parameter.
+func parseDigestParam(raw string) ([]byte, error) {
+	if raw == "" {
+		return nil, errors.New("digest query parameter is required")
+	}
+	algo, hexDigest, found := strings.Cut(raw, ":")
+	if !found || algo != "sha256" {
+		return nil, fmt.Errorf("unsupported digest %q, expected sha256:<hex>", raw)
+	}
+	digest, err := hex.DecodeString(hexDigest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid digest hex: %w", err)
+	}
+	return digest, nil
+}
+
+// janitorLoop periodically sweeps upload sessions that haven't been
+// touched in maxAge, removing both their temp file and their DB row so an
+// abandoned multi-gigabyte upload doesn't sit on disk forever.
+func (s *Server) janitorLoop(ctx context.Context, interval, maxAge time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sweepStaleUploads(ctx, maxAge)
+		}
+	}
+}
+
+func (s *Server) sweepStaleUploads(ctx context.Context, maxAge time.Duration) {
+	stale, err := s.uploadRepo.ListStaleUploads(ctx, time.Now().Add(-maxAge))
+	if err != nil {
+		log.Printf("[Server] Upload janitor failed to list stale uploads: %v", err)
+		return
+	}
+
+	for _, upload := range stale {
+		if err := os.Remove(upload.TempPath); err != nil && !os.IsNotExist(err) {
+			log.Printf("[Server] Upload janitor failed to remove temp file %s: %v", upload.TempPath, err)
+			continue
+		}
+		if err := s.uploadRepo.DeleteUpload(ctx, upload.ID); err != nil {
+			log.Printf("[Server] Upload janitor failed to delete session %s: %v", upload.ID, err)
+			continue
+		}
+		log.Printf("[Server] Upload janitor reclaimed abandoned upload %s", upload.ID)
+	}
+}
+
+// StartUploadJanitor launches the background sweep of abandoned uploads.
+// It is a no-op if the server wasn't configured with an UploadRepository.
+func (s *Server) StartUploadJanitor(ctx context.Context, interval, maxAge time.Duration) {
+	if s.uploadRepo == nil {
+		return
+	}
+	go s.janitorLoop(ctx, interval, maxAge)
+}
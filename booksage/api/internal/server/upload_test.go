@@ -0,0 +1,340 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/booksage/booksage-api/internal/database"
+	"github.com/booksage/booksage-api/internal/database/models"
+	"github.com/booksage/booksage-api/internal/ingest"
+)
+
+// mockUploadRepository is a trivial in-memory database.UploadRepository for
+// exercising the HTTP handlers without a real database.
+type mockUploadRepository struct {
+	mu      sync.Mutex
+	uploads map[string]*models.UploadSession
+}
+
+func newMockUploadRepository() *mockUploadRepository {
+	return &mockUploadRepository{uploads: make(map[string]*models.UploadSession)}
+}
+
+func (m *mockUploadRepository) CreateUpload(ctx context.Context, upload *models.UploadSession) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.uploads[upload.ID] = upload
+	return nil
+}
+
+func (m *mockUploadRepository) GetUpload(ctx context.Context, id string) (*models.UploadSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	upload, ok := m.uploads[id]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return upload, nil
+}
+
+func (m *mockUploadRepository) UpdateUploadOffset(ctx context.Context, id string, offset int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	upload, ok := m.uploads[id]
+	if !ok {
+		return database.ErrNotFound
+	}
+	upload.Offset = offset
+	return nil
+}
+
+func (m *mockUploadRepository) ClaimUpload(ctx context.Context, id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	upload, ok := m.uploads[id]
+	if !ok {
+		return false, database.ErrNotFound
+	}
+	if upload.Completing {
+		return false, nil
+	}
+	upload.Completing = true
+	return true, nil
+}
+
+func (m *mockUploadRepository) DeleteUpload(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.uploads, id)
+	return nil
+}
+
+func (m *mockUploadRepository) ListStaleUploads(ctx context.Context, cutoff time.Time) ([]*models.UploadSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var stale []*models.UploadSession
+	for _, upload := range m.uploads {
+		if upload.CreatedAt.Before(cutoff) {
+			stale = append(stale, upload)
+		}
+	}
+	return stale, nil
+}
+
+func newTestUploadServer(t *testing.T) (*Server, *mockUploadRepository) {
+	t.Helper()
+	repo := newMockUploadRepository()
+	s := newTestIngestServer(&ingest.MockDocumentRepository{}).WithUploadRepository(repo, t.TempDir())
+	return s, repo
+}
+
+func TestHandleCreateUpload_NotConfigured(t *testing.T) {
+	s := NewServer(nil, nil, nil, nil)
+	ts := httptest.NewServer(s.RegisterRoutes())
+	defer ts.Close()
+
+	resp, err := http.Post(ts.URL+"/api/v1/ingest/uploads", "", nil)
+	if err != nil {
+		t.Fatalf("req failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusNotImplemented {
+		t.Errorf("expected 501 when uploads aren't configured, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandleCreateUpload_Success(t *testing.T) {
+	s, _ := newTestUploadServer(t)
+	ts := httptest.NewServer(s.RegisterRoutes())
+	defer ts.Close()
+
+	resp, err := http.Post(ts.URL+"/api/v1/ingest/uploads", "", nil)
+	if err != nil {
+		t.Fatalf("req failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("expected 202 Accepted, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Location") == "" {
+		t.Error("expected a Location header pointing at the new upload")
+	}
+	if resp.Header.Get("Docker-Upload-UUID") == "" {
+		t.Error("expected a Docker-Upload-UUID header")
+	}
+}
+
+func TestHandleUploadChunk_OffsetMismatch(t *testing.T) {
+	s, repo := newTestUploadServer(t)
+	ts := httptest.NewServer(s.RegisterRoutes())
+	defer ts.Close()
+
+	f, err := os.CreateTemp(t.TempDir(), "upload-")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	upload := &models.UploadSession{ID: "abc", TempPath: f.Name(), Offset: 5}
+	if err := repo.CreateUpload(context.Background(), upload); err != nil {
+		t.Fatalf("failed to seed upload: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodPatch, ts.URL+"/api/v1/ingest/uploads/abc", strings.NewReader("data"))
+	req.Header.Set("Content-Range", "0-3")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("req failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("expected 409 on offset mismatch, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandleUploadChunk_Success(t *testing.T) {
+	s, repo := newTestUploadServer(t)
+	ts := httptest.NewServer(s.RegisterRoutes())
+	defer ts.Close()
+
+	f, err := os.CreateTemp(t.TempDir(), "upload-")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	upload := &models.UploadSession{ID: "def", TempPath: f.Name(), Offset: 0}
+	if err := repo.CreateUpload(context.Background(), upload); err != nil {
+		t.Fatalf("failed to seed upload: %v", err)
+	}
+
+	payload := "hello"
+	req, _ := http.NewRequest(http.MethodPatch, ts.URL+"/api/v1/ingest/uploads/def", strings.NewReader(payload))
+	req.Header.Set("Content-Range", "0-4")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("req failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("expected 202 Accepted, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Range"); got != "0-5" {
+		t.Errorf("expected Range header 0-5, got %q", got)
+	}
+
+	stored, err := repo.GetUpload(context.Background(), "def")
+	if err != nil {
+		t.Fatalf("failed to fetch upload: %v", err)
+	}
+	if stored.Offset != int64(len(payload)) {
+		t.Errorf("expected offset %d, got %d", len(payload), stored.Offset)
+	}
+}
+
+func TestHandleUploadProgress(t *testing.T) {
+	s, repo := newTestUploadServer(t)
+	ts := httptest.NewServer(s.RegisterRoutes())
+	defer ts.Close()
+
+	upload := &models.UploadSession{ID: "ghi", TempPath: "/tmp/unused", Offset: 42}
+	if err := repo.CreateUpload(context.Background(), upload); err != nil {
+		t.Fatalf("failed to seed upload: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodHead, ts.URL+"/api/v1/ingest/uploads/ghi", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("req failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("expected 204 No Content, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Range"); got != "0-42" {
+		t.Errorf("expected Range header 0-42, got %q", got)
+	}
+}
+
+func TestHandleUploadProgress_NotFound(t *testing.T) {
+	s, _ := newTestUploadServer(t)
+	ts := httptest.NewServer(s.RegisterRoutes())
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodHead, ts.URL+"/api/v1/ingest/uploads/missing", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("req failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 Not Found, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandleCompleteUpload_DigestMismatch(t *testing.T) {
+	s, repo := newTestUploadServer(t)
+	ts := httptest.NewServer(s.RegisterRoutes())
+	defer ts.Close()
+
+	f, err := os.CreateTemp(t.TempDir(), "upload-")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	if _, err := f.WriteString("some content"); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	_ = f.Close()
+
+	upload := &models.UploadSession{ID: "jkl", TempPath: f.Name(), Offset: int64(len("some content"))}
+	if err := repo.CreateUpload(context.Background(), upload); err != nil {
+		t.Fatalf("failed to seed upload: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodPut, ts.URL+"/api/v1/ingest/uploads/jkl?digest=sha256:0000000000000000000000000000000000000000000000000000000000000000", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("req failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 on digest mismatch, got %d", resp.StatusCode)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+}
+
+func TestHandleCompleteUpload_AlreadyClaimed(t *testing.T) {
+	s, repo := newTestUploadServer(t)
+	ts := httptest.NewServer(s.RegisterRoutes())
+	defer ts.Close()
+
+	f, err := os.CreateTemp(t.TempDir(), "upload-")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	content := "already being completed"
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	_ = f.Close()
+	digest := sha256.Sum256([]byte(content))
+
+	upload := &models.UploadSession{ID: "mno", TempPath: f.Name(), Offset: int64(len(content)), Completing: true}
+	if err := repo.CreateUpload(context.Background(), upload); err != nil {
+		t.Fatalf("failed to seed upload: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodPut, ts.URL+"/api/v1/ingest/uploads/mno?digest=sha256:"+hex.EncodeToString(digest[:]), nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("req failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("expected 409 when the upload is already being completed, got %d", resp.StatusCode)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+}
+
+func TestHandleCompleteUpload_DigestMismatchIsRetryable(t *testing.T) {
+	s, repo := newTestUploadServer(t)
+	ts := httptest.NewServer(s.RegisterRoutes())
+	defer ts.Close()
+
+	f, err := os.CreateTemp(t.TempDir(), "upload-")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	content := "retry me"
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	_ = f.Close()
+
+	upload := &models.UploadSession{ID: "pqr", TempPath: f.Name(), Offset: int64(len(content))}
+	if err := repo.CreateUpload(context.Background(), upload); err != nil {
+		t.Fatalf("failed to seed upload: %v", err)
+	}
+
+	badReq, _ := http.NewRequest(http.MethodPut, ts.URL+"/api/v1/ingest/uploads/pqr?digest=sha256:00", nil)
+	badResp, err := http.DefaultClient.Do(badReq)
+	if err != nil {
+		t.Fatalf("req failed: %v", err)
+	}
+	if badResp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 on the first, wrong-digest attempt, got %d", badResp.StatusCode)
+	}
+	_, _ = io.Copy(io.Discard, badResp.Body)
+
+	digest := sha256.Sum256([]byte(content))
+	goodReq, _ := http.NewRequest(http.MethodPut, ts.URL+"/api/v1/ingest/uploads/pqr?digest=sha256:"+hex.EncodeToString(digest[:]), nil)
+	goodResp, err := http.DefaultClient.Do(goodReq)
+	if err != nil {
+		t.Fatalf("req failed: %v", err)
+	}
+	if goodResp.StatusCode != http.StatusAccepted {
+		t.Errorf("expected a corrected retry with the right digest to succeed, got %d", goodResp.StatusCode)
+	}
+	_, _ = io.Copy(io.Discard, goodResp.Body)
+}
@@ -2,12 +2,25 @@ package query
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
+	"log"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/booksage/booksage-api/internal/database"
+	"github.com/booksage/booksage-api/internal/database/models"
 	"github.com/booksage/booksage-api/internal/domain/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// tracer is package-scoped so every SelfRAGCritique shares one tracer,
+// matching ingest.SagaOrchestrator's convention.
+var tracer = otel.Tracer("booksage-api/usecase/query")
+
 type SupportLevel string
 
 const (
@@ -16,24 +29,262 @@ const (
 	NoSupport      SupportLevel = "no_support"
 )
 
-// SelfRAGCritique evaluates RAG performance using an LLM.
+const (
+	// DefaultCritiqueMaxIterations bounds how many generate/critique
+	// rounds Critique runs before returning whatever it has, so a stubborn
+	// NoSupport verdict can't loop forever.
+	DefaultCritiqueMaxIterations = 3
+	// DefaultIrrelevantRatio is the fraction of a retrieval pass that must
+	// come back Irrelevant before Critique rewrites the query and retries
+	// retrieval rather than generating from a thin context.
+	DefaultIrrelevantRatio = 0.5
+
+	// DefaultInitialK is how many top-ranked chunks seed the first
+	// generation attempt.
+	DefaultInitialK = 3
+	// DefaultWidenStep is how many additional chunks a NoSupport verdict
+	// pulls in when no WebSearchClient is configured.
+	DefaultWidenStep = 3
+)
+
+// WebSearchClient performs a live web or knowledge-graph search. It's the
+// CRAG fallback Critique reaches for when EvaluateGeneration returns
+// NoSupport and widening internal retrieval hasn't helped, mirroring
+// agent.Generator's own web-search fallback for the same verdict.
+type WebSearchClient interface {
+	Search(ctx context.Context, query string) ([]repository.SearchResult, error)
+}
+
+// Retriever is the minimal retrieval surface SelfRAGCritique depends on.
+type Retriever interface {
+	Retrieve(ctx context.Context, query string) ([]repository.SearchResult, error)
+}
+
+// IterationTrace records one generate/critique round of a Critique call:
+// what was retrieved, whether the query got rewritten, what corrective
+// action (if any) followed a non-FullySupported verdict, and the verdict
+// itself. Trace.Iterations lets a caller see exactly why an answer was
+// accepted or rejected without re-deriving it from logs.
+type IterationTrace struct {
+	Round           int
+	RetrievedCount  int
+	RelevantCount   int
+	IrrelevantCount int
+	QueryRewritten  bool
+	RewrittenQuery  string
+	Action          string // "generate", "regenerate_widened", "regenerate_top_k", "web_fallback"
+	Support         SupportLevel
+}
+
+// Trace is the full decision record of one Critique call.
+type Trace struct {
+	Query      string
+	Iterations []IterationTrace
+}
+
+// Answer is Critique's result: the generated text, the SupportLevel the
+// critique settled on, and the chunks it was ultimately grounded in.
+type Answer struct {
+	Text    string
+	Support SupportLevel
+	Chunks  []repository.SearchResult
+}
+
+// Reflection is a critique verdict with a confidence score attached, so
+// callers can weight passages/answers instead of taking a hard boolean.
+// Relevant and Support only ever mean something in the pass they came
+// from: EvaluateRetrieval populates Relevant, EvaluateGeneration
+// populates Support, and the other stays zero-valued. SpanStart/SpanEnd
+// are byte offsets into the evaluated context marking the span the model
+// cited as its evidence; both are -1 when the model didn't cite one
+// (always true on the plain-word fallback path).
+type Reflection struct {
+	Relevant   bool
+	Support    SupportLevel
+	Confidence float64
+	Reason     string
+	SpanStart  int
+	SpanEnd    int
+}
+
+// retrievalReflectionSchema is the JSON Schema for a structured
+// EvaluateRetrieval verdict.
+var retrievalReflectionSchema = json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"relevant": {"type": "boolean"},
+		"confidence": {"type": "number"},
+		"reason": {"type": "string"},
+		"span_start": {"type": "integer"},
+		"span_end": {"type": "integer"}
+	},
+	"required": ["relevant", "confidence"]
+}`)
+
+// generationReflectionSchema is the JSON Schema for a structured
+// EvaluateGeneration verdict.
+var generationReflectionSchema = json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"support": {"type": "string", "enum": ["fully_supported", "partially_supported", "no_support"]},
+		"confidence": {"type": "number"},
+		"reason": {"type": "string"},
+		"span_start": {"type": "integer"},
+		"span_end": {"type": "integer"}
+	},
+	"required": ["support", "confidence"]
+}`)
+
+// SelfRAGCritique evaluates retrieval relevance and generation grounding
+// using an LLM, and — once WithRetriever has attached a retrieval backend
+// — can drive the full corrective-RAG (CRAG) generate/critique/correct
+// loop itself via Critique.
 type SelfRAGCritique struct {
-	router repository.LLMRouter
+	router    repository.LLMRouter
+	retriever Retriever
+	webSearch WebSearchClient
+	events    database.CritiqueRepository
+
+	// InitialK is how many relevant chunks the first retrieval pass keeps.
+	InitialK int
+	// WidenStep is how many additional chunks a NoSupport verdict pulls in
+	// when no WebSearchClient is configured.
+	WidenStep int
+	// MaxIterations bounds how many generate/critique rounds Critique
+	// runs. Zero means DefaultCritiqueMaxIterations.
+	MaxIterations int
+	// IrrelevantRatio is the fraction of evaluated passages that must come
+	// back Irrelevant before Critique rewrites the query and retries
+	// retrieval. Zero means DefaultIrrelevantRatio.
+	IrrelevantRatio float64
 }
 
-// NewSelfRAGCritique creates a new critique component.
+// NewSelfRAGCritique creates a new critique component. It can evaluate
+// retrieval/generation standalone immediately; attach WithRetriever (and
+// optionally WithWebSearch) to enable the full Critique loop.
 func NewSelfRAGCritique(router repository.LLMRouter) *SelfRAGCritique {
-	return &SelfRAGCritique{router: router}
+	return &SelfRAGCritique{
+		router:          router,
+		InitialK:        DefaultInitialK,
+		WidenStep:       DefaultWidenStep,
+		MaxIterations:   DefaultCritiqueMaxIterations,
+		IrrelevantRatio: DefaultIrrelevantRatio,
+	}
+}
+
+// WithRetriever attaches the retrieval backend Critique uses to fetch and,
+// if a pass comes back mostly Irrelevant, re-fetch candidate chunks. A
+// SelfRAGCritique built without one can still serve EvaluateRetrieval and
+// EvaluateGeneration standalone; it just can't run Critique.
+func (s *SelfRAGCritique) WithRetriever(r Retriever) *SelfRAGCritique {
+	s.retriever = r
+	return s
+}
+
+// WithWebSearch attaches the CRAG web/knowledge-graph fallback. Without
+// one, a NoSupport verdict falls back to widening internal retrieval
+// instead.
+func (s *SelfRAGCritique) WithWebSearch(w WebSearchClient) *SelfRAGCritique {
+	s.webSearch = w
+	return s
+}
+
+// WithCritiqueRepository attaches the audit log ReflectRetrieval and
+// ReflectGeneration write a CritiqueEvent to after every verdict. Without
+// one, critique still runs exactly the same; nothing is persisted for
+// offline evaluation or the critique-replay CLI.
+func (s *SelfRAGCritique) WithCritiqueRepository(r database.CritiqueRepository) *SelfRAGCritique {
+	s.events = r
+	return s
+}
+
+// recordCritiqueEvent persists one verdict to s.events, logging rather than
+// returning an error: a failed audit-log write shouldn't fail the critique
+// that produced the verdict it was trying to record.
+func (c *SelfRAGCritique) recordCritiqueEvent(ctx context.Context, kind, query, context, verdict, model string, latencyMS float64, answer string) {
+	if c.events == nil {
+		return
+	}
+	hash := sha256.Sum256([]byte(context))
+	event := &models.CritiqueEvent{
+		Kind:        kind,
+		Query:       query,
+		ContextHash: hash[:],
+		Verdict:     verdict,
+		Model:       model,
+		LatencyMS:   latencyMS,
+		Answer:      answer,
+	}
+	if _, err := c.events.CreateCritiqueEvent(ctx, event); err != nil {
+		log.Printf("[Self-RAG] Failed to persist critique event: %v", err)
+	}
 }
 
 // EvaluateRetrieval checks if a retrieved chunk is relevant to the query.
+// It's a thin wrapper over ReflectRetrieval for callers that only need the
+// boolean verdict; use ReflectRetrieval directly to see the confidence,
+// reason, and cited span behind it.
 func (c *SelfRAGCritique) EvaluateRetrieval(ctx context.Context, query, context string) bool {
+	return c.ReflectRetrieval(ctx, query, context).Relevant
+}
+
+// ReflectRetrieval asks the LLM whether context is relevant to query and
+// returns the full Reflection: relevance, confidence, the model's reason,
+// and the span of context it cited as evidence. It prefers a structured,
+// schema-constrained verdict via StructuredLLMClient and falls back to
+// parsing a plain "relevant"/"irrelevant" word for clients that don't
+// support JSON mode, in which case Confidence is a flat 0.5 and SpanStart/
+// SpanEnd are -1 since nothing was cited.
+func (c *SelfRAGCritique) ReflectRetrieval(ctx context.Context, query, context string) (reflection *Reflection) {
+	ctx, span := tracer.Start(ctx, "critique.reflect_retrieval")
+	start := time.Now()
+	var modelName string
+	defer func() {
+		latencyMS := float64(time.Since(start).Microseconds()) / 1000
+		span.SetAttributes(
+			attribute.Float64("critique.latency_ms", latencyMS),
+			attribute.Bool("critique.relevant", reflection.Relevant),
+			attribute.Float64("critique.confidence", reflection.Confidence),
+		)
+		span.End()
+		if modelName != "" {
+			verdict := "irrelevant"
+			if reflection.Relevant {
+				verdict = "relevant"
+			}
+			c.recordCritiqueEvent(ctx, "retrieval", query, context, verdict, modelName, latencyMS, "")
+		}
+	}()
+
 	if c == nil || c.router == nil {
-		return true
+		return &Reflection{Relevant: true, Confidence: 0.5, SpanStart: -1, SpanEnd: -1}
 	}
 	client := c.router.RouteLLMTask(repository.TaskType("agentic_reasoning"))
 	if client == nil {
-		return true
+		return &Reflection{Relevant: true, Confidence: 0.5, SpanStart: -1, SpanEnd: -1}
+	}
+	modelName = client.Name()
+	span.SetAttributes(attribute.String("critique.model", modelName))
+
+	if client.SupportsStructuredOutput() {
+		if structured, ok := client.(repository.StructuredLLMClient); ok {
+			prompt := fmt.Sprintf(`Evaluate if the following context is relevant to the user query. Cite the byte offsets of the span you relied on, or -1/-1 if none applies.
+
+Query: %s
+Context: %s`, query, context)
+
+			raw, err := structured.GenerateStructured(ctx, prompt, retrievalReflectionSchema)
+			if err == nil {
+				span.SetAttributes(attribute.Int("critique.response_tokens", len(strings.Fields(string(raw)))))
+				if reflection, verr := parseRetrievalReflection(raw); verr == nil {
+					return reflection
+				} else {
+					log.Printf("[Self-RAG] Retrieval reflection schema validation failed: %v", verr)
+				}
+			} else {
+				log.Printf("[Self-RAG] Structured retrieval critique failed, falling back to plain-word: %v", err)
+			}
+		}
 	}
 
 	prompt := fmt.Sprintf(`Evaluate if the following context is relevant to the user query.
@@ -44,15 +295,72 @@ Context: %s`, query, context)
 
 	resp, err := client.Generate(ctx, prompt)
 	if err != nil {
-		return true // Fallback to including it
+		return &Reflection{Relevant: true, Confidence: 0.5, SpanStart: -1, SpanEnd: -1} // Fallback to including it
 	}
+	span.SetAttributes(attribute.Int("critique.response_tokens", len(strings.Fields(resp))))
 
-	return strings.Contains(strings.ToLower(resp), "relevant")
+	relevant := strings.Contains(strings.ToLower(resp), "relevant") && !strings.Contains(strings.ToLower(resp), "irrelevant")
+	return &Reflection{Relevant: relevant, Confidence: 0.5, SpanStart: -1, SpanEnd: -1}
 }
 
-// EvaluateGeneration checks if an answer is supported by the context.
+// EvaluateGeneration checks if an answer is supported by the context. It's
+// a thin wrapper over ReflectGeneration for callers that only need the
+// SupportLevel; use ReflectGeneration directly to see the confidence,
+// reason, and cited span behind it.
 func (c *SelfRAGCritique) EvaluateGeneration(ctx context.Context, answer, context string) SupportLevel {
+	return c.ReflectGeneration(ctx, answer, context).Support
+}
+
+// ReflectGeneration asks the LLM whether answer is strictly supported by
+// context and returns the full Reflection: the SupportLevel, confidence,
+// the model's reason, and the span of context it cited as evidence. It
+// prefers a structured, schema-constrained verdict via StructuredLLMClient
+// and falls back to parsing a plain support-level word for clients that
+// don't support JSON mode, in which case Confidence is a flat 0.5 and
+// SpanStart/SpanEnd are -1 since nothing was cited.
+func (c *SelfRAGCritique) ReflectGeneration(ctx context.Context, answer, context string) (reflection *Reflection) {
+	ctx, span := tracer.Start(ctx, "critique.reflect_generation")
+	start := time.Now()
+	var modelName string
+	defer func() {
+		latencyMS := float64(time.Since(start).Microseconds()) / 1000
+		span.SetAttributes(
+			attribute.Float64("critique.latency_ms", latencyMS),
+			attribute.String("critique.verdict", string(reflection.Support)),
+			attribute.Float64("critique.confidence", reflection.Confidence),
+		)
+		span.End()
+		if modelName != "" {
+			// ReflectGeneration never sees the original query, only the
+			// answer it produced and the context it's judged against.
+			c.recordCritiqueEvent(ctx, "generation", "", context, string(reflection.Support), modelName, latencyMS, answer)
+		}
+	}()
+
 	client := c.router.RouteLLMTask(repository.TaskType("agentic_reasoning"))
+	modelName = client.Name()
+	span.SetAttributes(attribute.String("critique.model", modelName))
+
+	if client.SupportsStructuredOutput() {
+		if structured, ok := client.(repository.StructuredLLMClient); ok {
+			prompt := fmt.Sprintf(`Evaluate if the following answer is strictly supported by the provided context. Cite the byte offsets of the span you relied on, or -1/-1 if none applies.
+
+Context: %s
+Answer: %s`, context, answer)
+
+			raw, err := structured.GenerateStructured(ctx, prompt, generationReflectionSchema)
+			if err == nil {
+				span.SetAttributes(attribute.Int("critique.response_tokens", len(strings.Fields(string(raw)))))
+				if reflection, verr := parseGenerationReflection(raw); verr == nil {
+					return reflection
+				} else {
+					log.Printf("[Self-RAG] Generation reflection schema validation failed: %v", verr)
+				}
+			} else {
+				log.Printf("[Self-RAG] Structured generation critique failed, falling back to plain-word: %v", err)
+			}
+		}
+	}
 
 	prompt := fmt.Sprintf(`Evaluate if the following answer is strictly supported by the provided context.
 Respond ONLY with one of: "fully_supported", "partially_supported", "no_support".
@@ -62,16 +370,292 @@ Answer: %s`, context, answer)
 
 	resp, err := client.Generate(ctx, prompt)
 	if err != nil {
-		return FullySupported // Fallback
+		return &Reflection{Support: FullySupported, Confidence: 0.5, SpanStart: -1, SpanEnd: -1} // Fallback
 	}
+	span.SetAttributes(attribute.Int("critique.response_tokens", len(strings.Fields(resp))))
 
 	resp = strings.ToLower(strings.TrimSpace(resp))
+	result := &Reflection{Confidence: 0.5, SpanStart: -1, SpanEnd: -1}
 	switch {
 	case strings.Contains(resp, "fully"):
-		return FullySupported
+		result.Support = FullySupported
 	case strings.Contains(resp, "partially"):
-		return Partially
+		result.Support = Partially
+	default:
+		result.Support = NoSupport
+	}
+	return result
+}
+
+// parseRetrievalReflection unmarshals and validates a structured
+// EvaluateRetrieval verdict, rejecting a confidence outside [0, 1].
+func parseRetrievalReflection(raw []byte) (*Reflection, error) {
+	var payload struct {
+		Relevant   bool    `json:"relevant"`
+		Confidence float64 `json:"confidence"`
+		Reason     string  `json:"reason"`
+		SpanStart  int     `json:"span_start"`
+		SpanEnd    int     `json:"span_end"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("invalid retrieval reflection JSON: %w", err)
+	}
+	if payload.Confidence < 0 || payload.Confidence > 1 {
+		return nil, fmt.Errorf("confidence %v out of range [0, 1]", payload.Confidence)
+	}
+	if payload.SpanStart == 0 && payload.SpanEnd == 0 {
+		payload.SpanStart, payload.SpanEnd = -1, -1
+	}
+	return &Reflection{
+		Relevant:   payload.Relevant,
+		Confidence: payload.Confidence,
+		Reason:     payload.Reason,
+		SpanStart:  payload.SpanStart,
+		SpanEnd:    payload.SpanEnd,
+	}, nil
+}
+
+// parseGenerationReflection unmarshals and validates a structured
+// EvaluateGeneration verdict, rejecting a confidence outside [0, 1] or an
+// unrecognized support level.
+func parseGenerationReflection(raw []byte) (*Reflection, error) {
+	var payload struct {
+		Support    SupportLevel `json:"support"`
+		Confidence float64      `json:"confidence"`
+		Reason     string       `json:"reason"`
+		SpanStart  int          `json:"span_start"`
+		SpanEnd    int          `json:"span_end"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("invalid generation reflection JSON: %w", err)
+	}
+	switch payload.Support {
+	case FullySupported, Partially, NoSupport:
 	default:
-		return NoSupport
+		return nil, fmt.Errorf("unrecognized support level %q", payload.Support)
+	}
+	if payload.Confidence < 0 || payload.Confidence > 1 {
+		return nil, fmt.Errorf("confidence %v out of range [0, 1]", payload.Confidence)
+	}
+	if payload.SpanStart == 0 && payload.SpanEnd == 0 {
+		payload.SpanStart, payload.SpanEnd = -1, -1
+	}
+	return &Reflection{
+		Support:    payload.Support,
+		Confidence: payload.Confidence,
+		Reason:     payload.Reason,
+		SpanStart:  payload.SpanStart,
+		SpanEnd:    payload.SpanEnd,
+	}, nil
+}
+
+// Critique runs the full CRAG control loop for query: retrieve, critique
+// relevance, rewrite-and-retry if too much of the first pass was
+// Irrelevant, generate, critique grounding, and correct a non-
+// FullySupported verdict — either via the web/KG fallback or by
+// regenerating from the top-scoring supported chunks only — until
+// FullySupported or MaxIterations is reached. The returned Trace records
+// every round's decision so callers can see why the final Answer was
+// accepted.
+func (s *SelfRAGCritique) Critique(ctx context.Context, query string) (*Answer, *Trace, error) {
+	if s.retriever == nil {
+		return nil, nil, fmt.Errorf("self-rag critique: no retriever configured")
+	}
+
+	trace := &Trace{Query: query}
+	k := s.initialK()
+
+	candidates, err := s.retriever.Retrieve(ctx, query)
+	if err != nil {
+		return nil, trace, fmt.Errorf("retrieval failed: %w", err)
+	}
+
+	relevant, irrelevant := s.filterRelevant(ctx, query, candidates, k)
+	iter := IterationTrace{Round: 1, RetrievedCount: len(candidates), IrrelevantCount: irrelevant}
+
+	if s.shouldRewrite(len(candidates), irrelevant) {
+		if rewritten := s.rewriteQuery(ctx, query); rewritten != "" && rewritten != query {
+			iter.QueryRewritten = true
+			iter.RewrittenQuery = rewritten
+
+			if more, err := s.retriever.Retrieve(ctx, rewritten); err != nil {
+				log.Printf("[Self-RAG] Rewritten retrieval for %q failed: %v", rewritten, err)
+			} else {
+				candidates = append(candidates, more...)
+				relevant, irrelevant = s.filterRelevant(ctx, query, candidates, k)
+				iter.RetrievedCount = len(candidates)
+				iter.IrrelevantCount = irrelevant
+			}
+		}
+	}
+
+	answer, err := s.generate(ctx, query, relevant)
+	if err != nil {
+		return nil, trace, fmt.Errorf("generation failed: %w", err)
+	}
+
+	support := s.EvaluateGeneration(ctx, answer, joinChunks(relevant))
+	iter.RelevantCount = len(relevant)
+	iter.Action = "generate"
+	iter.Support = support
+	trace.Iterations = append(trace.Iterations, iter)
+	log.Printf("[Self-RAG] Round %d support: %s (%d/%d relevant)", iter.Round, support, len(relevant), len(candidates))
+
+	rounds := 1
+	for support != FullySupported && rounds < s.maxIterations() {
+		rounds++
+		iter = IterationTrace{Round: rounds, RetrievedCount: len(candidates)}
+
+		switch support {
+		case NoSupport:
+			if s.webSearch != nil {
+				results, err := s.webSearch.Search(ctx, query)
+				if err != nil {
+					log.Printf("[Self-RAG] Web fallback search failed: %v", err)
+				} else {
+					relevant = append(relevant, results...)
+				}
+				iter.Action = "web_fallback"
+			} else {
+				k += s.WidenStep
+				relevant, irrelevant = s.filterRelevant(ctx, query, candidates, k)
+				iter.IrrelevantCount = irrelevant
+				iter.Action = "regenerate_widened"
+			}
+		default: // Partially supported
+			relevant = topKByScore(relevant, s.initialK())
+			iter.Action = "regenerate_top_k"
+		}
+
+		answer, err = s.generate(ctx, query, relevant)
+		if err != nil {
+			return nil, trace, fmt.Errorf("regeneration failed: %w", err)
+		}
+
+		support = s.EvaluateGeneration(ctx, answer, joinChunks(relevant))
+		iter.RelevantCount = len(relevant)
+		iter.Support = support
+		trace.Iterations = append(trace.Iterations, iter)
+		log.Printf("[Self-RAG] Round %d support: %s (%d relevant, action=%s)", iter.Round, support, len(relevant), iter.Action)
+	}
+
+	return &Answer{Text: answer, Support: support, Chunks: relevant}, trace, nil
+}
+
+// filterRelevant evaluates each candidate through EvaluateRetrieval,
+// keeping up to k relevant ones and reporting how many were dropped as
+// Irrelevant along the way.
+func (s *SelfRAGCritique) filterRelevant(ctx context.Context, query string, candidates []repository.SearchResult, k int) (relevant []repository.SearchResult, irrelevantCount int) {
+	for _, c := range candidates {
+		if len(relevant) >= k {
+			break
+		}
+		if s.EvaluateRetrieval(ctx, query, c.Content) {
+			relevant = append(relevant, c)
+		} else {
+			irrelevantCount++
+		}
+	}
+	return relevant, irrelevantCount
+}
+
+// shouldRewrite reports whether enough of a retrieval pass came back
+// Irrelevant to justify rewriting the query and retrying, rather than
+// generating from whatever little survived.
+func (s *SelfRAGCritique) shouldRewrite(total, irrelevantCount int) bool {
+	if total == 0 {
+		return true
+	}
+	ratio := s.IrrelevantRatio
+	if ratio <= 0 {
+		ratio = DefaultIrrelevantRatio
+	}
+	return float64(irrelevantCount)/float64(total) >= ratio
+}
+
+// rewriteQuery asks the LLM, via the dedicated query_rewrite task, to
+// rephrase/expand query so a follow-up retrieval pass can surface chunks
+// the original phrasing missed. Falls back to the original query if the
+// rewrite fails or the router has nothing registered for that task.
+func (s *SelfRAGCritique) rewriteQuery(ctx context.Context, query string) string {
+	client := s.router.RouteLLMTask(repository.TaskType("query_rewrite"))
+	if client == nil {
+		return query
+	}
+
+	prompt := fmt.Sprintf(`The retrieved context did not sufficiently cover the following question. Rewrite the question to surface information a narrower search may have missed. Respond with ONLY the rewritten question.
+
+Question: %s`, query)
+
+	resp, err := client.Generate(ctx, prompt)
+	if err != nil {
+		log.Printf("[Self-RAG] Query rewrite failed: %v (reusing original query)", err)
+		return query
+	}
+	return strings.TrimSpace(resp)
+}
+
+// generate produces an answer from query and the current set of chunks.
+func (s *SelfRAGCritique) generate(ctx context.Context, query string, chunks []repository.SearchResult) (string, error) {
+	client := s.router.RouteLLMTask(repository.TaskType("agentic_reasoning"))
+	return client.Generate(ctx, buildRAGPrompt(query, chunksToStrings(chunks)))
+}
+
+// buildRAGPrompt constructs a prompt with retrieved context for the LLM.
+func buildRAGPrompt(query string, contextChunks []string) string {
+	if len(contextChunks) == 0 {
+		return "Answer the following question to the best of your ability:\n\n" + query
+	}
+
+	var sb strings.Builder
+	sb.WriteString("You are a helpful assistant that answers questions based on the provided context.\n")
+	sb.WriteString("Use ONLY the information in the context to answer. If the context doesn't contain the answer, say so.\n\n")
+	sb.WriteString("=== CONTEXT ===\n")
+	for i, chunk := range contextChunks {
+		sb.WriteString(fmt.Sprintf("[Source %d]\n%s\n\n", i+1, chunk))
+	}
+	sb.WriteString("=== QUESTION ===\n")
+	sb.WriteString(query)
+	sb.WriteString("\n\n=== ANSWER ===\n")
+	return sb.String()
+}
+
+// joinChunks flattens chunk content into the single context blob EvaluateGeneration expects.
+func joinChunks(chunks []repository.SearchResult) string {
+	return strings.Join(chunksToStrings(chunks), "\n\n")
+}
+
+func chunksToStrings(chunks []repository.SearchResult) []string {
+	out := make([]string, len(chunks))
+	for i, c := range chunks {
+		out[i] = c.Content
+	}
+	return out
+}
+
+func (s *SelfRAGCritique) initialK() int {
+	if s.InitialK > 0 {
+		return s.InitialK
+	}
+	return DefaultInitialK
+}
+
+func (s *SelfRAGCritique) maxIterations() int {
+	if s.MaxIterations > 0 {
+		return s.MaxIterations
+	}
+	return DefaultCritiqueMaxIterations
+}
+
+// topKByScore returns the k highest-scoring chunks, descending, for
+// regenerating from supported-only context after a Partially supported
+// verdict.
+func topKByScore(chunks []repository.SearchResult, k int) []repository.SearchResult {
+	sorted := make([]repository.SearchResult, len(chunks))
+	copy(sorted, chunks)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Score > sorted[j].Score })
+	if len(sorted) > k {
+		sorted = sorted[:k]
 	}
+	return sorted
 }
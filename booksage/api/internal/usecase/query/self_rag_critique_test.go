@@ -0,0 +1,150 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/booksage/booksage-api/internal/domain/repository"
+)
+
+// stubWebSearch is a canned WebSearchClient for exercising Critique's CRAG
+// fallback on a NoSupport verdict.
+type stubWebSearch struct {
+	results []repository.SearchResult
+	called  bool
+}
+
+func (s *stubWebSearch) Search(ctx context.Context, query string) ([]repository.SearchResult, error) {
+	s.called = true
+	return s.results, nil
+}
+
+func TestSelfRAGCritique_Critique_FullySupported_StopsAfterOneRound(t *testing.T) {
+	retriever := &stubRetriever{results: [][]repository.SearchResult{
+		{{ID: "c1", Content: "chunk one"}},
+	}}
+	client := &scriptedClient{resps: []string{"relevant", "the answer", "fully_supported"}}
+	router := &taskRouter{byTask: map[repository.TaskType]repository.LLMClient{
+		"agentic_reasoning": client,
+	}}
+
+	critique := NewSelfRAGCritique(router).WithRetriever(retriever)
+	answer, trace, err := critique.Critique(context.Background(), "what happens in chapter 1?")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if answer.Support != FullySupported || answer.Text != "the answer" {
+		t.Errorf("expected a fully supported answer, got %+v", answer)
+	}
+	if len(trace.Iterations) != 1 || trace.Iterations[0].Action != "generate" {
+		t.Errorf("expected a single 'generate' iteration, got %+v", trace.Iterations)
+	}
+	if retriever.calls != 1 {
+		t.Errorf("expected a single retrieval call, got %d", retriever.calls)
+	}
+}
+
+func TestSelfRAGCritique_Critique_RewritesQueryWhenMostlyIrrelevant(t *testing.T) {
+	retriever := &stubRetriever{results: [][]repository.SearchResult{
+		{{ID: "c1", Content: "chunk one"}, {ID: "c2", Content: "chunk two"}},
+		{{ID: "c3", Content: "chunk three"}},
+	}}
+	client := &scriptedClient{resps: []string{
+		"irrelevant", "irrelevant", // first filterRelevant pass: both dropped
+		"rewritten query", // rewriteQuery
+		"irrelevant", "irrelevant", "relevant", // filterRelevant pass over all 3 candidates
+		"the answer",      // generate
+		"fully_supported", // EvaluateGeneration
+	}}
+	router := &taskRouter{byTask: map[repository.TaskType]repository.LLMClient{
+		"agentic_reasoning": client,
+		"query_rewrite":     client,
+	}}
+
+	critique := NewSelfRAGCritique(router).WithRetriever(retriever)
+	answer, trace, err := critique.Critique(context.Background(), "obscure question")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if answer.Support != FullySupported {
+		t.Errorf("expected FullySupported, got %s", answer.Support)
+	}
+	if retriever.calls != 2 {
+		t.Errorf("expected retrieval to run again after the rewrite, got %d calls", retriever.calls)
+	}
+	if len(trace.Iterations) != 1 {
+		t.Fatalf("expected a single iteration, got %+v", trace.Iterations)
+	}
+	if !trace.Iterations[0].QueryRewritten || trace.Iterations[0].RewrittenQuery != "rewritten query" {
+		t.Errorf("expected the first iteration to record the rewrite, got %+v", trace.Iterations[0])
+	}
+}
+
+func TestSelfRAGCritique_Critique_NoSupport_FallsBackToWebSearch(t *testing.T) {
+	retriever := &stubRetriever{results: [][]repository.SearchResult{
+		{{ID: "c1", Content: "chunk one"}},
+	}}
+	client := &scriptedClient{resps: []string{
+		"relevant",    // filterRelevant
+		"weak answer", // generate
+		"no_support",  // EvaluateGeneration, round 1
+		"better answer",
+		"fully_supported", // EvaluateGeneration, round 2
+	}}
+	router := &taskRouter{byTask: map[repository.TaskType]repository.LLMClient{
+		"agentic_reasoning": client,
+	}}
+	webSearch := &stubWebSearch{results: []repository.SearchResult{{ID: "w1", Content: "web result", Source: "web"}}}
+
+	critique := NewSelfRAGCritique(router).WithRetriever(retriever).WithWebSearch(webSearch)
+	answer, trace, err := critique.Critique(context.Background(), "obscure question")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !webSearch.called {
+		t.Error("expected the web fallback to be invoked after a NoSupport verdict")
+	}
+	if answer.Support != FullySupported || answer.Text != "better answer" {
+		t.Errorf("expected the regenerated answer to be fully supported, got %+v", answer)
+	}
+	if len(trace.Iterations) != 2 || trace.Iterations[1].Action != "web_fallback" {
+		t.Errorf("expected the second iteration to record the web fallback, got %+v", trace.Iterations)
+	}
+}
+
+func TestSelfRAGCritique_Critique_PartiallySupported_RegeneratesFromTopKChunks(t *testing.T) {
+	retriever := &stubRetriever{results: [][]repository.SearchResult{
+		{{ID: "c1", Content: "chunk one", Score: 0.9}},
+	}}
+	client := &scriptedClient{resps: []string{
+		"relevant",            // filterRelevant
+		"overreaching answer", // generate
+		"partially_supported", // EvaluateGeneration, round 1
+		"trimmed answer",      // generate, round 2
+		"fully_supported",     // EvaluateGeneration, round 2
+	}}
+	router := &taskRouter{byTask: map[repository.TaskType]repository.LLMClient{
+		"agentic_reasoning": client,
+	}}
+
+	critique := NewSelfRAGCritique(router).WithRetriever(retriever)
+	answer, trace, err := critique.Critique(context.Background(), "question")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if answer.Text != "trimmed answer" || answer.Support != FullySupported {
+		t.Errorf("expected the trimmed, fully supported answer, got %+v", answer)
+	}
+	if len(trace.Iterations) != 2 || trace.Iterations[1].Action != "regenerate_top_k" {
+		t.Errorf("expected the second iteration to record a top-k regeneration, got %+v", trace.Iterations)
+	}
+}
+
+func TestSelfRAGCritique_Critique_NoRetriever_Errors(t *testing.T) {
+	router := &taskRouter{byTask: map[repository.TaskType]repository.LLMClient{}}
+	critique := NewSelfRAGCritique(router)
+
+	if _, _, err := critique.Critique(context.Background(), "question"); err == nil {
+		t.Fatal("expected an error when no retriever is configured")
+	}
+}
@@ -0,0 +1,75 @@
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/booksage/booksage-api/internal/domain/repository"
+)
+
+// structuredStubClient is an LLMClient that reports structured-output
+// support and returns a canned JSON payload from GenerateStructured,
+// exercising the schema-constrained path in ReflectRetrieval/ReflectGeneration.
+type structuredStubClient struct {
+	structuredResp json.RawMessage
+	structuredErr  error
+}
+
+func (s *structuredStubClient) Generate(ctx context.Context, prompt string) (string, error) {
+	return "", nil
+}
+func (s *structuredStubClient) Name() string                   { return "structured-stub" }
+func (s *structuredStubClient) SupportsStructuredOutput() bool { return true }
+func (s *structuredStubClient) GenerateStructured(ctx context.Context, prompt string, schema json.RawMessage) ([]byte, error) {
+	return s.structuredResp, s.structuredErr
+}
+
+func TestSelfRAGCritique_ReflectRetrieval_StructuredPath(t *testing.T) {
+	client := &structuredStubClient{structuredResp: json.RawMessage(`{"relevant": true, "confidence": 0.82, "reason": "mentions chapter 1", "span_start": 10, "span_end": 42}`)}
+	critique := NewSelfRAGCritique(&mockTaskRouter{client: client})
+
+	reflection := critique.ReflectRetrieval(context.Background(), "query", "context")
+
+	if !reflection.Relevant || reflection.Confidence != 0.82 || reflection.SpanStart != 10 || reflection.SpanEnd != 42 {
+		t.Errorf("expected a structured reflection, got %+v", reflection)
+	}
+}
+
+func TestSelfRAGCritique_ReflectRetrieval_InvalidConfidenceFallsBackToPlainWord(t *testing.T) {
+	client := &structuredStubClient{structuredResp: json.RawMessage(`{"relevant": true, "confidence": 4.2}`)}
+	critique := NewSelfRAGCritique(&mockTaskRouter{client: client})
+
+	reflection := critique.ReflectRetrieval(context.Background(), "query", "context")
+
+	if reflection.Confidence != 0.5 || reflection.SpanStart != -1 || reflection.SpanEnd != -1 {
+		t.Errorf("expected the plain-word fallback after schema validation failure, got %+v", reflection)
+	}
+}
+
+func TestSelfRAGCritique_ReflectGeneration_StructuredPath(t *testing.T) {
+	client := &structuredStubClient{structuredResp: json.RawMessage(`{"support": "partially_supported", "confidence": 0.55, "reason": "second claim unsupported"}`)}
+	critique := NewSelfRAGCritique(&mockTaskRouter{client: client})
+
+	reflection := critique.ReflectGeneration(context.Background(), "answer", "context")
+
+	if reflection.Support != Partially || reflection.Confidence != 0.55 {
+		t.Errorf("expected a structured partially-supported reflection, got %+v", reflection)
+	}
+	if reflection.SpanStart != -1 || reflection.SpanEnd != -1 {
+		t.Errorf("expected no cited span when the model didn't provide one, got %+v", reflection)
+	}
+}
+
+func TestSelfRAGCritique_ReflectGeneration_UnrecognizedSupportFallsBackToPlainWord(t *testing.T) {
+	client := &structuredStubClient{structuredResp: json.RawMessage(`{"support": "mostly_fine", "confidence": 0.9}`)}
+	critique := NewSelfRAGCritique(&mockTaskRouter{client: client})
+
+	reflection := critique.ReflectGeneration(context.Background(), "answer", "context")
+
+	if reflection.Support != NoSupport || reflection.Confidence != 0.5 {
+		t.Errorf("expected the plain-word fallback after an unrecognized support level, got %+v", reflection)
+	}
+}
+
+var _ repository.StructuredLLMClient = (*structuredStubClient)(nil)
@@ -0,0 +1,79 @@
+package query
+
+import (
+	"context"
+
+	"github.com/booksage/booksage-api/internal/domain/repository"
+)
+
+// stubRetriever returns a fixed (or per-call) slice of candidates.
+type stubRetriever struct {
+	calls   int
+	results [][]repository.SearchResult
+	err     error
+}
+
+func (s *stubRetriever) Retrieve(ctx context.Context, query string) ([]repository.SearchResult, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	idx := s.calls
+	s.calls++
+	if idx >= len(s.results) {
+		idx = len(s.results) - 1
+	}
+	return s.results[idx], nil
+}
+
+// taskRouter routes by TaskType so generation, critique, and query-rewrite
+// prompts can each return a different canned response.
+type taskRouter struct {
+	byTask map[repository.TaskType]repository.LLMClient
+}
+
+func (r *taskRouter) RouteLLMTask(task repository.TaskType) repository.LLMClient {
+	return r.byTask[task]
+}
+
+// scriptedClient returns successive responses from resps, repeating the last one.
+type scriptedClient struct {
+	resps []string
+	calls int
+}
+
+func (c *scriptedClient) Generate(ctx context.Context, prompt string) (string, error) {
+	i := c.calls
+	if i >= len(c.resps) {
+		i = len(c.resps) - 1
+	}
+	c.calls++
+	return c.resps[i], nil
+}
+func (c *scriptedClient) Name() string                   { return "scripted" }
+func (c *scriptedClient) SupportsStructuredOutput() bool { return false }
+
+// mockTaskRouter routes every task to the same client, for tests that only
+// care about one client's behavior regardless of TaskType.
+type mockTaskRouter struct {
+	client repository.LLMClient
+}
+
+func (r *mockTaskRouter) RouteLLMTask(task repository.TaskType) repository.LLMClient {
+	return r.client
+}
+
+// mockLLMClient always returns resp, for tests that only exercise a single
+// canned response regardless of prompt.
+type mockLLMClient struct {
+	resp string
+	err  error
+}
+
+func (c *mockLLMClient) Generate(ctx context.Context, prompt string) (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+	return c.resp, nil
+}
+func (c *mockLLMClient) Name() string                   { return "mock" }
+func (c *mockLLMClient) SupportsStructuredOutput() bool { return false }
@@ -1,42 +1,82 @@
 package main
 
 import (
+	"bookscout/internal/adapters/source/plugin"
 	"bookscout/internal/config"
 	"bookscout/internal/core/domain/models"
 	"bookscout/internal/core/domain/ports"
 	"bookscout/internal/core/service"
+	"bookscout/internal/logging"
+	"bookscout/internal/progress"
+	"bookscout/internal/resilience"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"mime/multipart"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
 	"sync"
+	"syscall"
 	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// tracer instruments the batch ingestion run; its child spans are
+// propagated to the API server via the outbound /ingest request headers so
+// the saga it starts continues the same trace.
+var tracer = otel.Tracer("bookscout/worker")
+
 func main() {
+	logger := logging.New()
 	cfg := config.GetConfig()
-	source := service.CreateBookSource(cfg)
+	source, err := service.CreateBookSource(cfg)
+	if err != nil {
+		logger.Error("failed to create book source", "error", err)
+		os.Exit(1)
+	}
+	defer plugin.CleanupClients()
+
+	reporter, err := progress.NewReporter(cfg)
+	if err != nil {
+		logger.Error("failed to create progress reporter", "error", err)
+		os.Exit(1)
+	}
+
+	// abortCtx is cancelled on SIGINT/SIGTERM; the dispatch loop checks it
+	// before launching each new book's goroutine, but work already in
+	// flight keeps using ctx (below) so it isn't cancelled mid-download.
+	abortCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
 	defer cancel()
 
-	if err := Run(ctx, cfg, source); err != nil {
-		log.Fatalf("Worker failed: %v", err)
+	if err := Run(ctx, abortCtx, cfg, source, logger, reporter); err != nil {
+		logger.Error("worker failed", "error", err)
+		os.Exit(1)
 	}
 }
 
-// Run executes the worker batch ingestion. Exposed for testing.
-func Run(ctx context.Context, cfg *config.Config, source ports.BookDataSource) error {
+// Run executes the worker batch ingestion. abortCtx, when cancelled, stops
+// the dispatch loop from launching new per-book goroutines; books already
+// in flight run to completion on ctx. Exposed for testing.
+func Run(ctx context.Context, abortCtx context.Context, cfg *config.Config, source ports.BookDataSource, logger hclog.Logger, reporter progress.Reporter) error {
 
 	// Determine since timestamp
 	since := cfg.WorkerSinceTimestamp
 	sinceTime := time.Unix(since, 0)
-	log.Printf("Starting Go Worker (Batch Execution, since %d [%s])...", since, sinceTime.Format(time.RFC3339))
-	log.Printf("Debug Config: WorkerSinceTimestamp=%d, WorkerBatchSize=%d", cfg.WorkerSinceTimestamp, cfg.WorkerBatchSize)
+	logger.Info("starting batch ingestion run", "since", since, "since_time", sinceTime.Format(time.RFC3339))
+	logger.Debug("worker config", "since_timestamp", cfg.WorkerSinceTimestamp, "batch_size", cfg.WorkerBatchSize)
 
 	var cancel context.CancelFunc
 	ctx, cancel = context.WithTimeout(ctx, 30*time.Minute)
@@ -48,62 +88,170 @@ func Run(ctx context.Context, cfg *config.Config, source ports.BookDataSource) e
 	}
 
 	if len(books) == 0 {
-		log.Println("No new books found. Exiting.")
+		logger.Info("no new books found, exiting")
+		reporter.Start(0)
+		reporter.Finish(progress.Snapshot{}, false)
 		return nil
 	}
 
 	if cfg.WorkerBatchSize > 0 && len(books) > cfg.WorkerBatchSize {
-		log.Printf("Limiting ingestion to first %d books (found %d total)", cfg.WorkerBatchSize, len(books))
+		logger.Info("limiting ingestion to batch size", "batch_size", cfg.WorkerBatchSize, "total_found", len(books))
 		books = books[:cfg.WorkerBatchSize]
 	}
 
-	log.Printf("Processing %d books. Starting concurrent ingestion...", len(books))
+	logger.Info("processing books, starting concurrent ingestion", "book_count", len(books))
+	reporter.Start(len(books))
+
+	api := newAPIClient(cfg, logger)
 
 	concurrency := cfg.WorkerConcurrency
-	log.Printf("Concurrency limit set to: %d", concurrency)
+	logger.Debug("concurrency limit set", "concurrency", concurrency)
 
 	sem := make(chan struct{}, concurrency)
 	var wg sync.WaitGroup
+
+	var mu sync.Mutex
+	snapshot := progress.Snapshot{Total: len(books)}
+	record := func(outcome string) {
+		mu.Lock()
+		switch outcome {
+		case "processed":
+			snapshot.Processed++
+		case "skipped":
+			snapshot.Skipped++
+		case "failed":
+			snapshot.Failed++
+		}
+		s := snapshot
+		mu.Unlock()
+		reporter.Update(s)
+	}
+
+	aborted := false
+	dispatched := 0
 	for _, book := range books {
+		select {
+		case <-abortCtx.Done():
+			aborted = true
+		default:
+		}
+		if aborted {
+			break
+		}
+		dispatched++
+
 		wg.Add(1)
 		sem <- struct{}{} // Acquire semaphore
 		go func(b models.BookMetadata) {
 			defer wg.Done()
 			defer func() { <-sem }() // Release semaphore
-			log.Printf("Processing: %s", b.Title)
 
-			content, err := source.DownloadBookContent(ctx, b)
+			bookCtx, span := tracer.Start(ctx, "worker.process_book", trace.WithAttributes(
+				attribute.String("book.id", b.ID),
+				attribute.String("book.title", b.Title),
+			))
+			defer span.End()
+
+			logger.Info("processing book", "doc_id", b.ID, "title", b.Title)
+
+			content, err := source.DownloadBookContent(bookCtx, b)
 			if err != nil {
-				log.Printf("Error downloading book %s: %v", b.ID, err)
+				logger.Error("error downloading book", "doc_id", b.ID, "error", err)
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				record("failed")
 				return
 			}
 
 			// Check if already registered
-			registered, err := isRegistered(cfg.APIBaseURL, b.ID)
+			registered, err := api.isRegistered(bookCtx, b.ID)
 			if err != nil {
-				log.Printf("Warning: Failed to check registration for %s: %v", b.ID, err)
+				logger.Warn("failed to check registration", "doc_id", b.ID, "error", err)
 			}
 			if registered {
-				log.Printf("Skipping already registered book: %s", b.Title)
+				logger.Info("skipping already registered book", "doc_id", b.ID, "title", b.Title)
+				record("skipped")
 				return
 			}
 
-			if err := ingestToAPI(cfg.APIBaseURL, b, content); err != nil {
-				log.Printf("Error ingesting book %s to API: %v", b.ID, err)
+			if err := api.ingest(bookCtx, b, content); err != nil {
+				logger.Error("error ingesting book to api", "doc_id", b.ID, "error", err)
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				record("failed")
 				return
 			}
-			log.Printf("Successfully queued for ingestion: %s", b.Title)
+			logger.Info("successfully queued for ingestion", "doc_id", b.ID, "title", b.Title)
+			record("processed")
 		}(book)
 	}
 
 	wg.Wait()
-	log.Println("Batch ingestion complete. Exiting.")
+
+	mu.Lock()
+	final := snapshot
+	mu.Unlock()
+
+	remaining := len(books) - dispatched
+	if aborted {
+		logger.Warn("aborted by signal, not dispatching remaining books", "dispatched", dispatched, "remaining", remaining)
+	}
+	reporter.Finish(final, aborted)
+
+	if aborted {
+		return fmt.Errorf("aborted by signal: %d of %d books left undispatched", remaining, len(books))
+	}
+
+	logger.Info("batch ingestion complete, exiting")
 	return nil
 }
 
-// ingestToAPI sends book metadata and content to the API server. Exposed for testing.
-func ingestToAPI(baseURL string, book models.BookMetadata, content []byte) error {
-	url := fmt.Sprintf("%s/ingest", baseURL)
+// apiClient wraps the worker's HTTP calls to the BookSage API with a
+// Retrier+CircuitBreaker pair, mirroring llm.Router's candidate resilience:
+// a single ingest/isRegistered attempt absorbs its own retries before being
+// recorded as one success/failure against the breaker, so an isolated
+// transient error (network blip, API restart, a single 5xx) never counts
+// against the breaker, but a persistently unhealthy API trips it and fails
+// the rest of the batch fast instead of burning the run's 30-minute
+// deadline on doomed calls.
+type apiClient struct {
+	baseURL string
+	http    *http.Client
+	retrier *resilience.Retrier
+	breaker *resilience.CircuitBreaker
+}
+
+// newAPIClient builds the apiClient used for the lifetime of one Run, sized
+// from cfg's BS_API_RETRY_*/BS_API_BREAKER_* settings.
+func newAPIClient(cfg *config.Config, logger hclog.Logger) *apiClient {
+	return &apiClient{
+		baseURL: cfg.APIBaseURL,
+		http:    http.DefaultClient,
+		retrier: resilience.NewRetrier(
+			cfg.APIClientRetryMax,
+			time.Duration(cfg.APIClientRetryBaseMS)*time.Millisecond,
+			time.Duration(cfg.APIClientRetryCapMS)*time.Millisecond,
+			time.Duration(cfg.APIClientRetryBudgetMS)*time.Millisecond,
+			nil,
+		),
+		breaker: resilience.NewCircuitBreaker(
+			cfg.APIClientBreakerThreshold,
+			time.Duration(cfg.APIClientBreakerOpenMS)*time.Millisecond,
+		),
+	}
+}
+
+// ingest sends book metadata and content to the API server.
+func (a *apiClient) ingest(ctx context.Context, book models.BookMetadata, content []byte) error {
+	return a.breaker.Execute(func() error {
+		return a.retrier.Execute(ctx, func() error {
+			return a.doIngest(ctx, book, content)
+		})
+	})
+}
+
+func (a *apiClient) doIngest(ctx context.Context, book models.BookMetadata, content []byte) error {
+	url := fmt.Sprintf("%s/ingest", a.baseURL)
 
 	var b bytes.Buffer
 	w := multipart.NewWriter(&b)
@@ -130,13 +278,16 @@ func ingestToAPI(baseURL string, book models.BookMetadata, content []byte) error
 
 	w.Close()
 
-	req, err := http.NewRequest("POST", url, &b)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, &b)
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", w.FormDataContentType())
 
-	resp, err := http.DefaultClient.Do(req)
+	// Propagate the current span context so the API server's saga continues this trace.
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := a.http.Do(req)
 	if err != nil {
 		return err
 	}
@@ -144,21 +295,40 @@ func ingestToAPI(baseURL string, book models.BookMetadata, content []byte) error
 
 	if resp.StatusCode != http.StatusAccepted {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+		return &resilience.HTTPStatusError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: retryAfterFromHeader(resp.Header),
+			Err:        fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body)),
+		}
 	}
 
 	return nil
 }
 
 // isRegistered checks if the document already exists in the destination API.
-func isRegistered(baseURL string, docID string) (bool, error) {
-	url := fmt.Sprintf("%s/documents/%s", baseURL, docID)
-	req, err := http.NewRequest("HEAD", url, nil)
+func (a *apiClient) isRegistered(ctx context.Context, docID string) (bool, error) {
+	var registered bool
+	err := a.breaker.Execute(func() error {
+		return a.retrier.Execute(ctx, func() error {
+			r, err := a.doIsRegistered(ctx, docID)
+			if err != nil {
+				return err
+			}
+			registered = r
+			return nil
+		})
+	})
+	return registered, err
+}
+
+func (a *apiClient) doIsRegistered(ctx context.Context, docID string) (bool, error) {
+	url := fmt.Sprintf("%s/documents/%s", a.baseURL, docID)
+	req, err := http.NewRequestWithContext(ctx, "HEAD", url, nil)
 	if err != nil {
 		return false, err
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := a.http.Do(req)
 	if err != nil {
 		return false, err
 	}
@@ -171,5 +341,24 @@ func isRegistered(baseURL string, docID string) (bool, error) {
 		return false, nil
 	}
 
-	return false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	return false, &resilience.HTTPStatusError{
+		StatusCode: resp.StatusCode,
+		RetryAfter: retryAfterFromHeader(resp.Header),
+		Err:        fmt.Errorf("unexpected status code: %d", resp.StatusCode),
+	}
+}
+
+// retryAfterFromHeader parses a Retry-After response header (seconds, the
+// only form the API emits) into a duration, so Retrier can honor a 429/503's
+// back-pressure hint instead of applying its own backoff schedule.
+func retryAfterFromHeader(h http.Header) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	secs, err := strconv.Atoi(v)
+	if err != nil || secs < 0 {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
 }
@@ -3,12 +3,18 @@ package main
 import (
 	"bookscout/internal/config"
 	"bookscout/internal/core/domain/models"
+	"bookscout/internal/progress"
+	"bookscout/internal/resilience"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
 )
 
 // mockBookSource is a simple mock for ports.BookDataSource
@@ -96,7 +102,7 @@ func TestRun_Success(t *testing.T) {
 		content: []byte("dummy pdf content..."),
 	}
 
-	err := Run(context.Background(), cfg, mockSource)
+	err := Run(context.Background(), context.Background(), cfg, mockSource, hclog.NewNullLogger(), progress.NopReporter{})
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -113,12 +119,50 @@ func TestRun_NoBooks(t *testing.T) {
 		books: []models.BookMetadata{}, // No books
 	}
 
-	err := Run(context.Background(), cfg, mockSource)
+	err := Run(context.Background(), context.Background(), cfg, mockSource, hclog.NewNullLogger(), progress.NopReporter{})
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
 }
 
+func TestRun_AbortedBeforeDispatch(t *testing.T) {
+	cfg := &config.Config{
+		WorkerSinceTimestamp: 1700000000,
+		WorkerBatchSize:      10,
+		WorkerConcurrency:    2,
+	}
+
+	mockSource := &mockBookSource{
+		books: []models.BookMetadata{
+			{Title: "Test Book", ID: "1", Author: "Author A"},
+		},
+		content: []byte("dummy pdf content..."),
+	}
+
+	abortCtx, cancel := context.WithCancel(context.Background())
+	cancel() // simulate SIGINT/SIGTERM having already fired
+
+	err := Run(context.Background(), abortCtx, cfg, mockSource, hclog.NewNullLogger(), progress.NopReporter{})
+	if err == nil {
+		t.Fatal("expected an error reporting undispatched books, got nil")
+	}
+	if !strings.Contains(err.Error(), "aborted by signal") {
+		t.Errorf("expected abort error, got %v", err)
+	}
+}
+
+// testAPIClient builds an apiClient against baseURL with a short retry
+// budget and a single retry, so tests asserting on a failure don't sit
+// through the production 5-minute retry budget.
+func testAPIClient(baseURL string) *apiClient {
+	return &apiClient{
+		baseURL: baseURL,
+		http:    http.DefaultClient,
+		retrier: resilience.NewRetrier(1, time.Millisecond, 2*time.Millisecond, 50*time.Millisecond, nil),
+		breaker: resilience.NewCircuitBreaker(5, time.Second),
+	}
+}
+
 func TestIngestToAPI_ErrorResponses(t *testing.T) {
 	// Server returning 500 Internal Server Error
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -128,7 +172,7 @@ func TestIngestToAPI_ErrorResponses(t *testing.T) {
 	defer ts.Close()
 
 	book := models.BookMetadata{Title: "Test Book"}
-	err := ingestToAPI(ts.URL, book, []byte("data"))
+	err := testAPIClient(ts.URL).ingest(context.Background(), book, []byte("data"))
 
 	if err == nil {
 		t.Fatal("expected error, got nil")
@@ -141,9 +185,30 @@ func TestIngestToAPI_ErrorResponses(t *testing.T) {
 
 func TestIngestToAPI_InvalidURL(t *testing.T) {
 	book := models.BookMetadata{Title: "Test Book"}
-	err := ingestToAPI("http://invalid-url-that-does-not-exist", book, []byte("data"))
+	err := testAPIClient("http://invalid-url-that-does-not-exist").ingest(context.Background(), book, []byte("data"))
 
 	if err == nil {
 		t.Fatal("expected error due to invalid URL, got nil")
 	}
 }
+
+func TestIsRegistered_CircuitOpensAfterRepeatedFailures(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	client := testAPIClient(ts.URL)
+	client.breaker = resilience.NewCircuitBreaker(2, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.isRegistered(context.Background(), "doc-1"); err == nil {
+			t.Fatalf("attempt %d: expected error, got nil", i)
+		}
+	}
+
+	_, err := client.isRegistered(context.Background(), "doc-1")
+	if !errors.Is(err, resilience.ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen once the breaker trips, got %v", err)
+	}
+}
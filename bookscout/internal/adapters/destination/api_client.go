@@ -4,14 +4,19 @@ import (
 	"bookscout/internal/adapters/util"
 	"bookscout/internal/core/domain/models"
 	"bookscout/internal/core/domain/ports"
+	"bookscout/internal/resilience"
 	"bytes"
 	"context"
-	"encoding/json"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
-	"mime/multipart"
+	"mime"
 	"net/http"
+	"net/url"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -19,13 +24,32 @@ import (
 // Ensure BookSageAPIAdapter implements BookDestination
 var _ ports.BookDestination = (*BookSageAPIAdapter)(nil)
 
+// defaultUploadChunkSize is how much of the source io.Reader each PATCH
+// carries, so a book is never buffered into memory all at once.
+const defaultUploadChunkSize = 8 * 1024 * 1024
+
+// errUploadNotFound means the server has no record of an upload ID this
+// adapter's resumeStore still remembers, most likely because its janitor
+// already reclaimed an abandoned upload.
+var errUploadNotFound = errors.New("upload not found on server")
+
 type BookSageAPIAdapter struct {
-	baseURL string
-	client  *http.Client
+	baseURL     string
+	client      *http.Client
+	chunkSize   int64
+	resumeStore *resumeStore
 }
 
-// NewBookSageAPIAdapter creates a new API client for BookSage.
-func NewBookSageAPIAdapter(baseURL string) *BookSageAPIAdapter {
+// NewBookSageAPIAdapter creates a new API client for BookSage. resumeStatePath
+// is where in-flight upload IDs and offsets are persisted, so a crashed
+// worker resumes a partially-sent book instead of re-uploading it from
+// scratch.
+func NewBookSageAPIAdapter(baseURL, resumeStatePath string) (*BookSageAPIAdapter, error) {
+	store, err := newResumeStore(resumeStatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load upload resume state: %w", err)
+	}
+
 	return &BookSageAPIAdapter{
 		baseURL: strings.TrimRight(baseURL, "/"),
 		client: &http.Client{
@@ -36,65 +60,301 @@ func NewBookSageAPIAdapter(baseURL string) *BookSageAPIAdapter {
 			},
 			Timeout: 5 * time.Minute, // Allow longer timeout for large file uploads
 		},
-	}
+		chunkSize:   defaultUploadChunkSize,
+		resumeStore: store,
+	}, nil
 }
 
+// WithChunkSize overrides the default 8 MiB PATCH chunk size, mainly so
+// tests can exercise multi-chunk uploads without a multi-megabyte fixture.
+func (a *BookSageAPIAdapter) WithChunkSize(n int64) *BookSageAPIAdapter {
+	a.chunkSize = n
+	return a
+}
+
+// Send uploads book's content through BookSage's resumable ingest endpoint:
+// POST .../uploads to start (or reuse an in-progress upload), PATCH
+// .../uploads/{id} to append chunkSize-sized chunks, then PUT
+// .../uploads/{id} with the now-known content digest to commit. If a prior
+// Send for this book ID left an upload partway through, it resumes: a HEAD
+// against the server gives the authoritative offset, content is fast-
+// forwarded past that many already-acknowledged bytes (while still being
+// hashed, so the final digest covers the whole file), and only what's left
+// is PATCHed. This relies on content replaying the same bytes from the
+// start on every call, which holds since callers re-fetch it via
+// BookSource.DownloadBookContent rather than reusing a drained reader.
+//
+// If book.ID last completed an upload under some digest and the server
+// still has a document indexed under it (checked via Stat), Send returns
+// immediately without reading content at all -- covers a worker retrying a
+// book it already delivered successfully, without needing content's
+// resolved size or a fresh hash to know that.
 func (a *BookSageAPIAdapter) Send(ctx context.Context, book models.BookMetadata, content io.Reader) error {
-	// Prepare multipart request
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
+	if digest, ok := a.resumeStore.completedDigest(book.ID); ok {
+		exists, err := a.Stat(ctx, digest)
+		if err != nil {
+			return fmt.Errorf("failed to check existing document for %s: %w", book.ID, err)
+		}
+		if exists {
+			return nil
+		}
+	}
+
+	uploadID, offset, err := a.resumeOrCreateUpload(ctx, book.ID)
+	if err != nil {
+		return err
+	}
+
+	hasher := sha256.New()
+	reader := io.TeeReader(content, hasher)
+
+	if offset > 0 {
+		if _, err := io.CopyN(io.Discard, reader, offset); err != nil {
+			return fmt.Errorf("failed to skip %d already-uploaded bytes for %s: %w", offset, book.ID, err)
+		}
+	}
+
+	pos := offset
+	buffer := make([]byte, a.chunkSize)
+	for {
+		n, readErr := reader.Read(buffer)
+		if n > 0 {
+			newOffset, patchErr := a.patchChunk(ctx, uploadID, pos, buffer[:n])
+			if patchErr != nil {
+				return fmt.Errorf("failed to upload chunk at offset %d for %s: %w", pos, book.ID, patchErr)
+			}
+			pos = newOffset
+			if err := a.resumeStore.set(book.ID, uploadProgress{UploadID: uploadID, Offset: pos}); err != nil {
+				return fmt.Errorf("failed to persist upload progress for %s: %w", book.ID, err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return fmt.Errorf("failed to read content for %s: %w", book.ID, readErr)
+		}
+	}
 
-	// Add file part
-	// Infer filename from download URL or ID
 	filename := filepath.Base(book.DownloadURL)
 	if filename == "" || filename == "." {
 		filename = fmt.Sprintf("%s.epub", book.ID)
 	}
+	mediaType := mime.TypeByExtension(filepath.Ext(filename))
+	if mediaType == "" {
+		mediaType = "application/octet-stream"
+	}
+
+	digest := hasher.Sum(nil)
+	if err := a.completeUpload(ctx, uploadID, digest, filename, mediaType); err != nil {
+		return fmt.Errorf("failed to complete upload for %s: %w", book.ID, err)
+	}
+
+	if err := a.resumeStore.delete(book.ID); err != nil {
+		return fmt.Errorf("failed to clear upload progress for %s: %w", book.ID, err)
+	}
+	if err := a.resumeStore.setCompletedDigest(book.ID, hex.EncodeToString(digest)); err != nil {
+		return fmt.Errorf("failed to persist completed digest for %s: %w", book.ID, err)
+	}
+	return nil
+}
+
+// Stat reports whether the server already has a document indexed under
+// sha256 hex digest, via a HEAD against the same endpoint handleDocumentExist
+// serves (see internal/server). Send uses it to skip re-uploading a book
+// that's already durably indexed.
+func (a *BookSageAPIAdapter) Stat(ctx context.Context, digest string) (bool, error) {
+	url := fmt.Sprintf("%s/api/v1/documents/sha256:%s", a.baseURL, digest)
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to send request to %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, newHTTPStatusError(resp)
+	}
+}
+
+// resumeOrCreateUpload returns an upload ID and the offset to resume
+// PATCHing from for bookID: the in-progress upload resumeStore already
+// knows about, verified still live via HEAD, or else a freshly created one
+// starting at offset 0.
+func (a *BookSageAPIAdapter) resumeOrCreateUpload(ctx context.Context, bookID string) (string, int64, error) {
+	if progress, ok := a.resumeStore.get(bookID); ok {
+		offset, err := a.headUploadOffset(ctx, progress.UploadID)
+		if err == nil {
+			return progress.UploadID, offset, nil
+		}
+		if !errors.Is(err, errUploadNotFound) {
+			return "", 0, fmt.Errorf("failed to check upload progress for %s: %w", bookID, err)
+		}
+		// The server no longer knows this upload ID (most likely its
+		// janitor reclaimed it as abandoned); fall through and start over.
+	}
+
+	id, err := a.createUpload(ctx)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to start upload for %s: %w", bookID, err)
+	}
+	if err := a.resumeStore.set(bookID, uploadProgress{UploadID: id}); err != nil {
+		return "", 0, fmt.Errorf("failed to persist new upload state for %s: %w", bookID, err)
+	}
+	return id, 0, nil
+}
+
+func (a *BookSageAPIAdapter) createUpload(ctx context.Context) (string, error) {
+	url := fmt.Sprintf("%s/ingest/uploads", a.baseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
 
-	part, err := writer.CreateFormFile("file", filename)
+	resp, err := a.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to create form file: %w", err)
+		return "", fmt.Errorf("failed to send request to %s: %w", url, err)
 	}
+	defer resp.Body.Close()
 
-	if _, err := io.Copy(part, content); err != nil {
-		return fmt.Errorf("failed to copy file content: %w", err)
+	if resp.StatusCode != http.StatusAccepted {
+		return "", newHTTPStatusError(resp)
+	}
+
+	id := resp.Header.Get("Docker-Upload-UUID")
+	if id == "" {
+		return "", fmt.Errorf("server did not return an upload ID")
+	}
+	return id, nil
+}
+
+// headUploadOffset queries how many bytes the server has durably received
+// for uploadID, returning errUploadNotFound if the server has no record of
+// it (a 404, not wrapped in resilience.HTTPStatusError since it's not a
+// transient failure worth retrying as-is).
+func (a *BookSageAPIAdapter) headUploadOffset(ctx context.Context, uploadID string) (int64, error) {
+	url := fmt.Sprintf("%s/ingest/uploads/%s", a.baseURL, uploadID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Add metadata part
-	metadataJSON, err := json.Marshal(book)
+	resp, err := a.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to marshal metadata: %w", err)
+		return 0, fmt.Errorf("failed to send request to %s: %w", url, err)
 	}
+	defer resp.Body.Close()
 
-	if err := writer.WriteField("metadata", string(metadataJSON)); err != nil {
-		return fmt.Errorf("failed to write metadata field: %w", err)
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, errUploadNotFound
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		return 0, newHTTPStatusError(resp)
 	}
 
-	// Close writer to finalize content type
-	if err := writer.Close(); err != nil {
-		return fmt.Errorf("failed to close multipart writer: %w", err)
+	return parseRangeHeader(resp.Header.Get("Range"))
+}
+
+// patchChunk appends data to uploadID at byte offset start, returning the
+// server's acknowledged total offset afterward.
+func (a *BookSageAPIAdapter) patchChunk(ctx context.Context, uploadID string, start int64, data []byte) (int64, error) {
+	url := fmt.Sprintf("%s/ingest/uploads/%s", a.baseURL, uploadID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, bytes.NewReader(data))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
 	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Range", fmt.Sprintf("%d-%d", start, start+int64(len(data))-1))
 
-	// Create request
-	url := fmt.Sprintf("%s/ingest", a.baseURL)
-	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
+	resp, err := a.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return 0, fmt.Errorf("failed to send request to %s: %w", url, err)
 	}
+	defer resp.Body.Close()
 
-	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if resp.StatusCode != http.StatusAccepted {
+		return 0, newHTTPStatusError(resp)
+	}
+
+	return parseRangeHeader(resp.Header.Get("Range"))
+}
+
+// completeUpload commits uploadID once its full content digest is known.
+func (a *BookSageAPIAdapter) completeUpload(ctx context.Context, uploadID string, digest []byte, filename, mediaType string) error {
+	query := url.Values{}
+	query.Set("digest", "sha256:"+hex.EncodeToString(digest))
+	query.Set("filename", filename)
+	query.Set("media_type", mediaType)
+
+	reqURL := fmt.Sprintf("%s/ingest/uploads/%s?%s", a.baseURL, uploadID, query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
 
-	// Execute request
 	resp, err := a.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to send request to %s: %w", url, err)
+		return fmt.Errorf("failed to send request to %s: %w", reqURL, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API returned error status %d: %s", resp.StatusCode, string(respBody))
+		return newHTTPStatusError(resp)
 	}
-
 	return nil
 }
+
+// newHTTPStatusError builds a resilience.HTTPStatusError from a non-2xx
+// response, draining and including its body the same way the prior
+// single-shot Send did.
+func newHTTPStatusError(resp *http.Response) error {
+	respBody, _ := io.ReadAll(resp.Body)
+	return &resilience.HTTPStatusError{
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		Err:        fmt.Errorf("API returned error status %d: %s", resp.StatusCode, string(respBody)),
+	}
+}
+
+// parseRangeHeader parses the "0-<offset>" Range header the server returns
+// from a successful upload create/HEAD/PATCH, returning the offset.
+func parseRangeHeader(header string) (int64, error) {
+	if header == "" {
+		return 0, errors.New("missing Range header")
+	}
+	_, offsetStr, found := strings.Cut(header, "-")
+	if !found {
+		return 0, fmt.Errorf("malformed Range header %q", header)
+	}
+	offset, err := strconv.ParseInt(offsetStr, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed Range header %q", header)
+	}
+	return offset, nil
+}
+
+// parseRetryAfter parses the Retry-After header as either a number of
+// seconds or an HTTP-date, returning 0 if absent or unparseable.
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,309 @@
+package destination
+
+import (
+	"bookscout/internal/core/domain/models"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// stubUploadServer is a minimal in-memory implementation of BookSage's
+// resumable ingest endpoints, just enough to exercise Send's create/PATCH/
+// PUT round trip (including resuming a partially-uploaded session).
+type stubUploadServer struct {
+	mu        sync.Mutex
+	nextID    int
+	uploads   map[string]*bytes.Buffer
+	digests   map[string]string
+	documents map[string]bool // digest ("sha256:<hex>") -> indexed
+
+	patchCalls int
+	statCalls  int
+}
+
+func newStubUploadServer() *stubUploadServer {
+	return &stubUploadServer{
+		uploads:   make(map[string]*bytes.Buffer),
+		digests:   make(map[string]string),
+		documents: make(map[string]bool),
+	}
+}
+
+// seedDocument marks digest as already indexed, as if a prior upload had
+// completed under it.
+func (s *stubUploadServer) seedDocument(digest string) {
+	s.documents[digest] = true
+}
+
+// seedUpload registers an upload ID with some bytes already received, as if
+// a prior PATCH had landed before a crash.
+func (s *stubUploadServer) seedUpload(id string, received []byte) {
+	s.uploads[id] = bytes.NewBuffer(append([]byte{}, received...))
+}
+
+func (s *stubUploadServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /ingest/uploads", s.handleCreate)
+	mux.HandleFunc("HEAD /ingest/uploads/{id}", s.handleProgress)
+	mux.HandleFunc("PATCH /ingest/uploads/{id}", s.handleChunk)
+	mux.HandleFunc("PUT /ingest/uploads/{id}", s.handleComplete)
+	mux.HandleFunc("HEAD /api/v1/documents/{digest}", s.handleDocumentExist)
+	return mux
+}
+
+func (s *stubUploadServer) handleDocumentExist(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.statCalls++
+	exists := s.documents[r.PathValue("digest")]
+	s.mu.Unlock()
+
+	if !exists {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *stubUploadServer) handleCreate(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.nextID++
+	id := fmt.Sprintf("upload-%d", s.nextID)
+	s.uploads[id] = &bytes.Buffer{}
+	s.mu.Unlock()
+
+	w.Header().Set("Docker-Upload-UUID", id)
+	w.Header().Set("Range", "0-0")
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *stubUploadServer) handleProgress(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	buf, ok := s.uploads[r.PathValue("id")]
+	var offset int
+	if ok {
+		offset = buf.Len()
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Range", fmt.Sprintf("0-%d", offset))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *stubUploadServer) handleChunk(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	buf, ok := s.uploads[r.PathValue("id")]
+	s.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	s.mu.Lock()
+	buf.Write(body)
+	offset := buf.Len()
+	s.patchCalls++
+	s.mu.Unlock()
+
+	w.Header().Set("Range", fmt.Sprintf("0-%d", offset))
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *stubUploadServer) handleComplete(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	digest := r.URL.Query().Get("digest")
+
+	s.mu.Lock()
+	_, ok := s.uploads[id]
+	if ok && digest != "" {
+		s.digests[id] = digest
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if digest == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func newTestAdapter(t *testing.T, baseURL string) *BookSageAPIAdapter {
+	t.Helper()
+	statePath := fmt.Sprintf("%s/resume-state.json", t.TempDir())
+	adapter, err := NewBookSageAPIAdapter(baseURL, statePath)
+	if err != nil {
+		t.Fatalf("failed to create adapter: %v", err)
+	}
+	return adapter
+}
+
+func TestSend_UploadsInMultipleChunks(t *testing.T) {
+	server := newStubUploadServer()
+	ts := httptest.NewServer(server.handler())
+	defer ts.Close()
+
+	adapter := newTestAdapter(t, ts.URL).WithChunkSize(4)
+
+	content := "hello world" // 11 bytes, so 4+4+3 => 3 chunks
+	book := models.BookMetadata{ID: "book-1", DownloadURL: "http://example.com/book.epub"}
+
+	if err := adapter.Send(context.Background(), book, strings.NewReader(content)); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if server.patchCalls != 3 {
+		t.Errorf("expected 3 PATCH calls, got %d", server.patchCalls)
+	}
+	if _, ok := adapter.resumeStore.get(book.ID); ok {
+		t.Error("expected resume state to be cleared after a completed upload")
+	}
+}
+
+func TestSend_ResumesFromRecordedOffset(t *testing.T) {
+	server := newStubUploadServer()
+	ts := httptest.NewServer(server.handler())
+	defer ts.Close()
+
+	content := "hello world"
+	alreadySent := []byte(content[:4])
+	server.seedUpload("upload-1", alreadySent)
+
+	adapter := newTestAdapter(t, ts.URL).WithChunkSize(4)
+	book := models.BookMetadata{ID: "book-2", DownloadURL: "http://example.com/book.epub"}
+	if err := adapter.resumeStore.set(book.ID, uploadProgress{UploadID: "upload-1", Offset: int64(len(alreadySent))}); err != nil {
+		t.Fatalf("failed to seed resume state: %v", err)
+	}
+
+	if err := adapter.Send(context.Background(), book, strings.NewReader(content)); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	got := server.uploads["upload-1"].Bytes()
+	if string(got) != content {
+		t.Errorf("expected server to have assembled %q, got %q", content, string(got))
+	}
+	// Only the remaining 7 bytes should have been PATCHed, not the 4
+	// already-acknowledged ones.
+	if server.patchCalls != 2 {
+		t.Errorf("expected 2 PATCH calls for the remaining bytes, got %d", server.patchCalls)
+	}
+}
+
+func TestSend_RestartsAfterServerForgetsUpload(t *testing.T) {
+	server := newStubUploadServer()
+	ts := httptest.NewServer(server.handler())
+	defer ts.Close()
+
+	adapter := newTestAdapter(t, ts.URL)
+	book := models.BookMetadata{ID: "book-3", DownloadURL: "http://example.com/book.epub"}
+	// Pretend a prior run recorded an upload ID the server's janitor has
+	// since reclaimed.
+	if err := adapter.resumeStore.set(book.ID, uploadProgress{UploadID: "stale-upload", Offset: 5}); err != nil {
+		t.Fatalf("failed to seed resume state: %v", err)
+	}
+
+	content := "fresh content"
+	if err := adapter.Send(context.Background(), book, strings.NewReader(content)); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if _, ok := server.uploads["stale-upload"]; ok {
+		t.Error("did not expect the stale upload ID to be reused")
+	}
+}
+
+func TestSend_DigestCoversFullContentAcrossResume(t *testing.T) {
+	server := newStubUploadServer()
+	ts := httptest.NewServer(server.handler())
+	defer ts.Close()
+
+	content := "hello world"
+	alreadySent := []byte(content[:4])
+	server.seedUpload("upload-9", alreadySent)
+
+	adapter := newTestAdapter(t, ts.URL).WithChunkSize(4)
+	book := models.BookMetadata{ID: "book-4"}
+	if err := adapter.resumeStore.set(book.ID, uploadProgress{UploadID: "upload-9", Offset: int64(len(alreadySent))}); err != nil {
+		t.Fatalf("failed to seed resume state: %v", err)
+	}
+
+	if err := adapter.Send(context.Background(), book, strings.NewReader(content)); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	want := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(content)))
+	if got := server.digests["upload-9"]; got != want {
+		t.Errorf("expected digest to cover the full content (including the skipped prefix), got %q want %q", got, want)
+	}
+}
+
+func TestSend_SkipsReuploadWhenServerAlreadyHasDigest(t *testing.T) {
+	server := newStubUploadServer()
+	ts := httptest.NewServer(server.handler())
+	defer ts.Close()
+
+	content := "hello world"
+	digestHex := fmt.Sprintf("%x", sha256.Sum256([]byte(content)))
+	server.seedDocument("sha256:" + digestHex)
+
+	adapter := newTestAdapter(t, ts.URL)
+	book := models.BookMetadata{ID: "book-5", DownloadURL: "http://example.com/book.epub"}
+	if err := adapter.resumeStore.setCompletedDigest(book.ID, digestHex); err != nil {
+		t.Fatalf("failed to seed completed digest: %v", err)
+	}
+
+	if err := adapter.Send(context.Background(), book, strings.NewReader(content)); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if server.statCalls != 1 {
+		t.Errorf("expected exactly 1 Stat call, got %d", server.statCalls)
+	}
+	if server.patchCalls != 0 {
+		t.Errorf("expected no PATCH calls, since the server already had this content, got %d", server.patchCalls)
+	}
+}
+
+func TestSend_ReuploadsWhenServerNoLongerHasRecordedDigest(t *testing.T) {
+	server := newStubUploadServer()
+	ts := httptest.NewServer(server.handler())
+	defer ts.Close()
+
+	adapter := newTestAdapter(t, ts.URL)
+	book := models.BookMetadata{ID: "book-6", DownloadURL: "http://example.com/book.epub"}
+	// A prior Send recorded this digest, but the server has since lost the
+	// document (e.g. it was deleted), so Send must fall back to a full
+	// upload rather than silently skipping it.
+	if err := adapter.resumeStore.setCompletedDigest(book.ID, "deadbeef"); err != nil {
+		t.Fatalf("failed to seed completed digest: %v", err)
+	}
+
+	content := "hello again"
+	if err := adapter.Send(context.Background(), book, strings.NewReader(content)); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if server.patchCalls == 0 {
+		t.Error("expected Send to fall back to a full upload when the server no longer has the recorded digest")
+	}
+}
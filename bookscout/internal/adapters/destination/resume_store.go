@@ -0,0 +1,154 @@
+package destination
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// uploadProgress tracks a single book's in-flight resumable upload: the
+// opaque ID the server assigned it and how many bytes it has durably
+// acknowledged, so a crashed worker can resume with a HEAD request instead
+// of re-uploading the whole book from scratch.
+type uploadProgress struct {
+	UploadID string `json:"upload_id"`
+	Offset   int64  `json:"offset"`
+}
+
+// resumeStoreData is resumeStore's on-disk shape: in-flight upload progress
+// alongside the last digest each book was successfully committed under, so
+// Send can skip re-uploading a book the server still has (see
+// BookSageAPIAdapter.Stat) without needing to re-read its content just to
+// find that out. Data from before this digest field existed decodes to an
+// empty InProgress/Completed pair rather than an error -- the affected
+// uploads just resume from scratch instead of from their last offset, which
+// is the same fallback a reclaimed-upload 404 already produces.
+type resumeStoreData struct {
+	InProgress map[string]uploadProgress `json:"in_progress"`
+	Completed  map[string]string         `json:"completed"` // bookID -> last committed sha256 hex digest
+}
+
+// resumeStore is a local, JSON-file-backed record of in-flight resumable
+// uploads, keyed by book ID. It mirrors tracker.FileStateStore's
+// load/atomic-save shape, but tracks a different concern: not which books
+// have already been fully processed, but where a partially-sent upload for
+// one left off, plus (once one completes) the digest it finished under.
+type resumeStore struct {
+	path string
+	mu   sync.Mutex
+	data resumeStoreData
+}
+
+// newResumeStore loads a resume store from path, creating an empty one if
+// the file doesn't exist yet.
+func newResumeStore(path string) (*resumeStore, error) {
+	store := &resumeStore{path: path, data: resumeStoreData{
+		InProgress: make(map[string]uploadProgress),
+		Completed:  make(map[string]string),
+	}}
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *resumeStore) load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	decoder := json.NewDecoder(f)
+	if err := decoder.Decode(&s.data); err != nil {
+		if err == io.EOF {
+			return nil // Empty file is fine
+		}
+		return err
+	}
+	if s.data.InProgress == nil {
+		s.data.InProgress = make(map[string]uploadProgress)
+	}
+	if s.data.Completed == nil {
+		s.data.Completed = make(map[string]string)
+	}
+	return nil
+}
+
+// get returns the recorded progress for bookID, if any.
+func (s *resumeStore) get(bookID string) (uploadProgress, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.data.InProgress[bookID]
+	return p, ok
+}
+
+// set records progress for bookID and persists it immediately, so a crash
+// right after this call still leaves the upload ID and offset recoverable.
+func (s *resumeStore) set(bookID string, progress uploadProgress) error {
+	s.mu.Lock()
+	s.data.InProgress[bookID] = progress
+	s.mu.Unlock()
+	return s.save()
+}
+
+// delete forgets bookID's in-flight upload, once it's been completed.
+func (s *resumeStore) delete(bookID string) error {
+	s.mu.Lock()
+	delete(s.data.InProgress, bookID)
+	s.mu.Unlock()
+	return s.save()
+}
+
+// completedDigest returns the sha256 hex digest bookID last completed an
+// upload under, if any.
+func (s *resumeStore) completedDigest(bookID string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	digest, ok := s.data.Completed[bookID]
+	return digest, ok
+}
+
+// setCompletedDigest records the sha256 hex digest bookID just completed an
+// upload under, persisting it immediately so a later Send for the same book
+// can skip re-uploading identical content via Stat.
+func (s *resumeStore) setCompletedDigest(bookID, digest string) error {
+	s.mu.Lock()
+	s.data.Completed[bookID] = digest
+	s.mu.Unlock()
+	return s.save()
+}
+
+func (s *resumeStore) save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Atomic write: write to temp file then rename
+	tmpFile := s.path + ".tmp"
+	f, err := os.Create(tmpFile)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(s.data); err != nil {
+		f.Close()
+		return err
+	}
+	f.Close()
+
+	return os.Rename(tmpFile, s.path)
+}
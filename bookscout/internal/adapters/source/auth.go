@@ -0,0 +1,179 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Authenticator applies credentials to an outbound HTTP request.
+type Authenticator interface {
+	Apply(req *http.Request) error
+}
+
+// BasicAuth applies HTTP Basic authentication.
+type BasicAuth struct {
+	User string
+	Pass string
+}
+
+func (a *BasicAuth) Apply(req *http.Request) error {
+	req.SetBasicAuth(a.User, a.Pass)
+	return nil
+}
+
+// BearerToken applies a static bearer token, e.g. a long-lived API key.
+type BearerToken struct {
+	Token string
+}
+
+func (b *BearerToken) Apply(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+b.Token)
+	return nil
+}
+
+// oidcTokenRefreshMargin is how long before expiry a cached access token is
+// treated as stale, so an in-flight request doesn't race the real expiry.
+const oidcTokenRefreshMargin = 30 * time.Second
+
+// OIDCClientCredentials authenticates via the OAuth2 client_credentials
+// grant against an OIDC provider, discovering the token endpoint from the
+// issuer's well-known document and caching the access token until shortly
+// before it expires.
+type OIDCClientCredentials struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+
+	// Client is the HTTP client used for discovery and token requests.
+	// Defaults to http.DefaultClient.
+	Client *http.Client
+
+	mu          sync.Mutex
+	tokenURL    string
+	accessToken string
+	expiresAt   time.Time
+}
+
+func (o *OIDCClientCredentials) Apply(req *http.Request) error {
+	token, err := o.token(req.Context())
+	if err != nil {
+		return fmt.Errorf("OIDC client credentials auth: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+func (o *OIDCClientCredentials) token(ctx context.Context) (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.accessToken != "" && time.Now().Before(o.expiresAt.Add(-oidcTokenRefreshMargin)) {
+		return o.accessToken, nil
+	}
+
+	if o.tokenURL == "" {
+		tokenURL, err := o.discoverTokenEndpoint(ctx)
+		if err != nil {
+			return "", err
+		}
+		o.tokenURL = tokenURL
+	}
+
+	token, expiresIn, err := o.fetchToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	o.accessToken = token
+	o.expiresAt = time.Now().Add(expiresIn)
+	return o.accessToken, nil
+}
+
+func (o *OIDCClientCredentials) httpClient() *http.Client {
+	if o.Client != nil {
+		return o.Client
+	}
+	return http.DefaultClient
+}
+
+func (o *OIDCClientCredentials) discoverTokenEndpoint(ctx context.Context) (string, error) {
+	discoveryURL := strings.TrimRight(o.Issuer, "/") + "/.well-known/openid-configuration"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := o.httpClient().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetching OIDC discovery document from %s: %w", discoveryURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("OIDC discovery document returned status %d", resp.StatusCode)
+	}
+
+	var doc struct {
+		TokenEndpoint string `json:"token_endpoint"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return "", fmt.Errorf("decoding OIDC discovery document: %w", err)
+	}
+	if doc.TokenEndpoint == "" {
+		return "", fmt.Errorf("OIDC discovery document has no token_endpoint")
+	}
+
+	return doc.TokenEndpoint, nil
+}
+
+func (o *OIDCClientCredentials) fetchToken(ctx context.Context) (string, time.Duration, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", o.ClientID)
+	form.Set("client_secret", o.ClientSecret)
+	if len(o.Scopes) > 0 {
+		form.Set("scope", strings.Join(o.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := o.httpClient().Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("requesting token from %s: %w", o.tokenURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("token endpoint returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", 0, fmt.Errorf("decoding token response: %w", err)
+	}
+	if body.AccessToken == "" {
+		return "", 0, fmt.Errorf("token response has no access_token")
+	}
+
+	expiresIn := time.Duration(body.ExpiresIn) * time.Second
+	if expiresIn <= 0 {
+		expiresIn = 5 * time.Minute
+	}
+
+	return body.AccessToken, expiresIn, nil
+}
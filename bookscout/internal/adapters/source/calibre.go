@@ -0,0 +1,149 @@
+package source
+
+import (
+	"bookscout/internal/core/domain/models"
+	"bookscout/internal/core/domain/ports"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var _ ports.BookDataSource = (*CalibreAdapter)(nil)
+
+// CalibreAdapter fetches books from a Calibre content server's JSON API.
+type CalibreAdapter struct {
+	baseURL   string
+	libraryID string
+	username  string
+	password  string
+	client    *http.Client
+	maxSize   int64
+}
+
+func NewCalibreAdapter(baseURL, libraryID, username, password string, maxSize int64) *CalibreAdapter {
+	return &CalibreAdapter{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		libraryID: libraryID,
+		username:  username,
+		password:  password,
+		client:    &http.Client{Timeout: 5 * time.Minute},
+		maxSize:   maxSize,
+	}
+}
+
+// calibreBookList is keyed by book ID, as returned by /ajax/books.
+type calibreBookList map[string]calibreBook
+
+type calibreBook struct {
+	Title        string   `json:"title"`
+	Authors      []string `json:"authors"`
+	Comments     string   `json:"comments"`
+	LastModified string   `json:"last_modified"`
+}
+
+func (a *CalibreAdapter) FetchNewBooks(ctx context.Context, lastCheckTimestamp int64) ([]models.BookMetadata, error) {
+	if a.baseURL == "" {
+		return nil, fmt.Errorf("calibre base URL is not configured")
+	}
+
+	apiURL := fmt.Sprintf("%s/ajax/books?library_id=%s", a.baseURL, url.QueryEscape(a.libraryID))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	a.applyAuth(req)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch calibre book list: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("calibre API returned status %d", resp.StatusCode)
+	}
+
+	var list calibreBookList
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to decode calibre book list: %w", err)
+	}
+
+	var books []models.BookMetadata
+	for id, b := range list {
+		addedAt, _ := time.Parse(time.RFC3339, b.LastModified)
+		if !addedAt.IsZero() && addedAt.Unix() <= lastCheckTimestamp {
+			continue
+		}
+		if addedAt.IsZero() {
+			addedAt = time.Now()
+		}
+
+		author := "Unknown"
+		if len(b.Authors) > 0 {
+			author = strings.Join(b.Authors, ", ")
+		}
+
+		books = append(books, models.BookMetadata{
+			ID:           id,
+			Title:        b.Title,
+			Author:       author,
+			Description:  b.Comments,
+			DownloadURL:  a.downloadURL(id),
+			ThumbnailURL: a.coverURL(id),
+			Source:       "calibre",
+			AddedAt:      addedAt,
+		})
+	}
+
+	return books, nil
+}
+
+func (a *CalibreAdapter) downloadURL(id string) string {
+	return fmt.Sprintf("%s/get/EPUB/%s/%s", a.baseURL, id, a.libraryID)
+}
+
+func (a *CalibreAdapter) coverURL(id string) string {
+	return fmt.Sprintf("%s/get/thumb/%s/%s", a.baseURL, id, a.libraryID)
+}
+
+func (a *CalibreAdapter) applyAuth(req *http.Request) {
+	if a.username != "" {
+		req.SetBasicAuth(a.username, a.password)
+	}
+}
+
+func (a *CalibreAdapter) DownloadBookContent(ctx context.Context, book models.BookMetadata) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, book.DownloadURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	a.applyAuth(req)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download book from calibre: %d", resp.StatusCode)
+	}
+
+	limitReader := io.LimitReader(resp.Body, a.maxSize+1)
+	data, err := io.ReadAll(limitReader)
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > a.maxSize {
+		return nil, fmt.Errorf("book content exceeds maximum allowed size")
+	}
+
+	return data, nil
+}
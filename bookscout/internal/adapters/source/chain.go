@@ -0,0 +1,92 @@
+package source
+
+import (
+	"bookscout/internal/core/domain/models"
+	"bookscout/internal/core/domain/ports"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var _ ports.BookDataSource = (*ChainAdapter)(nil)
+
+// ChainAdapter fans FetchNewBooks out across several BookDataSources and
+// dedupes the combined results by content hash, so a user subscribed to
+// more than one library at once doesn't get the same book ingested twice.
+type ChainAdapter struct {
+	sources []ports.BookDataSource
+
+	mu    sync.Mutex
+	owner map[string]ports.BookDataSource
+}
+
+func NewChainAdapter(sources ...ports.BookDataSource) *ChainAdapter {
+	return &ChainAdapter{
+		sources: sources,
+		owner:   make(map[string]ports.BookDataSource),
+	}
+}
+
+type chainFetchResult struct {
+	src   ports.BookDataSource
+	books []models.BookMetadata
+	err   error
+}
+
+func (a *ChainAdapter) FetchNewBooks(ctx context.Context, lastCheckTimestamp int64) ([]models.BookMetadata, error) {
+	results := make([]chainFetchResult, len(a.sources))
+
+	var wg sync.WaitGroup
+	for i, src := range a.sources {
+		wg.Add(1)
+		go func(i int, src ports.BookDataSource) {
+			defer wg.Done()
+			books, err := src.FetchNewBooks(ctx, lastCheckTimestamp)
+			results[i] = chainFetchResult{src: src, books: books, err: err}
+		}(i, src)
+	}
+	wg.Wait()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var all []models.BookMetadata
+	seen := make(map[string]struct{})
+	for i, r := range results {
+		if r.err != nil {
+			return nil, fmt.Errorf("chain source %d: %w", i, r.err)
+		}
+		for _, b := range r.books {
+			key := dedupeKey(b)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			a.owner[b.ID] = r.src
+			all = append(all, b)
+		}
+	}
+
+	return all, nil
+}
+
+func (a *ChainAdapter) DownloadBookContent(ctx context.Context, book models.BookMetadata) ([]byte, error) {
+	a.mu.Lock()
+	src, ok := a.owner[book.ID]
+	a.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("chain adapter: unknown book ID %q (FetchNewBooks must run first)", book.ID)
+	}
+	return src.DownloadBookContent(ctx, book)
+}
+
+// dedupeKey identifies a book by its title/author rather than by the
+// source-specific ID, so the same book surfaced by two different catalogs
+// is only counted once.
+func dedupeKey(b models.BookMetadata) string {
+	h := sha256.Sum256([]byte(strings.ToLower(b.Title) + "|" + strings.ToLower(b.Author)))
+	return hex.EncodeToString(h[:])
+}
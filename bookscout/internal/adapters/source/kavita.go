@@ -0,0 +1,129 @@
+package source
+
+import (
+	"bookscout/internal/core/domain/models"
+	"bookscout/internal/core/domain/ports"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var _ ports.BookDataSource = (*KavitaAdapter)(nil)
+
+// KavitaAdapter fetches recently added volumes from a Kavita server's REST
+// API, authenticating with a bearer API token.
+type KavitaAdapter struct {
+	baseURL  string
+	apiToken string
+	client   *http.Client
+	maxSize  int64
+}
+
+func NewKavitaAdapter(baseURL, apiToken string, maxSize int64) *KavitaAdapter {
+	return &KavitaAdapter{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		apiToken: apiToken,
+		client:   &http.Client{Timeout: 5 * time.Minute},
+		maxSize:  maxSize,
+	}
+}
+
+type kavitaVolume struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Summary string `json:"summary"`
+	Created string `json:"created"`
+}
+
+func (a *KavitaAdapter) FetchNewBooks(ctx context.Context, lastCheckTimestamp int64) ([]models.BookMetadata, error) {
+	if a.baseURL == "" {
+		return nil, fmt.Errorf("kavita base URL is not configured")
+	}
+
+	apiURL := fmt.Sprintf("%s/api/Library/recently-added-v2", a.baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	a.applyAuth(req)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch kavita volume list: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("kavita API returned status %d", resp.StatusCode)
+	}
+
+	var volumes []kavitaVolume
+	if err := json.NewDecoder(resp.Body).Decode(&volumes); err != nil {
+		return nil, fmt.Errorf("failed to decode kavita volume list: %w", err)
+	}
+
+	var books []models.BookMetadata
+	for _, v := range volumes {
+		createdAt, _ := time.Parse(time.RFC3339, v.Created)
+		if !createdAt.IsZero() && createdAt.Unix() <= lastCheckTimestamp {
+			continue
+		}
+		if createdAt.IsZero() {
+			createdAt = time.Now()
+		}
+
+		books = append(books, models.BookMetadata{
+			ID:          strconv.Itoa(v.ID),
+			Title:       v.Name,
+			Author:      "Unknown",
+			Description: v.Summary,
+			DownloadURL: fmt.Sprintf("%s/api/Download/volume?volumeId=%d", a.baseURL, v.ID),
+			Source:      "kavita",
+			AddedAt:     createdAt,
+		})
+	}
+
+	return books, nil
+}
+
+func (a *KavitaAdapter) applyAuth(req *http.Request) {
+	if a.apiToken != "" {
+		req.Header.Set("Authorization", "Bearer "+a.apiToken)
+	}
+}
+
+func (a *KavitaAdapter) DownloadBookContent(ctx context.Context, book models.BookMetadata) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, book.DownloadURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	a.applyAuth(req)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download book from kavita: %d", resp.StatusCode)
+	}
+
+	limitReader := io.LimitReader(resp.Body, a.maxSize+1)
+	data, err := io.ReadAll(limitReader)
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > a.maxSize {
+		return nil, fmt.Errorf("book content exceeds maximum allowed size")
+	}
+
+	return data, nil
+}
@@ -0,0 +1,140 @@
+package source
+
+import (
+	"bookscout/internal/core/domain/models"
+	"bookscout/internal/core/domain/ports"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var _ ports.BookDataSource = (*KomgaAdapter)(nil)
+
+// KomgaAdapter fetches books from a Komga server's REST API, authenticating
+// with an API key.
+type KomgaAdapter struct {
+	baseURL  string
+	apiToken string
+	client   *http.Client
+	maxSize  int64
+}
+
+func NewKomgaAdapter(baseURL, apiToken string, maxSize int64) *KomgaAdapter {
+	return &KomgaAdapter{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		apiToken: apiToken,
+		client:   &http.Client{Timeout: 5 * time.Minute},
+		maxSize:  maxSize,
+	}
+}
+
+type komgaBookPage struct {
+	Content []komgaBook `json:"content"`
+}
+
+type komgaBook struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Metadata struct {
+		Title   string `json:"title"`
+		Summary string `json:"summary"`
+	} `json:"metadata"`
+	Created string `json:"created"`
+}
+
+func (a *KomgaAdapter) FetchNewBooks(ctx context.Context, lastCheckTimestamp int64) ([]models.BookMetadata, error) {
+	if a.baseURL == "" {
+		return nil, fmt.Errorf("komga base URL is not configured")
+	}
+
+	apiURL := fmt.Sprintf("%s/api/v1/books?size=500", a.baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	a.applyAuth(req)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch komga book list: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("komga API returned status %d", resp.StatusCode)
+	}
+
+	var page komgaBookPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to decode komga book list: %w", err)
+	}
+
+	var books []models.BookMetadata
+	for _, b := range page.Content {
+		createdAt, _ := time.Parse(time.RFC3339, b.Created)
+		if !createdAt.IsZero() && createdAt.Unix() <= lastCheckTimestamp {
+			continue
+		}
+		if createdAt.IsZero() {
+			createdAt = time.Now()
+		}
+
+		title := b.Metadata.Title
+		if title == "" {
+			title = b.Name
+		}
+
+		books = append(books, models.BookMetadata{
+			ID:          b.ID,
+			Title:       title,
+			Author:      "Unknown",
+			Description: b.Metadata.Summary,
+			DownloadURL: fmt.Sprintf("%s/api/v1/books/%s/file", a.baseURL, b.ID),
+			Source:      "komga",
+			AddedAt:     createdAt,
+		})
+	}
+
+	return books, nil
+}
+
+func (a *KomgaAdapter) applyAuth(req *http.Request) {
+	if a.apiToken != "" {
+		req.Header.Set("X-API-Key", a.apiToken)
+	}
+}
+
+func (a *KomgaAdapter) DownloadBookContent(ctx context.Context, book models.BookMetadata) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, book.DownloadURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	a.applyAuth(req)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download book from komga: %d", resp.StatusCode)
+	}
+
+	limitReader := io.LimitReader(resp.Body, a.maxSize+1)
+	data, err := io.ReadAll(limitReader)
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > a.maxSize {
+		return nil, fmt.Errorf("book content exceeds maximum allowed size")
+	}
+
+	return data, nil
+}
@@ -0,0 +1,172 @@
+package source
+
+import (
+	"archive/zip"
+	"bookscout/internal/core/domain/models"
+	"bookscout/internal/core/domain/ports"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var _ ports.BookDataSource = (*LocalFilesystemAdapter)(nil)
+
+// LocalFilesystemAdapter walks a directory tree for EPUB/PDF files and emits
+// their metadata, for self-hosted libraries with no catalog server at all.
+type LocalFilesystemAdapter struct {
+	rootPath string
+	maxSize  int64
+}
+
+func NewLocalFilesystemAdapter(rootPath string, maxSize int64) *LocalFilesystemAdapter {
+	return &LocalFilesystemAdapter{rootPath: rootPath, maxSize: maxSize}
+}
+
+func (a *LocalFilesystemAdapter) FetchNewBooks(ctx context.Context, lastCheckTimestamp int64) ([]models.BookMetadata, error) {
+	if a.rootPath == "" {
+		return nil, fmt.Errorf("local source path is not configured")
+	}
+
+	var books []models.BookMetadata
+	err := filepath.WalkDir(a.rootPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext != ".epub" && ext != ".pdf" {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if info.ModTime().Unix() <= lastCheckTimestamp {
+			return nil
+		}
+
+		book := models.BookMetadata{
+			ID:          path,
+			Title:       strings.TrimSuffix(d.Name(), filepath.Ext(d.Name())),
+			Author:      "Unknown",
+			DownloadURL: path,
+			Source:      "local",
+			AddedAt:     info.ModTime(),
+		}
+
+		if ext == ".epub" {
+			if title, author, err := readEPUBMetadata(path); err == nil {
+				if title != "" {
+					book.Title = title
+				}
+				if author != "" {
+					book.Author = author
+				}
+			}
+		}
+
+		books = append(books, book)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk local library at %s: %w", a.rootPath, err)
+	}
+
+	return books, nil
+}
+
+func (a *LocalFilesystemAdapter) DownloadBookContent(ctx context.Context, book models.BookMetadata) ([]byte, error) {
+	data, err := os.ReadFile(book.DownloadURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local book %s: %w", book.DownloadURL, err)
+	}
+	if a.maxSize > 0 && int64(len(data)) > a.maxSize {
+		return nil, fmt.Errorf("book content exceeds maximum allowed size")
+	}
+	return data, nil
+}
+
+// opfMetadata mirrors the subset of an EPUB's OPF package document this
+// adapter cares about.
+type opfMetadata struct {
+	Metadata struct {
+		Title   string `xml:"title"`
+		Creator string `xml:"creator"`
+	} `xml:"metadata"`
+}
+
+// readEPUBMetadata extracts title/author from an EPUB's OPF package
+// document. EPUBs are zip archives; the OPF path is resolved via
+// META-INF/container.xml as the EPUB spec requires.
+func readEPUBMetadata(path string) (title, author string, err error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return "", "", err
+	}
+	defer r.Close()
+
+	opfPath, err := findOPFPath(r)
+	if err != nil {
+		return "", "", err
+	}
+
+	for _, f := range r.File {
+		if f.Name != opfPath {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return "", "", err
+		}
+		defer rc.Close()
+
+		var opf opfMetadata
+		if err := xml.NewDecoder(rc).Decode(&opf); err != nil {
+			return "", "", err
+		}
+		return opf.Metadata.Title, opf.Metadata.Creator, nil
+	}
+
+	return "", "", fmt.Errorf("OPF file %q not found in EPUB", opfPath)
+}
+
+// findOPFPath reads META-INF/container.xml to locate the OPF package document.
+func findOPFPath(r *zip.ReadCloser) (string, error) {
+	for _, f := range r.File {
+		if f.Name != "META-INF/container.xml" {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return "", err
+		}
+		defer rc.Close()
+
+		var container struct {
+			Rootfiles struct {
+				Rootfile []struct {
+					FullPath string `xml:"full-path,attr"`
+				} `xml:"rootfile"`
+			} `xml:"rootfiles"`
+		}
+		if err := xml.NewDecoder(rc).Decode(&container); err != nil {
+			return "", err
+		}
+		if len(container.Rootfiles.Rootfile) == 0 {
+			return "", fmt.Errorf("container.xml has no rootfile")
+		}
+		return container.Rootfiles.Rootfile[0].FullPath, nil
+	}
+
+	return "", fmt.Errorf("META-INF/container.xml not found")
+}
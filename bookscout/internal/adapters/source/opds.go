@@ -3,26 +3,30 @@ package source
 import (
 	"bookscout/internal/adapters/util"
 	"bookscout/internal/core/domain/models"
+	"bookscout/internal/core/domain/ports"
 	"context"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/mmcdole/gofeed/atom"
 )
 
+var _ ports.BookDataSource = (*OPDSAdapter)(nil)
+
 type OPDSAdapter struct {
 	catalogURL string
-	username   string
-	password   string
+	auth       Authenticator
 	client     *http.Client
 	maxSize    int64
+	facets     []string
 }
 
-func NewOPDSAdapter(catalogURL, username, password string, maxSize int64, logLevel string) *OPDSAdapter {
+func NewOPDSAdapter(catalogURL string, auth Authenticator, maxSize int64, logLevel string, facets ...string) *OPDSAdapter {
 	// Automated path generation
 	if catalogURL != "" {
 		if u, err := url.Parse(catalogURL); err == nil && u.Scheme != "" {
@@ -35,13 +39,13 @@ func NewOPDSAdapter(catalogURL, username, password string, maxSize int64, logLev
 
 	return &OPDSAdapter{
 		catalogURL: catalogURL,
-		username:   username,
-		password:   password,
+		auth:       auth,
 		client: &http.Client{
 			Transport: &util.LoggingTransport{LogLevel: logLevel},
 			Timeout:   5 * time.Minute,
 		},
 		maxSize: maxSize,
+		facets:  facets,
 	}
 }
 
@@ -116,16 +120,75 @@ const (
 	relThumbnail   = "http://opds-spec.org/image/thumbnail"
 	relSubsection  = "subsection"
 	relCatalog     = "http://opds-spec.org/catalog"
+	relFacet       = "http://opds-spec.org/facet"
+
+	// maxIndirectHops bounds how many opds:indirectAcquisition redirections
+	// DownloadBookContent follows before giving up, so a catalog with a
+	// misconfigured (or cyclical) indirection chain can't hang a download
+	// forever.
+	maxIndirectHops = 3
 )
 
+// matchesFacets reports whether entryCats satisfies a.facets: true if no
+// filter is configured, or if any category matches a configured facet
+// case-insensitively.
+func (a *OPDSAdapter) matchesFacets(entryCats []string) bool {
+	if len(a.facets) == 0 {
+		return true
+	}
+	for _, want := range a.facets {
+		for _, got := range entryCats {
+			if strings.EqualFold(want, got) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesFacetTitle reports whether a subsection/facet link's title matches
+// a.facets, so FetchNewBooks only descends into the shelves an operator
+// asked for. An empty title, or no configured filter, always matches -- a
+// link with no title can't be screened out, and with no filter everything
+// is traversed.
+func (a *OPDSAdapter) matchesFacetTitle(title string) bool {
+	if len(a.facets) == 0 || title == "" {
+		return true
+	}
+	for _, want := range a.facets {
+		if strings.EqualFold(want, title) {
+			return true
+		}
+	}
+	return false
+}
+
+// entryCategories collects an Atom entry's <category> term and label, which
+// is how OPDS expresses which shelf/genre an entry belongs to.
+func entryCategories(entry *atom.Entry) []string {
+	cats := make([]string, 0, len(entry.Categories)*2)
+	for _, c := range entry.Categories {
+		if c.Term != "" {
+			cats = append(cats, c.Term)
+		}
+		if c.Label != "" {
+			cats = append(cats, c.Label)
+		}
+	}
+	return cats
+}
+
 func (a *OPDSAdapter) fetchPage(ctx context.Context, targetURL string, lastCheckTimestamp int64) ([]models.BookMetadata, string, []string, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", targetURL, nil)
 	if err != nil {
 		return nil, "", nil, err
 	}
+	req.Header.Set("Accept", mimeOPDS2Catalog+", application/atom+xml;q=0.9")
 
-	if a.username != "" {
-		req.SetBasicAuth(a.username, a.password)
+	if a.auth != nil {
+		if err := a.auth.Apply(req); err != nil {
+			return nil, "", nil, fmt.Errorf("failed to authenticate OPDS request to %s: %w", targetURL, err)
+		}
 	}
 
 	resp, err := a.client.Do(req)
@@ -138,6 +201,12 @@ func (a *OPDSAdapter) fetchPage(ctx context.Context, targetURL string, lastCheck
 		return nil, "", nil, fmt.Errorf("OPDS feed returned status: %d", resp.StatusCode)
 	}
 
+	baseURL, _ := url.Parse(targetURL)
+
+	if strings.Contains(resp.Header.Get("Content-Type"), mimeOPDS2Catalog) {
+		return a.fetchOPDS2Page(resp.Body, baseURL, lastCheckTimestamp)
+	}
+
 	fp := &atom.Parser{}
 	feed, err := fp.Parse(resp.Body)
 	if err != nil {
@@ -146,12 +215,11 @@ func (a *OPDSAdapter) fetchPage(ctx context.Context, targetURL string, lastCheck
 
 	var books []models.BookMetadata
 	var subsections []string
-	baseURL, _ := url.Parse(targetURL)
 
 	for _, entry := range feed.Entries {
-		// Capture subsection/catalog links from entries
+		// Capture subsection/catalog/facet links from entries
 		for _, link := range entry.Links {
-			if link.Rel == relSubsection || link.Rel == relCatalog {
+			if (link.Rel == relSubsection || link.Rel == relCatalog || link.Rel == relFacet) && a.matchesFacetTitle(link.Title) {
 				if ref, err := url.Parse(link.Href); err == nil {
 					subsections = append(subsections, baseURL.ResolveReference(ref).String())
 				}
@@ -171,6 +239,10 @@ func (a *OPDSAdapter) fetchPage(ctx context.Context, targetURL string, lastCheck
 			continue
 		}
 
+		if !a.matchesFacets(entryCategories(entry)) {
+			continue
+		}
+
 		book := models.BookMetadata{
 			ID:          entry.ID,
 			Title:       entry.Title,
@@ -225,9 +297,9 @@ func (a *OPDSAdapter) fetchPage(ctx context.Context, targetURL string, lastCheck
 		}
 	}
 
-	// Capture subsection/catalog links from top-level feed links
+	// Capture subsection/catalog/facet links from top-level feed links
 	for _, link := range feed.Links {
-		if link.Rel == relSubsection || link.Rel == relCatalog {
+		if (link.Rel == relSubsection || link.Rel == relCatalog || link.Rel == relFacet) && a.matchesFacetTitle(link.Title) {
 			if ref, err := url.Parse(link.Href); err == nil {
 				subsections = append(subsections, baseURL.ResolveReference(ref).String())
 			}
@@ -248,35 +320,121 @@ func (a *OPDSAdapter) fetchPage(ctx context.Context, targetURL string, lastCheck
 	return books, nextPageURL, subsections, nil
 }
 
+// DownloadBookContent follows book.DownloadURL, honoring HTTP basic auth (or
+// whatever Authenticator this adapter was configured with). If the
+// acquisition link is indirect -- the response itself is another OPDS
+// document (an opds:indirectAcquisition hop) rather than the book -- it's
+// parsed for its own acquisition link, which is followed in turn, up to
+// maxIndirectHops deep.
 func (a *OPDSAdapter) DownloadBookContent(ctx context.Context, book models.BookMetadata) ([]byte, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", book.DownloadURL, nil)
-	if err != nil {
-		return nil, err
-	}
+	target := book.DownloadURL
+
+	for hop := 0; hop < maxIndirectHops; hop++ {
+		req, err := http.NewRequestWithContext(ctx, "GET", target, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if a.auth != nil {
+			if err := a.auth.Apply(req); err != nil {
+				return nil, fmt.Errorf("failed to authenticate OPDS download request: %w", err)
+			}
+		}
+
+		resp, err := a.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
 
-	if a.username != "" {
-		req.SetBasicAuth(a.username, a.password)
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to download book from OPDS link: %d", resp.StatusCode)
+		}
+
+		contentType := resp.Header.Get("Content-Type")
+		if !isIndirectAcquisition(contentType) {
+			limitReader := io.LimitReader(resp.Body, a.maxSize+1)
+			data, err := io.ReadAll(limitReader)
+			resp.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			if int64(len(data)) > a.maxSize {
+				return nil, fmt.Errorf("book content exceeds maximum allowed size")
+			}
+			return data, nil
+		}
+
+		next, err := a.resolveIndirectAcquisition(resp.Body, target, contentType)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve indirect acquisition for %s: %w", target, err)
+		}
+		if next == "" {
+			return nil, fmt.Errorf("indirect acquisition document at %s has no acquisition link", target)
+		}
+		target = next
 	}
 
-	resp, err := a.client.Do(req)
+	return nil, fmt.Errorf("too many indirect acquisition hops starting from %s", book.DownloadURL)
+}
+
+// isIndirectAcquisition reports whether a download response is itself
+// another OPDS document -- an opds:indirectAcquisition hop -- rather than
+// the book's actual bytes.
+func isIndirectAcquisition(contentType string) bool {
+	return strings.Contains(contentType, "profile=opds-catalog") ||
+		strings.Contains(contentType, "type=entry") ||
+		strings.Contains(contentType, mimeOPDS2Catalog)
+}
+
+// resolveIndirectAcquisition reads an indirect acquisition response -- a
+// single Atom entry or OPDS 2.0 publication document -- and returns the
+// acquisition link nested inside it.
+func (a *OPDSAdapter) resolveIndirectAcquisition(body io.Reader, sourceURL, contentType string) (string, error) {
+	baseURL, err := url.Parse(sourceURL)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to download book from OPDS link: %d", resp.StatusCode)
+	if strings.Contains(contentType, mimeOPDS2Catalog) {
+		feed, err := decodeOPDS2Feed(body)
+		if err != nil {
+			return "", err
+		}
+		if len(feed.Publications) == 0 {
+			return "", nil
+		}
+		href := bestOPDS2AcquisitionLink(feed.Publications[0].Links)
+		if href == "" {
+			return "", nil
+		}
+		return resolveOPDS2(baseURL, href), nil
 	}
 
-	limitReader := io.LimitReader(resp.Body, a.maxSize+1)
-	data, err := io.ReadAll(limitReader)
+	fp := &atom.Parser{}
+	feed, err := fp.Parse(body)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
-
-	if int64(len(data)) > a.maxSize {
-		return nil, fmt.Errorf("book content exceeds maximum allowed size")
+	if len(feed.Entries) == 0 {
+		return "", nil
 	}
 
-	return data, nil
+	var href string
+	for _, link := range feed.Entries[0].Links {
+		if link.Rel != relAcquisition && link.Rel != relOpenAccess {
+			continue
+		}
+		if href == "" || link.Type == "application/epub+zip" {
+			href = link.Href
+		}
+	}
+	if href == "" {
+		return "", nil
+	}
+	if ref, err := url.Parse(href); err == nil {
+		return baseURL.ResolveReference(ref).String(), nil
+	}
+	return href, nil
 }
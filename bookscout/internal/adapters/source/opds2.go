@@ -0,0 +1,234 @@
+package source
+
+import (
+	"bookscout/internal/core/domain/models"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"time"
+)
+
+const mimeOPDS2Catalog = "application/opds+json"
+
+// opds2Feed is the subset of an OPDS 2.0 catalog (https://drafts.opds.io/opds-2.0)
+// fetchPage understands: enough to walk publications, nested groups, and
+// pagination the same way it already walks an Atom feed.
+type opds2Feed struct {
+	Links        []opds2Link        `json:"links"`
+	Publications []opds2Publication `json:"publications"`
+	Navigation   []opds2Link        `json:"navigation"`
+	Groups       []opds2Group       `json:"groups"`
+}
+
+// opds2Group is a named sub-collection of publications/navigation embedded
+// directly in the feed (e.g. "Recent additions", "Staff picks") rather than
+// linked out to its own page.
+type opds2Group struct {
+	Publications []opds2Publication `json:"publications"`
+	Navigation   []opds2Link        `json:"navigation"`
+}
+
+type opds2Publication struct {
+	Metadata opds2Metadata `json:"metadata"`
+	Links    []opds2Link   `json:"links"`
+	Images   []opds2Link   `json:"images"`
+}
+
+type opds2Metadata struct {
+	Title     string          `json:"title"`
+	Identifier string         `json:"identifier"`
+	Modified  string          `json:"modified"`
+	Published string          `json:"published"`
+	Author    json.RawMessage `json:"author"`
+}
+
+type opds2Link struct {
+	Href  string          `json:"href"`
+	Title string          `json:"title"`
+	Type  string          `json:"type"`
+	Rel   json.RawMessage `json:"rel"`
+}
+
+func decodeOPDS2Feed(body io.Reader) (*opds2Feed, error) {
+	var feed opds2Feed
+	if err := json.NewDecoder(body).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("failed to parse OPDS feed as OPDS 2.0 JSON: %w", err)
+	}
+	return &feed, nil
+}
+
+// rels unmarshals a link's "rel" field, which the spec allows to be either a
+// single string or an array of strings.
+func (l opds2Link) rels() []string {
+	if len(l.Rel) == 0 {
+		return nil
+	}
+	var single string
+	if err := json.Unmarshal(l.Rel, &single); err == nil {
+		return []string{single}
+	}
+	var multi []string
+	if err := json.Unmarshal(l.Rel, &multi); err == nil {
+		return multi
+	}
+	return nil
+}
+
+func (l opds2Link) hasRel(want string) bool {
+	for _, rel := range l.rels() {
+		if rel == want {
+			return true
+		}
+	}
+	return false
+}
+
+// isOPDS2SectionLink mirrors the relSubsection/relCatalog check fetchPage
+// already applies to Atom links.
+func isOPDS2SectionLink(l opds2Link) bool {
+	return l.hasRel(relSubsection) || l.hasRel(relCatalog)
+}
+
+// bestOPDS2AcquisitionLink mirrors fetchPage's Atom acquisition search:
+// prefer a direct epub link, falling back to whatever acquisition link the
+// publication offers.
+func bestOPDS2AcquisitionLink(links []opds2Link) string {
+	var fallback string
+	for _, link := range links {
+		if !link.hasRel(relAcquisition) && !link.hasRel(relOpenAccess) {
+			continue
+		}
+		if fallback == "" || link.Type == "application/epub+zip" {
+			fallback = link.Href
+		}
+	}
+	return fallback
+}
+
+// opds2Authors flattens OPDS 2.0's "author" field, which the spec allows to
+// be a bare name string, a single {"name": ...} object, or an array of
+// either.
+func opds2Authors(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(raw, &name); err == nil {
+		if name == "" {
+			return nil
+		}
+		return []string{name}
+	}
+
+	type named struct {
+		Name string `json:"name"`
+	}
+	var one named
+	if err := json.Unmarshal(raw, &one); err == nil && one.Name != "" {
+		return []string{one.Name}
+	}
+
+	var many []named
+	if err := json.Unmarshal(raw, &many); err == nil {
+		var names []string
+		for _, a := range many {
+			if a.Name != "" {
+				names = append(names, a.Name)
+			}
+		}
+		return names
+	}
+
+	return nil
+}
+
+func opds2Modified(meta opds2Metadata) time.Time {
+	if t, err := time.Parse(time.RFC3339, meta.Modified); err == nil {
+		return t
+	}
+	if t, err := time.Parse(time.RFC3339, meta.Published); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
+// fetchOPDS2Page parses an OPDS 2.0 JSON catalog page, mirroring the
+// (books, next, subsections, err) shape fetchPage's Atom path returns so the
+// two can share FetchNewBooks' walking/pagination loop unchanged.
+func (a *OPDSAdapter) fetchOPDS2Page(body io.Reader, baseURL *url.URL, lastCheckTimestamp int64) ([]models.BookMetadata, string, []string, error) {
+	feed, err := decodeOPDS2Feed(body)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	publications := feed.Publications
+	navigation := feed.Navigation
+	for _, g := range feed.Groups {
+		publications = append(publications, g.Publications...)
+		navigation = append(navigation, g.Navigation...)
+	}
+
+	var books []models.BookMetadata
+	var subsections []string
+
+	for _, pub := range publications {
+		entryTime := opds2Modified(pub.Metadata)
+		if !entryTime.IsZero() && entryTime.Unix() <= lastCheckTimestamp {
+			log.Printf("DEBUG OPDS: Skipping book '%s' (modified: %s) - added before last check (%s)",
+				pub.Metadata.Title, entryTime.Format(time.RFC3339), time.Unix(lastCheckTimestamp, 0).Format(time.RFC3339))
+			continue
+		}
+
+		href := bestOPDS2AcquisitionLink(pub.Links)
+		if href == "" {
+			continue
+		}
+
+		book := models.BookMetadata{
+			ID:          pub.Metadata.Identifier,
+			Title:       pub.Metadata.Title,
+			Author:      "Unknown",
+			Source:      "opds",
+			AddedAt:     entryTime,
+			DownloadURL: resolveOPDS2(baseURL, href),
+		}
+		if book.AddedAt.IsZero() {
+			book.AddedAt = time.Now()
+		}
+		if authors := opds2Authors(pub.Metadata.Author); len(authors) > 0 {
+			book.Author = authors[0]
+		}
+		if len(pub.Images) > 0 {
+			book.ThumbnailURL = resolveOPDS2(baseURL, pub.Images[0].Href)
+		}
+
+		books = append(books, book)
+	}
+
+	for _, nav := range navigation {
+		if isOPDS2SectionLink(nav) {
+			subsections = append(subsections, resolveOPDS2(baseURL, nav.Href))
+		}
+	}
+
+	nextPageURL := ""
+	for _, link := range feed.Links {
+		if link.hasRel(relNext) {
+			nextPageURL = resolveOPDS2(baseURL, link.Href)
+			break
+		}
+	}
+
+	return books, nextPageURL, subsections, nil
+}
+
+func resolveOPDS2(base *url.URL, href string) string {
+	ref, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	return base.ResolveReference(ref).String()
+}
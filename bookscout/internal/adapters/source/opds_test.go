@@ -4,7 +4,6 @@ import (
 	"bookscout/internal/core/domain/models"
 	"context"
 	"fmt"
-	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -131,6 +130,73 @@ func TestOPDSAdapter_FetchNewBooks_Traversal(t *testing.T) {
 	}
 }
 
+func TestOPDSAdapter_FetchNewBooks_OPDS2(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/opds+json")
+		fmt.Fprint(w, `{
+			"publications": [{
+				"metadata": {"title": "OPDS2 Book", "identifier": "urn:uuid:opds2-1", "modified": "2026-02-20T12:00:00Z", "author": {"name": "Jane Doe"}},
+				"links": [{"rel": "http://opds-spec.org/acquisition/open-access", "href": "http://example.com/opds2.epub", "type": "application/epub+zip"}],
+				"images": [{"href": "http://example.com/opds2-thumb.jpg"}]
+			}]
+		}`)
+	}))
+	defer server.Close()
+
+	adapter := &OPDSAdapter{
+		catalogURL: server.URL,
+		client:     &http.Client{},
+	}
+
+	books, err := adapter.FetchNewBooks(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("FetchNewBooks failed: %v", err)
+	}
+	if len(books) != 1 {
+		t.Fatalf("Expected 1 book, got %d", len(books))
+	}
+	if books[0].Author != "Jane Doe" {
+		t.Errorf("Expected author 'Jane Doe', got '%s'", books[0].Author)
+	}
+	if books[0].ThumbnailURL != "http://example.com/opds2-thumb.jpg" {
+		t.Errorf("Expected thumbnail, got '%s'", books[0].ThumbnailURL)
+	}
+}
+
+func TestOPDSAdapter_FetchNewBooks_OPDS2Pagination(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/opds+json")
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `{"publications": [{
+				"metadata": {"title": "OPDS2 Page 2", "identifier": "urn:uuid:p2"},
+				"links": [{"rel": "http://opds-spec.org/acquisition", "href": "http://example.com/p2.epub"}]
+			}]}`)
+		} else {
+			fmt.Fprintf(w, `{
+				"publications": [{
+					"metadata": {"title": "OPDS2 Page 1", "identifier": "urn:uuid:p1"},
+					"links": [{"rel": "http://opds-spec.org/acquisition", "href": "http://example.com/p1.epub"}]
+				}],
+				"links": [{"rel": "next", "href": "%s?page=2"}]
+			}`, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	adapter := &OPDSAdapter{
+		catalogURL: server.URL,
+		client:     &http.Client{},
+	}
+
+	books, err := adapter.FetchNewBooks(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("FetchNewBooks failed: %v", err)
+	}
+	if len(books) != 2 {
+		t.Errorf("Expected 2 books across OPDS2 pages, got %d", len(books))
+	}
+}
+
 func TestOPDSAdapter_FetchNewBooks_InvalidXML(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// FP.Parse doesn't necessarily error on bad XML if it finds zero entries,
@@ -177,15 +243,9 @@ func TestOPDSAdapter_DownloadBookContent_Errors(t *testing.T) {
 	}))
 	defer serverLarge.Close()
 
-	rc, err := adapter.DownloadBookContent(context.Background(), models.BookMetadata{DownloadURL: serverLarge.URL})
-	if err != nil {
-		t.Fatalf("Expected no error on call, got %v", err)
-	}
-	defer rc.Close()
-
-	_, err = io.ReadAll(rc)
+	_, err = adapter.DownloadBookContent(context.Background(), models.BookMetadata{DownloadURL: serverLarge.URL})
 	if err == nil {
-		t.Fatal("Expected error during read for too large content")
+		t.Fatal("Expected error for too large content")
 	}
 }
 
@@ -216,8 +276,7 @@ func TestOPDSAdapter_Authentication(t *testing.T) {
 	// 1. Success with correct credentials
 	adapter := &OPDSAdapter{
 		catalogURL: server.URL,
-		username:   username,
-		password:   password,
+		auth:       &BasicAuth{User: username, Pass: password},
 		client:     &http.Client{},
 	}
 
@@ -232,8 +291,7 @@ func TestOPDSAdapter_Authentication(t *testing.T) {
 	// 2. Failure with wrong credentials
 	adapterWrong := &OPDSAdapter{
 		catalogURL: server.URL,
-		username:   "wrong",
-		password:   "wrong",
+		auth:       &BasicAuth{User: "wrong", Pass: "wrong"},
 		client:     &http.Client{},
 	}
 	books, err = adapterWrong.FetchNewBooks(context.Background(), 0)
@@ -244,3 +302,74 @@ func TestOPDSAdapter_Authentication(t *testing.T) {
 		t.Errorf("Expected 0 books with wrong credentials, got %d", len(books))
 	}
 }
+
+func TestOPDSAdapter_OIDCAuthentication(t *testing.T) {
+	const wantToken = "test-access-token"
+
+	resourceServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+wantToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/atom+xml")
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><feed xmlns="http://www.w3.org/2005/Atom"><entry><title>OIDC Book</title><id>oidc-1</id><link rel="http://opds-spec.org/acquisition" href="http://example.com/f.epub"/></entry></feed>`)
+	}))
+	defer resourceServer.Close()
+
+	var tokenRequests int
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"token_endpoint": "%s/token"}`, idpURL(r))
+		case "/token":
+			tokenRequests++
+			if err := r.ParseForm(); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if r.FormValue("grant_type") != "client_credentials" || r.FormValue("client_id") != "client-1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"access_token": "%s", "expires_in": 3600}`, wantToken)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer idp.Close()
+
+	adapter := &OPDSAdapter{
+		catalogURL: resourceServer.URL,
+		auth: &OIDCClientCredentials{
+			Issuer:       idp.URL,
+			ClientID:     "client-1",
+			ClientSecret: "secret-1",
+			Scopes:       []string{"catalog.read"},
+		},
+		client: &http.Client{},
+	}
+
+	books, err := adapter.FetchNewBooks(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("FetchNewBooks with OIDC auth failed: %v", err)
+	}
+	if len(books) != 1 {
+		t.Errorf("Expected 1 book, got %d", len(books))
+	}
+
+	// Second call should reuse the cached token rather than re-requesting one.
+	if _, err := adapter.FetchNewBooks(context.Background(), 0); err != nil {
+		t.Fatalf("second FetchNewBooks failed: %v", err)
+	}
+	if tokenRequests != 1 {
+		t.Errorf("expected the access token to be cached and reused, got %d token requests", tokenRequests)
+	}
+}
+
+// idpURL reconstructs the IdP's own base URL from an inbound request so the
+// discovery document can point back at the same test server.
+func idpURL(r *http.Request) string {
+	return "http://" + r.Host
+}
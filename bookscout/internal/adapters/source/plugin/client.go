@@ -0,0 +1,64 @@
+package plugin
+
+import (
+	"bookscout/internal/core/domain/ports"
+	"fmt"
+	"os/exec"
+
+	goplugin "github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+)
+
+// dialOpts forces the gob codec defined in grpc.go on every connection the
+// worker makes to a plugin, since neither side generates protobuf stubs.
+func dialOpts(opts []grpc.DialOption) []grpc.DialOption {
+	return append(opts, grpc.WithDefaultCallOptions(grpc.ForceCodec(gobCodec{})))
+}
+
+// NewClient launches the plugin binary at path, completes the go-plugin
+// gRPC handshake over it (with AutoMTLS, so the pipe between worker and
+// plugin is mutually authenticated without either side managing
+// certificates by hand), and returns a ports.BookDataSource backed by it.
+//
+// The returned *goplugin.Client must be passed to CleanupClients (or have
+// Kill called on it directly) before the worker process exits, or the
+// plugin subprocess is left running.
+func NewClient(name, path string) (ports.BookDataSource, *goplugin.Client, error) {
+	client := goplugin.NewClient(&goplugin.ClientConfig{
+		HandshakeConfig: Handshake,
+		Plugins:         pluginMap,
+		Cmd:             exec.Command(path),
+		AllowedProtocols: []goplugin.Protocol{
+			goplugin.ProtocolGRPC,
+		},
+		GRPCDialOptions: dialOpts(nil),
+		AutoMTLS:        true,
+	})
+
+	rpcClient, err := client.Client()
+	if err != nil {
+		client.Kill()
+		return nil, nil, fmt.Errorf("plugin %q: connect: %w", name, err)
+	}
+
+	raw, err := rpcClient.Dispense(pluginKey)
+	if err != nil {
+		client.Kill()
+		return nil, nil, fmt.Errorf("plugin %q: dispense: %w", name, err)
+	}
+
+	source, ok := raw.(ports.BookDataSource)
+	if !ok {
+		client.Kill()
+		return nil, nil, fmt.Errorf("plugin %q: does not implement BookDataSource", name)
+	}
+
+	return source, client, nil
+}
+
+// CleanupClients kills every plugin subprocess launched by NewClient.
+// Callers should defer this once from main, after building all of a run's
+// plugin sources.
+func CleanupClients() {
+	goplugin.CleanupClients()
+}
@@ -0,0 +1,132 @@
+package plugin
+
+import (
+	"bookscout/internal/core/domain/models"
+	"bookscout/internal/core/domain/ports"
+	"bytes"
+	"context"
+	"encoding/gob"
+
+	goplugin "github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+)
+
+// gobCodec transports the request/response types below as gob, not
+// protobuf: the worker has no protoc step, and gob round-trips
+// models.BookMetadata (including its time.Time field) without a .proto
+// mirror to keep in sync by hand. gRPC only cares that a codec can
+// Marshal/Unmarshal; it doesn't require protobuf specifically.
+type gobCodec struct{}
+
+func (gobCodec) Name() string { return "gob" }
+
+func (gobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// fetchRequest/fetchResponse and downloadRequest/downloadResponse mirror
+// ports.BookDataSource's two methods one-for-one; grpc.go's job is purely
+// to get these across a pipe, not to add behavior.
+type fetchRequest struct {
+	LastCheckTimestamp int64
+}
+
+type fetchResponse struct {
+	Books []models.BookMetadata
+}
+
+type downloadRequest struct {
+	Book models.BookMetadata
+}
+
+type downloadResponse struct {
+	Content []byte
+}
+
+const serviceName = "bookscout.plugin.BookDataSource"
+
+func serviceDesc(server ports.BookDataSource) *grpc.ServiceDesc {
+	return &grpc.ServiceDesc{
+		ServiceName: serviceName,
+		HandlerType: (*ports.BookDataSource)(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: "FetchNewBooks",
+				Handler: func(srv any, ctx context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+					req := new(fetchRequest)
+					if err := dec(req); err != nil {
+						return nil, err
+					}
+					books, err := srv.(ports.BookDataSource).FetchNewBooks(ctx, req.LastCheckTimestamp)
+					if err != nil {
+						return nil, err
+					}
+					return &fetchResponse{Books: books}, nil
+				},
+			},
+			{
+				MethodName: "DownloadBookContent",
+				Handler: func(srv any, ctx context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+					req := new(downloadRequest)
+					if err := dec(req); err != nil {
+						return nil, err
+					}
+					content, err := srv.(ports.BookDataSource).DownloadBookContent(ctx, req.Book)
+					if err != nil {
+						return nil, err
+					}
+					return &downloadResponse{Content: content}, nil
+				},
+			},
+		},
+		Streams:  []grpc.StreamDesc{},
+		Metadata: "bookdatasource.proto",
+	}
+}
+
+// grpcClient implements ports.BookDataSource by invoking the plugin process
+// over conn. It's what NewClient hands back to the worker.
+type grpcClient struct {
+	conn *grpc.ClientConn
+}
+
+func (c *grpcClient) FetchNewBooks(ctx context.Context, lastCheckTimestamp int64) ([]models.BookMetadata, error) {
+	resp := new(fetchResponse)
+	if err := c.conn.Invoke(ctx, "/"+serviceName+"/FetchNewBooks", &fetchRequest{LastCheckTimestamp: lastCheckTimestamp}, resp); err != nil {
+		return nil, err
+	}
+	return resp.Books, nil
+}
+
+func (c *grpcClient) DownloadBookContent(ctx context.Context, book models.BookMetadata) ([]byte, error) {
+	resp := new(downloadResponse)
+	if err := c.conn.Invoke(ctx, "/"+serviceName+"/DownloadBookContent", &downloadRequest{Book: book}, resp); err != nil {
+		return nil, err
+	}
+	return resp.Content, nil
+}
+
+// GRPCPlugin is the go-plugin Plugin implementation shared by the worker
+// (host, via GRPCClient) and every plugin binary (via GRPCServer, called
+// from Serve). impl is only set on the plugin side.
+type GRPCPlugin struct {
+	goplugin.NetRPCUnsupportedPlugin
+	Impl ports.BookDataSource
+}
+
+func (p *GRPCPlugin) GRPCServer(_ *goplugin.GRPCBroker, s *grpc.Server) error {
+	s.RegisterService(serviceDesc(p.Impl), p.Impl)
+	return nil
+}
+
+func (p *GRPCPlugin) GRPCClient(_ context.Context, _ *goplugin.GRPCBroker, conn *grpc.ClientConn) (any, error) {
+	return &grpcClient{conn: conn}, nil
+}
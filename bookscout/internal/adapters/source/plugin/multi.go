@@ -0,0 +1,87 @@
+package plugin
+
+import (
+	"bookscout/internal/core/domain/models"
+	"bookscout/internal/core/domain/ports"
+	"context"
+	"fmt"
+	"sync"
+)
+
+var _ ports.BookDataSource = (*MultiAdapter)(nil)
+
+// MultiAdapter fans FetchNewBooks out across several plugin-backed
+// BookDataSources concurrently and dedupes the combined results by book ID,
+// so the same title surfaced by two enabled plugins (e.g. a book mirrored
+// on both Project Gutenberg and the Internet Archive) is only ingested
+// once. It's the plugin-loaded counterpart of ChainAdapter, which fans out
+// across in-tree adapters instead.
+type MultiAdapter struct {
+	sources map[string]ports.BookDataSource
+
+	mu    sync.Mutex
+	owner map[string]ports.BookDataSource
+}
+
+// NewMultiAdapter builds a MultiAdapter over sources, keyed by the plugin
+// name each was loaded under (used only for error messages).
+func NewMultiAdapter(sources map[string]ports.BookDataSource) *MultiAdapter {
+	return &MultiAdapter{
+		sources: sources,
+		owner:   make(map[string]ports.BookDataSource),
+	}
+}
+
+type multiFetchResult struct {
+	name  string
+	src   ports.BookDataSource
+	books []models.BookMetadata
+	err   error
+}
+
+func (a *MultiAdapter) FetchNewBooks(ctx context.Context, lastCheckTimestamp int64) ([]models.BookMetadata, error) {
+	results := make(chan multiFetchResult, len(a.sources))
+
+	var wg sync.WaitGroup
+	for name, src := range a.sources {
+		wg.Add(1)
+		go func(name string, src ports.BookDataSource) {
+			defer wg.Done()
+			books, err := src.FetchNewBooks(ctx, lastCheckTimestamp)
+			results <- multiFetchResult{name: name, src: src, books: books, err: err}
+		}(name, src)
+	}
+	wg.Wait()
+	close(results)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var all []models.BookMetadata
+	seen := make(map[string]struct{})
+	for r := range results {
+		if r.err != nil {
+			return nil, fmt.Errorf("plugin %q: %w", r.name, r.err)
+		}
+		for _, b := range r.books {
+			if _, ok := seen[b.ID]; ok {
+				continue
+			}
+			seen[b.ID] = struct{}{}
+			a.owner[b.ID] = r.src
+			all = append(all, b)
+		}
+	}
+
+	return all, nil
+}
+
+func (a *MultiAdapter) DownloadBookContent(ctx context.Context, book models.BookMetadata) ([]byte, error) {
+	a.mu.Lock()
+	src, ok := a.owner[book.ID]
+	a.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("plugin adapter: unknown book ID %q (FetchNewBooks must run first)", book.ID)
+	}
+	return src.DownloadBookContent(ctx, book)
+}
@@ -0,0 +1,33 @@
+package plugin
+
+import (
+	"bookscout/internal/core/domain/ports"
+
+	goplugin "github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+)
+
+// serverOpts forces the gob codec defined in grpc.go on the plugin's gRPC
+// server, matching what dialOpts sets on the worker's client side.
+func serverOpts(opts []grpc.ServerOption) []grpc.ServerOption {
+	return append(opts, grpc.ForceServerCodec(gobCodec{}))
+}
+
+// Serve blocks forever, running impl as a BookDataSource plugin over
+// go-plugin's gRPC transport. A plugin binary's entire main() is expected
+// to be a call to this:
+//
+//	func main() {
+//	    plugin.Serve(&gutenbergSource{})
+//	}
+func Serve(impl ports.BookDataSource) {
+	goplugin.Serve(&goplugin.ServeConfig{
+		HandshakeConfig: Handshake,
+		Plugins: map[string]goplugin.Plugin{
+			pluginKey: &GRPCPlugin{Impl: impl},
+		},
+		GRPCServer: func(opts []grpc.ServerOption) *grpc.Server {
+			return grpc.NewServer(serverOpts(opts)...)
+		},
+	})
+}
@@ -0,0 +1,31 @@
+// Package plugin lets a BookDataSource live in a separate process from the
+// worker, launched and supervised over hashicorp/go-plugin's gRPC transport.
+// This is how out-of-tree catalog adapters (Project Gutenberg, Internet
+// Archive, Open Library, a site's private S3 bucket) get added without a
+// worker recompile: ship a binary that calls Serve with an implementation of
+// ports.BookDataSource, point BS_SOURCE_PLUGINS at it, and the worker
+// multiplexes fetch/download calls across it like any in-tree adapter.
+package plugin
+
+import (
+	goplugin "github.com/hashicorp/go-plugin"
+)
+
+// pluginKey is the single entry in the go-plugin Plugins map under which a
+// BookDataSource implementation is registered, on both ends of the pipe.
+const pluginKey = "bookdatasource"
+
+// Handshake is the go-plugin handshake both the worker (host) and every
+// plugin binary must agree on. ProtocolVersion only needs bumping if the
+// wire contract in grpc.go changes in a backwards-incompatible way.
+var Handshake = goplugin.HandshakeConfig{
+	ProtocolVersion:  1,
+	MagicCookieKey:   "BOOKSCOUT_SOURCE_PLUGIN",
+	MagicCookieValue: "ed18e9a2-9c3e-4f2b-9d7e-6a7c6a9c9b3a",
+}
+
+// pluginMap is shared by NewClient (host side) and Serve (plugin side) so
+// both halves of the handshake agree on what's being served.
+var pluginMap = map[string]goplugin.Plugin{
+	pluginKey: &GRPCPlugin{},
+}
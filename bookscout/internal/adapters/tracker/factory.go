@@ -0,0 +1,30 @@
+package tracker
+
+import (
+	"bookscout/internal/config"
+	"bookscout/internal/core/domain/ports"
+	"fmt"
+	"strings"
+)
+
+// CreateStateStore builds the ports.StateStore selected by
+// cfg.StateStoreBackend: "file" (default), "redis", or "postgres". source
+// namespaces the store so multiple book sources sharing one Redis/Postgres
+// instance don't collide (FileStateStore ignores it; it's one file already).
+//
+// Only service.WorkerService consumes a ports.StateStore today; cmd/worker's
+// standalone Run loop predates that abstraction and tracks dedup itself via
+// the BookSage API, so pointing BS_STATE_STORE_BACKEND at redis/postgres has
+// no effect there yet.
+func CreateStateStore(cfg *config.Config, source string) (ports.StateStore, error) {
+	switch strings.ToLower(cfg.StateStoreBackend) {
+	case "file", "":
+		return NewFileStateStore(cfg.StateFilePath)
+	case "redis":
+		return NewRedisStateStore(cfg.StateRedisAddr, cfg.StateRedisPassword, cfg.StateRedisDB, source)
+	case "postgres":
+		return NewPostgresStateStore(cfg.StatePostgresDSN, source)
+	default:
+		return nil, fmt.Errorf("unknown state store backend %q", cfg.StateStoreBackend)
+	}
+}
@@ -98,6 +98,19 @@ func (s *FileStateStore) MarkProcessed(bookID string) error {
 	return nil
 }
 
+// MarkProcessedBatch records every book ID in bookIDs as processed in
+// memory. FileStateStore keeps the whole map in memory regardless, so this
+// saves nothing over repeated MarkProcessed calls here -- it exists to
+// satisfy ports.StateStore for backends where batching actually matters.
+func (s *FileStateStore) MarkProcessedBatch(bookIDs []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range bookIDs {
+		s.state.ProcessedIDs[id] = true
+	}
+	return nil
+}
+
 // UpdateWatermark updates the global high-water mark in memory.
 func (s *FileStateStore) UpdateWatermark(timestamp int64) error {
 	s.mu.Lock()
@@ -0,0 +1,146 @@
+package tracker
+
+import (
+	"bookscout/internal/core/domain/ports"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	_ "github.com/lib/pq"
+)
+
+// PostgresStateStore implements ports.StateStore against a shared Postgres
+// database, the other multi-replica-safe backend alongside RedisStateStore.
+// processed_books has one row per (source, book_id) so IsProcessed is a
+// primary-key lookup; watermarks has one row per source, advanced with a
+// monotonic "WHERE ts < $1" guard so a stale writer can't regress it.
+var _ ports.StateStore = (*PostgresStateStore)(nil)
+
+type PostgresStateStore struct {
+	db     *sql.DB
+	source string
+}
+
+// NewPostgresStateStore opens dsn (a "postgres://..." connection string)
+// and ensures the processed_books and watermarks tables exist, binding the
+// store to source the same way RedisStateStore binds to a key namespace.
+func NewPostgresStateStore(dsn, source string) (*PostgresStateStore, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS processed_books (
+			source       TEXT NOT NULL,
+			book_id      TEXT NOT NULL,
+			processed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			PRIMARY KEY (source, book_id)
+		)`); err != nil {
+		return nil, fmt.Errorf("failed to create processed_books table: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS watermarks (
+			source TEXT PRIMARY KEY,
+			ts     BIGINT NOT NULL DEFAULT 0
+		)`); err != nil {
+		return nil, fmt.Errorf("failed to create watermarks table: %w", err)
+	}
+
+	return &PostgresStateStore{db: db, source: source}, nil
+}
+
+// GetWatermark returns the timestamp of the last successfully processed
+// batch. No row yet for this source reports 0.
+func (s *PostgresStateStore) GetWatermark() int64 {
+	var ts int64
+	err := s.db.QueryRowContext(context.Background(),
+		`SELECT ts FROM watermarks WHERE source = $1`, s.source).Scan(&ts)
+	if err != nil {
+		return 0
+	}
+	return ts
+}
+
+// IsProcessed checks if a specific book ID has already been processed.
+func (s *PostgresStateStore) IsProcessed(bookID string) bool {
+	var exists bool
+	err := s.db.QueryRowContext(context.Background(),
+		`SELECT EXISTS(SELECT 1 FROM processed_books WHERE source = $1 AND book_id = $2)`,
+		s.source, bookID).Scan(&exists)
+	if err != nil {
+		log.Printf("[PostgresStateStore] IsProcessed check failed for %s: %v", bookID, err)
+		return false
+	}
+	return exists
+}
+
+// MarkProcessed records a single book ID as processed.
+func (s *PostgresStateStore) MarkProcessed(bookID string) error {
+	return s.MarkProcessedBatch([]string{bookID})
+}
+
+// MarkProcessedBatch inserts every ID in bookIDs via one multi-row INSERT
+// instead of one round trip per book. ON CONFLICT DO NOTHING makes
+// re-marking an already-processed book idempotent rather than an error.
+func (s *PostgresStateStore) MarkProcessedBatch(bookIDs []string) error {
+	if len(bookIDs) == 0 {
+		return nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("INSERT INTO processed_books (source, book_id) VALUES ")
+	args := make([]any, 0, len(bookIDs)*2)
+	for i, id := range bookIDs {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "($%d, $%d)", len(args)+1, len(args)+2)
+		args = append(args, s.source, id)
+	}
+	sb.WriteString(" ON CONFLICT (source, book_id) DO NOTHING")
+
+	_, err := s.db.ExecContext(context.Background(), sb.String(), args...)
+	return err
+}
+
+// UpdateWatermark advances the source's watermark row only if timestamp is
+// newer than what's currently stored, the SQL equivalent of
+// RedisStateStore's WATCH/MULTI CAS. A zero rows-affected UPDATE means
+// either there's no row yet for this source or ts is already >= timestamp;
+// the follow-up INSERT ... ON CONFLICT DO NOTHING tells those two cases
+// apart without racing a concurrent first insert for the same source.
+func (s *PostgresStateStore) UpdateWatermark(timestamp int64) error {
+	ctx := context.Background()
+
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE watermarks SET ts = $1 WHERE source = $2 AND ts < $1`, timestamp, s.source)
+	if err != nil {
+		return fmt.Errorf("updating watermark: %w", err)
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("updating watermark: %w", err)
+	} else if affected > 0 {
+		return nil
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO watermarks (source, ts) VALUES ($1, $2) ON CONFLICT (source) DO NOTHING`,
+		s.source, timestamp); err != nil {
+		return fmt.Errorf("updating watermark: %w", err)
+	}
+	return nil
+}
+
+// Save is a no-op: every PostgresStateStore mutation above writes through
+// immediately, unlike FileStateStore's in-memory state that only reaches
+// disk on Save.
+func (s *PostgresStateStore) Save() error {
+	return nil
+}
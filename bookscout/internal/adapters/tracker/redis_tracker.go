@@ -0,0 +1,131 @@
+package tracker
+
+import (
+	"bookscout/internal/core/domain/ports"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStateStore implements ports.StateStore against a shared Redis
+// instance, so multiple worker replicas can track ingestion progress
+// without racing each other the way FileStateStore's local JSON file would.
+// Processed IDs live in a per-source SET (processed_ids:{sourceID}); the
+// watermark is a separate string key (watermark:{source}), advanced via
+// WATCH/MULTI so a replica that read a stale watermark can't clobber a
+// newer one another replica already committed.
+var _ ports.StateStore = (*RedisStateStore)(nil)
+
+type RedisStateStore struct {
+	client *redis.Client
+	source string
+}
+
+// NewRedisStateStore connects to addr and binds the store to source, the
+// namespace its keys are scoped under, so distinct sources sharing one
+// Redis instance don't collide.
+func NewRedisStateStore(addr, password string, db int, source string) (*RedisStateStore, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	return &RedisStateStore{client: client, source: source}, nil
+}
+
+func (s *RedisStateStore) processedKey() string {
+	return fmt.Sprintf("processed_ids:%s", s.source)
+}
+
+func (s *RedisStateStore) watermarkKey() string {
+	return fmt.Sprintf("watermark:%s", s.source)
+}
+
+// GetWatermark returns the timestamp of the last successfully processed
+// batch. A missing key (nothing ingested yet for this source) reports 0.
+func (s *RedisStateStore) GetWatermark() int64 {
+	val, err := s.client.Get(context.Background(), s.watermarkKey()).Int64()
+	if err != nil {
+		return 0
+	}
+	return val
+}
+
+// IsProcessed checks if a specific book ID has already been processed.
+func (s *RedisStateStore) IsProcessed(bookID string) bool {
+	ok, err := s.client.SIsMember(context.Background(), s.processedKey(), bookID).Result()
+	if err != nil {
+		log.Printf("[RedisStateStore] IsProcessed check failed for %s: %v", bookID, err)
+		return false
+	}
+	return ok
+}
+
+// MarkProcessed records a single book ID as processed.
+func (s *RedisStateStore) MarkProcessed(bookID string) error {
+	return s.client.SAdd(context.Background(), s.processedKey(), bookID).Err()
+}
+
+// MarkProcessedBatch records every ID in bookIDs via one SAdd call instead
+// of one round trip per book.
+func (s *RedisStateStore) MarkProcessedBatch(bookIDs []string) error {
+	if len(bookIDs) == 0 {
+		return nil
+	}
+	members := make([]interface{}, len(bookIDs))
+	for i, id := range bookIDs {
+		members[i] = id
+	}
+	return s.client.SAdd(context.Background(), s.processedKey(), members...).Err()
+}
+
+// UpdateWatermark advances the watermark key only if timestamp is newer
+// than what's currently stored. The WATCH/MULTI transaction is retried on
+// a lost optimistic-lock race (another replica updated the key between our
+// Get and our Set), re-reading the now-current value each time, so a
+// stale writer can never regress the watermark past a newer commit.
+func (s *RedisStateStore) UpdateWatermark(timestamp int64) error {
+	ctx := context.Background()
+	key := s.watermarkKey()
+
+	txf := func(tx *redis.Tx) error {
+		current, err := tx.Get(ctx, key).Int64()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		if timestamp <= current {
+			return nil
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, timestamp, 0)
+			return nil
+		})
+		return err
+	}
+
+	const maxRetries = 5
+	for i := 0; i < maxRetries; i++ {
+		err := s.client.Watch(ctx, txf, key)
+		if err == nil {
+			return nil
+		}
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		return fmt.Errorf("updating watermark: %w", err)
+	}
+	return fmt.Errorf("updating watermark: exhausted retries racing concurrent writers")
+}
+
+// Save is a no-op: every RedisStateStore mutation above writes through to
+// Redis immediately, unlike FileStateStore's in-memory state that only
+// reaches disk on Save.
+func (s *RedisStateStore) Save() error {
+	return nil
+}
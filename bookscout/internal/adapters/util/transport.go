@@ -2,9 +2,13 @@ package util
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io"
 	"log"
+	"math/rand"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -64,10 +68,120 @@ func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error)
 	return resp, nil
 }
 
-// RetryTransport is an http.RoundTripper that retries on transient errors.
+// Clock abstracts time.Now so tests can control how a parsed Retry-After
+// HTTP-date compares to "now" without depending on wall-clock time.
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// Sleeper abstracts waiting out a retry delay so tests can observe or
+// short-circuit backoff (and context cancellation mid-sleep) without
+// actually blocking for real.
+type Sleeper interface {
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+type realSleeper struct{}
+
+func (realSleeper) Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// RetryPolicy controls how RetryTransport decides whether to retry a
+// response and how long to wait between attempts.
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+
+	// RetryableStatus lists the status codes that trigger a retry. A nil
+	// map falls back to 429 and 5xx.
+	RetryableStatus map[int]bool
+
+	// PerAttemptTimeout bounds a single attempt's RoundTrip call, separate
+	// from any deadline on req's context. Zero means no per-attempt limit.
+	PerAttemptTimeout time.Duration
+}
+
+// DefaultRetryPolicy retries network errors plus 429 and 5xx responses,
+// with full-jitter backoff starting at 500ms and capped at 30s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 3,
+		BaseDelay:  500 * time.Millisecond,
+		MaxDelay:   30 * time.Second,
+	}
+}
+
+func (p RetryPolicy) isRetryableStatus(code int) bool {
+	if p.RetryableStatus != nil {
+		return p.RetryableStatus[code]
+	}
+	return code == http.StatusTooManyRequests || (code >= 500 && code <= 599)
+}
+
+// errNotRewindable is returned internally when a retry would need to
+// replay req's body but req.GetBody isn't set.
+var errNotRewindable = errors.New("util: request body is not rewindable (no GetBody); see WithRewindableBody")
+
+// RetryTransport is an http.RoundTripper that retries on transient errors,
+// replaying the request body (via req.GetBody) between attempts.
 type RetryTransport struct {
-	Base       http.RoundTripper
+	Base http.RoundTripper
+
+	// MaxRetries is a deprecated shim predating Policy. It's still honored
+	// when Policy is nil, so existing callers that only set MaxRetries see
+	// the same retry count as before; everything else (backoff, jitter,
+	// Retry-After, retryable statuses) now comes from DefaultRetryPolicy.
+	//
+	// Deprecated: set Policy instead.
 	MaxRetries int
+
+	// Policy overrides the retry behavior entirely. Defaults to
+	// DefaultRetryPolicy with MaxRetries substituted in when nil.
+	Policy *RetryPolicy
+
+	// Clock and Sleeper default to real time; tests inject fakes to
+	// control Retry-After comparisons and skip actual backoff delays.
+	Clock   Clock
+	Sleeper Sleeper
+}
+
+func (t *RetryTransport) policy() RetryPolicy {
+	if t.Policy != nil {
+		return *t.Policy
+	}
+	policy := DefaultRetryPolicy()
+	policy.MaxRetries = t.MaxRetries
+	return policy
+}
+
+func (t *RetryTransport) clock() Clock {
+	if t.Clock != nil {
+		return t.Clock
+	}
+	return realClock{}
+}
+
+func (t *RetryTransport) sleeper() Sleeper {
+	if t.Sleeper != nil {
+		return t.Sleeper
+	}
+	return realSleeper{}
 }
 
 func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -75,43 +189,123 @@ func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	if base == nil {
 		base = http.DefaultTransport
 	}
+	policy := t.policy()
 
 	var lastErr error
 	var resp *http.Response
 
-	for i := 0; i <= t.MaxRetries; i++ {
-		// If it's not the first attempt, we need to handle potential body issues.
-		// For GET requests (Fetch/Download), req.Body is nil anyway.
-		if i > 0 && req.Body != nil {
-			// We can't easily retry requests with streams.
-			// So we only retry if Body is nil or we have a way to reset it.
-			return base.RoundTrip(req)
+	for attempt := 0; attempt <= policy.MaxRetries; attempt++ {
+		if attempt > 0 {
+			body, err := rewindBody(req)
+			if err != nil {
+				// Can't replay this body, so there's nothing left to try;
+				// surface whatever the previous attempt produced.
+				return resp, lastErr
+			}
+			req.Body = body
 		}
 
-		resp, lastErr = base.RoundTrip(req)
-		if lastErr != nil {
-			// Retry on network errors
-			time.Sleep(t.backoff(i))
-			continue
+		attemptReq := req
+		cancel := func() {}
+		if policy.PerAttemptTimeout > 0 {
+			var ctx context.Context
+			ctx, cancel = context.WithTimeout(req.Context(), policy.PerAttemptTimeout)
+			attemptReq = req.WithContext(ctx)
 		}
 
-		if resp.StatusCode == http.StatusTooManyRequests || (resp.StatusCode >= 500 && resp.StatusCode <= 599) {
-			// Retry on 429 or 5xx
+		resp, lastErr = base.RoundTrip(attemptReq)
+		cancel()
+
+		var retryAfter time.Duration
+		retry := lastErr != nil
+		if !retry && policy.isRetryableStatus(resp.StatusCode) {
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), t.clock())
 			resp.Body.Close()
-			time.Sleep(t.backoff(i))
-			continue
+			retry = true
 		}
 
-		return resp, nil
+		if !retry {
+			return resp, nil
+		}
+		if attempt == policy.MaxRetries {
+			break
+		}
+
+		delay := t.backoff(policy, attempt)
+		if retryAfter > delay {
+			delay = retryAfter
+		}
+		if err := t.sleeper().Sleep(req.Context(), delay); err != nil {
+			return resp, err
+		}
 	}
 
 	return resp, lastErr
 }
 
-func (t *RetryTransport) backoff(attempt int) time.Duration {
-	if attempt == 0 {
+// backoff computes full-jitter backoff: rand(0, min(MaxDelay, BaseDelay*2^attempt)).
+func (t *RetryTransport) backoff(policy RetryPolicy, attempt int) time.Duration {
+	if policy.BaseDelay <= 0 {
+		return 0
+	}
+	maxDelay := policy.MaxDelay
+	if scaled := policy.BaseDelay << uint(attempt); scaled > 0 && (maxDelay <= 0 || scaled < maxDelay) {
+		maxDelay = scaled
+	}
+	if maxDelay <= 0 {
 		return 0
 	}
-	// Exponential backoff: 1s, 2s, 4s...
-	return time.Duration(1<<(attempt-1)) * time.Second
+	return time.Duration(rand.Int63n(int64(maxDelay)))
+}
+
+// rewindBody produces a fresh, unread copy of req's body for a retry
+// attempt via req.GetBody, returning errNotRewindable if req has a body
+// that can't be replayed.
+func rewindBody(req *http.Request) (io.ReadCloser, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req.Body, nil
+	}
+	if req.GetBody == nil {
+		return nil, errNotRewindable
+	}
+	return req.GetBody()
+}
+
+// WithRewindableBody buffers body in memory and attaches a GetBody func to
+// req so RetryTransport can replay it across attempts. Use this when a
+// request was built from a body that doesn't already populate GetBody on
+// its own, e.g. http.NewRequest with an io.Reader that isn't one of the
+// handful of types (*bytes.Buffer, *bytes.Reader, *strings.Reader) the
+// stdlib special-cases.
+func WithRewindableBody(req *http.Request, body io.Reader) (*http.Request, error) {
+	buf, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(buf))
+	req.Body = io.NopCloser(bytes.NewReader(buf))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf)), nil
+	}
+	return req, nil
+}
+
+// parseRetryAfter parses the Retry-After header as either delta-seconds or
+// an HTTP-date, returning 0 if absent, unparseable, or already past.
+func parseRetryAfter(header string, clock Clock) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(strings.TrimSpace(header)); err == nil {
+		if secs <= 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if when, err := http.ParseTime(header); err == nil {
+		if d := when.Sub(clock.Now()); d > 0 {
+			return d
+		}
+	}
+	return 0
 }
@@ -0,0 +1,196 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakeSleeper records requested delays and returns immediately, so tests
+// don't actually wait out backoff.
+type fakeSleeper struct {
+	delays []time.Duration
+}
+
+func (f *fakeSleeper) Sleep(ctx context.Context, d time.Duration) error {
+	f.delays = append(f.delays, d)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		return nil
+	}
+}
+
+// cancelingSleeper cancels its own context instead of sleeping, simulating
+// a caller whose context expires mid-backoff.
+type cancelingSleeper struct {
+	cancel context.CancelFunc
+}
+
+func (c *cancelingSleeper) Sleep(ctx context.Context, d time.Duration) error {
+	c.cancel()
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (f fakeClock) Now() time.Time { return f.now }
+
+func TestRetryTransport_POSTRetryReplaysBody(t *testing.T) {
+	var gotBodies []string
+	attempts := 0
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		b, _ := io.ReadAll(req.Body)
+		gotBodies = append(gotBodies, string(b))
+		if attempts < 3 {
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: io.NopCloser(bytes.NewReader(nil)), Header: http.Header{}}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(nil)), Header: http.Header{}}, nil
+	})
+
+	rt := &RetryTransport{
+		Base:    base,
+		Policy:  &RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond},
+		Sleeper: &fakeSleeper{},
+	}
+
+	req, err := http.NewRequest(http.MethodPost, "http://example.com/ingest", bytes.NewBufferString("payload"))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected eventual 200, got %d", resp.StatusCode)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+	for i, b := range gotBodies {
+		if b != "payload" {
+			t.Errorf("attempt %d: body = %q, want %q", i, b, "payload")
+		}
+	}
+}
+
+func TestRetryTransport_NonRewindableBodyStopsAfterFirstAttempt(t *testing.T) {
+	attempts := 0
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: io.NopCloser(bytes.NewReader(nil)), Header: http.Header{}}, nil
+	})
+
+	rt := &RetryTransport{
+		Base:    base,
+		Policy:  &RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond},
+		Sleeper: &fakeSleeper{},
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		_, _ = pw.Write([]byte("payload"))
+		pw.Close()
+	}()
+	req, err := http.NewRequest(http.MethodPost, "http://example.com/ingest", pr)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	req.GetBody = nil
+
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected the single attempt's 503 to be returned, got %d", resp.StatusCode)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected exactly 1 attempt for a non-rewindable body, got %d", attempts)
+	}
+}
+
+func TestRetryTransport_RetryAfterOverridesBackoff(t *testing.T) {
+	attempts := 0
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		if attempts == 1 {
+			h := http.Header{}
+			h.Set("Retry-After", "3")
+			return &http.Response{StatusCode: http.StatusTooManyRequests, Body: io.NopCloser(bytes.NewReader(nil)), Header: h}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(nil)), Header: http.Header{}}, nil
+	})
+
+	sleeper := &fakeSleeper{}
+	rt := &RetryTransport{
+		Base:    base,
+		Policy:  &RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond},
+		Sleeper: sleeper,
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/ingest", nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected eventual 200, got %d", resp.StatusCode)
+	}
+	if len(sleeper.delays) != 1 || sleeper.delays[0] != 3*time.Second {
+		t.Fatalf("expected a single 3s delay from Retry-After, got %v", sleeper.delays)
+	}
+}
+
+func TestRetryTransport_ContextCancelledMidBackoff(t *testing.T) {
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: io.NopCloser(bytes.NewReader(nil)), Header: http.Header{}}, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rt := &RetryTransport{
+		Base:    base,
+		Policy:  &RetryPolicy{MaxRetries: 5, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond},
+		Sleeper: &cancelingSleeper{cancel: cancel},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/ingest", nil).WithContext(ctx)
+	_, err := rt.RoundTrip(req)
+	if err == nil {
+		t.Fatal("expected context cancellation error, got nil")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	clock := fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	if got := parseRetryAfter("3", clock); got != 3*time.Second {
+		t.Errorf("delta-seconds: got %v, want 3s", got)
+	}
+	if got := parseRetryAfter("", clock); got != 0 {
+		t.Errorf("empty header: got %v, want 0", got)
+	}
+	future := clock.now.Add(5 * time.Second).Format(http.TimeFormat)
+	if got := parseRetryAfter(future, clock); got < 4*time.Second || got > 5*time.Second {
+		t.Errorf("HTTP-date: got %v, want ~5s", got)
+	}
+	past := clock.now.Add(-5 * time.Second).Format(http.TimeFormat)
+	if got := parseRetryAfter(past, clock); got != 0 {
+		t.Errorf("past HTTP-date: got %v, want 0", got)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
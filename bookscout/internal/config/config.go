@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"log"
+	"strings"
 	"sync"
 
 	"github.com/caarlos0/env/v10"
@@ -18,13 +19,91 @@ type Config struct {
 	OPDSUsername   string `env:"BS_OPDS_USERNAME"`
 	OPDSPassword   string `env:"BS_OPDS_PASSWORD"`
 
+	// OPDSAuthMode selects the Authenticator used for the OPDS adapter:
+	// "basic" (default), "bearer", or "oidc".
+	OPDSAuthMode         string `env:"BS_OPDS_AUTH_MODE" envDefault:"basic"`
+	OPDSBearerToken      string `env:"BS_OPDS_BEARER_TOKEN"`
+	OPDSOIDCIssuer       string `env:"BS_OPDS_OIDC_ISSUER"`
+	OPDSOIDCClientID     string `env:"BS_OPDS_OIDC_CLIENT_ID"`
+	OPDSOIDCClientSecret string `env:"BS_OPDS_OIDC_CLIENT_SECRET"`
+	OPDSOIDCScopes       string `env:"BS_OPDS_OIDC_SCOPES"`
+
+	// OPDSFacets restricts ingestion to the comma-separated shelf/category
+	// names listed here, matched against entry <category> terms/labels and
+	// facet/subsection link titles. Empty means ingest everything the
+	// catalog serves.
+	OPDSFacets string `env:"BS_OPDS_FACETS"`
+
+	CalibreBaseURL   string `env:"BS_CALIBRE_BASE_URL"`
+	CalibreLibraryID string `env:"BS_CALIBRE_LIBRARY_ID"`
+	CalibreUsername  string `env:"BS_CALIBRE_USERNAME"`
+	CalibrePassword  string `env:"BS_CALIBRE_PASSWORD"`
+
+	KomgaBaseURL  string `env:"BS_KOMGA_BASE_URL"`
+	KomgaAPIToken string `env:"BS_KOMGA_API_TOKEN"`
+
+	KavitaBaseURL  string `env:"BS_KAVITA_BASE_URL"`
+	KavitaAPIToken string `env:"BS_KAVITA_API_TOKEN"`
+
+	LocalSourcePath string `env:"BS_LOCAL_SOURCE_PATH"`
+
+	// ChainSources lists the comma-separated source types fanned out to by
+	// the "chain" BookSourceType, e.g. "opds,komga".
+	ChainSources string `env:"BS_CHAIN_SOURCES"`
+
 	APIBaseURL string `env:"BS_API_BASE_URL" envDefault:"http://api:8080/api/v1"`
 
 	WorkerSinceTimestamp int64 `env:"BS_WORKER_SINCE_TIMESTAMP" envDefault:"0"`
 	WorkerConcurrency    int   `env:"BS_WORKER_CONCURRENCY" envDefault:"5"`
 	WorkerBatchSize      int   `env:"BS_WORKER_BATCH_SIZE" envDefault:"0"`
+	WorkerDelayMS        int   `env:"BS_WORKER_DELAY_MS" envDefault:"0"`
+
+	// Retry policy around processBook (download + send), see resilience.Retrier.
+	WorkerRetryMax      int `env:"BS_WORKER_RETRY_MAX" envDefault:"3"`
+	WorkerRetryBaseMS   int `env:"BS_WORKER_RETRY_BASE_MS" envDefault:"200"`
+	WorkerRetryCapMS    int `env:"BS_WORKER_RETRY_CAP_MS" envDefault:"10000"`
+	WorkerRetryBudgetMS int `env:"BS_WORKER_RETRY_BUDGET_MS" envDefault:"30000"`
 
 	MaxBookSizeBytes int64 `env:"BS_MAX_BOOK_SIZE_BYTES" envDefault:"52428800"`
+
+	// Retry+circuit-breaker policy around the worker's ingestToAPI/isRegistered
+	// calls to the BookSage API (see cmd/worker's apiClient). The retry budget
+	// is the effective stop condition -- APIClientRetryMax is set high enough
+	// that it practically never exhausts first -- so a flaky API gets retried
+	// for up to the budget before the call gives up, while APIClientBreaker*
+	// makes a sustained outage fail fast instead of burning that budget on
+	// every book in the batch.
+	APIClientRetryMax         int `env:"BS_API_RETRY_MAX" envDefault:"1000"`
+	APIClientRetryBaseMS      int `env:"BS_API_RETRY_BASE_MS" envDefault:"500"`
+	APIClientRetryCapMS       int `env:"BS_API_RETRY_CAP_MS" envDefault:"30000"`
+	APIClientRetryBudgetMS    int `env:"BS_API_RETRY_BUDGET_MS" envDefault:"300000"`
+	APIClientBreakerThreshold int `env:"BS_API_BREAKER_THRESHOLD" envDefault:"5"`
+	APIClientBreakerOpenMS    int `env:"BS_API_BREAKER_OPEN_MS" envDefault:"30000"`
+
+	// StateStoreBackend selects the ports.StateStore implementation: "file"
+	// (default, a single local JSON file, see tracker.FileStateStore),
+	// "redis", or "postgres". The latter two are safe to share across
+	// multiple worker replicas; "file" is not.
+	StateStoreBackend  string `env:"BS_STATE_STORE_BACKEND" envDefault:"file"`
+	StateFilePath      string `env:"BS_STATE_FILE_PATH" envDefault:"./data/state.json"`
+	StateRedisAddr     string `env:"BS_STATE_REDIS_ADDR" envDefault:"localhost:6379"`
+	StateRedisPassword string `env:"BS_STATE_REDIS_PASSWORD"`
+	StateRedisDB       int    `env:"BS_STATE_REDIS_DB" envDefault:"0"`
+	StatePostgresDSN   string `env:"BS_STATE_POSTGRES_DSN"`
+
+	// ProgressReporter selects the progress.Reporter the worker reports
+	// through: "bar" (default, a terminal progress bar on stderr), "json"
+	// (a status file at ProgressStatusFile, for CI/k8s sidecar polling), or
+	// "none".
+	ProgressReporter   string `env:"BS_PROGRESS_REPORTER" envDefault:"bar"`
+	ProgressStatusFile string `env:"BS_PROGRESS_STATUS_FILE" envDefault:"./data/worker-status.json"`
+
+	// SourcePlugins is a comma-separated list of name:path pairs, e.g.
+	// "gutenberg:./plugins/gutenberg,archive:./plugins/archive". Each path
+	// is an out-of-process binary implementing ports.BookDataSource over
+	// plugin.Serve; setting BS_BOOK_SOURCE_TYPE=plugin fans FetchNewBooks
+	// and DownloadBookContent out across all of them concurrently.
+	SourcePlugins string `env:"BS_SOURCE_PLUGINS"`
 }
 
 func (c *Config) Validate() error {
@@ -32,6 +111,10 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("BS_OPDS_BASE_URL is required when BS_BOOK_SOURCE_TYPE is opds")
 	}
 
+	if strings.ToLower(c.BookSourceType) == "plugin" && strings.TrimSpace(c.SourcePlugins) == "" {
+		return fmt.Errorf("BS_SOURCE_PLUGINS is required when BS_BOOK_SOURCE_TYPE is plugin")
+	}
+
 	if c.APIPort <= 0 || c.APIPort > 65535 {
 		return fmt.Errorf("BS_PORT must be between 1 and 65535")
 	}
@@ -48,6 +131,44 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("BS_WORKER_SINCE_TIMESTAMP cannot be negative")
 	}
 
+	if c.WorkerRetryMax < 0 {
+		return fmt.Errorf("BS_WORKER_RETRY_MAX cannot be negative")
+	}
+
+	if c.APIClientRetryMax < 0 {
+		return fmt.Errorf("BS_API_RETRY_MAX cannot be negative")
+	}
+
+	if c.APIClientBreakerThreshold < 1 {
+		return fmt.Errorf("BS_API_BREAKER_THRESHOLD must be at least 1")
+	}
+
+	switch strings.ToLower(c.OPDSAuthMode) {
+	case "basic", "bearer", "oidc":
+	default:
+		return fmt.Errorf("BS_OPDS_AUTH_MODE must be one of basic, bearer, oidc")
+	}
+
+	switch strings.ToLower(c.StateStoreBackend) {
+	case "file", "":
+	case "redis":
+		if c.StateRedisAddr == "" {
+			return fmt.Errorf("BS_STATE_REDIS_ADDR is required when BS_STATE_STORE_BACKEND is redis")
+		}
+	case "postgres":
+		if c.StatePostgresDSN == "" {
+			return fmt.Errorf("BS_STATE_POSTGRES_DSN is required when BS_STATE_STORE_BACKEND is postgres")
+		}
+	default:
+		return fmt.Errorf("BS_STATE_STORE_BACKEND must be one of file, redis, postgres")
+	}
+
+	switch strings.ToLower(c.ProgressReporter) {
+	case "bar", "json", "none", "":
+	default:
+		return fmt.Errorf("BS_PROGRESS_REPORTER must be one of bar, json, none")
+	}
+
 	return nil
 }
 
@@ -14,6 +14,18 @@ type BookSource interface {
 	DownloadBookContent(ctx context.Context, book models.BookMetadata) (io.ReadCloser, error)
 }
 
+// BookDataSource defines the interface for pluggable book catalog adapters
+// (OPDS, Calibre, Komga, Kavita, local filesystem, or a chain of several).
+// Unlike BookSource, DownloadBookContent returns the content already
+// buffered in memory, which is what the standalone cmd/worker entrypoint
+// expects.
+type BookDataSource interface {
+	// FetchNewBooks returns a list of books that are strictly newer than the given timestamp.
+	FetchNewBooks(ctx context.Context, lastCheckTimestamp int64) ([]models.BookMetadata, error)
+	// DownloadBookContent downloads the binary content of a book (e.g., PDF/EPUB).
+	DownloadBookContent(ctx context.Context, book models.BookMetadata) ([]byte, error)
+}
+
 // BookDestination defines the interface for sending books to the ingestion system.
 type BookDestination interface {
 	// Send uploads the book content and metadata to the BookSage API.
@@ -28,6 +40,11 @@ type StateStore interface {
 	IsProcessed(bookID string) bool
 	// MarkProcessed records a book ID as processed.
 	MarkProcessed(bookID string) error
+	// MarkProcessedBatch records every ID in bookIDs as processed in one
+	// call, so a caller with many books to record doesn't pay one round
+	// trip per book the way repeated MarkProcessed calls would against a
+	// networked backend (Redis, Postgres).
+	MarkProcessedBatch(bookIDs []string) error
 	// UpdateWatermark updates the global high-water mark.
 	UpdateWatermark(timestamp int64) error
 	// Save persists the current state (watermark + processed IDs) to storage.
@@ -2,16 +2,118 @@ package service
 
 import (
 	"bookscout/internal/adapters/source"
+	"bookscout/internal/adapters/source/plugin"
 	"bookscout/internal/config"
 	"bookscout/internal/core/domain/ports"
+	"fmt"
+	"strings"
 )
 
-func CreateBookSource(cfg *config.Config) ports.BookDataSource {
-	switch cfg.BookSourceType {
-	case "opds":
-		return source.NewOPDSAdapter(cfg.OPDSBaseURL, cfg.OPDSUsername, cfg.OPDSPassword, cfg.MaxBookSizeBytes, cfg.LogLevel)
+// CreateBookSource builds the configured ports.BookDataSource. BS_CHAIN_SOURCES
+// takes priority when set, fanning out across several underlying sources;
+// otherwise BookSourceType selects a single adapter.
+func CreateBookSource(cfg *config.Config) (ports.BookDataSource, error) {
+	if strings.TrimSpace(cfg.ChainSources) != "" {
+		return createChainSource(cfg)
+	}
+	return createSingleSource(cfg, cfg.BookSourceType)
+}
+
+func createSingleSource(cfg *config.Config, sourceType string) (ports.BookDataSource, error) {
+	switch strings.ToLower(sourceType) {
+	case "opds", "":
+		return source.NewOPDSAdapter(cfg.OPDSBaseURL, opdsAuthenticator(cfg), cfg.MaxBookSizeBytes, cfg.LogLevel, opdsFacets(cfg)...), nil
+	case "calibre":
+		return source.NewCalibreAdapter(cfg.CalibreBaseURL, cfg.CalibreLibraryID, cfg.CalibreUsername, cfg.CalibrePassword, cfg.MaxBookSizeBytes), nil
+	case "komga":
+		return source.NewKomgaAdapter(cfg.KomgaBaseURL, cfg.KomgaAPIToken, cfg.MaxBookSizeBytes), nil
+	case "kavita":
+		return source.NewKavitaAdapter(cfg.KavitaBaseURL, cfg.KavitaAPIToken, cfg.MaxBookSizeBytes), nil
+	case "local":
+		return source.NewLocalFilesystemAdapter(cfg.LocalSourcePath, cfg.MaxBookSizeBytes), nil
+	case "plugin":
+		return createPluginSource(cfg)
+	default:
+		return nil, fmt.Errorf("unknown book source type %q", sourceType)
+	}
+}
+
+// createPluginSource launches every binary listed in BS_SOURCE_PLUGINS and
+// fans FetchNewBooks/DownloadBookContent out across all of them through a
+// plugin.MultiAdapter. Each launched subprocess is tracked by go-plugin
+// internally; plugin.CleanupClients (called from main) kills them all on
+// exit.
+func createPluginSource(cfg *config.Config) (ports.BookDataSource, error) {
+	sources := make(map[string]ports.BookDataSource)
+	for _, spec := range strings.Split(cfg.SourcePlugins, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		name, path, ok := strings.Cut(spec, ":")
+		if !ok || name == "" || path == "" {
+			return nil, fmt.Errorf("invalid BS_SOURCE_PLUGINS entry %q, expected name:path", spec)
+		}
+		src, _, err := plugin.NewClient(name, path)
+		if err != nil {
+			return nil, fmt.Errorf("loading plugin %q: %w", name, err)
+		}
+		sources[name] = src
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("BS_SOURCE_PLUGINS is set but contains no valid entries")
+	}
+	return plugin.NewMultiAdapter(sources), nil
+}
+
+// createChainSource builds a ChainAdapter from the comma-separated
+// BS_CHAIN_SOURCES list, e.g. "opds,komga,local".
+func createChainSource(cfg *config.Config) (ports.BookDataSource, error) {
+	var sources []ports.BookDataSource
+	for _, name := range strings.Split(cfg.ChainSources, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		src, err := createSingleSource(cfg, name)
+		if err != nil {
+			return nil, fmt.Errorf("chain source %q: %w", name, err)
+		}
+		sources = append(sources, src)
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("BS_CHAIN_SOURCES is set but contains no valid source names")
+	}
+	return source.NewChainAdapter(sources...), nil
+}
+
+// opdsAuthenticator builds the OPDS Authenticator selected by BS_OPDS_AUTH_MODE.
+func opdsAuthenticator(cfg *config.Config) source.Authenticator {
+	switch strings.ToLower(cfg.OPDSAuthMode) {
+	case "bearer":
+		return &source.BearerToken{Token: cfg.OPDSBearerToken}
+	case "oidc":
+		return &source.OIDCClientCredentials{
+			Issuer:       cfg.OPDSOIDCIssuer,
+			ClientID:     cfg.OPDSOIDCClientID,
+			ClientSecret: cfg.OPDSOIDCClientSecret,
+			Scopes:       strings.Fields(cfg.OPDSOIDCScopes),
+		}
 	default:
-		// Default to OPDS
-		return source.NewOPDSAdapter(cfg.OPDSBaseURL, cfg.OPDSUsername, cfg.OPDSPassword, cfg.MaxBookSizeBytes, cfg.LogLevel)
+		return &source.BasicAuth{User: cfg.OPDSUsername, Pass: cfg.OPDSPassword}
+	}
+}
+
+// opdsFacets splits BS_OPDS_FACETS into the shelf/category names the OPDS
+// adapter should restrict ingestion to, trimming whitespace around each and
+// dropping empty entries left by a trailing/doubled comma.
+func opdsFacets(cfg *config.Config) []string {
+	var facets []string
+	for _, f := range strings.Split(cfg.OPDSFacets, ",") {
+		f = strings.TrimSpace(f)
+		if f != "" {
+			facets = append(facets, f)
+		}
 	}
+	return facets
 }
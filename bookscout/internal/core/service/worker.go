@@ -4,18 +4,22 @@ import (
 	"bookscout/internal/config"
 	"bookscout/internal/core/domain/models"
 	"bookscout/internal/core/domain/ports"
+	"bookscout/internal/resilience"
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"sync"
 	"time"
 )
 
 type WorkerService struct {
-	cfg   *config.Config
-	src   ports.BookSource
-	dest  ports.BookDestination
-	state ports.StateStore
+	cfg     *config.Config
+	src     ports.BookSource
+	dest    ports.BookDestination
+	state   ports.StateStore
+	retrier *resilience.Retrier
 }
 
 func NewWorkerService(
@@ -29,6 +33,13 @@ func NewWorkerService(
 		src:   src,
 		dest:  dest,
 		state: state,
+		retrier: resilience.NewRetrier(
+			cfg.WorkerRetryMax,
+			time.Duration(cfg.WorkerRetryBaseMS)*time.Millisecond,
+			time.Duration(cfg.WorkerRetryCapMS)*time.Millisecond,
+			time.Duration(cfg.WorkerRetryBudgetMS)*time.Millisecond,
+			nil,
+		),
 	}
 }
 
@@ -85,11 +96,12 @@ func (s *WorkerService) Run(ctx context.Context) error {
 
 	// 5. Process concurrently
 	var (
-		wg           sync.WaitGroup
-		mu           sync.Mutex
-		maxTimestamp = since
-		successCount = 0
-		failCount    = 0
+		wg             sync.WaitGroup
+		mu             sync.Mutex
+		maxTimestamp   = since
+		successCount   = 0
+		failCount      = 0
+		processedBooks []string
 	)
 
 	// Semaphore to control concurrency
@@ -116,12 +128,11 @@ func (s *WorkerService) Run(ctx context.Context) error {
 				return
 			}
 
-			// On success, update state and watermark tracking
+			// On success, record for the batched MarkProcessedBatch call below
+			// and update watermark tracking
 			mu.Lock()
 			successCount++
-			if err := s.state.MarkProcessed(b.ID); err != nil {
-				log.Printf("WARNING: Failed to mark book %s as processed: %v", b.ID, err)
-			}
+			processedBooks = append(processedBooks, b.ID)
 			if b.AddedAt.Unix() > maxTimestamp {
 				maxTimestamp = b.AddedAt.Unix()
 			}
@@ -134,6 +145,14 @@ func (s *WorkerService) Run(ctx context.Context) error {
 	// 6. Finalize State
 	log.Printf("Batch Complete. Success: %d, Failed: %d", successCount, failCount)
 
+	// Record every successfully processed book in one round trip instead of
+	// one MarkProcessed call per book.
+	if len(processedBooks) > 0 {
+		if err := s.state.MarkProcessedBatch(processedBooks); err != nil {
+			log.Printf("WARNING: Failed to mark books as processed: %v", err)
+		}
+	}
+
 	// Only update watermark if we processed something successfully and the new timestamp is greater
 	if maxTimestamp > since {
 		if err := s.state.UpdateWatermark(maxTimestamp); err != nil {
@@ -151,15 +170,29 @@ func (s *WorkerService) Run(ctx context.Context) error {
 }
 
 func (s *WorkerService) processBook(ctx context.Context, book models.BookMetadata) error {
-	// A. Download
-	content, err := s.src.DownloadBookContent(ctx, book)
+	// A. Download, retrying transient failures with backoff.
+	var content io.ReadCloser
+	err := s.retrier.Execute(ctx, func() error {
+		var downloadErr error
+		content, downloadErr = s.src.DownloadBookContent(ctx, book)
+		return downloadErr
+	})
 	if err != nil {
 		return fmt.Errorf("download failed: %w", err)
 	}
 	defer content.Close()
 
-	// B. Send to Destination
-	if err := s.dest.Send(ctx, book, content); err != nil {
+	// Buffer the downloaded content so a retried Send can replay the exact
+	// same bytes; the stream from the source can't be rewound once consumed.
+	buf, err := io.ReadAll(content)
+	if err != nil {
+		return fmt.Errorf("buffering downloaded content failed: %w", err)
+	}
+
+	// B. Send to Destination, retrying transient failures with backoff.
+	if err := s.retrier.Execute(ctx, func() error {
+		return s.dest.Send(ctx, book, bytes.NewReader(buf))
+	}); err != nil {
 		return fmt.Errorf("send failed: %w", err)
 	}
 
@@ -6,17 +6,109 @@ import (
 	"bookscout/internal/config"
 	"bookscout/internal/core/domain/models"
 	"bookscout/internal/core/service"
+	"bytes"
 	"context"
-	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
 
+// mockUploadServer is a minimal in-memory implementation of BookSage's
+// resumable ingest endpoints, just enough for BookSageAPIAdapter.Send to
+// complete a full create/PATCH/PUT round trip against.
+type mockUploadServer struct {
+	mu      sync.Mutex
+	nextID  int
+	uploads map[string]*bytes.Buffer
+}
+
+func newMockUploadServer() *mockUploadServer {
+	return &mockUploadServer{uploads: make(map[string]*bytes.Buffer)}
+}
+
+func (m *mockUploadServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /ingest/uploads", m.handleCreate)
+	mux.HandleFunc("HEAD /ingest/uploads/{id}", m.handleProgress)
+	mux.HandleFunc("PATCH /ingest/uploads/{id}", m.handleChunk)
+	mux.HandleFunc("PUT /ingest/uploads/{id}", m.handleComplete)
+	return mux
+}
+
+func (m *mockUploadServer) handleCreate(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	m.nextID++
+	id := fmt.Sprintf("upload-%d", m.nextID)
+	m.uploads[id] = &bytes.Buffer{}
+	m.mu.Unlock()
+
+	w.Header().Set("Docker-Upload-UUID", id)
+	w.Header().Set("Range", "0-0")
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (m *mockUploadServer) handleProgress(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	buf, ok := m.uploads[r.PathValue("id")]
+	var offset int
+	if ok {
+		offset = buf.Len()
+	}
+	m.mu.Unlock()
+
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Range", fmt.Sprintf("0-%d", offset))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *mockUploadServer) handleChunk(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	buf, ok := m.uploads[r.PathValue("id")]
+	m.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	m.mu.Lock()
+	buf.Write(body)
+	offset := buf.Len()
+	m.mu.Unlock()
+
+	w.Header().Set("Range", fmt.Sprintf("0-%d", offset))
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (m *mockUploadServer) handleComplete(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	_, ok := m.uploads[r.PathValue("id")]
+	m.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if r.URL.Query().Get("digest") == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
 // mockBookSource implements ports.BookSource
 type mockBookSource struct {
 	books    []models.BookMetadata
@@ -48,34 +140,7 @@ func (m *mockBookSource) DownloadBookContent(ctx context.Context, book models.Bo
 
 func TestWorkerService_Run(t *testing.T) {
 	// 1. Mock Destination Server
-	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path != "/ingest" {
-			t.Errorf("expected path /ingest, got %s", r.URL.Path)
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
-		if r.Method != "POST" {
-			t.Errorf("expected POST method, got %s", r.Method)
-			w.WriteHeader(http.StatusMethodNotAllowed)
-			return
-		}
-		// Verify multipart
-		if err := r.ParseMultipartForm(10 << 20); err != nil {
-			t.Fatal("failed to parse multipart form")
-		}
-
-		// Verify metadata
-		metaStr := r.FormValue("metadata")
-		var meta models.BookMetadata
-		if err := json.Unmarshal([]byte(metaStr), &meta); err != nil {
-			t.Errorf("invalid metadata json: %v", err)
-		}
-		if meta.Title == "" {
-			t.Error("metadata title is empty")
-		}
-
-		w.WriteHeader(http.StatusOK)
-	}))
+	ts := httptest.NewServer(newMockUploadServer().handler())
 	defer ts.Close()
 
 	// 2. Setup State Store
@@ -111,7 +176,17 @@ func TestWorkerService_Run(t *testing.T) {
 	}
 
 	// 5. Setup Destination Adapter
-	dest := destination.NewBookSageAPIAdapter(ts.URL)
+	uploadStateFile, err := os.CreateTemp("", "scout_upload_state_*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(uploadStateFile.Name())
+	uploadStateFile.Close()
+
+	dest, err := destination.NewBookSageAPIAdapter(ts.URL, uploadStateFile.Name())
+	if err != nil {
+		t.Fatalf("failed to create destination adapter: %v", err)
+	}
 
 	// 6. Run Worker
 	svc := service.NewWorkerService(cfg, mockSrc, dest, state)
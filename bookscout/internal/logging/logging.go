@@ -0,0 +1,24 @@
+// Package logging builds the hclog.Logger the worker's main() hands to Run
+// and the HTTP helpers it calls. There's only one subsystem in this binary,
+// so the root logger is named "worker" directly rather than being derived
+// from a shared parent the way the API process names its children.
+package logging
+
+import (
+	"os"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// New builds the root logger for the worker process. Output is
+// human-readable text by default; setting BS_LOG_FORMAT=json switches to
+// structured JSON lines, for deployments that feed logs into an aggregator
+// rather than a terminal.
+func New() hclog.Logger {
+	return hclog.New(&hclog.LoggerOptions{
+		Name:       "worker",
+		Level:      hclog.Info,
+		Output:     os.Stderr,
+		JSONFormat: os.Getenv("BS_LOG_FORMAT") == "json",
+	})
+}
@@ -0,0 +1,24 @@
+package progress
+
+import (
+	"bookscout/internal/config"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// NewReporter builds the Reporter selected by cfg.ProgressReporter: "bar"
+// (default, a terminal bar on stderr), "json" (a status file at
+// cfg.ProgressStatusFile), or "none".
+func NewReporter(cfg *config.Config) (Reporter, error) {
+	switch strings.ToLower(cfg.ProgressReporter) {
+	case "bar", "":
+		return NewTerminalReporter(os.Stderr), nil
+	case "json":
+		return NewJSONReporter(cfg.ProgressStatusFile), nil
+	case "none":
+		return NopReporter{}, nil
+	default:
+		return nil, fmt.Errorf("unknown progress reporter %q", cfg.ProgressReporter)
+	}
+}
@@ -0,0 +1,97 @@
+package progress
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// jsonStatus is the on-disk shape of a JSONReporter's status file, suitable
+// for a CI job or Kubernetes sidecar to poll with a plain file read.
+type jsonStatus struct {
+	Status           string  `json:"status"` // "running", "done", or "aborted"
+	Total            int     `json:"total"`
+	Processed        int     `json:"processed"`
+	Skipped          int     `json:"skipped"`
+	Failed           int     `json:"failed"`
+	ElapsedSeconds   float64 `json:"elapsed_seconds"`
+	ThroughputPerSec float64 `json:"throughput_per_sec"`
+	ETASeconds       float64 `json:"eta_seconds"`
+}
+
+// JSONReporter writes Snapshot updates to a status file at Path, atomically
+// (write to a temp file, then rename) so a poller never observes a
+// half-written document.
+type JSONReporter struct {
+	path string
+
+	mu      sync.Mutex
+	started time.Time
+}
+
+// NewJSONReporter builds a JSONReporter writing status to path.
+func NewJSONReporter(path string) *JSONReporter {
+	return &JSONReporter{path: path}
+}
+
+func (j *JSONReporter) Start(total int) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.started = time.Now()
+	j.write(Snapshot{Total: total}, "running")
+}
+
+func (j *JSONReporter) Update(s Snapshot) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.write(s, "running")
+}
+
+func (j *JSONReporter) Finish(s Snapshot, aborted bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	status := "done"
+	if aborted {
+		status = "aborted"
+	}
+	j.write(s, status)
+}
+
+// write must be called with j.mu held. Write failures are swallowed: losing
+// the progress sidecar file is not worth failing the ingestion run over.
+func (j *JSONReporter) write(s Snapshot, status string) {
+	elapsed := time.Since(j.started)
+	perSec, eta := throughputAndETA(s, elapsed)
+
+	doc := jsonStatus{
+		Status:           status,
+		Total:            s.Total,
+		Processed:        s.Processed,
+		Skipped:          s.Skipped,
+		Failed:           s.Failed,
+		ElapsedSeconds:   elapsed.Seconds(),
+		ThroughputPerSec: perSec,
+		ETASeconds:       eta.Seconds(),
+	}
+
+	if err := os.MkdirAll(filepath.Dir(j.path), 0755); err != nil {
+		return
+	}
+
+	tmpFile := j.path + ".tmp"
+	f, err := os.Create(tmpFile)
+	if err != nil {
+		return
+	}
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		f.Close()
+		return
+	}
+	f.Close()
+
+	_ = os.Rename(tmpFile, j.path)
+}
@@ -0,0 +1,57 @@
+// Package progress reports the book worker's batch-ingestion progress to an
+// operator, either as a live terminal bar or a machine-readable status file
+// a CI job or Kubernetes sidecar can poll.
+package progress
+
+import "time"
+
+// Snapshot is the running tally of a batch ingestion, reported after every
+// book finishes (successfully, skipped, or failed).
+type Snapshot struct {
+	Total     int
+	Processed int
+	Skipped   int
+	Failed    int
+}
+
+// done returns how many of Total have been accounted for, whether they
+// succeeded, were skipped, or failed.
+func (s Snapshot) done() int {
+	return s.Processed + s.Skipped + s.Failed
+}
+
+// Reporter is notified of a batch ingestion's progress. Start is called once
+// with the total book count before any work begins, Update after every book
+// finishes, and Finish once the run ends, successfully or not. Implementations
+// must be safe for concurrent use: Update is called from every in-flight
+// book's goroutine.
+type Reporter interface {
+	Start(total int)
+	Update(s Snapshot)
+	Finish(s Snapshot, aborted bool)
+}
+
+// NopReporter discards every call. It's the zero value callers get from
+// NewReporter when BS_PROGRESS_REPORTER=none, and what tests use when
+// progress output would just be noise.
+type NopReporter struct{}
+
+func (NopReporter) Start(int)             {}
+func (NopReporter) Update(Snapshot)       {}
+func (NopReporter) Finish(Snapshot, bool) {}
+
+// throughputAndETA derives books/sec and estimated time remaining from how
+// much of the batch is done and how long that took. Both implementations
+// need this, so it lives here instead of being duplicated.
+func throughputAndETA(s Snapshot, elapsed time.Duration) (perSec float64, eta time.Duration) {
+	done := s.done()
+	if done == 0 || elapsed <= 0 {
+		return 0, 0
+	}
+	perSec = float64(done) / elapsed.Seconds()
+	remaining := s.Total - done
+	if remaining <= 0 || perSec <= 0 {
+		return perSec, 0
+	}
+	return perSec, time.Duration(float64(remaining)/perSec) * time.Second
+}
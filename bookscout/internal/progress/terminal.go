@@ -0,0 +1,70 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TerminalReporter renders a single self-overwriting progress line to an
+// interactive terminal: a filled bar, the processed/skipped/failed counts,
+// throughput, and an ETA. Every call re-renders the whole line behind a
+// carriage return, so it's safe to call Update from multiple goroutines as
+// books finish out of order.
+type TerminalReporter struct {
+	out   io.Writer
+	width int
+
+	mu      sync.Mutex
+	total   int
+	started time.Time
+}
+
+// NewTerminalReporter builds a TerminalReporter writing to out (typically
+// os.Stderr, so redirected stdout isn't polluted with \r-laden lines).
+func NewTerminalReporter(out io.Writer) *TerminalReporter {
+	return &TerminalReporter{out: out, width: 30}
+}
+
+func (t *TerminalReporter) Start(total int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.total = total
+	t.started = time.Now()
+	t.render(Snapshot{Total: total})
+}
+
+func (t *TerminalReporter) Update(s Snapshot) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.render(s)
+}
+
+func (t *TerminalReporter) Finish(s Snapshot, aborted bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.render(s)
+	status := "done"
+	if aborted {
+		status = "aborted"
+	}
+	fmt.Fprintf(t.out, "\n%s: %d processed, %d skipped, %d failed (of %d) in %s\n",
+		status, s.Processed, s.Skipped, s.Failed, s.Total, time.Since(t.started).Round(time.Second))
+}
+
+// render must be called with t.mu held.
+func (t *TerminalReporter) render(s Snapshot) {
+	done := s.done()
+	pct := 0.0
+	if s.Total > 0 {
+		pct = float64(done) / float64(s.Total)
+	}
+	filled := int(pct * float64(t.width))
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", t.width-filled)
+
+	perSec, eta := throughputAndETA(s, time.Since(t.started))
+	fmt.Fprintf(t.out, "\r[%s] %d/%d (%d skipped, %d failed) %.1f/s ETA %s",
+		bar, done, s.Total, s.Skipped, s.Failed, perSec, eta.Round(time.Second))
+}
@@ -0,0 +1,125 @@
+package resilience
+
+import (
+	"sync"
+	"time"
+)
+
+// State is one of CircuitBreaker's three states.
+type State int
+
+const (
+	StateClosed State = iota
+	StateOpen
+	StateHalfOpen
+)
+
+// CircuitBreaker trips Open after maxFailures consecutive failures, stays
+// Open for timeout, then allows exactly one probe call through (HalfOpen) to
+// decide whether to close again or re-open. It's a sibling to Retrier: a
+// flaky call is typically wrapped in a Retrier first (so isolated transient
+// errors don't count as a breaker failure) and the whole thing guarded by a
+// CircuitBreaker (so a sustained outage fails fast via ErrCircuitOpen instead
+// of burning the caller's deadline on doomed retries).
+type CircuitBreaker struct {
+	maxFailures int
+	timeout     time.Duration
+
+	mu            sync.Mutex
+	state         State
+	failures      int
+	openedAt      time.Time
+	halfOpenInUse bool
+}
+
+// NewCircuitBreaker creates a closed breaker that trips after maxFailures
+// consecutive failures and stays open for timeout before probing again.
+func NewCircuitBreaker(maxFailures int, timeout time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		maxFailures: maxFailures,
+		timeout:     timeout,
+		state:       StateClosed,
+	}
+}
+
+// Execute runs fn if the breaker allows it, returning ErrCircuitOpen without
+// calling fn otherwise. fn's outcome is recorded against the breaker's state.
+func (cb *CircuitBreaker) Execute(fn func() error) error {
+	if !cb.Allow() {
+		return ErrCircuitOpen
+	}
+
+	err := fn()
+	if err != nil {
+		cb.RecordFailure()
+		return err
+	}
+	cb.RecordSuccess()
+	return nil
+}
+
+// Allow reports whether a call should be attempted right now, transitioning
+// Open to HalfOpen and handing out one probe slot once timeout has elapsed.
+func (cb *CircuitBreaker) Allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case StateClosed:
+		return true
+	case StateHalfOpen:
+		if cb.halfOpenInUse {
+			return false
+		}
+		cb.halfOpenInUse = true
+		return true
+	default: // StateOpen
+		if time.Since(cb.openedAt) < cb.timeout {
+			return false
+		}
+		cb.state = StateHalfOpen
+		cb.halfOpenInUse = true
+		return true
+	}
+}
+
+// RecordSuccess closes the breaker and resets its failure streak.
+func (cb *CircuitBreaker) RecordSuccess() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.failures = 0
+	cb.state = StateClosed
+	cb.halfOpenInUse = false
+}
+
+// RecordFailure counts a failed call, tripping Open once failures reaches
+// maxFailures; a failed HalfOpen probe re-opens immediately regardless of
+// the threshold.
+func (cb *CircuitBreaker) RecordFailure() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == StateHalfOpen {
+		cb.tripLocked()
+		return
+	}
+
+	cb.failures++
+	if cb.failures >= cb.maxFailures {
+		cb.tripLocked()
+	}
+}
+
+func (cb *CircuitBreaker) tripLocked() {
+	cb.state = StateOpen
+	cb.openedAt = time.Now()
+	cb.halfOpenInUse = false
+}
+
+// CurrentState returns the breaker's current state, exactly as last left by
+// Allow/RecordSuccess/RecordFailure.
+func (cb *CircuitBreaker) CurrentState() State {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state
+}
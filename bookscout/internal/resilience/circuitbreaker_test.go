@@ -0,0 +1,72 @@
+package resilience
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker_ClosedState(t *testing.T) {
+	cb := NewCircuitBreaker(3, 100*time.Millisecond)
+
+	err := cb.Execute(func() error { return nil })
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cb.CurrentState() != StateClosed {
+		t.Errorf("expected Closed, got %d", cb.CurrentState())
+	}
+}
+
+func TestCircuitBreaker_OpensAfterThreshold(t *testing.T) {
+	cb := NewCircuitBreaker(3, 100*time.Millisecond)
+	testErr := errors.New("fail")
+
+	for i := 0; i < 3; i++ {
+		_ = cb.Execute(func() error { return testErr })
+	}
+
+	if cb.CurrentState() != StateOpen {
+		t.Errorf("expected Open after 3 failures, got %d", cb.CurrentState())
+	}
+
+	err := cb.Execute(func() error { return nil })
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen, got %v", err)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenAfterTimeout(t *testing.T) {
+	cb := NewCircuitBreaker(2, 50*time.Millisecond)
+	testErr := errors.New("fail")
+
+	_ = cb.Execute(func() error { return testErr })
+	_ = cb.Execute(func() error { return testErr })
+
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("expected Open, got %d", cb.CurrentState())
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	err := cb.Execute(func() error { return nil })
+	if err != nil {
+		t.Fatalf("expected success in HalfOpen, got %v", err)
+	}
+	if cb.CurrentState() != StateClosed {
+		t.Errorf("expected Closed after successful HalfOpen call, got %d", cb.CurrentState())
+	}
+}
+
+func TestCircuitBreaker_HalfOpenFailure(t *testing.T) {
+	cb := NewCircuitBreaker(1, 50*time.Millisecond)
+	testErr := errors.New("fail")
+
+	_ = cb.Execute(func() error { return testErr })
+	time.Sleep(60 * time.Millisecond)
+	_ = cb.Execute(func() error { return testErr })
+
+	if cb.CurrentState() != StateOpen {
+		t.Errorf("expected Open after HalfOpen failure, got %d", cb.CurrentState())
+	}
+}
@@ -0,0 +1,154 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"time"
+)
+
+// ErrCircuitOpen is returned by circuit-breaker-guarded calls when the
+// breaker is open. A Retrier treats it specially: it is neither retried nor
+// counted against MaxRetries, so a tripped breaker fails fast instead of
+// being hammered with retry attempts.
+var ErrCircuitOpen = errors.New("resilience: circuit open")
+
+// HTTPStatusError carries the HTTP status code (and an optional Retry-After
+// hint) behind a failed outbound call so retry policies can distinguish
+// transient failures from permanent client errors without parsing message
+// strings.
+type HTTPStatusError struct {
+	StatusCode int
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *HTTPStatusError) Error() string { return e.Err.Error() }
+func (e *HTTPStatusError) Unwrap() error { return e.Err }
+
+// Retrier retries a function with exponential backoff and full jitter,
+// bounded by a maximum elapsed-time budget. It is a sibling to
+// CircuitBreaker: a flaky call is typically guarded by a CircuitBreaker and
+// wrapped in a Retrier, so transient errors get retried while a tripped
+// breaker fails fast via ErrCircuitOpen.
+type Retrier struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	CapDelay   time.Duration
+	Budget     time.Duration
+
+	// IsRetryable decides whether an error should be retried. Defaults to
+	// DefaultIsRetryable when nil.
+	IsRetryable func(error) bool
+}
+
+// NewRetrier builds a Retrier with the given bounds. A nil isRetryable falls
+// back to DefaultIsRetryable.
+func NewRetrier(maxRetries int, base, cap, budget time.Duration, isRetryable func(error) bool) *Retrier {
+	if isRetryable == nil {
+		isRetryable = DefaultIsRetryable
+	}
+	return &Retrier{
+		MaxRetries:  maxRetries,
+		BaseDelay:   base,
+		CapDelay:    cap,
+		Budget:      budget,
+		IsRetryable: isRetryable,
+	}
+}
+
+// Execute runs fn, retrying on retryable errors with exponential backoff and
+// full jitter: nextDelay = rand(0, min(CapDelay, BaseDelay*2^attempt)).
+// Retrying stops as soon as MaxRetries is exhausted, the Budget elapses, or
+// fn returns a non-retryable error. An ErrCircuitOpen error is returned
+// immediately without consuming an attempt or sleeping.
+func (r *Retrier) Execute(ctx context.Context, fn func() error) error {
+	isRetryable := r.IsRetryable
+	if isRetryable == nil {
+		isRetryable = DefaultIsRetryable
+	}
+
+	deadline := time.Now().Add(r.Budget)
+	var lastErr error
+
+	for attempt := 0; attempt <= r.MaxRetries; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		if errors.Is(err, ErrCircuitOpen) {
+			return err
+		}
+		if !isRetryable(err) {
+			return err
+		}
+		if attempt == r.MaxRetries || time.Now().After(deadline) {
+			break
+		}
+
+		delay := r.nextDelay(attempt)
+		if retryAfter, ok := retryAfterDelay(err); ok && retryAfter > delay {
+			delay = retryAfter
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return lastErr
+}
+
+// nextDelay computes rand(0, min(CapDelay, BaseDelay*2^attempt)).
+func (r *Retrier) nextDelay(attempt int) time.Duration {
+	maxDelay := r.CapDelay
+	if r.BaseDelay > 0 && attempt < 62 {
+		if scaled := r.BaseDelay << uint(attempt); scaled > 0 && scaled < maxDelay {
+			maxDelay = scaled
+		}
+	}
+	if maxDelay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(maxDelay)))
+}
+
+func retryAfterDelay(err error) (time.Duration, bool) {
+	var httpErr *HTTPStatusError
+	if errors.As(err, &httpErr) && httpErr.RetryAfter > 0 {
+		return httpErr.RetryAfter, true
+	}
+	return 0, false
+}
+
+// DefaultIsRetryable retries network errors, io.ErrUnexpectedEOF, and 5xx
+// responses. 4xx responses are not retried except 408 (Request Timeout) and
+// 429 (Too Many Requests).
+func DefaultIsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var httpErr *HTTPStatusError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == 408 || httpErr.StatusCode == 429 || httpErr.StatusCode >= 500
+	}
+
+	return false
+}
@@ -0,0 +1,116 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetrier_SucceedsWithoutRetry(t *testing.T) {
+	r := NewRetrier(3, time.Millisecond, 10*time.Millisecond, time.Second, nil)
+
+	calls := 0
+	err := r.Execute(context.Background(), func() error {
+		calls++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestRetrier_RetriesRetryableErrors(t *testing.T) {
+	r := NewRetrier(2, time.Millisecond, 5*time.Millisecond, time.Second, func(error) bool { return true })
+
+	calls := 0
+	transient := errors.New("transient")
+	err := r.Execute(context.Background(), func() error {
+		calls++
+		if calls < 3 {
+			return transient
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected eventual success, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls (1 + 2 retries), got %d", calls)
+	}
+}
+
+func TestRetrier_StopsOnNonRetryableError(t *testing.T) {
+	r := NewRetrier(5, time.Millisecond, 5*time.Millisecond, time.Second, func(error) bool { return false })
+
+	calls := 0
+	permanent := errors.New("permanent")
+	err := r.Execute(context.Background(), func() error {
+		calls++
+		return permanent
+	})
+	if !errors.Is(err, permanent) {
+		t.Fatalf("expected permanent error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected exactly 1 call, got %d", calls)
+	}
+}
+
+func TestRetrier_StopsImmediatelyOnCircuitOpen(t *testing.T) {
+	r := NewRetrier(5, time.Millisecond, 5*time.Millisecond, time.Second, func(error) bool { return true })
+
+	calls := 0
+	err := r.Execute(context.Background(), func() error {
+		calls++
+		return ErrCircuitOpen
+	})
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected exactly 1 call (no retries on open circuit), got %d", calls)
+	}
+}
+
+func TestRetrier_ExhaustsMaxRetries(t *testing.T) {
+	r := NewRetrier(2, time.Millisecond, 5*time.Millisecond, time.Second, func(error) bool { return true })
+
+	calls := 0
+	transient := errors.New("always fails")
+	err := r.Execute(context.Background(), func() error {
+		calls++
+		return transient
+	})
+	if !errors.Is(err, transient) {
+		t.Fatalf("expected last error to be returned, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls (1 + 2 retries), got %d", calls)
+	}
+}
+
+func TestDefaultIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"5xx", &HTTPStatusError{StatusCode: 503, Err: errors.New("x")}, true},
+		{"429", &HTTPStatusError{StatusCode: 429, Err: errors.New("x")}, true},
+		{"408", &HTTPStatusError{StatusCode: 408, Err: errors.New("x")}, true},
+		{"404", &HTTPStatusError{StatusCode: 404, Err: errors.New("x")}, false},
+		{"unrelated", errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultIsRetryable(tt.err); got != tt.want {
+				t.Errorf("DefaultIsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}